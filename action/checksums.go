@@ -0,0 +1,162 @@
+package action
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/simon04/aur-out-of-date/logging"
+	"github.com/simon04/aur-out-of-date/transport"
+)
+
+// checksumAlgorithms lists the supported checksum arrays, b2sums are computed using b2sum from coreutils
+var checksumAlgorithms = map[string]func() hash.Hash{
+	"md5sums":    md5.New,
+	"sha1sums":   sha1.New,
+	"sha224sums": sha256.New224,
+	"sha256sums": sha256.New,
+	"sha384sums": sha512.New384,
+	"sha512sums": sha512.New,
+	"b2sums":     nil,
+}
+
+// checksumArray matches the checksum arrays of a PKGBUILD, including architecture specific ones such as sha256sums_x86_64
+var checksumArray = regexp.MustCompile(`(?m)^(md5|sha1|sha224|sha256|sha384|sha512|b2)sums(?:_(\w+))?=\(([^)]*)\)`)
+
+// updateChecksums recomputes the checksum arrays of the PKGBUILD for the given sources by architecture (see pkg.SourcesForPkgver),
+// returning the new PKGBUILD and a diff of the changed arrays
+func updateChecksums(input string, sources map[string][]string, dir string) (string, string, error) {
+	matches := checksumArray.FindAllStringSubmatchIndex(input, -1)
+	if len(matches) == 0 {
+		return input, "", nil
+	}
+	files := map[string][]string{}
+	var downloads []string
+	defer func() {
+		for _, file := range downloads {
+			os.Remove(file)
+		}
+	}()
+	for arch, list := range sources {
+		for _, source := range list {
+			file, downloaded, err := sourceFile(source, dir)
+			if err != nil {
+				return "", "", err
+			} else if downloaded {
+				downloads = append(downloads, file)
+			}
+			files[arch] = append(files[arch], file)
+		}
+	}
+
+	var output, diff strings.Builder
+	last := 0
+	for _, match := range matches {
+		algorithm := input[match[2]:match[3]] + "sums"
+		name, arch := algorithm, ""
+		if match[4] >= 0 {
+			arch = input[match[4]:match[5]]
+			name += "_" + arch
+		}
+		old := input[match[0]:match[1]]
+		previous := strings.Fields(strings.NewReplacer("'", " ", "\"", " ").Replace(input[match[6]:match[7]]))
+		if len(previous) != len(sources[arch]) {
+			return "", "", fmt.Errorf("Found %d %s for %d sources", len(previous), name, len(sources[arch]))
+		}
+		var sums []string
+		for i, file := range files[arch] {
+			if file == "" || previous[i] == "SKIP" {
+				sums = append(sums, "SKIP")
+				continue
+			}
+			sum, err := checksum(algorithm, file)
+			if err != nil {
+				return "", "", fmt.Errorf("Failed to compute %s of %s: %w", name, sources[arch][i], err)
+			}
+			sums = append(sums, sum)
+		}
+		update := formatArray(name, sums)
+		if update != old {
+			fmt.Fprintf(&diff, "-%s\n+%s\n", strings.Replace(old, "\n", "\n-", -1), strings.Replace(update, "\n", "\n+", -1))
+		}
+		output.WriteString(input[last:match[0]])
+		output.WriteString(update)
+		last = match[1]
+	}
+	output.WriteString(input[last:])
+	return output.String(), diff.String(), nil
+}
+
+// sourceFile returns the local file of a source, downloading it to a temporary file, or "" for VCS sources
+func sourceFile(source string, dir string) (file string, downloaded bool, err error) {
+	if i := strings.Index(source, "::"); i >= 0 {
+		source = source[i+2:]
+	}
+	if !strings.Contains(source, "://") {
+		return path.Join(dir, path.Base(source)), false, nil
+	} else if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		// VCS sources such as git+https:// are skipped
+		return "", false, nil
+	}
+	if i := strings.Index(source, "#"); i >= 0 {
+		source = source[:i]
+	}
+	logging.Infof("Downloading %s", source)
+	resp, err := transport.GetUncached(source)
+	if err != nil {
+		return "", false, fmt.Errorf("Failed to download %s: %w", source, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", false, fmt.Errorf("Failed to download %s: %s", source, resp.Status)
+	}
+	f, err := ioutil.TempFile("", "aur-out-of-date-")
+	if err != nil {
+		return "", false, err
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		os.Remove(f.Name())
+		return "", false, fmt.Errorf("Failed to download %s: %w", source, err)
+	}
+	return f.Name(), true, nil
+}
+
+func checksum(name string, file string) (string, error) {
+	newHash := checksumAlgorithms[name]
+	if newHash == nil {
+		output, err := exec.Command("b2sum", file).Output()
+		if err != nil {
+			return "", err
+		}
+		return strings.Fields(string(output))[0], nil
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := newHash()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// formatArray formats a bash array the way makepkg -g does
+func formatArray(name string, values []string) string {
+	indent := "\n" + strings.Repeat(" ", len(name)+2)
+	return name + "=('" + strings.Join(values, "'"+indent+"'") + "')"
+}
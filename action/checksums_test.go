@@ -0,0 +1,83 @@
+package action
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/h2non/gock"
+)
+
+func TestUpdateChecksums(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://example.com").
+		Get("/foo-1.1.tar.gz").
+		Reply(200).
+		BodyString("foo")
+
+	dir, err := ioutil.TempDir("", "aur-out-of-date")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(path.Join(dir, "foo.service"), []byte("bar"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	input := "pkgver=1.1\n" +
+		"source=(\"https://example.com/foo-$pkgver.tar.gz\" foo.service git+https://example.com/foo.git)\n" +
+		"sha256sums=('0000000000000000000000000000000000000000000000000000000000000000'\n" +
+		"            '0000000000000000000000000000000000000000000000000000000000000000'\n" +
+		"            'SKIP')\n" +
+		"md5sums=(\"0\" \"0\" \"SKIP\")\n"
+	sources := map[string][]string{"": {"https://example.com/foo-1.1.tar.gz", "foo.service", "git+https://example.com/foo.git"}}
+	output, diff, err := updateChecksums(input, sources, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "pkgver=1.1\n" +
+		"source=(\"https://example.com/foo-$pkgver.tar.gz\" foo.service git+https://example.com/foo.git)\n" +
+		"sha256sums=('2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae'\n" +
+		"            'fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9'\n" +
+		"            'SKIP')\n" +
+		"md5sums=('acbd18db4cc2f85cedef654fccc4a4d8'\n" +
+		"         '37b51d194a7513e45b56f6524f2d51f2'\n" +
+		"         'SKIP')\n"
+	if output != expected {
+		t.Errorf("Expecting %s, but got %s", expected, output)
+	}
+	if diff == "" {
+		t.Error("Expecting a diff")
+	}
+}
+
+func TestUpdateChecksumsMismatch(t *testing.T) {
+	if _, _, err := updateChecksums("sha256sums=('SKIP')\n", map[string][]string{"": {"a", "b"}}, ""); err == nil {
+		t.Error("Expecting an error for mismatching number of checksums")
+	}
+}
+
+func TestFormatArray(t *testing.T) {
+	if actual := formatArray("b2sums", []string{"abc"}); actual != "b2sums=('abc')" {
+		t.Errorf("Unexpected %s", actual)
+	}
+}
+
+func TestUpdateChecksumsArchitecture(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://example.com").
+		Get("/foo-x86_64.bin").
+		Reply(200).
+		BodyString("foo")
+
+	input := "sha256sums=('SKIP')\nsha256sums_x86_64=('0')\n"
+	sources := map[string][]string{"": {"git+https://example.com/foo.git"}, "x86_64": {"https://example.com/foo-x86_64.bin"}}
+	output, _, err := updateChecksums(input, sources, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "sha256sums=('SKIP')\nsha256sums_x86_64=('2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae')\n"; output != expected {
+		t.Errorf("Expecting %s, but got %s", expected, output)
+	}
+}
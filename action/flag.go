@@ -1,27 +1,44 @@
+// Package action flags, updates and publishes out-of-date packages
 package action
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 
+	"github.com/simon04/aur-out-of-date/aurweb"
+	"github.com/simon04/aur-out-of-date/logging"
 	"github.com/simon04/aur-out-of-date/pkg"
 	"github.com/simon04/aur-out-of-date/upstream"
 )
 
+// AURWeb flags packages via the AUR web interface reusing its session, if set, otherwise via SSH
+var AURWeb *aurweb.Client
+
 // FlagOnAur flags the package out-of-date after prompting the user
 func FlagOnAur(pkg pkg.Pkg, upstreamVersion upstream.Version) {
-	fmt.Printf("Should the package %s be flagged out-of-date? [y/N] ", pkg.Name())
+	comment := fmt.Sprintf("Version %s is out. #simon04/aur-out-of-date", upstreamVersion)
+	if DryRun {
+		fmt.Fprintf(os.Stderr, "Would flag package %s out-of-date: %s\n", pkg.Name(), comment)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Should the package %s be flagged out-of-date? [y/N] ", pkg.Name())
 	if !promptYesNo() {
 		return
 	}
-	fmt.Printf("Flagging package %s out-of-date ...\n", pkg.Name())
-	comment := fmt.Sprintf("Version %s is out. #simon04/aur-out-of-date", upstreamVersion)
+	fmt.Fprintf(os.Stderr, "Flagging package %s out-of-date ...\n", pkg.Name())
+	if AURWeb != nil {
+		if err := AURWeb.Flag(pkg.Name(), comment); err != nil {
+			logging.Errorf("Failed to flag out-of-date: %v", err)
+		}
+		return
+	}
 	cmd := exec.Command("ssh", "aur@aur.archlinux.org", "flag", pkg.Name(), "\""+comment+"\"")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		fmt.Printf("Failed to flag out-of-date (running \"%v\"): %v\n%s\n", strings.Join(cmd.Args, "\" \""), err, output)
+		logging.Errorf("Failed to flag out-of-date (running \"%v\"): %v\n%s", strings.Join(cmd.Args, "\" \""), err, output)
 	} else {
-		fmt.Printf("%s", output)
+		fmt.Fprintf(os.Stderr, "%s", output)
 	}
 }
@@ -0,0 +1,66 @@
+package action
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/simon04/aur-out-of-date/logging"
+	"github.com/simon04/aur-out-of-date/pkg"
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+// MergeRequester opens merge requests (pull requests) in a repository
+type MergeRequester interface {
+	Name() string
+	CreateMergeRequest(branch, base, title, body string) error
+}
+
+// branchName returns the branch used for the merge request of a version bump
+func branchName(pkgname string, version upstream.Version) string {
+	return fmt.Sprintf("aur-out-of-date/%s-%s", pkgname, version)
+}
+
+// OpenMergeRequest commits the updated PKGBUILD/.SRCINFO to a new branch, pushes it, and opens a merge request
+func OpenMergeRequest(pkg pkg.Pkg, upstreamVersion upstream.Version, requesters ...MergeRequester) {
+	file := pkg.LocalPKGBUILD()
+	if file == "" {
+		return
+	}
+	dir := path.Dir(file)
+	output, err := exec.Command("git", "-C", dir, "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		logging.Errorf("Failed to determine the current branch of %s: %v", dir, err)
+		return
+	}
+	base := strings.TrimSpace(string(output))
+	branch := branchName(pkg.Name(), upstreamVersion)
+	title := commitMessage(pkg.Name(), upstreamVersion)
+	if !runCommands("create branch "+branch, dir, append(testBuildCommands(), []string{"git", "checkout", "-b", branch})) {
+		return
+	}
+	// restore the base branch even if committing or pushing fails
+	defer runCommands("restore branch "+base, dir, [][]string{{"git", "checkout", base}})
+	commands := [][]string{
+		{"git", "add", "PKGBUILD", ".SRCINFO"},
+		{"git", "commit", "-m", title},
+		{"git", "push", "origin", branch},
+	}
+	if !runCommands("push branch "+branch, dir, commands) {
+		return
+	}
+	body := fmt.Sprintf("Update %s to %s.\n\nOpened by aur-out-of-date.", pkg.Name(), upstreamVersion)
+	for _, r := range requesters {
+		if DryRun {
+			fmt.Fprintf(os.Stderr, "Would open %s merge request %q (%s → %s)\n", r.Name(), title, branch, base)
+			continue
+		}
+		if err := r.CreateMergeRequest(branch, base, title, body); err != nil {
+			logging.Errorf("Failed to open %s merge request for %s: %v", r.Name(), pkg.Name(), err)
+		} else {
+			fmt.Fprintf(os.Stderr, "Opened %s merge request %q\n", r.Name(), title)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package action
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/pkg"
+)
+
+func TestOpenMergeRequestRestoresBranch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aur-out-of-date")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, env := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME", "GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		defer os.Setenv(env, os.Getenv(env))
+		os.Setenv(env, "foo@example.com")
+	}
+	srcinfo := "pkgbase = foo\n\tpkgver = 1.0\n\tpkgrel = 1\n\tarch = any\n\npkgname = foo\n"
+	for _, file := range []string{"PKGBUILD", ".SRCINFO"} {
+		if err := ioutil.WriteFile(path.Join(dir, file), []byte(srcinfo), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git := func(args ...string) string {
+		output, err := exec.Command("git", append([]string{"-C", dir}, args...)...).Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	git("init", "-q")
+	git("checkout", "-q", "-b", "main")
+	git("add", "PKGBUILD", ".SRCINFO")
+	git("commit", "-q", "-m", "initial")
+	if err := ioutil.WriteFile(path.Join(dir, "PKGBUILD"), []byte("pkgver=1.1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	packages, err := pkg.NewLocalPkgs([]string{path.Join(dir, ".SRCINFO")}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// pushing fails without origin
+	OpenMergeRequest(packages[0], "1.1")
+	if branch := git("rev-parse", "--abbrev-ref", "HEAD"); branch != "main" {
+		t.Errorf("Expecting the base branch main to be restored, but got %s", branch)
+	}
+}
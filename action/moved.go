@@ -0,0 +1,68 @@
+package action
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/simon04/aur-out-of-date/logging"
+	"github.com/simon04/aur-out-of-date/pkg"
+)
+
+// RewriteMoved replaces the old repository location in the local PKGBUILD by the new one after prompting the user,
+// reporting whether it has been rewritten
+func RewriteMoved(pkg pkg.Pkg, from, to string) bool {
+	file := pkg.LocalPKGBUILD()
+	if file == "" {
+		return false
+	}
+	input, err := ioutil.ReadFile(file)
+	if err != nil {
+		logging.Errorf("rewriteMoved: failed to read file %s: %v", file, err)
+		return false
+	}
+	output, diff := rewriteLocation(string(input), from, to)
+	if diff == "" {
+		logging.Warnf("Upstream of %s moved to %s, but %s does not contain %s literally", pkg.Name(), to, file, from)
+		return false
+	}
+	fmt.Fprintf(os.Stderr, "--- a/%s\n", file)
+	fmt.Fprintf(os.Stderr, "+++ b/%s\n", file)
+	fmt.Fprint(os.Stderr, diff)
+	if DryRun {
+		fmt.Fprintf(os.Stderr, "Would rewrite %s to %s in package %s\n", from, to, pkg.Name())
+		return true
+	}
+	fmt.Fprintf(os.Stderr, "Should %s be rewritten to %s in package %s? [y/N] ", from, to, pkg.Name())
+	if !promptYesNo() {
+		return false
+	}
+	if err := ioutil.WriteFile(file, []byte(output), 0644); err != nil {
+		logging.Errorf("rewriteMoved: failed to write file %s: %v", file, err)
+		return false
+	}
+	if err := writeSRCINFO(path.Dir(file)); err != nil {
+		logging.Errorf("rewriteMoved: failed to regenerate .SRCINFO: %v", err)
+		return false
+	}
+	return true
+}
+
+// rewriteLocation replaces the repository location from (e.g. github.com/owner/name, optionally suffixed by .git) by to,
+// returning the new PKGBUILD and a diff of the changed lines
+func rewriteLocation(input, from, to string) (string, string) {
+	location := regexp.MustCompile(`(?i)` + regexp.QuoteMeta(from) + `(\.git)?([/"'#\s)]|$)`)
+	var diff strings.Builder
+	lines := strings.Split(input, "\n")
+	for i, line := range lines {
+		update := location.ReplaceAllString(line, to+"$1$2")
+		if update != line {
+			fmt.Fprintf(&diff, "-%s\n+%s\n", line, update)
+			lines[i] = update
+		}
+	}
+	return strings.Join(lines, "\n"), diff.String()
+}
@@ -0,0 +1,32 @@
+package action
+
+import (
+	"testing"
+)
+
+func TestRewriteLocation(t *testing.T) {
+	input := `pkgname=gogs
+url="https://github.com/gogits/gogs"
+source=("$pkgname-$pkgver.tar.gz::https://github.com/Gogits/gogs/archive/v$pkgver.tar.gz"
+        "git+https://github.com/gogits/gogs.git#tag=v$pkgver"
+        "https://github.com/gogits/gogs-docs/archive/master.tar.gz")`
+	expected := `pkgname=gogs
+url="https://github.com/gogs/gogs"
+source=("$pkgname-$pkgver.tar.gz::https://github.com/gogs/gogs/archive/v$pkgver.tar.gz"
+        "git+https://github.com/gogs/gogs.git#tag=v$pkgver"
+        "https://github.com/gogits/gogs-docs/archive/master.tar.gz")`
+	output, diff := rewriteLocation(input, "github.com/gogits/gogs", "github.com/gogs/gogs")
+	if output != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, output)
+	}
+	expectedDiff := `-url="https://github.com/gogits/gogs"
++url="https://github.com/gogs/gogs"
+-source=("$pkgname-$pkgver.tar.gz::https://github.com/Gogits/gogs/archive/v$pkgver.tar.gz"
++source=("$pkgname-$pkgver.tar.gz::https://github.com/gogs/gogs/archive/v$pkgver.tar.gz"
+-        "git+https://github.com/gogits/gogs.git#tag=v$pkgver"
++        "git+https://github.com/gogs/gogs.git#tag=v$pkgver"
+`
+	if diff != expectedDiff {
+		t.Errorf("Expecting '%s', but got '%s'", expectedDiff, diff)
+	}
+}
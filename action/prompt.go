@@ -1,8 +1,18 @@
 package action
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+)
+
+// AssumeYes answers all prompts with yes
+var AssumeYes bool
 
 func promptYesNo() bool {
+	if AssumeYes {
+		fmt.Fprintln(os.Stderr, "y")
+		return true
+	}
 	var response string
 	chars, err := fmt.Scanln(&response)
 	if err != nil || chars == 0 {
@@ -0,0 +1,66 @@
+package action
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/simon04/aur-out-of-date/logging"
+	"github.com/simon04/aur-out-of-date/pkg"
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+// DryRun prints the actions (flagging, updating, publishing, merge requests) instead of performing them
+var DryRun bool
+
+// TestBuild is a shell command (such as "makepkg --nobuild" or "extra-x86_64-build") which has to succeed before publishing
+var TestBuild string
+
+// commitMessage returns the standardized commit message for a version bump
+func commitMessage(pkgname string, version upstream.Version) string {
+	return fmt.Sprintf("upgpkg: %s %s-1", pkgname, version)
+}
+
+func testBuildCommands() [][]string {
+	if TestBuild == "" {
+		return nil
+	}
+	return [][]string{{"/bin/sh", "-c", TestBuild}}
+}
+
+// PublishPKGBUILD commits the updated PKGBUILD/.SRCINFO and pushes it to the AUR
+func PublishPKGBUILD(pkg pkg.Pkg, upstreamVersion upstream.Version) {
+	file := pkg.LocalPKGBUILD()
+	if file == "" {
+		return
+	}
+	dir := path.Dir(file)
+	commands := append(testBuildCommands(), [][]string{
+		{"git", "add", "PKGBUILD", ".SRCINFO"},
+		{"git", "commit", "-m", commitMessage(pkg.Name(), upstreamVersion)},
+		{"git", "push", fmt.Sprintf("ssh://aur@aur.archlinux.org/%s.git", pkg.Base()), "HEAD:master"},
+	}...)
+	if runCommands("publish "+pkg.Name(), dir, commands) && !DryRun {
+		fmt.Fprintf(os.Stderr, "Published %s %s to AUR\n", pkg.Name(), upstreamVersion)
+	}
+}
+
+// runCommands runs the commands in dir, or prints them for DryRun, reporting whether all succeeded
+func runCommands(what string, dir string, commands [][]string) bool {
+	for _, args := range commands {
+		if DryRun {
+			fmt.Fprintf(os.Stderr, "(cd %s && %s)\n", dir, strings.Join(args, " "))
+			continue
+		}
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			logging.Errorf("Failed to %s (running \"%v\"): %v\n%s", what, strings.Join(cmd.Args, "\" \""), err, output)
+			return false
+		}
+	}
+	return true
+}
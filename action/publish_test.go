@@ -0,0 +1,17 @@
+package action
+
+import (
+	"testing"
+)
+
+func TestCommitMessage(t *testing.T) {
+	if actual := commitMessage("foo", "2.4.1"); actual != "upgpkg: foo 2.4.1-1" {
+		t.Errorf("Unexpected commit message %s", actual)
+	}
+}
+
+func TestBranchName(t *testing.T) {
+	if actual := branchName("foo", "2.4.1"); actual != "aur-out-of-date/foo-2.4.1" {
+		t.Errorf("Unexpected branch name %s", actual)
+	}
+}
@@ -1,45 +1,113 @@
 package action
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
 	"strings"
 
+	"github.com/simon04/aur-out-of-date/logging"
 	"github.com/simon04/aur-out-of-date/pkg"
 	"github.com/simon04/aur-out-of-date/upstream"
 )
 
-// UpdatePKGBUILD updates pkgver/pkgrel in local PKGBUILD files after prompting the user
-func UpdatePKGBUILD(pkg pkg.Pkg, upstreamVersion upstream.Version) {
+// UpdatePKGBUILD updates pkgver/pkgrel in local PKGBUILD files after prompting the user, reporting whether it has been updated
+func UpdatePKGBUILD(pkg pkg.Pkg, upstreamVersion upstream.Version) bool {
 	file := pkg.LocalPKGBUILD()
 	if file == "" {
-		return
+		return false
 	}
 	input, err := ioutil.ReadFile(file)
 	if err != nil {
-		fmt.Printf("updatePKGBUILD: failed to read file %s: %v\n", file, err)
+		logging.Errorf("updatePKGBUILD: failed to read file %s: %v", file, err)
+		return false
 	}
 
-	lines := strings.Split(string(input), "\n")
+	output, diff, err := bumpPKGBUILD(string(input), upstreamVersion.String())
+	if err != nil {
+		logging.Errorf("updatePKGBUILD: failed to update %s: %v", file, err)
+		return false
+	}
+	sources, err := bumpSources(pkg, upstreamVersion.String())
+	if err != nil {
+		logging.Errorf("updatePKGBUILD: failed to obtain sources of %s: %v", pkg.Name(), err)
+		return false
+	}
+	output, checksumsDiff, err := updateChecksums(output, sources, path.Dir(file))
+	if err != nil {
+		logging.Errorf("updatePKGBUILD: failed to update checksums of %s: %v", file, err)
+		return false
+	}
+	diff += checksumsDiff
+	fmt.Fprintf(os.Stderr, "--- a/%s\n", file)
+	fmt.Fprintf(os.Stderr, "+++ b/%s\n", file)
+	fmt.Fprint(os.Stderr, diff)
+	if DryRun {
+		fmt.Fprintf(os.Stderr, "Would update package %s to version %s\n", pkg.Name(), upstreamVersion)
+		return true
+	}
+	fmt.Fprintf(os.Stderr, "Should the package %s be updated to version %s? [y/N] ", pkg.Name(), upstreamVersion)
+	if !promptYesNo() {
+		return false
+	}
+	err = ioutil.WriteFile(file, []byte(output), 0644)
+	if err != nil {
+		logging.Errorf("updatePKGBUILD: failed to write file %s: %v", file, err)
+		return false
+	}
+	if err := writeSRCINFO(path.Dir(file)); err != nil {
+		logging.Errorf("updatePKGBUILD: failed to regenerate .SRCINFO: %v", err)
+		return false
+	}
+	return true
+}
+
+// writeSRCINFO regenerates .SRCINFO in dir using makepkg --printsrcinfo
+func writeSRCINFO(dir string) error {
+	cmd := exec.Command("makepkg", "--printsrcinfo")
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("Failed to run makepkg --printsrcinfo in %s: %w\n%s", dir, err, stderr.String())
+	}
+	return ioutil.WriteFile(path.Join(dir, ".SRCINFO"), output, 0644)
+}
 
+// bumpSources returns the sources of the PKGBUILD by architecture for the new version
+func bumpSources(p pkg.Pkg, version string) (map[string][]string, error) {
+	return pkg.SourcesForPkgver(p, version)
+}
+
+// bumpPKGBUILD sets pkgver to version and resets pkgrel to 1, returning the new PKGBUILD and a diff of the changed lines
+func bumpPKGBUILD(input string, version string) (string, string, error) {
+	if strings.ContainsAny(version, "-/: ") {
+		return "", "", fmt.Errorf("Version %s is not a valid pkgver", version)
+	}
+	var diff strings.Builder
+	found := false
+	lines := strings.Split(input, "\n")
 	for i, line := range lines {
+		var update string
 		if strings.HasPrefix(line, "pkgver=") {
-			lineUpdate := strings.Replace(line, string(pkg.Version().Version), upstreamVersion.String(), 1)
-			fmt.Printf("--- a/%s\n", file)
-			fmt.Printf("+++ b/%s\n", file)
-			fmt.Printf("-%s\n", line)
-			fmt.Printf("+%s\n", lineUpdate)
-			fmt.Printf("Should the package %s be updated to version %s? [y/N] ", pkg.Name(), upstreamVersion)
-			if !promptYesNo() {
-				return
-			}
-			lines[i] = lineUpdate
+			update = "pkgver=" + version
+			found = true
 		} else if strings.HasPrefix(line, "pkgrel=") {
-			lines[i] = "pkgrel=1"
+			update = "pkgrel=1"
+		} else {
+			continue
+		}
+		if update != line {
+			fmt.Fprintf(&diff, "-%s\n+%s\n", line, update)
+			lines[i] = update
 		}
 	}
-	err = ioutil.WriteFile(file, []byte(strings.Join(lines, "\n")), 0644)
-	if err != nil {
-		fmt.Printf("updatePKGBUILD: failed to write file %s: %v\n", file, err)
+	if !found {
+		return "", "", fmt.Errorf("No pkgver found")
 	}
+	return strings.Join(lines, "\n"), diff.String(), nil
 }
@@ -0,0 +1,28 @@
+package action
+
+import (
+	"testing"
+)
+
+func TestBumpPKGBUILD(t *testing.T) {
+	input := "pkgname=foo\npkgver=1.0\npkgrel=3\nsource=(\"https://example.com/foo-$pkgver.tar.gz\")\n"
+	output, diff, err := bumpPKGBUILD(input, "1.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "pkgname=foo\npkgver=1.1\npkgrel=1\nsource=(\"https://example.com/foo-$pkgver.tar.gz\")\n"; output != expected {
+		t.Errorf("Expecting %q, but got %q", expected, output)
+	}
+	if expected := "-pkgver=1.0\n+pkgver=1.1\n-pkgrel=3\n+pkgrel=1\n"; diff != expected {
+		t.Errorf("Expecting %q, but got %q", expected, diff)
+	}
+}
+
+func TestBumpPKGBUILDInvalid(t *testing.T) {
+	if _, _, err := bumpPKGBUILD("pkgver=1.0\n", "1.1-rc1"); err == nil {
+		t.Error("Expecting an error for invalid pkgver")
+	}
+	if _, _, err := bumpPKGBUILD("pkgname=foo\n", "1.1"); err == nil {
+		t.Error("Expecting an error for missing pkgver")
+	}
+}
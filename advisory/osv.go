@@ -0,0 +1,88 @@
+// Package advisory looks up security advisories affecting package versions using the OSV API (https://osv.dev)
+package advisory
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// apiURL is the endpoint of the OSV query API
+var apiURL = "https://api.osv.dev/v1/query"
+
+// Vulnerability is an OSV vulnerability entry
+type Vulnerability struct {
+	ID      string   `json:"id"`
+	Aliases []string `json:"aliases"`
+	Summary string   `json:"summary"`
+}
+
+// Name returns the CVE identifier of the vulnerability if known, its OSV identifier otherwise
+func (v Vulnerability) Name() string {
+	for _, alias := range v.Aliases {
+		if strings.HasPrefix(alias, "CVE-") {
+			return alias
+		}
+	}
+	return v.ID
+}
+
+type query struct {
+	Version string       `json:"version"`
+	Package queryPackage `json:"package"`
+}
+
+type queryPackage struct {
+	Name      string `json:"name"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+type response struct {
+	Vulns []Vulnerability `json:"vulns"`
+}
+
+// Query returns the vulnerabilities affecting the package in version
+func Query(ecosystem, name, version string) ([]Vulnerability, error) {
+	body, err := json.Marshal(query{version, queryPackage{name, ecosystem}})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Post(apiURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query OSV for %s %s: %w", name, version, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Failed to query OSV for %s %s: %s", name, version, resp.Status)
+	}
+	var r response
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("Failed to parse OSV response for %s %s: %w", name, version, err)
+	}
+	return r.Vulns, nil
+}
+
+// Fixed returns the vulnerabilities affecting the package in version current, but not in version latest
+func Fixed(ecosystem, name, current, latest string) ([]Vulnerability, error) {
+	affected, err := Query(ecosystem, name, current)
+	if err != nil || len(affected) == 0 {
+		return nil, err
+	}
+	remaining, err := Query(ecosystem, name, latest)
+	if err != nil {
+		return nil, err
+	}
+	unfixed := map[string]bool{}
+	for _, v := range remaining {
+		unfixed[v.ID] = true
+	}
+	var fixed []Vulnerability
+	for _, v := range affected {
+		if !unfixed[v.ID] {
+			fixed = append(fixed, v)
+		}
+	}
+	return fixed, nil
+}
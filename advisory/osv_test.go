@@ -0,0 +1,43 @@
+package advisory
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+)
+
+func TestFixed(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.osv.dev/").
+		Post("/v1/query").
+		MatchType("json").
+		JSON(map[string]interface{}{"version": "2.0.0", "package": map[string]string{"name": "jinja2", "ecosystem": "PyPI"}}).
+		Reply(http.StatusOK).
+		JSON(map[string]interface{}{"vulns": []map[string]interface{}{
+			{"id": "GHSA-g3rq-g295-4j3m", "aliases": []string{"CVE-2020-28493"}},
+			{"id": "GHSA-h5c8-rqwp-cp95", "aliases": []string{"CVE-2024-22195"}},
+		}})
+	gock.New("https://api.osv.dev/").
+		Post("/v1/query").
+		MatchType("json").
+		JSON(map[string]interface{}{"version": "2.11.3", "package": map[string]string{"name": "jinja2", "ecosystem": "PyPI"}}).
+		Reply(http.StatusOK).
+		JSON(map[string]interface{}{"vulns": []map[string]interface{}{
+			{"id": "GHSA-h5c8-rqwp-cp95", "aliases": []string{"CVE-2024-22195"}},
+		}})
+
+	fixed, err := Fixed("PyPI", "jinja2", "2.0.0", "2.11.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixed) != 1 || fixed[0].Name() != "CVE-2020-28493" {
+		t.Errorf("Expecting CVE-2020-28493 to be fixed, but got %v", fixed)
+	}
+}
+
+func TestName(t *testing.T) {
+	if name := (Vulnerability{ID: "PYSEC-2021-66"}).Name(); name != "PYSEC-2021-66" {
+		t.Errorf("Expecting PYSEC-2021-66, but got %s", name)
+	}
+}
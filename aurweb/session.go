@@ -0,0 +1,214 @@
+// Package aurweb performs authenticated actions on the AUR web interface (aurweb), reusing a persisted session
+package aurweb
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// baseURL is the AUR web interface
+var baseURL = "https://aur.archlinux.org"
+
+// execCommand is replaced in tests
+var execCommand = exec.Command
+
+// sessionCookie is the name of the aurweb session cookie
+const sessionCookie = "AURSID"
+
+// Config holds the AUR account used for authenticated actions
+type Config struct {
+	Username string `json:"username"`
+	// PasswordCommand prints the password, e.g. "pass show aur", otherwise the password is prompted for
+	PasswordCommand string `json:"password_command"`
+	// TOTP enables two-factor authentication, the code is read from the output of TOTPCommand (e.g. "oathtool --totp -b …") or prompted for
+	TOTP        bool   `json:"totp"`
+	TOTPCommand string `json:"totp_command"`
+	// Keyring stores the session using secret-tool (libsecret) instead of a file
+	Keyring bool `json:"keyring"`
+}
+
+// session is the persisted aurweb session cookie
+type session struct {
+	Username string    `json:"username"`
+	Cookie   string    `json:"cookie"`
+	Expires  time.Time `json:"expires,omitempty"`
+}
+
+// Client performs actions using the session of the configured account, logging in only if no valid session is stored
+type Client struct {
+	config Config
+	file   string
+	mutex  sync.Mutex
+	http   *http.Client
+}
+
+// NewClient returns a Client persisting the session to file (readable by the user only), or to the keyring if configured
+func NewClient(config Config, file string) *Client {
+	return &Client{config: config, file: file, http: &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error { return http.ErrUseLastResponse },
+	}}
+}
+
+// Flag flags the package base out-of-date with the comment
+func (c *Client) Flag(pkgbase, comment string) error {
+	return c.post("/pkgbase/"+url.PathEscape(pkgbase)+"/flag", url.Values{"comments": {comment}})
+}
+
+// post submits the form using the stored session, logging in again once if the session has expired
+func (c *Client) post(page string, form url.Values) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	s, err := c.load()
+	if err != nil {
+		return err
+	}
+	for attempt := 0; ; attempt++ {
+		if s == nil {
+			if s, err = c.login(); err != nil {
+				return err
+			}
+		}
+		req, err := http.NewRequest("POST", baseURL+page, strings.NewReader(form.Encode()))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.AddCookie(&http.Cookie{Name: sessionCookie, Value: s.Cookie})
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return fmt.Errorf("Failed to submit %s: %w", page, err)
+		}
+		resp.Body.Close()
+		expired := resp.StatusCode == http.StatusUnauthorized || strings.HasPrefix(resp.Header.Get("Location"), "/login")
+		if expired && attempt == 0 {
+			s = nil
+			continue
+		} else if expired || resp.StatusCode >= 400 {
+			return fmt.Errorf("Failed to submit %s: %s", page, resp.Status)
+		}
+		return nil
+	}
+}
+
+// login logs in using the password of the account and stores the new session
+func (c *Client) login() (*session, error) {
+	if c.config.Username == "" {
+		return nil, fmt.Errorf("Failed to log in to AUR: no username configured")
+	}
+	password, err := c.secret("password", c.config.PasswordCommand)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{"user": {c.config.Username}, "passwd": {password}, "remember_me": {"on"}, "next": {"/"}}
+	if c.config.TOTP || c.config.TOTPCommand != "" {
+		code, err := c.secret("TOTP code", c.config.TOTPCommand)
+		if err != nil {
+			return nil, err
+		}
+		form.Set("otp", strings.TrimSpace(code))
+	}
+	resp, err := c.http.PostForm(baseURL+"/login", form)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to log in to AUR: %w", err)
+	}
+	resp.Body.Close()
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == sessionCookie && cookie.Value != "" {
+			s := &session{Username: c.config.Username, Cookie: cookie.Value}
+			if cookie.MaxAge > 0 {
+				s.Expires = time.Now().Add(time.Duration(cookie.MaxAge) * time.Second)
+			} else if !cookie.Expires.IsZero() {
+				s.Expires = cookie.Expires
+			}
+			return s, c.save(s)
+		}
+	}
+	return nil, fmt.Errorf("Failed to log in to AUR as %s: %s", c.config.Username, resp.Status)
+}
+
+// secret runs the command printing the secret (password or TOTP code), or prompts for it on the terminal
+func (c *Client) secret(name, command string) (string, error) {
+	if command != "" {
+		output, err := execCommand("sh", "-c", command).Output()
+		if err != nil {
+			return "", fmt.Errorf("Failed to run %s command: %w", name, err)
+		}
+		return strings.TrimRight(string(output), "\r\n"), nil
+	}
+	fmt.Fprintf(os.Stderr, "AUR %s for %s: ", name, c.config.Username)
+	echo := func(on string) {
+		cmd := execCommand("stty", on)
+		cmd.Stdin = os.Stdin
+		cmd.Run()
+	}
+	echo("-echo")
+	defer echo("echo")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("Failed to read %s: %w", name, err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// load returns the stored session of the account, nil if none or expired
+func (c *Client) load() (*session, error) {
+	var content []byte
+	var err error
+	if c.config.Keyring {
+		content, err = execCommand("secret-tool", "lookup", "service", "aur-out-of-date", "username", c.config.Username).Output()
+		if err != nil {
+			// secret-tool fails if no secret is stored
+			return nil, nil
+		}
+	} else {
+		content, err = ioutil.ReadFile(c.file)
+		if os.IsNotExist(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("Failed to read AUR session: %w", err)
+		}
+	}
+	var s session
+	if err := json.Unmarshal(content, &s); err != nil || s.Username != c.config.Username || s.Cookie == "" {
+		return nil, nil
+	}
+	if !s.Expires.IsZero() && time.Now().After(s.Expires) {
+		return nil, nil
+	}
+	return &s, nil
+}
+
+// save stores the session in the keyring or in the file, readable by the user only
+func (c *Client) save(s *session) error {
+	content, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if c.config.Keyring {
+		cmd := execCommand("secret-tool", "store", "--label=aur-out-of-date AUR session", "service", "aur-out-of-date", "username", c.config.Username)
+		cmd.Stdin = strings.NewReader(string(content))
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("Failed to store AUR session in keyring: %w: %s", err, output)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(path.Dir(c.file), 0700); err != nil {
+		return fmt.Errorf("Failed to store AUR session: %w", err)
+	}
+	tmp := c.file + ".tmp"
+	if err := ioutil.WriteFile(tmp, content, 0600); err != nil {
+		return fmt.Errorf("Failed to store AUR session: %w", err)
+	}
+	return os.Rename(tmp, c.file)
+}
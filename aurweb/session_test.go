@@ -0,0 +1,128 @@
+package aurweb
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path"
+	"testing"
+)
+
+func TestFlagReusesSession(t *testing.T) {
+	logins, flags := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch r.URL.Path {
+		case "/login":
+			logins++
+			if r.Form.Get("user") != "alice" || r.Form.Get("passwd") != "secret" {
+				t.Errorf("Unexpected credentials %v", r.Form)
+			}
+			http.SetCookie(w, &http.Cookie{Name: "AURSID", Value: "session1", MaxAge: 3600})
+			http.Redirect(w, r, "/", http.StatusSeeOther)
+		case "/pkgbase/foo/flag":
+			if cookie, err := r.Cookie("AURSID"); err != nil || cookie.Value != "session1" {
+				http.Redirect(w, r, "/login?next=/pkgbase/foo/flag", http.StatusSeeOther)
+				return
+			}
+			flags++
+			if r.Form.Get("comments") != "Version 2 is out." {
+				t.Errorf("Unexpected comment %q", r.Form.Get("comments"))
+			}
+			http.Redirect(w, r, "/pkgbase/foo", http.StatusSeeOther)
+		}
+	}))
+	defer server.Close()
+	baseURL = server.URL
+	defer func() { baseURL = "https://aur.archlinux.org" }()
+
+	dir, err := ioutil.TempDir("", "aurweb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := path.Join(dir, "aurweb-session.json")
+	config := Config{Username: "alice", PasswordCommand: "echo secret"}
+	for i := 0; i < 2; i++ {
+		if err := NewClient(config, file).Flag("foo", "Version 2 is out."); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if logins != 1 || flags != 2 {
+		t.Errorf("Expecting 1 login and 2 flags, but got %d and %d", logins, flags)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	} else if info.Mode().Perm() != 0600 {
+		t.Errorf("Expecting session file mode 0600, but got %v", info.Mode().Perm())
+	}
+
+	// an expired session is renewed once
+	if err := ioutil.WriteFile(file, []byte(`{"username":"alice","cookie":"session0"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewClient(config, file).Flag("foo", "Version 2 is out."); err != nil {
+		t.Fatal(err)
+	}
+	if logins != 2 || flags != 3 {
+		t.Errorf("Expecting 2 logins and 3 flags, but got %d and %d", logins, flags)
+	}
+}
+
+func TestKeyring(t *testing.T) {
+	var commands [][]string
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		commands = append(commands, append([]string{name}, arg...))
+		if name == "secret-tool" && arg[0] == "lookup" {
+			return exec.Command("echo", `{"username":"alice","cookie":"session1"}`)
+		}
+		return exec.Command("true")
+	}
+	defer func() { execCommand = exec.Command }()
+
+	c := NewClient(Config{Username: "alice", Keyring: true}, "")
+	s, err := c.load()
+	if err != nil {
+		t.Fatal(err)
+	} else if s == nil || s.Cookie != "session1" {
+		t.Errorf("Expecting session1, but got %v", s)
+	}
+	if err := c.save(&session{Username: "alice", Cookie: "session2"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(commands) != 2 || commands[1][1] != "store" {
+		t.Errorf("Expecting secret-tool lookup and store, but got %q", commands)
+	}
+}
+
+func TestLoginTOTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.URL.Path == "/login" && r.Form.Get("passwd") == "secret" && r.Form.Get("otp") == "123456" {
+			http.SetCookie(w, &http.Cookie{Name: "AURSID", Value: "session1"})
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	baseURL = server.URL
+	defer func() { baseURL = "https://aur.archlinux.org" }()
+
+	dir, err := ioutil.TempDir("", "aurweb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := path.Join(dir, "aurweb-session.json")
+	if _, err := NewClient(Config{Username: "alice", PasswordCommand: "echo secret"}, file).login(); err == nil {
+		t.Error("Expecting login without TOTP code to fail")
+	}
+	s, err := NewClient(Config{Username: "alice", PasswordCommand: "echo secret", TOTPCommand: "echo 123456"}, file).login()
+	if err != nil {
+		t.Fatal(err)
+	} else if s.Cookie != "session1" {
+		t.Errorf("Expecting session1, but got %v", s)
+	}
+}
@@ -0,0 +1,84 @@
+// Package badge renders shields.io-style SVG status badges
+package badge
+
+import (
+	"fmt"
+	"html"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/simon04/aur-out-of-date/logging"
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+// Colors used for the badge message
+const (
+	Green = "#4c1"
+	Red   = "#e05d44"
+	Grey  = "#9f9f9f"
+)
+
+// textWidth approximates the rendered width of s in pixels (Verdana 11px)
+func textWidth(s string) int {
+	return 7*len([]rune(s)) + 10
+}
+
+// SVG renders a flat badge with the given label, message and message color
+func SVG(label, message, color string) string {
+	lw := textWidth(label)
+	mw := textWidth(message)
+	w := lw + mw
+	label = html.EscapeString(label)
+	message = html.EscapeString(message)
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`+
+		`<title>%s: %s</title>`+
+		`<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`+
+		`<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`+
+		`<g clip-path="url(#r)"><rect width="%d" height="20" fill="#555"/><rect x="%d" width="%d" height="20" fill="%s"/><rect width="%d" height="20" fill="url(#s)"/></g>`+
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`+
+		`<text x="%d" y="14">%s</text><text x="%d" y="14">%s</text></g></svg>`+"\n",
+		w, label, message,
+		label, message,
+		w,
+		lw, lw, mw, color, w,
+		lw/2, label, lw+mw/2, message)
+}
+
+// ForStatus renders the badge for the given package status
+func ForStatus(s *status.Status) string {
+	switch s.Status {
+	case status.UpToDate:
+		return SVG("upstream", "up to date", Green)
+	case status.OutOfDate, status.FlaggedOutOfDate:
+		return SVG("upstream", "out of date: "+s.Upstream.String(), Red)
+	default:
+		return SVG("upstream", "unknown", Grey)
+	}
+}
+
+// Writer writes a badge <package>.svg per package to a directory
+type Writer struct {
+	dir string
+}
+
+// NewWriter returns a Writer for the given directory
+func NewWriter(dir string) *Writer {
+	return &Writer{dir}
+}
+
+// Status implements status.Formatter
+func (w *Writer) Status(s *status.Status) {
+	if err := os.MkdirAll(w.dir, 0755); err != nil {
+		logging.Errorf("Failed to create badge directory %s: %v", w.dir, err)
+		return
+	}
+	filename := path.Join(w.dir, path.Base(s.Package)+".svg")
+	if err := ioutil.WriteFile(filename, []byte(ForStatus(s)), 0644); err != nil {
+		logging.Errorf("Failed to write badge %s: %v", filename, err)
+	}
+}
+
+// Finish implements status.Formatter
+func (w *Writer) Finish(statistics *status.Statistics) {
+}
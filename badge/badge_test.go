@@ -0,0 +1,40 @@
+package badge
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestSVG(t *testing.T) {
+	svg := SVG("upstream", "out of date: <2.4.1>", Red)
+	if err := xml.Unmarshal([]byte(svg), new(struct{})); err != nil {
+		t.Errorf("Expecting valid XML, but got %v", err)
+	}
+	if !strings.Contains(svg, "<title>upstream: out of date: &lt;2.4.1&gt;</title>") || !strings.Contains(svg, `fill="#e05d44"`) {
+		t.Errorf("Unexpected SVG '%s'", svg)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aur-out-of-date")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	w := NewWriter(path.Join(dir, "badges"))
+	w.Status(&status.Status{Package: "foo", Upstream: "v2.4.1", Status: status.OutOfDate})
+	svg, err := ioutil.ReadFile(path.Join(dir, "badges", "foo.svg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(svg), "out of date: 2.4.1") {
+		t.Errorf("Unexpected SVG '%s'", svg)
+	}
+}
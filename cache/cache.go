@@ -0,0 +1,59 @@
+// Package cache provides the storage backends for cached HTTP responses and upstream versions
+package cache
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/gregjones/httpcache/diskcache"
+)
+
+// Cache stores values by key, e.g. on disk or in Redis (compatible with httpcache.Cache)
+type Cache interface {
+	// Get returns the value for the key and whether it has been found
+	Get(key string) ([]byte, bool)
+	// Set stores the value for the key
+	Set(key string, value []byte)
+	// Delete removes the key
+	Delete(key string)
+}
+
+// Dir returns a Cache storing each value as file in the directory
+func Dir(dir string) Cache {
+	return diskcache.New(dir)
+}
+
+// Open returns the Cache for the given location, i.e. a Redis URL (redis://[:password@]host[:port][/db]) or a directory
+func Open(location string) (Cache, error) {
+	if strings.HasPrefix(location, "redis://") {
+		u, err := url.Parse(location)
+		if err != nil {
+			return nil, err
+		}
+		return Redis(u)
+	}
+	return Dir(location), nil
+}
+
+// prefixed namespaces the keys of a Cache
+type prefixed struct {
+	Cache
+	prefix string
+}
+
+// Prefix returns a Cache prepending prefix to all keys of c, so that several kinds of values can share a backend
+func Prefix(c Cache, prefix string) Cache {
+	return &prefixed{c, prefix}
+}
+
+func (p *prefixed) Get(key string) ([]byte, bool) {
+	return p.Cache.Get(p.prefix + key)
+}
+
+func (p *prefixed) Set(key string, value []byte) {
+	p.Cache.Set(p.prefix+key, value)
+}
+
+func (p *prefixed) Delete(key string) {
+	p.Cache.Delete(p.prefix + key)
+}
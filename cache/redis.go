@@ -0,0 +1,181 @@
+package cache
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/logging"
+)
+
+// redisTimeout limits connecting to Redis and each command
+const redisTimeout = 5 * time.Second
+
+// errNil is the reply of Redis for missing keys
+var errNil = errors.New("nil")
+
+// redisCache is a Cache storing values in Redis using a single connection speaking RESP
+type redisCache struct {
+	addr     string
+	password string
+	db       int
+	mutex    sync.Mutex
+	conn     net.Conn
+	reader   *bufio.Reader
+}
+
+// Redis returns a Cache storing values in the Redis server of the URL redis://[:password@]host[:port][/db]
+func Redis(u *url.URL) (Cache, error) {
+	c := &redisCache{addr: u.Host}
+	if u.Port() == "" {
+		c.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if password, ok := u.User.Password(); ok {
+		c.password = password
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		n, err := strconv.Atoi(db)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid Redis database %s: %w", db, err)
+		}
+		c.db = n
+	}
+	if _, err := c.do("PING"); err != nil {
+		return nil, fmt.Errorf("Failed to connect to Redis at %s: %w", c.addr, err)
+	}
+	return c, nil
+}
+
+func (c *redisCache) Get(key string) ([]byte, bool) {
+	reply, err := c.do("GET", key)
+	if err == errNil {
+		return nil, false
+	} else if err != nil {
+		logging.Warnf("Failed to get %s from Redis: %v", key, err)
+		return nil, false
+	}
+	value, ok := reply.([]byte)
+	return value, ok
+}
+
+func (c *redisCache) Set(key string, value []byte) {
+	if _, err := c.do("SET", key, string(value)); err != nil {
+		logging.Warnf("Failed to set %s in Redis: %v", key, err)
+	}
+}
+
+func (c *redisCache) Delete(key string) {
+	if _, err := c.do("DEL", key); err != nil {
+		logging.Warnf("Failed to delete %s from Redis: %v", key, err)
+	}
+}
+
+// do sends the command and reads its reply, (re)connecting as needed
+func (c *redisCache) do(args ...string) (interface{}, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.conn == nil {
+		if err := c.connect(); err != nil {
+			return nil, err
+		}
+	}
+	reply, err := c.command(args...)
+	if _, ok := err.(redisError); err != nil && err != errNil && !ok {
+		c.conn.Close()
+		c.conn = nil
+	}
+	return reply, err
+}
+
+func (c *redisCache) connect() error {
+	conn, err := net.DialTimeout("tcp", c.addr, redisTimeout)
+	if err != nil {
+		return err
+	}
+	c.conn, c.reader = conn, bufio.NewReader(conn)
+	if c.password != "" {
+		_, err = c.command("AUTH", c.password)
+	}
+	if err == nil && c.db != 0 {
+		_, err = c.command("SELECT", strconv.Itoa(c.db))
+	}
+	if err != nil {
+		conn.Close()
+		c.conn = nil
+	}
+	return err
+}
+
+func (c *redisCache) command(args ...string) (interface{}, error) {
+	c.conn.SetDeadline(time.Now().Add(redisTimeout))
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c.conn, b.String()); err != nil {
+		return nil, err
+	}
+	return readReply(c.reader)
+}
+
+// redisError is an error reply of Redis
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+// readReply reads a RESP reply: simple strings, errors, integers, bulk strings and arrays
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("Invalid Redis reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		} else if n < 0 {
+			return nil, errNil
+		}
+		value := make([]byte, n+2)
+		if _, err := io.ReadFull(r, value); err != nil {
+			return nil, err
+		}
+		return value[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		} else if n < 0 {
+			return nil, errNil
+		}
+		values := make([]interface{}, n)
+		for i := range values {
+			if values[i], err = readReply(r); err != nil && err != errNil {
+				return nil, err
+			}
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("Invalid Redis reply %q", line)
+}
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeRedis serves GET/SET/DEL/PING/AUTH/SELECT from a map
+func fakeRedis(t *testing.T, password string) (string, func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mutex sync.Mutex
+	values := map[string]string{}
+	serve := func(conn net.Conn) {
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		authenticated := password == ""
+		for {
+			reply, err := readReply(r)
+			if err != nil {
+				return
+			}
+			var args []string
+			for _, arg := range reply.([]interface{}) {
+				args = append(args, string(arg.([]byte)))
+			}
+			mutex.Lock()
+			switch {
+			case args[0] == "AUTH":
+				authenticated = args[1] == password
+				fmt.Fprint(conn, "+OK\r\n")
+			case !authenticated:
+				fmt.Fprint(conn, "-NOAUTH Authentication required.\r\n")
+			case args[0] == "PING":
+				fmt.Fprint(conn, "+PONG\r\n")
+			case args[0] == "SELECT":
+				fmt.Fprint(conn, "+OK\r\n")
+			case args[0] == "SET":
+				values[args[1]] = args[2]
+				fmt.Fprint(conn, "+OK\r\n")
+			case args[0] == "GET":
+				if value, ok := values[args[1]]; ok {
+					fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(value), value)
+				} else {
+					fmt.Fprint(conn, "$-1\r\n")
+				}
+			case args[0] == "DEL":
+				delete(values, args[1])
+				fmt.Fprint(conn, ":1\r\n")
+			default:
+				fmt.Fprintf(conn, "-ERR unknown command %s\r\n", strconv.Quote(strings.Join(args, " ")))
+			}
+			mutex.Unlock()
+		}
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn)
+		}
+	}()
+	return l.Addr().String(), func() { l.Close() }
+}
+
+func TestRedis(t *testing.T) {
+	addr, stop := fakeRedis(t, "secret")
+	defer stop()
+
+	if _, err := Open("redis://" + addr); err == nil {
+		t.Errorf("Expecting an error without password")
+	}
+	u, _ := url.Parse("redis://:secret@" + addr + "/2")
+	c, err := Redis(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c = Prefix(c, "test:")
+	if _, ok := c.Get("foo"); ok {
+		t.Errorf("Expecting foo to be missing")
+	}
+	c.Set("foo", []byte("bar\r\nbaz"))
+	if value, ok := c.Get("foo"); !ok || string(value) != "bar\r\nbaz" {
+		t.Errorf("Expecting bar\\r\\nbaz, but got %q", value)
+	}
+	c.Delete("foo")
+	if _, ok := c.Get("foo"); ok {
+		t.Errorf("Expecting foo to be deleted")
+	}
+}
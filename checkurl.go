@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+// checkURL determines the upstream version of the URL (or provider identifier such as github:owner/repo) without an AUR package,
+// printing the provider, the HTTP requests performed and the version found
+func checkURL(w io.Writer, arg string) error {
+	var fetch func() (upstream.Result, error)
+	if plugin := conf.Plugin("", arg); plugin != "" {
+		fmt.Fprintf(w, "Selected:  plugin `%s` configured in plugins\n", plugin)
+		fetch = func() (upstream.Result, error) {
+			return upstream.ResultForPlugin(plugin, upstream.PluginRequest{URL: arg})
+		}
+	} else if !strings.Contains(arg, "://") {
+		entry, err := upstream.ParseIdentifier(arg)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Selected:  %v source of provider identifier %s\n", entry["source"], arg)
+		fetch = func() (upstream.Result, error) {
+			return upstream.ResultForNvchecker(entry)
+		}
+	} else if p := upstream.ProviderForURL(arg); p != nil {
+		fmt.Fprintf(w, "Selected:  provider %s matching the URL\n", p.Name())
+		fetch = func() (upstream.Result, error) {
+			return upstream.ResultForURL(arg)
+		}
+	} else {
+		return fmt.Errorf("No provider supports %s", arg)
+	}
+
+	next := http.DefaultClient.Transport
+	requests := &requestLog{next: next}
+	http.DefaultClient.Transport = requests
+	defer func() { http.DefaultClient.Transport = next }()
+	result, err := fetch()
+	printList(w, "Requests:", requests.requests)
+	if result.Provider != "" {
+		fmt.Fprintf(w, "Provider:  %s\n", result.Provider)
+	}
+	if err != nil {
+		return err
+	}
+	raw := string(result.Version)
+	if result.Raw != "" {
+		raw = result.Raw
+	}
+	fmt.Fprintf(w, "Raw:       %q\n", raw)
+	fmt.Fprintf(w, "Version:   %s\n", result.Version.String())
+	if !result.Released.IsZero() {
+		fmt.Fprintf(w, "Released:  %s\n", result.Released.Format("2006-01-02"))
+	}
+	if result.ReleaseURL != "" {
+		fmt.Fprintf(w, "Release:   %s\n", result.ReleaseURL)
+	}
+	return nil
+}
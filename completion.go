@@ -0,0 +1,186 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/simon04/aur-out-of-date/state"
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+// subcommands lists the subcommands given as first argument
+var subcommands = []string{"daemon", "watch", "history", "stats", "triage", "explain", "check-url", "lint", "init", "completion"}
+
+// fileFlags and dirFlags list the flags expecting a file or a directory
+var fileFlags = []string{"ca-file", "config", "feed", "from-file", "ignore-file", "lockfile", "nvchecker", "providers", "record", "replay"}
+var dirFlags = []string{"badges", "cache"}
+
+// flagValues returns the values of flags accepting a fixed set of values
+func flagValues() map[string][]string {
+	return map[string][]string{
+		"o":            status.Formats,
+		"sort":         status.SortKeys,
+		"min-severity": status.Severities,
+		"exit-code":    {"out-of-date", "error", "any", "never"},
+		"log-format":   {"text", "json"},
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// knownPackages returns the names of the packages checked before, according to the last run and the history
+func knownPackages() []string {
+	names := map[string]bool{}
+	var lastRun state.LastRun
+	if err := state.Load(path.Join(state.Dir(), "last-run.json"), &lastRun); err == nil {
+		for name := range lastRun.Packages {
+			names[name] = true
+		}
+	}
+	if observations, err := state.ReadHistory(historyFile()); err == nil {
+		for _, o := range observations {
+			names[o.Package] = true
+		}
+	}
+	var packages []string
+	for name := range names {
+		packages = append(packages, name)
+	}
+	sort.Strings(packages)
+	return packages
+}
+
+// printCompletion prints the completion script for the shell (bash, zsh, fish), or the known package names for "packages"
+func printCompletion(w io.Writer, shell string) error {
+	switch shell {
+	case "bash":
+		printBashCompletion(w)
+	case "zsh":
+		printZshCompletion(w)
+	case "fish":
+		printFishCompletion(w)
+	case "packages":
+		for _, name := range knownPackages() {
+			fmt.Fprintln(w, name)
+		}
+	default:
+		return fmt.Errorf("Unknown shell %q, supported shells: bash, zsh, fish", shell)
+	}
+	return nil
+}
+
+func printBashCompletion(w io.Writer) {
+	var flags, valueFlags []string
+	flag.VisitAll(func(f *flag.Flag) {
+		flags = append(flags, "-"+f.Name)
+		if !isBoolFlag(f) {
+			valueFlags = append(valueFlags, "-"+f.Name)
+		}
+	})
+	fmt.Fprintln(w, "# bash completion for aur-out-of-date, generated by aur-out-of-date completion bash")
+	fmt.Fprintln(w, "_aur_out_of_date() {")
+	fmt.Fprintln(w, "\tlocal cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\"")
+	fmt.Fprintln(w, "\tcase \"$prev\" in")
+	values := flagValues()
+	var names []string
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "\t-%s) COMPREPLY=($(compgen -W %q -- \"$cur\")); return ;;\n", name, strings.Join(values[name], " "))
+	}
+	fmt.Fprintf(w, "\t-%s) COMPREPLY=($(compgen -f -- \"$cur\")); return ;;\n", strings.Join(fileFlags, "|-"))
+	fmt.Fprintf(w, "\t-%s) COMPREPLY=($(compgen -d -- \"$cur\")); return ;;\n", strings.Join(dirFlags, "|-"))
+	fmt.Fprintf(w, "\t%s) return ;;\n", strings.Join(valueFlags, "|"))
+	fmt.Fprintln(w, "\tcompletion) COMPREPLY=($(compgen -W \"bash zsh fish\" -- \"$cur\")); return ;;")
+	fmt.Fprintln(w, "\tesac")
+	fmt.Fprintln(w, "\tif [[ \"$cur\" == -* ]]; then")
+	fmt.Fprintf(w, "\t\tCOMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(flags, " "))
+	fmt.Fprintln(w, "\telif [[ $COMP_CWORD -eq 1 ]]; then")
+	fmt.Fprintf(w, "\t\tCOMPREPLY=($(compgen -W \"%s $(aur-out-of-date completion packages 2>/dev/null)\" -- \"$cur\"))\n", strings.Join(subcommands, " "))
+	fmt.Fprintln(w, "\telse")
+	fmt.Fprintln(w, "\t\tCOMPREPLY=($(compgen -W \"$(aur-out-of-date completion packages 2>/dev/null)\" -- \"$cur\"))")
+	fmt.Fprintln(w, "\tfi")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w, "complete -o default -F _aur_out_of_date aur-out-of-date")
+}
+
+// zshEscape escapes a flag description for _arguments
+func zshEscape(s string) string {
+	return strings.NewReplacer("'", "'\\''", "[", "\\[", "]", "\\]", ":", "\\:").Replace(s)
+}
+
+func printZshCompletion(w io.Writer) {
+	fmt.Fprintln(w, "#compdef aur-out-of-date")
+	fmt.Fprintln(w, "# zsh completion for aur-out-of-date, generated by aur-out-of-date completion zsh")
+	fmt.Fprintln(w, "_aur_out_of_date() {")
+	fmt.Fprintln(w, "\t_arguments \\")
+	values := flagValues()
+	flag.VisitAll(func(f *flag.Flag) {
+		spec := fmt.Sprintf("-%s[%s]", f.Name, zshEscape(f.Usage))
+		switch {
+		case isBoolFlag(f):
+		case values[f.Name] != nil:
+			spec += fmt.Sprintf(":%s:(%s)", f.Name, strings.Join(values[f.Name], " "))
+		case contains(fileFlags, f.Name):
+			spec += ":file:_files"
+		case contains(dirFlags, f.Name):
+			spec += ":directory:_files -/"
+		default:
+			spec += ":" + f.Name + ":"
+		}
+		fmt.Fprintf(w, "\t\t'%s' \\\n", spec)
+	})
+	fmt.Fprintln(w, "\t\t'*:package:_aur_out_of_date_packages'")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w, "_aur_out_of_date_packages() {")
+	fmt.Fprintf(w, "\tcompadd %s ${(f)\"$(aur-out-of-date completion packages 2>/dev/null)\"}\n", strings.Join(subcommands, " "))
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w, "compdef _aur_out_of_date aur-out-of-date")
+}
+
+// fishEscape quotes a string for fish
+func fishEscape(s string) string {
+	return "'" + strings.NewReplacer("\\", "\\\\", "'", "\\'").Replace(s) + "'"
+}
+
+func printFishCompletion(w io.Writer) {
+	fmt.Fprintln(w, "# fish completion for aur-out-of-date, generated by aur-out-of-date completion fish")
+	fmt.Fprintln(w, "complete -c aur-out-of-date -f")
+	fmt.Fprintf(w, "complete -c aur-out-of-date -n __fish_use_subcommand -a %s\n", fishEscape(strings.Join(subcommands, " ")))
+	fmt.Fprintln(w, "complete -c aur-out-of-date -n '__fish_seen_subcommand_from completion' -a 'bash zsh fish'")
+	fmt.Fprintln(w, "complete -c aur-out-of-date -a '(aur-out-of-date completion packages 2>/dev/null)'")
+	values := flagValues()
+	flag.VisitAll(func(f *flag.Flag) {
+		line := fmt.Sprintf("complete -c aur-out-of-date -o %s -d %s", f.Name, fishEscape(f.Usage))
+		switch {
+		case isBoolFlag(f):
+		case values[f.Name] != nil:
+			line += " -x -a " + fishEscape(strings.Join(values[f.Name], " "))
+		case contains(fileFlags, f.Name):
+			line += " -r -F"
+		case contains(dirFlags, f.Name):
+			line += " -x -a '(__fish_complete_directories)'"
+		default:
+			line += " -x"
+		}
+		fmt.Fprintln(w, line)
+	})
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/simon04/aur-out-of-date/pkg"
+	"github.com/simon04/aur-out-of-date/status"
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+// components checks every source of the package mapping to a provider, for -all-sources,
+// skipping those yielding the upstream version of the package itself
+func components(pkg pkg.Pkg, main upstream.Result) []status.Component {
+	sources, err := pkg.Sources()
+	if err != nil {
+		return nil
+	}
+	seen := map[string]bool{main.Provider + " " + main.Version.String(): true}
+	var components []status.Component
+	for _, source := range sources {
+		if i := strings.Index(source, "::"); i >= 0 {
+			source = source[i+2:]
+		}
+		if upstream.ProviderForURL(source) == nil {
+			continue
+		}
+		result, err := upstream.ResultForURL(source)
+		if err != nil {
+			components = append(components, status.Component{Source: source, Provider: result.Provider, Status: status.Unknown, Message: err.Error()})
+			continue
+		}
+		key := result.Provider + " " + result.Version.String()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		components = append(components, status.NewComponent(source, result))
+	}
+	return components
+}
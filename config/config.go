@@ -1,46 +1,429 @@
+// Package config reads the configuration of aur-out-of-date
 package config
 
 import (
 	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
 	"os"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
 
+	pkgbuild "github.com/mikkeloscar/gopkgbuild"
+	"github.com/simon04/aur-out-of-date/aurweb"
+	"github.com/simon04/aur-out-of-date/issues"
+	"github.com/simon04/aur-out-of-date/notify"
+	"github.com/simon04/aur-out-of-date/transport"
 	"github.com/simon04/aur-out-of-date/upstream"
 )
 
 // Config contains options for running aur-out-of-date
 type Config struct {
 	Ignore  map[string]([]upstream.Version) `json:"ignore"`
+	Max     map[string]MaxVersion           `json:"max"`
 	Scripts map[string]string               `json:"scripts"`
+	Notify  notify.Config                   `json:"notify"`
+	Issues  issues.Config                   `json:"issues"`
+	Plugins Plugins                         `json:"plugins"`
+	// Auth holds credentials per host, taking precedence over ~/.netrc
+	Auth map[string]transport.Credentials `json:"auth"`
+	// Headers holds extra request headers per host or provider name, e.g. {"github": {"Accept": "…"}}
+	Headers map[string]map[string]string `json:"headers"`
+	// Contact is appended to the User-Agent, e.g. an email address for upstream operators
+	Contact string `json:"contact"`
+	// AUR holds the account used to flag packages via the AUR web interface instead of SSH
+	AUR aurweb.Config `json:"aur"`
+	// Settings holds default values for command line flags, e.g. {"o": "json", "jobs": "4"}
+	Settings map[string]string `json:"settings"`
+	// Env holds environment variables such as GITHUB_TOKEN, unless already set
+	Env      map[string]string        `json:"env"`
+	Packages map[string]PackageConfig `json:"packages"`
+	// Fallback is the fallback chain of packages not configuring one, e.g. ["default", "github-tags", "anitya", "scrape"]
+	Fallback []string `json:"fallback"`
+	// Intervals holds the check intervals per provider in daemon and watch mode, e.g. {"cpan": "24h"}
+	Intervals map[string]string `json:"intervals"`
+	// IgnoreRules holds the rules read from the ignore file, see FromIgnoreFile
+	IgnoreRules map[string][]IgnoreRule `json:"-"`
+
+	// given records the flags given on the command line, appliedEnv the environment variables set by Apply
+	given      map[string]bool
+	appliedEnv map[string]bool
+	// directives holds the overrides read from the PKGBUILDs, see SetDirectives
+	directives      map[string]PackageConfig
+	directivesMutex sync.RWMutex
+}
+
+// PackageConfig holds per-package overrides
+type PackageConfig struct {
+	// URL overrides the upstream URL used to find a provider
+	URL string `json:"url"`
+	// Provider pins the package to a provider identifier, e.g. "github:BurntSushi/ripgrep"
+	Provider string `json:"provider"`
+	// Regex extracts the version from the upstream version using its first group
+	Regex  string             `json:"regex"`
+	Ignore []upstream.Version `json:"ignore"`
+	// IgnoreRegex ignores the upstream versions matching the regular expression, e.g. ".*rc.*"
+	IgnoreRegex string `json:"ignore_regex"`
+	// MinSeverity overrides -min-severity, e.g. "minor" to ignore patch updates
+	MinSeverity string `json:"min_severity"`
+	// Channel selects the release channel tracked by the package, e.g. "prerelease" or an npm dist-tag
+	Channel string `json:"channel"`
+	// Watch holds the rules of a Debian watch file, see upstream.ResultForWatch
+	Watch string `json:"watch"`
+	// Interval overrides the check interval in daemon and watch mode, e.g. "24h"
+	Interval string `json:"interval"`
+	// Fallback lists the steps tried in order until one finds a version, see upstream.ResultForChain
+	Fallback []string `json:"fallback"`
+	// Skip skips the upstream check, e.g. for intentionally pinned packages
+	Skip *Skip `json:"skip"`
+}
+
+// Skip describes why and until when the upstream check of a package is skipped
+type Skip struct {
+	Reason string `json:"reason"`
+	// Until is the date (YYYY-MM-DD) after which the package is checked again, empty to skip indefinitely
+	Until string `json:"until"`
+}
+
+// skipDate is the format of Skip.Until
+const skipDate = "2006-01-02"
+
+// Message describes the skip for the status, e.g. "skipped until 2025-06-30 (pinned to 1.x)"
+func (s *Skip) Message() string {
+	m := "skipped"
+	if s.Until != "" {
+		m += " until " + s.Until
+	}
+	if s.Reason != "" {
+		m += " (" + s.Reason + ")"
+	}
+	return m
+}
+
+// Plugins configures provider plugins per package or per host of the upstream URL
+type Plugins struct {
+	Packages map[string]string `json:"packages"`
+	Hosts    map[string]string `json:"hosts"`
+}
+
+// MaxVersion caps the upstream version reported for a package
+type MaxVersion struct {
+	Version upstream.Version `json:"version"`
+	Reason  string           `json:"reason"`
 }
 
-// FromFile reads the config from the given filename
+// DefaultFile returns config.toml in the directory, or config.json if only the latter exists
+func DefaultFile(dir string) string {
+	filename := path.Join(dir, "config.toml")
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		if _, err := os.Stat(path.Join(dir, "config.json")); err == nil {
+			return path.Join(dir, "config.json")
+		}
+	}
+	return filename
+}
+
+// FromFile reads the config from the given filename, TOML for *.toml and JSON otherwise
 func FromFile(filename string) (*Config, error) {
 	var config Config
 	if _, err := os.Stat(filename); os.IsNotExist(err) {
 		return &config, nil
 	}
-	f, err := os.Open(filename)
+	data, err := ioutil.ReadFile(filename)
 	if err != nil {
 		return nil, err
 	}
-	dec := json.NewDecoder(f)
-	err = dec.Decode(&config)
+	if strings.HasSuffix(filename, ".toml") {
+		if data, err = tomlToJSON(filename, data); err != nil {
+			return nil, err
+		}
+	}
+	err = json.Unmarshal(data, &config)
 	if err != nil {
 		return nil, err
 	}
 	return &config, nil
 }
 
+// tomlToJSON converts the TOML config to JSON, decoded using the same field names.
+// The values of settings and env are converted to strings, allowing e.g. jobs = 4.
+func tomlToJSON(filename string, data []byte) ([]byte, error) {
+	doc, err := decodeTOML(filename, data)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range []string{"settings", "env"} {
+		table, _ := doc[key].(map[string]interface{})
+		for name, value := range table {
+			switch v := value.(type) {
+			case bool, int64, float64:
+				table[name] = fmt.Sprint(v)
+			}
+		}
+	}
+	return json.Marshal(doc)
+}
+
+// Apply sets the environment and the flags not given on the command line according to the config
+func (conf *Config) Apply(flags *flag.FlagSet) error {
+	return conf.Replace(nil, flags)
+}
+
+// Replace applies the config in place of the previous one (e.g., when reloading),
+// overriding or resetting the flags and environment variables set by the previous config.
+// On errors, the flags, the environment and the config are left unchanged.
+func (conf *Config) Replace(previous *Config, flags *flag.FlagSet) error {
+	given := map[string]bool{}
+	if previous != nil {
+		given = previous.given
+	} else {
+		previous = &Config{}
+		flags.Visit(func(f *flag.Flag) {
+			given[f.Name] = true
+		})
+	}
+	for name := range conf.Settings {
+		if !given[name] && flags.Lookup(name) == nil {
+			return fmt.Errorf("Unknown setting %s", name)
+		}
+	}
+	if err := conf.setFlags(previous, flags, given); err != nil {
+		return err
+	}
+	appliedEnv := map[string]bool{}
+	for key := range previous.appliedEnv {
+		if _, ok := conf.Env[key]; !ok {
+			os.Unsetenv(key)
+		}
+	}
+	for key, value := range conf.Env {
+		if _, ok := os.LookupEnv(key); !ok || previous.appliedEnv[key] {
+			os.Setenv(key, value)
+			appliedEnv[key] = true
+		}
+	}
+	conf.given, conf.appliedEnv = given, appliedEnv
+	return nil
+}
+
+// setFlags resets the flags set by the previous config and sets those of the config not given on the command line,
+// restoring the former values if a setting is invalid
+func (conf *Config) setFlags(previous *Config, flags *flag.FlagSet, given map[string]bool) error {
+	former := map[string]string{}
+	remember := func(f *flag.Flag) {
+		if _, ok := former[f.Name]; !ok {
+			former[f.Name] = f.Value.String()
+		}
+	}
+	restore := func(err error) error {
+		for name, value := range former {
+			flags.Lookup(name).Value.Set(value)
+		}
+		return err
+	}
+	for name := range previous.Settings {
+		f := flags.Lookup(name)
+		if _, ok := conf.Settings[name]; !ok && f != nil && !given[name] {
+			remember(f)
+			if err := f.Value.Set(f.DefValue); err != nil {
+				return restore(fmt.Errorf("Failed to reset setting %s: %w", name, err))
+			}
+		}
+	}
+	for name, value := range conf.Settings {
+		if given[name] {
+			continue
+		}
+		remember(flags.Lookup(name))
+		if err := flags.Set(name, value); err != nil {
+			return restore(fmt.Errorf("Invalid setting %s: %w", name, err))
+		}
+	}
+	return nil
+}
+
+// Extract applies the version regex configured for the package, if any
+func (conf *Config) Extract(pkg string, version upstream.Version) (upstream.Version, error) {
+	regex := conf.Package(pkg).Regex
+	if regex == "" {
+		return version, nil
+	}
+	re, err := regexp.Compile(regex)
+	if err != nil {
+		return version, fmt.Errorf("Invalid regex for %s: %w", pkg, err)
+	}
+	match := re.FindStringSubmatch(string(version))
+	if match == nil {
+		return version, fmt.Errorf("Version %s of %s does not match %s", version, pkg, regex)
+	} else if len(match) > 1 {
+		return upstream.Version(match[1]), nil
+	}
+	return upstream.Version(match[0]), nil
+}
+
+// ExtractResult applies the version regex configured for the package to the raw release name or tag of the result (if known), if any
+func (conf *Config) ExtractResult(pkg string, result upstream.Result) (upstream.Version, error) {
+	if conf.Package(pkg).Regex == "" || result.Raw == "" {
+		return conf.Extract(pkg, result.Version)
+	}
+	return conf.Extract(pkg, upstream.Version(result.Raw))
+}
+
 // IsIgnored determines whether the package in version is to be ignored
 func (conf *Config) IsIgnored(pkg string, version upstream.Version) bool {
-	ignoredVersions, ok := conf.Ignore[pkg]
-	if !ok {
-		return false
-	}
+	ignoredVersions := make([]upstream.Version, 0, len(conf.Ignore[pkg])+len(conf.Packages[pkg].Ignore))
+	ignoredVersions = append(ignoredVersions, conf.Ignore[pkg]...)
+	ignoredVersions = append(ignoredVersions, conf.Packages[pkg].Ignore...)
 	for _, v := range ignoredVersions {
 		if v == "*" || v.String() == version.String() {
 			return true
 		}
 	}
+	if pattern := conf.Package(pkg).IgnoreRegex; pattern != "" {
+		if re, err := regexp.Compile("^(?:" + pattern + ")$"); err == nil && re.MatchString(version.String()) {
+			return true
+		}
+	}
+	for _, rule := range conf.IgnoreRules[pkg] {
+		if rule.Matches(version) {
+			return true
+		}
+	}
 	return false
 }
+
+// Plugin returns the provider plugin configured for the package or for the host of one of the URLs, "" if none
+func (conf *Config) Plugin(pkg string, urls ...string) string {
+	if plugin, ok := conf.Plugins.Packages[pkg]; ok {
+		return plugin
+	}
+	for _, u := range urls {
+		if i := strings.Index(u, "::"); i >= 0 {
+			u = u[i+2:]
+		}
+		parsed, err := url.Parse(u)
+		if err != nil {
+			continue
+		}
+		host := parsed.Hostname()
+		for h, plugin := range conf.Plugins.Hosts {
+			if host == h || strings.HasSuffix(host, "."+h) {
+				return plugin
+			}
+		}
+	}
+	return ""
+}
+
+// RequestHeaders returns the configured request headers by host, mapping provider names to the hosts of their APIs
+func (conf *Config) RequestHeaders() map[string]http.Header {
+	headers := map[string]http.Header{}
+	for key, values := range conf.Headers {
+		hosts, ok := upstream.APIHosts[key]
+		if !ok {
+			hosts = []string{key}
+		}
+		for _, host := range hosts {
+			if headers[host] == nil {
+				headers[host] = http.Header{}
+			}
+			for name, value := range values {
+				headers[host].Set(name, value)
+			}
+		}
+	}
+	return headers
+}
+
+// Interval returns the check interval configured for the package, otherwise for its provider, 0 if none
+func (conf *Config) Interval(pkg, provider string) (time.Duration, error) {
+	interval := conf.Package(pkg).Interval
+	if interval == "" {
+		interval = conf.Intervals[provider]
+	}
+	if interval == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(interval)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid interval %s: %w", interval, err)
+	}
+	return d, nil
+}
+
+// MinInterval returns the shortest check interval configured for a package or provider, 0 if none
+func (conf *Config) MinInterval() time.Duration {
+	var intervals []string
+	for _, c := range conf.Packages {
+		intervals = append(intervals, c.Interval)
+	}
+	for _, interval := range conf.Intervals {
+		intervals = append(intervals, interval)
+	}
+	conf.directivesMutex.RLock()
+	for _, c := range conf.directives {
+		intervals = append(intervals, c.Interval)
+	}
+	conf.directivesMutex.RUnlock()
+	var min time.Duration
+	for _, interval := range intervals {
+		if d, err := time.ParseDuration(interval); err == nil && d > 0 && (min == 0 || d < min) {
+			min = d
+		}
+	}
+	return min
+}
+
+// Skip returns the skip configured for the package unless it expired before now, nil otherwise
+func (conf *Config) Skip(pkg string, now time.Time) (*Skip, error) {
+	skip := conf.Package(pkg).Skip
+	if skip == nil || skip.Until == "" {
+		return skip, nil
+	}
+	until, err := time.ParseInLocation(skipDate, skip.Until, now.Location())
+	if err != nil {
+		return nil, fmt.Errorf("Invalid skip date %s: %w", skip.Until, err)
+	} else if !now.Before(until.AddDate(0, 0, 1)) {
+		return nil, nil
+	}
+	return skip, nil
+}
+
+// FallbackChain returns the fallback chain configured for the package, otherwise the global one, nil if none
+func (conf *Config) FallbackChain(pkg string) []string {
+	if chain := conf.Package(pkg).Fallback; len(chain) > 0 {
+		return chain
+	}
+	return conf.Fallback
+}
+
+// MinSeverity returns the minimum severity configured for the package, def otherwise
+func (conf *Config) MinSeverity(pkg string, def string) string {
+	if min := conf.Package(pkg).MinSeverity; min != "" {
+		return min
+	}
+	return def
+}
+
+// Cap returns the configured maximum version if version exceeds it, nil otherwise
+func (conf *Config) Cap(pkg string, version upstream.Version) *MaxVersion {
+	max, ok := conf.Max[pkg]
+	if !ok {
+		return nil
+	}
+	maxVersion, err := pkgbuild.NewCompleteVersion(max.Version.String())
+	if err != nil {
+		return nil
+	}
+	v, err := pkgbuild.NewCompleteVersion(version.String())
+	if err != nil || !v.Newer(maxVersion) {
+		return nil
+	}
+	return &max
+}
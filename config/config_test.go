@@ -1,7 +1,12 @@
 package config
 
 import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path"
 	"testing"
+	"time"
 
 	"github.com/simon04/aur-out-of-date/upstream"
 )
@@ -36,3 +41,250 @@ func TestIsIgnored(t *testing.T) {
 		t.Errorf("baz-bin-1.0 should not be ignored")
 	}
 }
+
+func TestCap(t *testing.T) {
+	conf := Config{
+		Max: map[string]MaxVersion{
+			"foo": {Version: "2.4", Reason: "license change"},
+		},
+	}
+	if max := conf.Cap("foo", "2.5"); max == nil || max.Version != "2.4" || max.Reason != "license change" {
+		t.Errorf("foo-2.5 should be capped at 2.4, but got %v", max)
+	}
+	if max := conf.Cap("foo", "v2.4"); max != nil {
+		t.Errorf("foo-2.4 should not be capped, but got %v", max)
+	}
+	if max := conf.Cap("foo", "2.3.9"); max != nil {
+		t.Errorf("foo-2.3.9 should not be capped, but got %v", max)
+	}
+	if max := conf.Cap("bar", "9.9"); max != nil {
+		t.Errorf("bar-9.9 should not be capped, but got %v", max)
+	}
+}
+
+func TestPlugin(t *testing.T) {
+	conf := Config{
+		Plugins: Plugins{
+			Packages: map[string]string{"foo": "foo-provider"},
+			Hosts:    map[string]string{"example.org": "example-provider"},
+		},
+	}
+	if plugin := conf.Plugin("foo", "https://example.org/foo"); plugin != "foo-provider" {
+		t.Errorf("Expecting foo-provider, but got %s", plugin)
+	}
+	if plugin := conf.Plugin("bar", "https://github.com/bar/bar", "bar.tar.gz::https://download.example.org/bar.tar.gz"); plugin != "example-provider" {
+		t.Errorf("Expecting example-provider, but got %s", plugin)
+	}
+	if plugin := conf.Plugin("baz", "https://notexample.org/baz"); plugin != "" {
+		t.Errorf("Expecting no plugin, but got %s", plugin)
+	}
+}
+
+func TestPackages(t *testing.T) {
+	conf := Config{
+		Packages: map[string]PackageConfig{
+			"foo": {Regex: `^foo-v(.+)$`, Ignore: []upstream.Version{"2.0"}, MinSeverity: "minor"},
+		},
+	}
+	if !conf.IsIgnored("foo", "2.0") {
+		t.Errorf("foo-2.0 should be ignored")
+	}
+	if v, err := conf.Extract("foo", "foo-v1.2"); err != nil || v != "1.2" {
+		t.Errorf("Expecting 1.2, but got %v (%v)", v, err)
+	}
+	if _, err := conf.Extract("foo", "bar-1.2"); err == nil {
+		t.Errorf("Expecting an error for non-matching version")
+	}
+	if v, err := conf.Extract("bar", "bar-1.2"); err != nil || v != "bar-1.2" {
+		t.Errorf("Expecting bar-1.2, but got %v (%v)", v, err)
+	}
+	if v, err := conf.ExtractResult("foo", upstream.Result{Version: "1.2", Raw: "foo-v1.2"}); err != nil || v != "1.2" {
+		t.Errorf("Expecting 1.2 extracted from the raw tag, but got %v (%v)", v, err)
+	}
+	if v, err := conf.ExtractResult("bar", upstream.Result{Version: "1.2", Raw: "bar-1.2"}); err != nil || v != "1.2" {
+		t.Errorf("Expecting 1.2, but got %v (%v)", v, err)
+	}
+	if min := conf.MinSeverity("foo", "patch"); min != "minor" {
+		t.Errorf("Expecting minor, but got %s", min)
+	}
+	if min := conf.MinSeverity("bar", "patch"); min != "patch" {
+		t.Errorf("Expecting patch, but got %s", min)
+	}
+}
+
+func TestFromFileTOML(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := path.Join(dir, "config.toml")
+	content := `[settings]
+user = "alice"
+jobs = 4
+
+[env]
+GITHUB_TOKEN = "ghp_secret"
+
+# detected provider github
+[packages.foo]
+url = "https://github.com/example/foo"
+ignore = ["2.0-rc1"]
+skip = { reason = "pinned to 1.x", until = "2025-06-30" }
+
+# [packages.bar]
+# url = ""
+
+[max.baz]
+version = "2.4.1"
+reason = "3.x switched to a non-free license"
+`
+	if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	conf, err := FromFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.Settings["user"] != "alice" || conf.Settings["jobs"] != "4" || conf.Env["GITHUB_TOKEN"] != "ghp_secret" {
+		t.Errorf("Unexpected settings %v and env %v", conf.Settings, conf.Env)
+	}
+	foo := conf.Packages["foo"]
+	if len(conf.Packages) != 1 || foo.URL != "https://github.com/example/foo" || len(foo.Ignore) != 1 || foo.Skip == nil || foo.Skip.Until != "2025-06-30" {
+		t.Errorf("Expecting only foo, but got %v", conf.Packages)
+	}
+	if conf.Max["baz"].Version != "2.4.1" {
+		t.Errorf("Expecting max version 2.4.1, but got %v", conf.Max)
+	}
+	if err := ioutil.WriteFile(filename, []byte("[packages.foo]\nurl = 42\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FromFile(filename); err == nil {
+		t.Error("Expecting an error for an invalid url")
+	}
+}
+
+func TestDefaultFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if filename := DefaultFile(dir); filename != path.Join(dir, "config.toml") {
+		t.Errorf("Expecting config.toml, but got %s", filename)
+	}
+	if err := ioutil.WriteFile(path.Join(dir, "config.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if filename := DefaultFile(dir); filename != path.Join(dir, "config.json") {
+		t.Errorf("Expecting config.json, but got %s", filename)
+	}
+	if err := ioutil.WriteFile(path.Join(dir, "config.toml"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if filename := DefaultFile(dir); filename != path.Join(dir, "config.toml") {
+		t.Errorf("Expecting config.toml, but got %s", filename)
+	}
+}
+
+func TestApply(t *testing.T) {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	output := flags.String("o", "text", "")
+	jobs := flags.Int("jobs", 8, "")
+	if err := flags.Parse([]string{"-jobs", "2"}); err != nil {
+		t.Fatal(err)
+	}
+	conf := Config{Settings: map[string]string{"o": "json", "jobs": "4"}}
+	if err := conf.Apply(flags); err != nil {
+		t.Fatal(err)
+	}
+	if *output != "json" || *jobs != 2 {
+		t.Errorf("Expecting -o json -jobs 2, but got -o %s -jobs %d", *output, *jobs)
+	}
+	conf = Config{Settings: map[string]string{"unknown": "1"}}
+	if err := conf.Apply(flags); err == nil {
+		t.Errorf("Expecting an error for unknown setting")
+	}
+}
+
+func TestReplace(t *testing.T) {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	output := flags.String("o", "text", "")
+	jobs := flags.Int("jobs", 8, "")
+	if err := flags.Parse([]string{"-jobs", "2"}); err != nil {
+		t.Fatal(err)
+	}
+	os.Unsetenv("AUR_OUT_OF_DATE_TEST_TOKEN")
+	previous := &Config{Settings: map[string]string{"o": "json"}, Env: map[string]string{"AUR_OUT_OF_DATE_TEST_TOKEN": "old"}}
+	if err := previous.Apply(flags); err != nil {
+		t.Fatal(err)
+	}
+	conf := &Config{Settings: map[string]string{"jobs": "4"}, Env: map[string]string{"AUR_OUT_OF_DATE_TEST_TOKEN": "new"}}
+	if err := conf.Replace(previous, flags); err != nil {
+		t.Fatal(err)
+	}
+	if *output != "text" || *jobs != 2 {
+		t.Errorf("Expecting -o text -jobs 2, but got -o %s -jobs %d", *output, *jobs)
+	}
+	if token := os.Getenv("AUR_OUT_OF_DATE_TEST_TOKEN"); token != "new" {
+		t.Errorf("Expecting token new, but got %s", token)
+	}
+	if err := (&Config{}).Replace(conf, flags); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := os.LookupEnv("AUR_OUT_OF_DATE_TEST_TOKEN"); ok {
+		t.Errorf("Expecting token to be unset")
+	}
+}
+
+func TestReplaceInvalid(t *testing.T) {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	output := flags.String("o", "text", "")
+	flags.Duration("timeout", time.Minute, "")
+	os.Unsetenv("AUR_OUT_OF_DATE_TEST_TOKEN")
+	defer os.Unsetenv("AUR_OUT_OF_DATE_TEST_TOKEN")
+	previous := &Config{Settings: map[string]string{"o": "json"}, Env: map[string]string{"AUR_OUT_OF_DATE_TEST_TOKEN": "old"}}
+	if err := previous.Apply(flags); err != nil {
+		t.Fatal(err)
+	}
+	conf := &Config{Settings: map[string]string{"o": "csv", "timeout": "soon"}, Env: map[string]string{"AUR_OUT_OF_DATE_TEST_TOKEN": "new"}}
+	if err := conf.Replace(previous, flags); err == nil {
+		t.Fatal("Expecting an error for the invalid setting")
+	}
+	if *output != "json" {
+		t.Errorf("Expecting -o json to be kept, but got -o %s", *output)
+	}
+	if token := os.Getenv("AUR_OUT_OF_DATE_TEST_TOKEN"); token != "old" {
+		t.Errorf("Expecting token old to be kept, but got %s", token)
+	}
+	if err := (&Config{}).Replace(previous, flags); err != nil || *output != "text" {
+		t.Errorf("Expecting the previous config to be replaceable, but got %v and -o %s", err, *output)
+	}
+}
+
+func TestInterval(t *testing.T) {
+	conf := Config{
+		Packages:  map[string]PackageConfig{"foo": {Interval: "1h"}, "bar": {Interval: "soon"}},
+		Intervals: map[string]string{"cpan": "24h"},
+	}
+	conf.SetDirectives("baz", PackageConfig{Interval: "30m"})
+	for _, test := range []struct {
+		pkg, provider string
+		expected      time.Duration
+	}{
+		{"foo", "cpan", time.Hour},
+		{"baz", "github", 30 * time.Minute},
+		{"qux", "cpan", 24 * time.Hour},
+		{"qux", "github", 0},
+	} {
+		if d, err := conf.Interval(test.pkg, test.provider); err != nil || d != test.expected {
+			t.Errorf("Expecting %v for %s, but got %v (%v)", test.expected, test.pkg, d, err)
+		}
+	}
+	if _, err := conf.Interval("bar", "cpan"); err == nil {
+		t.Error("Expecting an error for an invalid interval")
+	}
+	if min := conf.MinInterval(); min != 30*time.Minute {
+		t.Errorf("Expecting the shortest interval 30m, but got %v", min)
+	}
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/status"
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+// directive matches "# aur-out-of-date: key=value …" comments in a PKGBUILD
+var directive = regexp.MustCompile(`(?m)^[ \t]*#[ \t]*aur-out-of-date:(.*)$`)
+
+// ParseDirectives reads the per-package overrides from the "# aur-out-of-date:" comments of the PKGBUILD,
+// such as "# aur-out-of-date: provider=github:owner/repo channel=stable ignore=.*rc.*"
+// or "# aur-out-of-date: skip until=2025-06-30 pinned to 1.x" (the rest of the line being the reason)
+func ParseDirectives(content string) (PackageConfig, error) {
+	var c PackageConfig
+	for _, match := range directive.FindAllStringSubmatch(content, -1) {
+		fields := strings.Fields(match[1])
+		if len(fields) > 0 && fields[0] == "skip" {
+			skip, err := parseSkip(fields[1:])
+			if err != nil {
+				return c, err
+			}
+			c.Skip = skip
+			continue
+		}
+		for _, field := range fields {
+			parts := strings.SplitN(field, "=", 2)
+			if len(parts) != 2 || parts[1] == "" {
+				return c, fmt.Errorf("Invalid directive %s, expecting key=value", field)
+			}
+			key, value := parts[0], parts[1]
+			switch key {
+			case "provider":
+				if _, err := upstream.ParseIdentifier(value); err != nil {
+					return c, fmt.Errorf("Invalid directive %s: %w", field, err)
+				}
+				c.Provider = value
+			case "fallback":
+				steps := strings.Split(value, ",")
+				if err := upstream.ValidateChain(steps); err != nil {
+					return c, fmt.Errorf("Invalid directive %s: %w", field, err)
+				}
+				c.Fallback = steps
+			case "url":
+				c.URL = value
+			case "regex":
+				c.Regex = value
+			case "ignore":
+				if _, err := regexp.Compile(value); err != nil {
+					return c, fmt.Errorf("Invalid directive %s: %w", field, err)
+				}
+				c.IgnoreRegex = value
+			case "channel":
+				c.Channel = value
+			case "interval":
+				if _, err := time.ParseDuration(value); err != nil {
+					return c, fmt.Errorf("Invalid directive %s: %w", field, err)
+				}
+				c.Interval = value
+			case "min_severity":
+				if !contains(status.Severities, value) {
+					return c, fmt.Errorf("Invalid directive %s: unknown severity", field)
+				}
+				c.MinSeverity = value
+			default:
+				return c, fmt.Errorf("Unknown directive %s", key)
+			}
+		}
+	}
+	return c, nil
+}
+
+// parseSkip parses the fields following "skip", i.e. an optional until=YYYY-MM-DD and the reason
+func parseSkip(fields []string) (*Skip, error) {
+	skip := &Skip{}
+	if len(fields) > 0 && strings.HasPrefix(fields[0], "until=") {
+		skip.Until = strings.TrimPrefix(fields[0], "until=")
+		if _, err := time.Parse(skipDate, skip.Until); err != nil {
+			return nil, fmt.Errorf("Invalid directive %s, expecting until=YYYY-MM-DD", fields[0])
+		}
+		fields = fields[1:]
+	}
+	skip.Reason = strings.Join(fields, " ")
+	return skip, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// SetDirectives sets the overrides read from the PKGBUILD of the package, see ParseDirectives
+func (conf *Config) SetDirectives(pkg string, directives PackageConfig) {
+	conf.directivesMutex.Lock()
+	defer conf.directivesMutex.Unlock()
+	if conf.directives == nil {
+		conf.directives = map[string]PackageConfig{}
+	}
+	conf.directives[pkg] = directives
+}
+
+// Package returns the overrides of the package, those configured in packages taking precedence over the directives of its PKGBUILD
+func (conf *Config) Package(pkg string) PackageConfig {
+	c := conf.Packages[pkg]
+	conf.directivesMutex.RLock()
+	d := conf.directives[pkg]
+	conf.directivesMutex.RUnlock()
+	for _, field := range []struct{ value, directive *string }{
+		{&c.URL, &d.URL},
+		{&c.Provider, &d.Provider},
+		{&c.Regex, &d.Regex},
+		{&c.IgnoreRegex, &d.IgnoreRegex},
+		{&c.MinSeverity, &d.MinSeverity},
+		{&c.Channel, &d.Channel},
+		{&c.Interval, &d.Interval},
+	} {
+		if *field.value == "" {
+			*field.value = *field.directive
+		}
+	}
+	if c.Skip == nil {
+		c.Skip = d.Skip
+	}
+	if len(c.Fallback) == 0 {
+		c.Fallback = d.Fallback
+	}
+	return c
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDirectives(t *testing.T) {
+	content := "# Maintainer: Jane <jane@example.com>\n" +
+		"# aur-out-of-date: provider=github:BurntSushi/ripgrep channel=stable\n" +
+		"#aur-out-of-date: ignore=.*rc.* min_severity=minor interval=24h\n" +
+		"pkgname=ripgrep\n"
+	c, err := ParseDirectives(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := PackageConfig{Provider: "github:BurntSushi/ripgrep", Channel: "stable", IgnoreRegex: ".*rc.*", MinSeverity: "minor", Interval: "24h"}
+	if c.Provider != expected.Provider || c.Channel != expected.Channel || c.IgnoreRegex != expected.IgnoreRegex || c.MinSeverity != expected.MinSeverity || c.Interval != expected.Interval {
+		t.Errorf("Expecting %v, but got %v", expected, c)
+	}
+
+	for _, invalid := range []string{
+		"# aur-out-of-date: provider",
+		"# aur-out-of-date: provider=unknown:foo",
+		"# aur-out-of-date: ignore=(",
+		"# aur-out-of-date: min_severity=huge",
+		"# aur-out-of-date: interval=soon",
+		"# aur-out-of-date: color=blue",
+		"# aur-out-of-date: fallback=default,bogus",
+		"# aur-out-of-date: skip until=soon",
+	} {
+		if _, err := ParseDirectives(invalid); err == nil {
+			t.Errorf("Expecting error for %q", invalid)
+		}
+	}
+}
+
+func TestPackageDirectives(t *testing.T) {
+	conf := Config{Packages: map[string]PackageConfig{"foo": {Channel: "prerelease"}}}
+	conf.SetDirectives("foo", PackageConfig{Channel: "stable", IgnoreRegex: ".*rc.*"})
+	if c := conf.Package("foo"); c.Channel != "prerelease" || c.IgnoreRegex != ".*rc.*" {
+		t.Errorf("Expecting packages to take precedence over directives, but got %v", c)
+	}
+	if !conf.IsIgnored("foo", "2.0rc1") || conf.IsIgnored("foo", "2.0") {
+		t.Errorf("Expecting 2.0rc1 to be ignored by the directive")
+	}
+}
+
+func TestFallbackChain(t *testing.T) {
+	conf := Config{
+		Fallback: []string{"default", "anitya"},
+		Packages: map[string]PackageConfig{"foo": {Fallback: []string{"github-tags"}}},
+	}
+	d, err := ParseDirectives("# aur-out-of-date: fallback=default,github-tags,scrape\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf.SetDirectives("foo", d)
+	conf.SetDirectives("bar", d)
+	if chain := conf.FallbackChain("foo"); len(chain) != 1 || chain[0] != "github-tags" {
+		t.Errorf("Expecting the configured chain, but got %v", chain)
+	}
+	if chain := conf.FallbackChain("bar"); len(chain) != 3 || chain[2] != "scrape" {
+		t.Errorf("Expecting the chain of the directive, but got %v", chain)
+	}
+	if chain := conf.FallbackChain("baz"); len(chain) != 2 || chain[1] != "anitya" {
+		t.Errorf("Expecting the global chain, but got %v", chain)
+	}
+}
+
+func TestSkipDirective(t *testing.T) {
+	c, err := ParseDirectives("# aur-out-of-date: skip until=2025-06-30 pinned to 1.x for ABI compatibility\n")
+	if err != nil {
+		t.Fatal(err)
+	} else if c.Skip == nil || c.Skip.Until != "2025-06-30" || c.Skip.Reason != "pinned to 1.x for ABI compatibility" {
+		t.Errorf("Expecting skip until 2025-06-30, but got %v", c.Skip)
+	}
+	conf := Config{}
+	conf.SetDirectives("foo", c)
+	if skip, err := conf.Skip("foo", time.Date(2025, 6, 30, 23, 0, 0, 0, time.UTC)); err != nil || skip == nil {
+		t.Errorf("Expecting foo to be skipped on 2025-06-30, but got %v (%v)", skip, err)
+	} else if skip.Message() != "skipped until 2025-06-30 (pinned to 1.x for ABI compatibility)" {
+		t.Errorf("Unexpected message %q", skip.Message())
+	}
+	if skip, err := conf.Skip("foo", time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)); err != nil || skip != nil {
+		t.Errorf("Expecting the skip of foo to expire, but got %v (%v)", skip, err)
+	}
+
+	c, err = ParseDirectives("# aur-out-of-date: skip\n")
+	if err != nil || c.Skip == nil || c.Skip.Message() != "skipped" {
+		t.Errorf("Expecting skip without reason, but got %v (%v)", c.Skip, err)
+	}
+	conf = Config{Packages: map[string]PackageConfig{"bar": {Skip: &Skip{Until: "tomorrow"}}}}
+	if _, err := conf.Skip("bar", time.Now()); err == nil {
+		t.Error("Expecting an error for an invalid date")
+	}
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	pkgbuild "github.com/mikkeloscar/gopkgbuild"
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+// IgnoreRule ignores all upstream versions of a package (empty Operator) or those satisfying the constraint, e.g. <= 2.5
+type IgnoreRule struct {
+	Operator string
+	Version  upstream.Version
+}
+
+// Matches determines whether the version is to be ignored
+func (r IgnoreRule) Matches(version upstream.Version) bool {
+	if r.Operator == "" {
+		return true
+	} else if r.Operator == "=" {
+		return version.String() == r.Version.String()
+	}
+	v, err := pkgbuild.NewCompleteVersion(version.String())
+	if err != nil {
+		return false
+	}
+	limit, err := pkgbuild.NewCompleteVersion(r.Version.String())
+	if err != nil {
+		return false
+	}
+	switch r.Operator {
+	case "<":
+		return limit.Newer(v)
+	case "<=":
+		return !v.Newer(limit)
+	}
+	return false
+}
+
+// AppendIgnoreRule adds the rule for the package to the ignore file, creating it (and its directory) as needed
+func AppendIgnoreRule(filename string, pkg string, rule IgnoreRule) error {
+	if err := os.MkdirAll(path.Dir(filename), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	line := pkg
+	if rule.Operator != "" {
+		line += " " + rule.Operator + " " + rule.Version.String()
+	}
+	if _, err := fmt.Fprintln(f, line); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+var ignoreRuleRegexp = regexp.MustCompile(`^([^\s<=]+)\s*(?:(<=|<|==|=)\s*(\S+))?$`)
+
+// FromIgnoreFile reads the ignore rules per package from the file, none if it does not exist.
+// Each line holds a package name, optionally followed by a constraint (foo <= 2.5, foo < 3, foo = 2.5); # starts a comment.
+func FromIgnoreFile(filename string) (map[string][]IgnoreRule, error) {
+	rules := map[string][]IgnoreRule{}
+	f, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return rules, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.Index(text, "#"); i >= 0 {
+			text = text[:i]
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		match := ignoreRuleRegexp.FindStringSubmatch(text)
+		if match == nil {
+			return nil, fmt.Errorf("Failed to parse %s:%d: %s", filename, line, text)
+		}
+		operator := match[2]
+		if operator == "==" {
+			operator = "="
+		}
+		rules[match[1]] = append(rules[match[1]], IgnoreRule{Operator: operator, Version: upstream.Version(match[3])})
+	}
+	return rules, scanner.Err()
+}
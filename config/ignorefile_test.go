@@ -0,0 +1,69 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+func TestFromIgnoreFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := path.Join(dir, "ignore")
+	content := `# permanently ignored
+foo
+bar <= 2.5 # snoozed until the next release
+baz<3
+qux == 1.0-beta
+`
+	if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rules, err := FromIgnoreFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := Config{IgnoreRules: rules}
+	for _, c := range []struct {
+		pkg     string
+		version upstream.Version
+		ignored bool
+	}{
+		{"foo", "1.0", true},
+		{"bar", "2.4", true},
+		{"bar", "2.5", true},
+		{"bar", "2.5.1", false},
+		{"baz", "2.9", true},
+		{"baz", "3", false},
+		{"qux", "1.0-beta", true},
+		{"qux", "1.0", false},
+		{"other", "1.0", false},
+	} {
+		if ignored := conf.IsIgnored(c.pkg, c.version); ignored != c.ignored {
+			t.Errorf("Expecting IsIgnored(%s, %s) to be %v", c.pkg, c.version, c.ignored)
+		}
+	}
+
+	if err := AppendIgnoreRule(filename, "quux", IgnoreRule{"<=", "1.1"}); err != nil {
+		t.Fatal(err)
+	}
+	if rules, err := FromIgnoreFile(filename); err != nil || len(rules["quux"]) != 1 || rules["quux"][0].Version != "1.1" {
+		t.Errorf("Expecting appended rule quux <= 1.1, but got %v (%v)", rules["quux"], err)
+	}
+
+	if err := ioutil.WriteFile(filename, []byte("foo >= 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FromIgnoreFile(filename); err == nil {
+		t.Errorf("Expecting an error for unsupported operator")
+	}
+	if rules, err := FromIgnoreFile(path.Join(dir, "missing")); err != nil || len(rules) != 0 {
+		t.Errorf("Expecting no rules for missing file, but got %v (%v)", rules, err)
+	}
+}
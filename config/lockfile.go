@@ -0,0 +1,117 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	pkgbuild "github.com/mikkeloscar/gopkgbuild"
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+// Lockfile holds the last-known upstream versions (one "name = version" per line), so that only newer versions are reported
+type Lockfile struct {
+	filename string
+	locked   map[string]upstream.Version
+	observed map[string]upstream.Version
+	mutex    sync.Mutex
+}
+
+// ReadLockfile reads the lockfile, a missing file locking no versions
+func ReadLockfile(filename string) (*Lockfile, error) {
+	l := &Lockfile{filename: filename, locked: map[string]upstream.Version{}, observed: map[string]upstream.Version{}}
+	f, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return l, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.Index(text, "#"); i >= 0 {
+			text = text[:i]
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		parts := strings.SplitN(text, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+			return nil, fmt.Errorf("Failed to parse %s:%d: %s", filename, line, text)
+		}
+		l.locked[strings.TrimSpace(parts[0])] = upstream.Version(strings.TrimSpace(parts[1]))
+	}
+	return l, scanner.Err()
+}
+
+// Locked determines whether the upstream version of the package is not newer than the locked one
+func (l *Lockfile) Locked(pkg string, version upstream.Version) bool {
+	if l == nil {
+		return false
+	}
+	l.mutex.Lock()
+	locked, ok := l.locked[pkg]
+	l.mutex.Unlock()
+	if !ok {
+		return false
+	} else if version.String() == locked.String() {
+		return true
+	}
+	v, err := pkgbuild.NewCompleteVersion(version.String())
+	if err != nil {
+		return false
+	}
+	limit, err := pkgbuild.NewCompleteVersion(locked.String())
+	if err != nil {
+		return false
+	}
+	return !v.Newer(limit)
+}
+
+// Observe records the upstream version of the package found in this run, written using Write
+func (l *Lockfile) Observe(pkg string, version upstream.Version) {
+	if l == nil {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.observed[pkg] = version
+}
+
+// Write replaces the lockfile by the locked versions updated to the observed ones, sorted by package name
+func (l *Lockfile) Write() error {
+	if l == nil {
+		return nil
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for pkg, version := range l.observed {
+		l.locked[pkg] = version
+	}
+	var names []string
+	for pkg := range l.locked {
+		names = append(names, pkg)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString("# Upstream versions accepted by aur-out-of-date -update-lock\n")
+	for _, pkg := range names {
+		fmt.Fprintf(&b, "%s = %s\n", pkg, l.locked[pkg])
+	}
+	if dir := path.Dir(l.filename); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	tmp := l.filename + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(b.String()), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, l.filename)
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+func TestLockfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := path.Join(dir, "versions.lock")
+
+	l, err := ReadLockfile(filename)
+	if err != nil {
+		t.Fatal(err)
+	} else if l.Locked("foo", "1.0") {
+		t.Error("Expecting no locked versions without lockfile")
+	}
+	if err := ioutil.WriteFile(filename, []byte("# comment\nfoo = 1.2\nbar = v2.0 # accepted\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if l, err = ReadLockfile(filename); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		pkg, version string
+		locked       bool
+	}{
+		{"foo", "1.2", true},
+		{"foo", "1.1", true},
+		{"foo", "1.3", false},
+		{"bar", "v2.0", true},
+		{"bar", "2.1", false},
+		{"baz", "1.0", false},
+	} {
+		if l.Locked(test.pkg, upstream.Version(test.version)) != test.locked {
+			t.Errorf("Expecting %s %s locked=%v", test.pkg, test.version, test.locked)
+		}
+	}
+
+	l.Observe("foo", "1.3")
+	l.Observe("baz", "0.9")
+	if err := l.Write(); err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "# Upstream versions accepted by aur-out-of-date -update-lock\nbar = 2.0\nbaz = 0.9\nfoo = 1.3\n"
+	if string(content) != expected {
+		t.Errorf("Expecting %q, but got %q", expected, content)
+	}
+
+	if err := ioutil.WriteFile(filename, []byte("foo 1.2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadLockfile(filename); err == nil {
+		t.Error("Expecting an error for an invalid line")
+	}
+	var missing *Lockfile
+	if missing.Locked("foo", "1.0") || missing.Write() != nil {
+		t.Error("Expecting a nil lockfile to lock nothing")
+	}
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+// FromNvchecker reads the package entries of an nvchecker TOML configuration
+func FromNvchecker(filename string) (map[string]upstream.NvcheckerEntry, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decodeTOML(filename, data)
+	if err != nil {
+		return nil, err
+	}
+	entries := map[string]upstream.NvcheckerEntry{}
+	for name, value := range doc {
+		entry, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Failed to parse %s: %s is not a table", filename, name)
+		} else if name != "__config__" {
+			entries[name] = entry
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+func TestFromNvchecker(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nvchecker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := path.Join(dir, "nvchecker.toml")
+	toml := `[__config__]
+oldver = "old_ver.json"
+
+# comment
+[nvchecker]
+source = "github" # trailing comment
+github = "lilydjwg/nvchecker"
+use_max_tag = true
+
+["foo-bin"]
+source = 'regex'
+url = "https://example.org/#download"
+regex = 'foo-([\d.]+)\.tar\.gz'
+exclude_regex = ["rc", "beta"]
+`
+	if err := ioutil.WriteFile(filename, []byte(toml), 0644); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := FromNvchecker(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]upstream.NvcheckerEntry{
+		"nvchecker": {"source": "github", "github": "lilydjwg/nvchecker", "use_max_tag": true},
+		"foo-bin": {"source": "regex", "url": "https://example.org/#download", "regex": `foo-([\d.]+)\.tar\.gz`,
+			"exclude_regex": []interface{}{"rc", "beta"}},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Expecting %v, but got %v", expected, entries)
+	}
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+// FromProviderFile reads the provider mapping from the file, none if it does not exist.
+// Each line pins a package to a provider identifier (ripgrep = github:BurntSushi/ripgrep, see upstream.ParseIdentifier); # starts a comment.
+func FromProviderFile(filename string) (map[string]upstream.NvcheckerEntry, error) {
+	entries := map[string]upstream.NvcheckerEntry{}
+	f, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return entries, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.Index(text, "#"); i >= 0 {
+			text = text[:i]
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		parts := strings.SplitN(text, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("Failed to parse %s:%d: %s", filename, line, text)
+		}
+		e, err := upstream.ParseIdentifier(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse %s:%d: %w", filename, line, err)
+		}
+		entries[strings.TrimSpace(parts[0])] = e
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestFromProviderFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "providers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := path.Join(dir, "providers")
+	content := `# pinned providers
+ripgrep = github:BurntSushi/ripgrep
+yt-dlp=pypi:yt-dlp # mirrored on GitHub
+`
+	if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := FromProviderFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries["ripgrep"]["github"] != "BurntSushi/ripgrep" || entries["yt-dlp"]["pypi"] != "yt-dlp" {
+		t.Errorf("Unexpected entries %v", entries)
+	}
+
+	if err := ioutil.WriteFile(filename, []byte("ripgrep = BurntSushi/ripgrep\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FromProviderFile(filename); err == nil {
+		t.Errorf("Expecting an error for missing provider")
+	}
+}
@@ -0,0 +1,566 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// decodeTOML parses a TOML document into nested maps: tables as map[string]interface{}, arrays as []interface{},
+// strings, booleans, integers as int64, floats as float64, and dates and times as strings
+func decodeTOML(filename string, data []byte) (map[string]interface{}, error) {
+	p := &tomlParser{input: string(data), kinds: map[string]tableKind{}}
+	root, err := p.document()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse %s:%d: %w", filename, p.line(), err)
+	}
+	return root, nil
+}
+
+// tomlParser parses a TOML document, see https://toml.io/en/v1.0.0
+type tomlParser struct {
+	input string
+	pos   int
+	// kinds of the tables and arrays by path, implicitTable if missing
+	kinds map[string]tableKind
+}
+
+func (p *tomlParser) line() int {
+	return strings.Count(p.input[:p.pos], "\n") + 1
+}
+
+func (p *tomlParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *tomlParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *tomlParser) consume(prefix string) bool {
+	if strings.HasPrefix(p.input[p.pos:], prefix) {
+		p.pos += len(prefix)
+		return true
+	}
+	return false
+}
+
+func (p *tomlParser) expect(s string) error {
+	if !p.consume(s) {
+		return fmt.Errorf("Expecting %q", s)
+	}
+	return nil
+}
+
+// skipSpaces skips spaces and tabs
+func (p *tomlParser) skipSpaces() {
+	for c := p.peek(); c == ' ' || c == '\t'; c = p.peek() {
+		p.pos++
+	}
+}
+
+// skipComment skips a # comment up to the end of the line
+func (p *tomlParser) skipComment() {
+	if p.peek() == '#' {
+		for !p.eof() && p.peek() != '\n' {
+			p.pos++
+		}
+	}
+}
+
+// skipBlank skips whitespace, newlines and comments
+func (p *tomlParser) skipBlank() {
+	for {
+		p.skipSpaces()
+		p.skipComment()
+		if !p.consume("\n") && !p.consume("\r\n") {
+			return
+		}
+	}
+}
+
+// endOfLine requires the rest of the line to be blank or a comment
+func (p *tomlParser) endOfLine() error {
+	p.skipSpaces()
+	p.skipComment()
+	if p.eof() || p.consume("\n") || p.consume("\r\n") {
+		return nil
+	}
+	return fmt.Errorf("Unexpected %q", p.rest())
+}
+
+// rest returns the remainder of the current line for error messages
+func (p *tomlParser) rest() string {
+	rest := p.input[p.pos:]
+	if i := strings.IndexByte(rest, '\n'); i >= 0 {
+		rest = rest[:i]
+	}
+	return rest
+}
+
+func (p *tomlParser) document() (map[string]interface{}, error) {
+	root := map[string]interface{}{}
+	current, path := root, ""
+	for p.skipBlank(); !p.eof(); p.skipBlank() {
+		var err error
+		switch {
+		case p.consume("[["):
+			current, path, err = p.tableHeader(root, "]]", true)
+		case p.consume("["):
+			current, path, err = p.tableHeader(root, "]", false)
+		default:
+			err = p.keyValue(current, path)
+		}
+		if err == nil {
+			err = p.endOfLine()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return root, nil
+}
+
+// tableKind tells how a table or array has been defined, which determines whether it may be extended
+type tableKind int
+
+const (
+	// implicitTable is created by a header of one of its sub-tables and may be defined by a header later
+	implicitTable tableKind = iota
+	// headerTable is defined by a [table] header
+	headerTable
+	// dottedTable is created by a dotted key and may be extended by further dotted keys
+	dottedTable
+	// tableArray is created by [[array of tables]] headers
+	tableArray
+	// inlineValue is an inline table or array, which cannot be extended at all
+	inlineValue
+)
+
+// childPath returns the path of the key in the table at path, used to look up the tableKind
+func childPath(path string, key string) string {
+	return path + "." + strconv.Quote(key)
+}
+
+// tableHeader parses the keys of [table] or [[array of tables]] and returns the table to add the following keys to
+func (p *tomlParser) tableHeader(root map[string]interface{}, end string, array bool) (map[string]interface{}, string, error) {
+	p.skipSpaces()
+	keys, err := p.key()
+	if err != nil {
+		return nil, "", err
+	}
+	if err := p.expect(end); err != nil {
+		return nil, "", err
+	}
+	parent, path, err := p.descend(root, "", keys[:len(keys)-1], true)
+	if err != nil {
+		return nil, "", err
+	}
+	name := keys[len(keys)-1]
+	path = childPath(path, name)
+	table := map[string]interface{}{}
+	switch existing := parent[name].(type) {
+	case nil:
+		if array {
+			parent[name] = []interface{}{table}
+			p.kinds[path] = tableArray
+			path += "[0]"
+		} else {
+			parent[name] = table
+		}
+	case []interface{}:
+		if !array || p.kinds[path] != tableArray {
+			return nil, "", fmt.Errorf("Duplicate key %s", strings.Join(keys, "."))
+		}
+		parent[name] = append(existing, table)
+		path += "[" + strconv.Itoa(len(existing)) + "]"
+	case map[string]interface{}:
+		if array || p.kinds[path] != implicitTable {
+			return nil, "", fmt.Errorf("Duplicate key %s", strings.Join(keys, "."))
+		}
+		// a table implicitly created by a dotted header such as [a.b]
+		table = existing
+	default:
+		return nil, "", fmt.Errorf("Duplicate key %s", strings.Join(keys, "."))
+	}
+	p.kinds[path] = headerTable
+	return table, path, nil
+}
+
+// descend returns the table at the keys along with its path, creating missing tables. Headers may descend into any
+// table but inline ones, using the last table of arrays of tables, whereas dotted keys may only extend dotted tables.
+func (p *tomlParser) descend(table map[string]interface{}, path string, keys []string, header bool) (map[string]interface{}, string, error) {
+	for _, key := range keys {
+		path = childPath(path, key)
+		kind := p.kinds[path]
+		switch existing := table[key].(type) {
+		case nil:
+			child := map[string]interface{}{}
+			table[key] = child
+			table = child
+			if !header {
+				p.kinds[path] = dottedTable
+			}
+		case map[string]interface{}:
+			if kind == inlineValue || !header && kind != dottedTable {
+				return nil, "", fmt.Errorf("Cannot extend table %s", key)
+			}
+			table = existing
+		case []interface{}:
+			if kind != tableArray || !header {
+				return nil, "", fmt.Errorf("Cannot extend array %s", key)
+			}
+			path += "[" + strconv.Itoa(len(existing)-1) + "]"
+			table = existing[len(existing)-1].(map[string]interface{})
+		default:
+			return nil, "", fmt.Errorf("Key %s is not a table", key)
+		}
+	}
+	return table, path, nil
+}
+
+// keyValue parses key = value into the table at path
+func (p *tomlParser) keyValue(table map[string]interface{}, path string) error {
+	keys, err := p.key()
+	if err != nil {
+		return err
+	}
+	if err := p.expect("="); err != nil {
+		return err
+	}
+	p.skipSpaces()
+	parent, path, err := p.descend(table, path, keys[:len(keys)-1], false)
+	if err != nil {
+		return err
+	}
+	name := keys[len(keys)-1]
+	if _, ok := parent[name]; ok {
+		return fmt.Errorf("Duplicate key %s", strings.Join(keys, "."))
+	}
+	path = childPath(path, name)
+	value, err := p.value(path)
+	if err != nil {
+		return err
+	}
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		p.kinds[path] = inlineValue
+	}
+	parent[name] = value
+	return nil
+}
+
+var bareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+`)
+
+// key parses a bare, quoted or dotted key along with the following spaces
+func (p *tomlParser) key() ([]string, error) {
+	var keys []string
+	for {
+		var key string
+		var err error
+		switch p.peek() {
+		case '"':
+			key, err = p.basicString()
+		case '\'':
+			key, err = p.literalString()
+		default:
+			key = bareKey.FindString(p.input[p.pos:])
+			if key == "" {
+				return nil, fmt.Errorf("Invalid key %q", p.rest())
+			}
+			p.pos += len(key)
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		p.skipSpaces()
+		if !p.consume(".") {
+			return keys, nil
+		}
+		p.skipSpaces()
+	}
+}
+
+// value parses the value of the key at path
+func (p *tomlParser) value(path string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(p.input[p.pos:], `"""`):
+		return p.multilineBasicString()
+	case p.peek() == '"':
+		return p.basicString()
+	case strings.HasPrefix(p.input[p.pos:], "'''"):
+		return p.multilineLiteralString()
+	case p.peek() == '\'':
+		return p.literalString()
+	case p.consume("["):
+		return p.array(path)
+	case p.consume("{"):
+		return p.inlineTable(path)
+	}
+	return p.scalar()
+}
+
+func (p *tomlParser) array(path string) (interface{}, error) {
+	values := []interface{}{}
+	for {
+		p.skipBlank()
+		if p.consume("]") {
+			return values, nil
+		}
+		value, err := p.value(path + "[" + strconv.Itoa(len(values)) + "]")
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		p.skipBlank()
+		if p.consume("]") {
+			return values, nil
+		} else if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *tomlParser) inlineTable(path string) (interface{}, error) {
+	table := map[string]interface{}{}
+	p.skipSpaces()
+	if p.consume("}") {
+		return table, nil
+	}
+	for {
+		p.skipSpaces()
+		if err := p.keyValue(table, path); err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if p.consume("}") {
+			return table, nil
+		} else if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+var (
+	tomlDate     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	tomlTime     = regexp.MustCompile(`^\d{2}:\d{2}:\d{2}`)
+	tomlDateTime = regexp.MustCompile(`^(?:\d{4}-\d{2}-\d{2}(?:[Tt ]\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:[Zz]|[+-]\d{2}:\d{2})?)?|\d{2}:\d{2}:\d{2}(?:\.\d+)?)$`)
+	// underscores are only allowed between digits
+	tomlInteger = regexp.MustCompile(`^[+-]?(?:0|[1-9](?:_?\d)*)$`)
+	tomlHex     = regexp.MustCompile(`^0x[0-9A-Fa-f](?:_?[0-9A-Fa-f])*$`)
+	tomlOctal   = regexp.MustCompile(`^0o[0-7](?:_?[0-7])*$`)
+	tomlBinary  = regexp.MustCompile(`^0b[01](?:_?[01])*$`)
+	tomlFloat   = regexp.MustCompile(`^[+-]?(?:0|[1-9](?:_?\d)*)(?:\.\d(?:_?\d)*)?(?:[eE][+-]?\d(?:_?\d)*)?$`)
+)
+
+// scalar parses booleans, numbers, dates and times
+func (p *tomlParser) scalar() (interface{}, error) {
+	end := p.tokenEnd(p.pos)
+	if tomlDate.MatchString(p.input[p.pos:end]) && strings.HasPrefix(p.input[end:], " ") && tomlTime.MatchString(p.input[end+1:]) {
+		// date and time separated by a space, such as 1979-05-27 07:32:00
+		end = p.tokenEnd(end + 1)
+	}
+	token := p.input[p.pos:end]
+	p.pos = end
+	number := strings.Replace(token, "_", "", -1)
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("Missing value")
+	case token == "true":
+		return true, nil
+	case token == "false":
+		return false, nil
+	case token == "inf" || token == "+inf":
+		return math.Inf(1), nil
+	case token == "-inf":
+		return math.Inf(-1), nil
+	case token == "nan" || token == "+nan" || token == "-nan":
+		return math.NaN(), nil
+	case tomlDateTime.MatchString(token):
+		return token, nil
+	case tomlInteger.MatchString(token):
+		return strconv.ParseInt(number, 10, 64)
+	case tomlHex.MatchString(token):
+		return strconv.ParseInt(number[2:], 16, 64)
+	case tomlOctal.MatchString(token):
+		return strconv.ParseInt(number[2:], 8, 64)
+	case tomlBinary.MatchString(token):
+		return strconv.ParseInt(number[2:], 2, 64)
+	case tomlFloat.MatchString(token):
+		return strconv.ParseFloat(number, 64)
+	}
+	return nil, fmt.Errorf("Invalid value %q", token)
+}
+
+// tokenEnd returns the position of the end of the unquoted value starting at from
+func (p *tomlParser) tokenEnd(from int) int {
+	if i := strings.IndexAny(p.input[from:], ",]}# \t\r\n"); i >= 0 {
+		return from + i
+	}
+	return len(p.input)
+}
+
+func (p *tomlParser) literalString() (string, error) {
+	p.pos++
+	end := strings.IndexAny(p.input[p.pos:], "'\n")
+	if end < 0 || p.input[p.pos+end] != '\'' {
+		return "", fmt.Errorf("Unterminated string")
+	}
+	s := p.input[p.pos : p.pos+end]
+	p.pos += end + 1
+	return s, nil
+}
+
+func (p *tomlParser) multilineLiteralString() (string, error) {
+	p.pos += 3
+	p.consume("\n")
+	p.consume("\r\n")
+	end := strings.Index(p.input[p.pos:], "'''")
+	if end < 0 {
+		return "", fmt.Errorf("Unterminated string")
+	}
+	// up to two quotes are allowed right before the closing delimiter
+	for strings.HasPrefix(p.input[p.pos+end+1:], "'''") {
+		end++
+	}
+	s := p.input[p.pos : p.pos+end]
+	p.pos += end + 3
+	return s, nil
+}
+
+func (p *tomlParser) basicString() (string, error) {
+	p.pos++
+	var b strings.Builder
+	for {
+		switch c := p.peek(); {
+		case p.eof() || c == '\n':
+			return "", fmt.Errorf("Unterminated string")
+		case c == '"':
+			p.pos++
+			return b.String(), nil
+		case c == '\\':
+			if err := p.escape(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+func (p *tomlParser) multilineBasicString() (string, error) {
+	p.pos += 3
+	p.consume("\n")
+	p.consume("\r\n")
+	var b strings.Builder
+	for {
+		switch c := p.peek(); {
+		case p.eof():
+			return "", fmt.Errorf("Unterminated string")
+		case strings.HasPrefix(p.input[p.pos:], `"""`) && !strings.HasPrefix(p.input[p.pos+1:], `"""`):
+			p.pos += 3
+			return b.String(), nil
+		case c == '\\' && p.lineEndingBackslash():
+			// trims the newline along with the whitespace up to the next non-whitespace character
+			p.pos++
+			for c := p.peek(); c == ' ' || c == '\t' || c == '\r' || c == '\n'; c = p.peek() {
+				p.pos++
+			}
+		case c == '\\':
+			if err := p.escape(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+// lineEndingBackslash reports whether the backslash at the current position is only followed by whitespace on its line
+func (p *tomlParser) lineEndingBackslash() bool {
+	rest := p.input[p.pos+1:]
+	if i := strings.IndexByte(rest, '\n'); i >= 0 {
+		return strings.TrimSpace(rest[:i]) == ""
+	}
+	return false
+}
+
+// escape decodes the escape sequence at the current position
+func (p *tomlParser) escape(b *strings.Builder) error {
+	if p.pos+1 >= len(p.input) {
+		return fmt.Errorf("Unterminated string")
+	}
+	c := p.input[p.pos+1]
+	p.pos += 2
+	switch c {
+	case 'b':
+		b.WriteByte('\b')
+	case 't':
+		b.WriteByte('\t')
+	case 'n':
+		b.WriteByte('\n')
+	case 'f':
+		b.WriteByte('\f')
+	case 'r':
+		b.WriteByte('\r')
+	case '"', '\\':
+		b.WriteByte(c)
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		if p.pos+n > len(p.input) {
+			return fmt.Errorf("Invalid escape sequence \\%c", c)
+		}
+		r, err := strconv.ParseUint(p.input[p.pos:p.pos+n], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(r)) {
+			return fmt.Errorf("Invalid escape sequence \\%c%s", c, p.input[p.pos:p.pos+n])
+		}
+		b.WriteRune(rune(r))
+		p.pos += n
+	default:
+		return fmt.Errorf("Invalid escape sequence \\%c", c)
+	}
+	return nil
+}
+
+// TOMLKey returns the key bare if possible, quoted otherwise, e.g. "c++-lib"
+func TOMLKey(key string) string {
+	if bareKey.FindString(key) == key && key != "" {
+		return key
+	}
+	return TOMLString(key)
+}
+
+// TOMLString returns the string as TOML basic string
+func TOMLString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\u%04X`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeTOML(t *testing.T) {
+	doc, err := decodeTOML("config.toml", []byte(`# comment
+title = "aur-out-of-date" # trailing comment
+jobs = 1_000
+ratio = 0.5
+enabled = true
+until = 2025-06-30
+ignore = [
+  "2.0-rc1", # comment
+  '3.0.0-beta',
+]
+escaped = "tab\tquote\" \u00e9"
+literal = 'C:\path'
+multiline = """
+foo \
+  bar"""
+raw = '''
+^v(.+)$'''
+
+[packages."c++-lib"]
+url = "https://example.com/c++-lib"
+skip = { reason = "pinned", until = "2025-06-30" }
+
+[packages.foo]
+max.version = "2.4.1"
+
+[[notify.webhook]]
+url = "https://example.com/a"
+
+[[notify.webhook]]
+url = "https://example.com/b"
+
+[a.b]
+hex = 0xdead_beef
+[a]
+c.d = 1.5e1_0
+[a.c.e]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"title":     "aur-out-of-date",
+		"jobs":      int64(1000),
+		"ratio":     0.5,
+		"enabled":   true,
+		"until":     "2025-06-30",
+		"ignore":    []interface{}{"2.0-rc1", "3.0.0-beta"},
+		"escaped":   "tab\tquote\" é",
+		"literal":   `C:\path`,
+		"multiline": "foo bar",
+		"raw":       "^v(.+)$",
+		"packages": map[string]interface{}{
+			"c++-lib": map[string]interface{}{
+				"url":  "https://example.com/c++-lib",
+				"skip": map[string]interface{}{"reason": "pinned", "until": "2025-06-30"},
+			},
+			"foo": map[string]interface{}{"max": map[string]interface{}{"version": "2.4.1"}},
+		},
+		"notify": map[string]interface{}{
+			"webhook": []interface{}{
+				map[string]interface{}{"url": "https://example.com/a"},
+				map[string]interface{}{"url": "https://example.com/b"},
+			},
+		},
+		"a": map[string]interface{}{
+			"b": map[string]interface{}{"hex": int64(0xdeadbeef)},
+			"c": map[string]interface{}{"d": 1.5e10, "e": map[string]interface{}{}},
+		},
+	}
+	if !reflect.DeepEqual(doc, expected) {
+		t.Errorf("Expecting %v, but got %v", expected, doc)
+	}
+}
+
+func TestDecodeTOMLErrors(t *testing.T) {
+	for _, input := range []string{
+		"foo = \"unterminated\n",
+		"foo = 1\nfoo = 2\n",
+		"foo = bar\n",
+		"foo = 1 2\n",
+		"[foo\n",
+		"= 1\n",
+		"[a]\nx = 1\n[a]\ny = 2\n",
+		"[a]\nb.c = 1\n[a.b]\n",
+		"[a.b]\nc = 1\n[a]\nb.d = 2\n",
+		"x = {a = 1}\n[x.b]\n",
+		"x = {a = 1}\nx.b = 2\n",
+		"x = [{a = 1}]\n[[x]]\n",
+		"[[x]]\n[x]\n",
+		"x = 1__2\n",
+		"x = _12\n",
+		"x = 12_\n",
+		"x = 0x_ff\n",
+		"x = 1._5\n",
+	} {
+		if _, err := decodeTOML("config.toml", []byte(input)); err == nil {
+			t.Errorf("Expecting an error for %q", input)
+		}
+	}
+}
+
+func TestTOMLKey(t *testing.T) {
+	for key, expected := range map[string]string{
+		"ripgrep":     "ripgrep",
+		"python-foo":  "python-foo",
+		"c++-lib":     `"c++-lib"`,
+		"foo.bar":     `"foo.bar"`,
+		`say "hello"`: `"say \"hello\""`,
+	} {
+		if actual := TOMLKey(key); actual != expected {
+			t.Errorf("Expecting %s, but got %s", expected, actual)
+		}
+	}
+}
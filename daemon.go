@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/logging"
+	"github.com/simon04/aur-out-of-date/systemd"
+)
+
+// jitter returns the interval randomly varied by up to 10%, so that several instances do not check in lockstep
+func jitter(interval time.Duration) time.Duration {
+	if interval <= 0 {
+		return interval
+	}
+	return interval + time.Duration((rand.Float64()*0.2-0.1)*float64(interval))
+}
+
+// repeat calls check every -interval (with jitter) until interrupted, notifying systemd about readiness and status.
+// If reload receives, the config is reloaded and the packages are checked immediately.
+func repeat(check func(), reload <-chan struct{}) {
+	periodic = true
+	go systemd.Watchdog(interrupted.Done())
+	// the first check of many packages may take longer than the start timeout of systemd
+	systemd.Notify("READY=1\nSTATUS=Checking packages")
+	for {
+		check()
+		systemd.Notify(fmt.Sprintf("STATUS=Checked %d packages, %d out-of-date, %d errors",
+			statistics.Total(), statistics.OutOfDate, checkErrors))
+		d := jitter(checkInterval())
+		logging.Infof("Next check in %s", d.Round(time.Second))
+		wait(d, reload)
+	}
+}
+
+// spreader delays the start of each check (with jitter), spreading the checks of a run over a fraction of the interval, see -spread
+type spreader struct {
+	mutex   sync.Mutex
+	next    time.Time
+	spacing time.Duration
+}
+
+// newSpreader spaces the checks of the given number of packages, returns nil unless in daemon or watch mode with -spread
+func newSpreader(packages int) *spreader {
+	if !periodic || commandline.spread <= 0 || packages == 0 {
+		return nil
+	}
+	window := time.Duration(commandline.spread * float64(checkInterval()))
+	return &spreader{next: time.Now(), spacing: window / time.Duration(packages)}
+}
+
+// wait blocks until the next check may start, returning false if ctx is cancelled before
+func (s *spreader) wait(ctx context.Context) bool {
+	if s == nil {
+		return true
+	}
+	s.mutex.Lock()
+	start := s.next
+	s.next = s.next.Add(jitter(s.spacing))
+	s.mutex.Unlock()
+	timer := time.NewTimer(time.Until(start))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// checkInterval returns -interval, or the shortest interval configured per package or provider if shorter
+func checkInterval() time.Duration {
+	if min := conf.MinInterval(); min > 0 && min < commandline.interval {
+		return min
+	}
+	return commandline.interval
+}
+
+// wait sleeps for the duration or until the config has been reloaded successfully, exits if interrupted
+func wait(d time.Duration, reload <-chan struct{}) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			return
+		case <-reload:
+			systemd.Notify("RELOADING=1")
+			err := reloadConfig()
+			systemd.Notify("READY=1")
+			if err == nil {
+				return
+			}
+			logging.Errorf("%v", err)
+		case <-interrupted.Done():
+			systemd.Notify("STOPPING=1")
+			os.Exit(0)
+		}
+	}
+}
+
+// daemon periodically checks all packages, printing the results and driving notifications
+func daemon() {
+	repeat(func() {
+		if f, err := newFormatter(os.Stdout, commandline.output); err != nil {
+			logging.Errorf("Failed to create formatter: %v", err)
+		} else {
+			formatter = f
+		}
+		run(commandline.printStatistics)
+	}, watch(5*time.Second, configFiles()...))
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"io"
+	"os"
+)
+
+// optionalFile is a flag optionally taking a file name, - (stderr) if given without value
+type optionalFile struct {
+	value *string
+}
+
+func (f optionalFile) String() string {
+	if f.value == nil {
+		return ""
+	}
+	return *f.value
+}
+
+func (f optionalFile) Set(s string) error {
+	switch s {
+	case "true":
+		*f.value = "-"
+	case "false":
+		*f.value = ""
+	default:
+		*f.value = s
+	}
+	return nil
+}
+
+func (f optionalFile) IsBoolFlag() bool {
+	return true
+}
+
+// debugHTTPWriter returns the destination of -debug-http, nil if disabled
+func debugHTTPWriter() (io.Writer, error) {
+	switch commandline.debugHTTP {
+	case "":
+		return nil, nil
+	case "-":
+		return os.Stderr, nil
+	}
+	return os.OpenFile(commandline.debugHTTP, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+}
@@ -0,0 +1,65 @@
+// Package distro looks up the versions other distributions ship for an AUR package using Repology (https://repology.org)
+package distro
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// apiURL resolves an AUR package to the packages of its Repology project
+var apiURL = "https://repology.org/tools/project-by"
+
+// Distribution is a repository known to Repology
+type Distribution struct {
+	Name string
+	Repo string
+}
+
+// Distributions lists the distributions to compare against
+var Distributions = []Distribution{
+	{"nixpkgs", "nix_unstable"},
+	{"Homebrew", "homebrew"},
+	{"Debian unstable", "debian_unstable"},
+}
+
+// Version is the version a distribution ships
+type Version struct {
+	Distribution string `json:"distribution"`
+	Version      string `json:"version"`
+}
+
+type repologyPackage struct {
+	Repo    string `json:"repo"`
+	Version string `json:"version"`
+}
+
+// Versions returns the versions shipped by Distributions for the project of the AUR package, in the order of Distributions
+func Versions(name string) ([]Version, error) {
+	query := url.Values{"repo": {"aur"}, "name_type": {"binname"}, "target_page": {"api_v1_project"}, "name": {name}}
+	resp, err := http.DefaultClient.Get(apiURL + "?" + query.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query Repology for %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("Package %s not known to Repology", name)
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Failed to query Repology for %s: %s", name, resp.Status)
+	}
+	var packages []repologyPackage
+	if err := json.NewDecoder(resp.Body).Decode(&packages); err != nil {
+		return nil, fmt.Errorf("Failed to parse Repology response for %s: %w", name, err)
+	}
+	var versions []Version
+	for _, d := range Distributions {
+		for _, p := range packages {
+			if p.Repo == d.Repo {
+				versions = append(versions, Version{d.Name, p.Version})
+				break
+			}
+		}
+	}
+	return versions, nil
+}
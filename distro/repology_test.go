@@ -0,0 +1,47 @@
+package distro
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+)
+
+func TestVersions(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://repology.org/").
+		Get("/tools/project-by").
+		MatchParam("repo", "aur").
+		MatchParam("name", "ripgrep").
+		Reply(http.StatusFound).
+		SetHeader("Location", "https://repology.org/api/v1/project/ripgrep")
+	gock.New("https://repology.org/").
+		Get("/api/v1/project/ripgrep").
+		Reply(http.StatusOK).
+		JSON([]map[string]string{
+			{"repo": "debian_unstable", "version": "13.0.0"},
+			{"repo": "aur", "version": "14.1.0"},
+			{"repo": "nix_unstable", "version": "14.1.0"},
+			{"repo": "debian_unstable", "version": "13.0.0"},
+		})
+
+	versions, err := Versions("ripgrep")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Version{{"nixpkgs", "14.1.0"}, {"Debian unstable", "13.0.0"}}
+	if len(versions) != 2 || versions[0] != expected[0] || versions[1] != expected[1] {
+		t.Errorf("Expecting %v, but got %v", expected, versions)
+	}
+}
+
+func TestVersionsUnknown(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://repology.org/").
+		Get("/tools/project-by").
+		Reply(http.StatusNotFound)
+
+	if _, err := Versions("unknown"); err == nil || err.Error() != "Package unknown not known to Repology" {
+		t.Errorf("Expecting error, but got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/mikkeloscar/aur"
+	"github.com/simon04/aur-out-of-date/pkg"
+	"github.com/simon04/aur-out-of-date/status"
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+// requestLog records the requests performed by next
+type requestLog struct {
+	next     http.RoundTripper
+	mutex    sync.Mutex
+	requests []string
+}
+
+// RoundTrip implements http.RoundTripper
+func (l *requestLog) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := l.next.RoundTrip(req)
+	entry := fmt.Sprintf("%s %s", req.Method, req.URL)
+	if err != nil {
+		entry += fmt.Sprintf(" failed: %v", err)
+	} else if resp.Header.Get("X-From-Cache") == "1" {
+		entry += fmt.Sprintf(" %d (cached)", resp.StatusCode)
+	} else {
+		entry += fmt.Sprintf(" %d", resp.StatusCode)
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.requests = append(l.requests, entry)
+	return resp, err
+}
+
+// providerName returns the name of the provider supporting the URL, "-" if none
+func providerName(url string) string {
+	if p := upstream.ProviderForURL(url); p != nil {
+		return p.Name()
+	}
+	return "-"
+}
+
+// printList prints the lines, labeling the first one
+func printList(w io.Writer, label string, lines []string) {
+	for i, line := range lines {
+		if i > 0 {
+			label = ""
+		}
+		fmt.Fprintf(w, "%-10s %s\n", label, line)
+	}
+}
+
+// explainPackages obtains the packages to explain, the .SRCINFO files for -local or the AUR packages otherwise
+func explainPackages(args []string) ([]pkg.Pkg, error) {
+	if commandline.local {
+		return localPackages(args, true)
+	} else if commandline.official {
+		return pkg.NewOfficialPkgs(args)
+	}
+	packages, err := aur.Info(args)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to obtain AUR packages: %w", err)
+	}
+	found := map[string]bool{}
+	for _, p := range packages {
+		found[p.Name] = true
+	}
+	for _, name := range args {
+		if !found[name] {
+			return nil, fmt.Errorf("Package %s not found in AUR", name)
+		}
+	}
+	return pkg.NewRemotePkgs(packages), nil
+}
+
+// explain describes how the upstream version of each package is determined, bypassing the cached upstream versions
+func explain(w io.Writer, packages []pkg.Pkg) {
+	next := http.DefaultClient.Transport
+	requests := &requestLog{next: next}
+	http.DefaultClient.Transport = requests
+	defer func() { http.DefaultClient.Transport = next }()
+
+	for i, pkg := range packages {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		readDirectives(pkg)
+		fmt.Fprintf(w, "Package:   %s %s\n", pkg.Name(), pkg.Version().String())
+		fmt.Fprintf(w, "URL:       %s (provider %s)\n", pkg.URL(), providerName(pkg.URL()))
+		sources, err := pkg.Sources()
+		if err != nil {
+			fmt.Fprintf(w, "Sources:   %v\n", err)
+		}
+		var lines []string
+		for _, source := range sources {
+			lines = append(lines, fmt.Sprintf("%s (provider %s)", source, providerName(source)))
+		}
+		printList(w, "Sources:", lines)
+
+		reason, fetch := upstreamSource(pkg)
+		fmt.Fprintf(w, "Selected:  %s\n", reason)
+		requests.requests = nil
+		result, err := fetch()
+		printList(w, "Requests:", requests.requests)
+		if result.Provider != "" {
+			fmt.Fprintf(w, "Provider:  %s\n", result.Provider)
+		}
+		if err != nil {
+			fmt.Fprintf(w, "Error:     %v\n", err)
+			continue
+		}
+		raw := string(result.Version)
+		if result.Raw != "" {
+			raw = result.Raw
+		}
+		fmt.Fprintf(w, "Raw:       %q\n", raw)
+		version, err := conf.ExtractResult(pkg.Name(), result)
+		if err != nil {
+			fmt.Fprintf(w, "Error:     %v\n", err)
+			continue
+		}
+		fmt.Fprintf(w, "Version:   %s\n", version.String())
+
+		s := status.Status{Package: pkg.Name(), Version: pkg.Version().String()}
+		if max := conf.Cap(pkg.Name(), version); max != nil {
+			s.CompareCapped(version, max.Version, max.Reason)
+		} else {
+			s.Compare(version)
+		}
+		if conf.IsIgnored(pkg.Name(), version) {
+			s.Message += " (ignored)"
+		}
+		fmt.Fprintf(w, "Status:    %s %s\n", s.Status, s.Message)
+	}
+}
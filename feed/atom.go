@@ -0,0 +1,116 @@
+// Package feed maintains an Atom feed of newly out-of-date packages
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/logging"
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+// maxEntries limits the number of entries kept in the feed
+const maxEntries = 100
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Link    atomLink `xml:"link"`
+	Summary string   `xml:"summary"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+// Writer maintains an Atom feed file with an entry per newly detected out-of-date package
+type Writer struct {
+	filename string
+	packages []*status.Status
+}
+
+// New returns a Writer updating the given Atom feed file
+func New(filename string) *Writer {
+	return &Writer{filename: filename}
+}
+
+// Status implements status.Formatter
+func (w *Writer) Status(s *status.Status) {
+	if s.Status == status.OutOfDate || s.Status == status.FlaggedOutOfDate {
+		w.packages = append(w.packages, s)
+	}
+}
+
+// Finish implements status.Formatter
+func (w *Writer) Finish(statistics *status.Statistics) {
+	if err := w.update(time.Now()); err != nil {
+		logging.Errorf("Failed to update feed %s: %v", w.filename, err)
+	}
+}
+
+func entryID(s *status.Status) string {
+	return fmt.Sprintf("tag:aur-out-of-date,%s:%s", s.Package, s.Upstream.String())
+}
+
+func (w *Writer) update(now time.Time) error {
+	feed := atomFeed{
+		ID:    "tag:aur-out-of-date,feed",
+		Title: "aur-out-of-date",
+		Link:  atomLink{"https://github.com/simon04/aur-out-of-date"},
+	}
+	if input, err := ioutil.ReadFile(w.filename); err == nil {
+		if err := xml.Unmarshal(input, &feed); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	known := map[string]bool{}
+	for _, e := range feed.Entries {
+		known[e.ID] = true
+	}
+	var entries []atomEntry
+	for _, s := range w.packages {
+		id := entryID(s)
+		if known[id] {
+			continue
+		}
+		known[id] = true
+		entries = append(entries, atomEntry{
+			ID:      id,
+			Title:   fmt.Sprintf("%s should be updated to %s", s.Package, s.Upstream.String()),
+			Updated: now.UTC().Format(time.RFC3339),
+			Link:    atomLink{"https://aur.archlinux.org/packages/" + s.Package},
+			Summary: strings.TrimSpace(fmt.Sprintf("[%s][%s] %s %s", s.Package, s.Version, s.Message, s.ReleaseURL)),
+		})
+	}
+	if len(entries) == 0 && feed.Updated != "" {
+		return nil
+	}
+	feed.Entries = append(entries, feed.Entries...)
+	if len(feed.Entries) > maxEntries {
+		feed.Entries = feed.Entries[:maxEntries]
+	}
+	feed.Updated = now.UTC().Format(time.RFC3339)
+
+	output, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(w.filename, append([]byte(xml.Header), output...), 0644)
+}
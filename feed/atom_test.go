@@ -0,0 +1,49 @@
+package feed
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestUpdate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aur-out-of-date")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := path.Join(dir, "feed.atom")
+
+	w := New(filename)
+	w.Status(&status.Status{Package: "foo", Version: "1.0-1", Upstream: "1.1", Status: status.OutOfDate})
+	w.Status(&status.Status{Package: "bar", Version: "1.0-1", Upstream: "1.0", Status: status.UpToDate})
+	if err := w.update(time.Date(2021, 3, 16, 12, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+
+	w = New(filename)
+	w.Status(&status.Status{Package: "foo", Version: "1.0-1", Upstream: "1.1", Status: status.OutOfDate})
+	w.Status(&status.Status{Package: "baz", Version: "2.0-1", Upstream: "2.1", Status: status.OutOfDate})
+	if err := w.update(time.Date(2021, 3, 17, 12, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+
+	output, _ := ioutil.ReadFile(filename)
+	actual := string(output)
+	if strings.Count(actual, "<entry>") != 2 {
+		t.Errorf("Expecting 2 entries, but got '%s'", actual)
+	}
+	if !strings.Contains(actual, "<id>tag:aur-out-of-date,foo:1.1</id>") ||
+		!strings.Contains(actual, "<title>baz should be updated to 2.1</title>") ||
+		strings.Contains(actual, "bar") {
+		t.Errorf("Unexpected feed '%s'", actual)
+	}
+	if strings.Index(actual, "baz") > strings.Index(actual, "foo") {
+		t.Errorf("Expecting newest entry first, but got '%s'", actual)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path"
+
+	"github.com/simon04/aur-out-of-date/badge"
+	"github.com/simon04/aur-out-of-date/feed"
+	"github.com/simon04/aur-out-of-date/issues"
+	"github.com/simon04/aur-out-of-date/notify"
+	"github.com/simon04/aur-out-of-date/pushgateway"
+	"github.com/simon04/aur-out-of-date/state"
+	"github.com/simon04/aur-out-of-date/status"
+	"github.com/simon04/aur-out-of-date/upload"
+)
+
+// nagios is the formatter of the last newFormatter call for -o nagios, nil otherwise
+var nagios *status.NagiosFormatter
+
+// newFormatter returns the formatter for one run according to the command line and config
+func newFormatter(w io.Writer, output string) (status.Formatter, error) {
+	formatter, err := status.NewFormatterWriter(output, w)
+	if err != nil {
+		return nil, err
+	}
+	nagios, _ = formatter.(*status.NagiosFormatter)
+	if nagios != nil {
+		nagios.Warning = commandline.nagiosWarning
+		nagios.Critical = commandline.nagiosCritical
+	}
+	if commandline.quiet {
+		formatter = status.Filter(formatter, status.UpToDate, status.Unknown)
+	} else if commandline.onlyOutdated {
+		formatter = status.Filter(formatter, status.UpToDate)
+	}
+	if commandline.groupBy && output == "text" {
+		formatter = status.GroupByMaintainer(formatter, w)
+	} else if commandline.groupBy {
+		formatter = status.GroupByMaintainer(formatter, nil)
+	}
+	if commandline.sort != "" {
+		if formatter, err = status.Sort(formatter, commandline.sort); err != nil {
+			return nil, err
+		}
+	}
+	if commandline.providerSummary {
+		formatter = status.ProviderSummary(formatter, os.Stderr)
+	}
+	if commandline.summary {
+		formatter = status.ErrorSummary(formatter, os.Stderr)
+	}
+	if commandline.outputFiles != "" {
+		files, err := status.OutputFiles(commandline.outputFiles)
+		if err != nil {
+			return nil, err
+		}
+		formatter = status.MultiFormatter(append([]status.Formatter{formatter}, files...)...)
+	}
+	formatter = state.RecordLastRun(formatter, path.Join(state.Dir(), "last-run.json"), commandline.changedOnly)
+	if commandline.history {
+		formatter = status.MultiFormatter(formatter, state.RecordHistory(historyFile(), commandline.historyMaxAge))
+	}
+	if commandline.badges != "" {
+		formatter = status.MultiFormatter(formatter, badge.NewWriter(commandline.badges))
+	}
+	if commandline.feed != "" {
+		formatter = status.MultiFormatter(formatter, feed.New(commandline.feed))
+	}
+	if commandline.pushgateway != "" {
+		formatter = status.MultiFormatter(formatter, pushgateway.New(commandline.pushgateway, commandline.pushgatewayJob, commandline.pushgatewayInst))
+	}
+	if commandline.upload != "" {
+		formatter = status.MultiFormatter(formatter, upload.New(commandline.upload))
+	}
+	notifiers := conf.Notify.Notifiers()
+	if commandline.notifyDesktop && conf.Notify.Desktop == nil {
+		notifiers = append(notifiers, &notify.DesktopConfig{})
+	}
+	if len(notifiers) > 0 {
+		n := notify.NewFormatter(notifiers...)
+		n.PerPackage = conf.Notify.PerPackage
+		n.DigestThreshold = conf.Notify.DigestThreshold
+		n.DryRun = commandline.dryRun
+		if !conf.Notify.Repeat {
+			n.StateFile = path.Join(state.Dir(), "notified.json")
+		}
+		formatter = status.MultiFormatter(formatter, n)
+	}
+	for _, tracker := range conf.Issues.Trackers() {
+		i := issues.NewFormatter(tracker)
+		i.DryRun = commandline.dryRun
+		formatter = status.MultiFormatter(formatter, i)
+	}
+	return formatter, nil
+}
@@ -0,0 +1,34 @@
+// Package healthcheck pings a dead man's switch such as healthchecks.io, which alerts if scheduled runs stop
+package healthcheck
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Event is the signal sent to the ping URL, appended to its path
+type Event string
+
+const (
+	// Start signals the start of a run, so that the duration is measured and hanging runs are detected
+	Start = Event("/start")
+	// Success signals a completed run
+	Success = Event("")
+	// Fail signals a failed run
+	Fail = Event("/fail")
+)
+
+// Ping sends the event to the ping URL (e.g. https://hc-ping.com/<uuid>), including body as log message
+func Ping(url string, event Event, body string) error {
+	url = strings.TrimSuffix(url, "/") + string(event)
+	resp, err := http.DefaultClient.Post(url, "text/plain; charset=utf-8", strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("POST %s: %s", url, resp.Status)
+	}
+	return nil
+}
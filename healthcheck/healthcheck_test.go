@@ -0,0 +1,36 @@
+package healthcheck
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPing(t *testing.T) {
+	var pings []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		pings = append(pings, r.Method+" "+r.URL.Path+" "+string(body))
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	for _, event := range []Event{Start, Success, Fail} {
+		if err := Ping(server.URL+"/uuid", event, "3 checked"); err != nil {
+			t.Error(err)
+		}
+	}
+	expected := []string{"POST /uuid/start 3 checked", "POST /uuid 3 checked", "POST /uuid/fail 3 checked"}
+	for i := range expected {
+		if i >= len(pings) || pings[i] != expected[i] {
+			t.Errorf("Expecting %q, but got %q", expected, pings)
+			break
+		}
+	}
+	if err := Ping(server.URL+"/gone", Success, ""); err == nil {
+		t.Error("Expecting an error for 404")
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"text/tabwriter"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/state"
+)
+
+// historyFile returns the JSON Lines file recording the observed versions of all packages
+func historyFile() string {
+	return path.Join(state.Dir(), "history.jsonl")
+}
+
+// printHistory prints the observed versions of the packages (all if none are given) as table, as JSON array for -o json or as JSON Lines for -o ndjson
+func printHistory(w io.Writer, names []string, output string) error {
+	observations, err := state.ReadHistory(historyFile())
+	if err != nil {
+		return fmt.Errorf("Failed to read history: %w", err)
+	}
+	selected := map[string]bool{}
+	for _, name := range names {
+		selected[name] = true
+	}
+	printed := []state.Observation{}
+	for _, o := range observations {
+		if len(selected) == 0 || selected[o.Package] {
+			printed = append(printed, o)
+		}
+	}
+	enc := json.NewEncoder(w)
+	switch output {
+	case "json":
+		return enc.Encode(printed)
+	case "ndjson":
+		for _, o := range printed {
+			if err := enc.Encode(o); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tPACKAGE\tVERSION\tUPSTREAM\tRELEASED\tSTATUS")
+	for _, o := range printed {
+		released := "-"
+		if o.Released != nil {
+			released = o.Released.Local().Format("2006-01-02")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", o.Time.Local().Format(time.RFC3339), o.Package, o.Version, o.Upstream, released, o.Status)
+	}
+	return tw.Flush()
+}
+
+type lagStats struct {
+	Package  string  `json:"name,omitempty"`
+	Releases int     `json:"releases"`
+	Pending  int     `json:"pending"`
+	Mean     float64 `json:"mean_seconds"`
+	Median   float64 `json:"median_seconds"`
+}
+
+func lagStatsOf(s state.LagStats) lagStats {
+	return lagStats{s.Package, s.Releases, s.Pending, s.Mean.Seconds(), s.Median.Seconds()}
+}
+
+// formatLag formats the duration in hours or, if longer than two days, in days
+func formatLag(d time.Duration) string {
+	if d < 48*time.Hour {
+		return fmt.Sprintf("%.1fh", d.Hours())
+	}
+	return fmt.Sprintf("%.1fd", d.Hours()/24)
+}
+
+// printStats prints the mean and median lag between upstream releases and AUR updates per package (all if none are given) and overall
+func printStats(w io.Writer, names []string, output string) error {
+	observations, err := state.ReadHistory(historyFile())
+	if err != nil {
+		return fmt.Errorf("Failed to read history: %w", err)
+	}
+	if len(names) > 0 {
+		selected := map[string]bool{}
+		for _, name := range names {
+			selected[name] = true
+		}
+		var filtered []state.Observation
+		for _, o := range observations {
+			if selected[o.Package] {
+				filtered = append(filtered, o)
+			}
+		}
+		observations = filtered
+	}
+	packages, overall := state.Staleness(state.Lags(observations), time.Now())
+	if output == "json" || output == "ndjson" {
+		response := struct {
+			Packages []lagStats `json:"packages"`
+			Overall  lagStats   `json:"overall"`
+		}{Packages: []lagStats{}, Overall: lagStatsOf(overall)}
+		for _, s := range packages {
+			response.Packages = append(response.Packages, lagStatsOf(s))
+		}
+		return json.NewEncoder(w).Encode(response)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PACKAGE\tRELEASES\tPENDING\tMEAN LAG\tMEDIAN LAG")
+	for _, s := range append(packages, overall) {
+		name := s.Package
+		if name == "" {
+			name = "overall"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\n", name, s.Releases, s.Pending, formatLag(s.Mean), formatLag(s.Median))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/state"
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestPrintHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aur-out-of-date")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer os.Setenv("STATE_DIRECTORY", os.Getenv("STATE_DIRECTORY"))
+	os.Setenv("STATE_DIRECTORY", dir)
+	f := state.RecordHistory(historyFile(), 0)
+	f.Status(&status.Status{Package: "foo", Version: "1.0-1", Upstream: "1.1", Status: status.OutOfDate})
+	f.Status(&status.Status{Package: "bar", Version: "2.0-1", Upstream: "2.0", Status: status.UpToDate})
+	f.Finish(nil)
+
+	var b bytes.Buffer
+	if err := printHistory(&b, []string{"foo"}, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var observations []state.Observation
+	if err := json.Unmarshal(b.Bytes(), &observations); err != nil {
+		t.Fatal(err)
+	}
+	if len(observations) != 1 || observations[0].Package != "foo" {
+		t.Errorf("Expecting a JSON array of the observation of foo, but got %s", b.String())
+	}
+
+	b.Reset()
+	if err := printHistory(&b, nil, "ndjson"); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(b.String()), "\n"); len(lines) != 2 || !strings.HasPrefix(lines[0], `{"time":`) {
+		t.Errorf("Expecting JSON Lines of foo and bar, but got %s", b.String())
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/mikkeloscar/aur"
+	"github.com/simon04/aur-out-of-date/config"
+	"github.com/simon04/aur-out-of-date/pkg"
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+// detectProvider returns the URL (the upstream URL or the first source) and the name of the provider supporting it, "" if none
+func detectProvider(p pkg.Pkg) (string, string) {
+	urls := []string{p.URL()}
+	if sources, err := p.Sources(); err == nil {
+		urls = append(urls, sources...)
+	}
+	for _, url := range urls {
+		if provider := upstream.ProviderForURL(url); url != "" && provider != nil {
+			return url, provider.Name()
+		}
+	}
+	return "", ""
+}
+
+// writeStarterConfig writes a TOML config for the user pinning the url of the packages with a detected provider,
+// along with commented-out stubs for the other packages
+func writeStarterConfig(w io.Writer, user string, packages []pkg.Pkg) (int, error) {
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Name() < packages[j].Name() })
+	unmapped := 0
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "[settings]\nuser = %s\n", config.TOMLString(user))
+	for _, p := range packages {
+		table := "packages." + config.TOMLKey(p.Name())
+		if url, provider := detectProvider(p); provider != "" {
+			fmt.Fprintf(&b, "\n# detected provider %s\n[%s]\nurl = %s\n", provider, table, config.TOMLString(url))
+		} else {
+			unmapped++
+			fmt.Fprintf(&b, "\n# no provider found for %s, set a supported url, a provider identifier or a watch file\n", config.TOMLString(p.URL()))
+			fmt.Fprintf(&b, "# [%s]\n# url = \"\"\n# provider = \"\"\n", table)
+		}
+	}
+	_, err := w.Write(b.Bytes())
+	return unmapped, err
+}
+
+// initConfig writes a starter config for the AUR packages maintained by the user to the filename, which must not exist yet
+func initConfig(filename, user string) error {
+	if _, err := os.Stat(filename); err == nil {
+		return fmt.Errorf("Config file %s already exists, use -config to write another file", filename)
+	}
+	if !strings.HasSuffix(filename, ".toml") {
+		return fmt.Errorf("The starter config is written as TOML, use -config to write a *.toml file")
+	}
+	packages, err := aur.SearchBy(user, aur.Maintainer)
+	if err != nil {
+		return fmt.Errorf("Failed to obtain AUR packages of %s: %w", user, err)
+	}
+	var checked []pkg.Pkg
+	for _, p := range pkg.NewRemotePkgs(packages) {
+		if commandline.includeVcsPkgs == p.IsVcs() {
+			checked = append(checked, p)
+		}
+	}
+	if err := os.MkdirAll(path.Dir(filename), 0755); err != nil {
+		return fmt.Errorf("Failed to create config directory: %w", err)
+	}
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return fmt.Errorf("Failed to write config: %w", err)
+	}
+	unmapped, err := writeStarterConfig(f, user, checked)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to write config: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s for %d packages, %d without detected provider\n", filename, len(checked), unmapped)
+	return nil
+}
@@ -0,0 +1,73 @@
+package issues
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// GitHubConfig configures issues of a GitHub repository
+type GitHubConfig struct {
+	// Repository is given as owner/name
+	Repository string `json:"repository"`
+	Token      string `json:"token"`
+}
+
+type gitHubIssue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+}
+
+// Name implements Tracker
+func (c *GitHubConfig) Name() string {
+	return "github"
+}
+
+func (c *GitHubConfig) header() http.Header {
+	return http.Header{
+		"Accept":        {"application/vnd.github+json"},
+		"Authorization": {"token " + c.Token},
+	}
+}
+
+func (c *GitHubConfig) issuesURL() string {
+	// API documentation: https://docs.github.com/en/rest/issues/issues
+	return fmt.Sprintf("https://api.github.com/repos/%s/issues", c.Repository)
+}
+
+// OpenIssues implements Tracker
+func (c *GitHubConfig) OpenIssues() ([]Issue, error) {
+	var r []Issue
+	url := c.issuesURL() + "?state=open&per_page=100&labels=" + Label
+	for url != "" {
+		var issues []gitHubIssue
+		next, err := requestPage("GET", url, c.header(), nil, &issues)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			r = append(r, Issue{issue.Number, issue.Title})
+		}
+		url = next
+	}
+	return r, nil
+}
+
+// Create implements Tracker
+func (c *GitHubConfig) Create(title, body string) error {
+	payload := map[string]interface{}{"title": title, "body": body, "labels": []string{Label}}
+	return request("POST", c.issuesURL(), c.header(), payload, nil)
+}
+
+// Close implements Tracker
+func (c *GitHubConfig) Close(issue Issue) error {
+	url := fmt.Sprintf("%s/%d", c.issuesURL(), issue.ID)
+	return request("PATCH", url, c.header(), map[string]string{"state": "closed"}, nil)
+}
+
+// CreateMergeRequest opens a pull request
+func (c *GitHubConfig) CreateMergeRequest(branch, base, title, body string) error {
+	// API documentation: https://docs.github.com/en/rest/pulls/pulls#create-a-pull-request
+	url := fmt.Sprintf("https://api.github.com/repos/%s/pulls", c.Repository)
+	payload := map[string]string{"title": title, "body": body, "head": branch, "base": base}
+	return request("POST", url, c.header(), payload, nil)
+}
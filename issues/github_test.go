@@ -0,0 +1,75 @@
+package issues
+
+import (
+	"testing"
+
+	"github.com/h2non/gock"
+)
+
+func TestGitHub(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.github.com").
+		Get("/repos/simon04/aur-packages/issues").
+		MatchParam("labels", "aur-out-of-date").
+		MatchHeader("Authorization", "^token secret$").
+		Reply(200).
+		JSON([]map[string]interface{}{{"number": 42, "title": "Update foo to 1.1"}})
+	gock.New("https://api.github.com").
+		Patch("/repos/simon04/aur-packages/issues/42").
+		BodyString(`{"state":"closed"}`).
+		Reply(200)
+	gock.New("https://api.github.com").
+		Post("/repos/simon04/aur-packages/issues").
+		BodyString(`"title":"Update foo to 1.2"`).
+		Reply(201)
+
+	c := &GitHubConfig{Repository: "simon04/aur-packages", Token: "secret"}
+	issues, err := c.OpenIssues()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0] != (Issue{42, "Update foo to 1.1"}) {
+		t.Errorf("Unexpected issues %v", issues)
+	}
+	if err := c.Close(issues[0]); err != nil {
+		t.Error(err)
+	}
+	if err := c.Create("Update foo to 1.2", "Release: https://example.com"); err != nil {
+		t.Error(err)
+	}
+	gock.New("https://api.github.com").
+		Post("/repos/simon04/aur-packages/pulls").
+		BodyString(`"head":"aur-out-of-date/foo-1.2"`).
+		Reply(201)
+	if err := c.CreateMergeRequest("aur-out-of-date/foo-1.2", "main", "upgpkg: foo 1.2-1", ""); err != nil {
+		t.Error(err)
+	}
+	if !gock.IsDone() {
+		t.Error("Expecting all requests to be sent")
+	}
+}
+
+func TestGitHubPagination(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.github.com").
+		Get("/repos/simon04/aur-packages/issues").
+		MatchParam("page", "2").
+		Reply(200).
+		JSON([]map[string]interface{}{{"number": 43, "title": "Update bar to 2.0"}})
+	gock.New("https://api.github.com").
+		Get("/repos/simon04/aur-packages/issues").
+		MatchParam("labels", "aur-out-of-date").
+		Reply(200).
+		SetHeader("Link", `<https://api.github.com/repos/simon04/aur-packages/issues?state=open&per_page=100&labels=aur-out-of-date&page=2>; rel="next", `+
+			`<https://api.github.com/repos/simon04/aur-packages/issues?state=open&per_page=100&labels=aur-out-of-date&page=2>; rel="last"`).
+		JSON([]map[string]interface{}{{"number": 42, "title": "Update foo to 1.1"}})
+
+	c := &GitHubConfig{Repository: "simon04/aur-packages", Token: "secret"}
+	issues, err := c.OpenIssues()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 2 || issues[0] != (Issue{42, "Update foo to 1.1"}) || issues[1] != (Issue{43, "Update bar to 2.0"}) {
+		t.Errorf("Expecting the issues of both pages, but got %v", issues)
+	}
+}
@@ -0,0 +1,77 @@
+package issues
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GitLabConfig configures issues of a GitLab project
+type GitLabConfig struct {
+	// URL defaults to https://gitlab.com
+	URL string `json:"url"`
+	// Project is given as group/name or numeric ID
+	Project string `json:"project"`
+	Token   string `json:"token"`
+}
+
+type gitLabIssue struct {
+	IID   int    `json:"iid"`
+	Title string `json:"title"`
+}
+
+// Name implements Tracker
+func (c *GitLabConfig) Name() string {
+	return "gitlab"
+}
+
+func (c *GitLabConfig) header() http.Header {
+	return http.Header{"Private-Token": {c.Token}}
+}
+
+func (c *GitLabConfig) projectURL() string {
+	// API documentation: https://docs.gitlab.com/ee/api/issues.html
+	base := c.URL
+	if base == "" {
+		base = "https://gitlab.com"
+	}
+	return fmt.Sprintf("%s/api/v4/projects/%s", strings.TrimSuffix(base, "/"), url.PathEscape(c.Project))
+}
+
+// OpenIssues implements Tracker
+func (c *GitLabConfig) OpenIssues() ([]Issue, error) {
+	var r []Issue
+	url := c.projectURL() + "/issues?state=opened&per_page=100&labels=" + Label
+	for url != "" {
+		var issues []gitLabIssue
+		next, err := requestPage("GET", url, c.header(), nil, &issues)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			r = append(r, Issue{issue.IID, issue.Title})
+		}
+		url = next
+	}
+	return r, nil
+}
+
+// Create implements Tracker
+func (c *GitLabConfig) Create(title, body string) error {
+	payload := map[string]string{"title": title, "description": body, "labels": Label}
+	return request("POST", c.projectURL()+"/issues", c.header(), payload, nil)
+}
+
+// Close implements Tracker
+func (c *GitLabConfig) Close(issue Issue) error {
+	url := fmt.Sprintf("%s/issues/%d", c.projectURL(), issue.ID)
+	return request("PUT", url, c.header(), map[string]string{"state_event": "close"}, nil)
+}
+
+// CreateMergeRequest opens a merge request
+func (c *GitLabConfig) CreateMergeRequest(branch, base, title, body string) error {
+	// API documentation: https://docs.gitlab.com/ee/api/merge_requests.html#create-mr
+	payload := map[string]string{"title": title, "description": body, "source_branch": branch, "target_branch": base, "labels": Label}
+	return request("POST", c.projectURL()+"/merge_requests", c.header(), payload, nil)
+}
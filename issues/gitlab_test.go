@@ -0,0 +1,43 @@
+package issues
+
+import (
+	"testing"
+
+	"github.com/h2non/gock"
+)
+
+func TestGitLab(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://gitlab.com").
+		Get("/api/v4/projects/simon04/aur-packages/issues").
+		MatchParam("state", "opened").
+		MatchHeader("Private-Token", "^secret$").
+		Reply(200).
+		JSON([]map[string]interface{}{{"iid": 7, "title": "Update foo to 1.1"}})
+	gock.New("https://gitlab.com").
+		Put("/api/v4/projects/simon04/aur-packages/issues/7").
+		BodyString(`{"state_event":"close"}`).
+		Reply(200)
+
+	c := &GitLabConfig{Project: "simon04/aur-packages", Token: "secret"}
+	issues, err := c.OpenIssues()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0] != (Issue{7, "Update foo to 1.1"}) {
+		t.Errorf("Unexpected issues %v", issues)
+	}
+	if err := c.Close(issues[0]); err != nil {
+		t.Error(err)
+	}
+	gock.New("https://gitlab.com").
+		Post("/api/v4/projects/simon04/aur-packages/merge_requests").
+		BodyString(`"source_branch":"aur-out-of-date/foo-1.2"`).
+		Reply(201)
+	if err := c.CreateMergeRequest("aur-out-of-date/foo-1.2", "main", "upgpkg: foo 1.2-1", ""); err != nil {
+		t.Error(err)
+	}
+	if !gock.IsDone() {
+		t.Error("Expecting all requests to be sent")
+	}
+}
@@ -0,0 +1,173 @@
+// Package issues opens and closes tracking issues for new upstream versions
+package issues
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/simon04/aur-out-of-date/logging"
+	"github.com/simon04/aur-out-of-date/status"
+	"github.com/simon04/aur-out-of-date/transport"
+)
+
+// Label is attached to all issues opened by aur-out-of-date
+const Label = "aur-out-of-date"
+
+// Config holds the configuration of all issue trackers
+type Config struct {
+	GitHub *GitHubConfig `json:"github"`
+	GitLab *GitLabConfig `json:"gitlab"`
+}
+
+// Trackers returns the issue trackers enabled in the configuration
+func (c *Config) Trackers() []Tracker {
+	var trackers []Tracker
+	if c.GitHub != nil {
+		trackers = append(trackers, c.GitHub)
+	}
+	if c.GitLab != nil {
+		trackers = append(trackers, c.GitLab)
+	}
+	return trackers
+}
+
+// Issue is an open issue of a tracker
+type Issue struct {
+	ID    int
+	Title string
+}
+
+// Tracker manages issues labeled with Label
+type Tracker interface {
+	Name() string
+	// OpenIssues returns the open issues labeled with Label
+	OpenIssues() ([]Issue, error)
+	Create(title, body string) error
+	Close(issue Issue) error
+}
+
+// Formatter opens an issue per out-of-date package and version, and closes outdated issues
+type Formatter struct {
+	tracker Tracker
+	open    []Issue
+	loaded  bool
+	// DryRun prints the issues to open and close instead of modifying them
+	DryRun bool
+}
+
+// NewFormatter returns a status.Formatter managing issues of the given tracker
+func NewFormatter(tracker Tracker) *Formatter {
+	return &Formatter{tracker: tracker}
+}
+
+// Title returns the issue title for updating the package
+func Title(s *status.Status) string {
+	return fmt.Sprintf("Update %s to %s", s.Package, s.Upstream.String())
+}
+
+func body(s *status.Status) string {
+	body := s.Message
+	if s.ReleaseURL != "" {
+		body += "\n\nRelease: " + s.ReleaseURL
+	}
+	return body
+}
+
+// Status implements status.Formatter
+func (f *Formatter) Status(s *status.Status) {
+	var title string
+	switch s.Status {
+	case status.OutOfDate, status.FlaggedOutOfDate:
+		title = Title(s)
+	case status.UpToDate:
+	default:
+		return
+	}
+	if !f.loaded {
+		open, err := f.tracker.OpenIssues()
+		if err != nil {
+			logging.Errorf("Failed to obtain %s issues: %v", f.tracker.Name(), err)
+			return
+		}
+		f.open, f.loaded = open, true
+	}
+	found := false
+	prefix := fmt.Sprintf("Update %s to ", s.Package)
+	for _, issue := range f.open {
+		if issue.Title == title {
+			found = true
+		} else if strings.HasPrefix(issue.Title, prefix) && f.DryRun {
+			fmt.Fprintf(os.Stderr, "Would close %s issue %q\n", f.tracker.Name(), issue.Title)
+		} else if strings.HasPrefix(issue.Title, prefix) {
+			if err := f.tracker.Close(issue); err != nil {
+				logging.Errorf("Failed to close %s issue %q: %v", f.tracker.Name(), issue.Title, err)
+			} else {
+				logging.Infof("Closed %s issue %q", f.tracker.Name(), issue.Title)
+			}
+		}
+	}
+	if title == "" || found {
+		return
+	} else if f.DryRun {
+		fmt.Fprintf(os.Stderr, "Would open %s issue %q\n", f.tracker.Name(), title)
+		return
+	}
+	if err := f.tracker.Create(title, body(s)); err != nil {
+		logging.Errorf("Failed to open %s issue %q: %v", f.tracker.Name(), title, err)
+	} else {
+		logging.Infof("Opened %s issue %q", f.tracker.Name(), title)
+	}
+}
+
+// Finish implements status.Formatter
+func (f *Formatter) Finish(statistics *status.Statistics) {
+}
+
+// nextLink matches the URL of the next page in a Link header, as used by GitHub and GitLab for pagination
+var nextLink = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// request performs an authenticated JSON request, decoding the response into v (unless nil)
+func request(method, url string, header http.Header, payload interface{}, v interface{}) error {
+	_, err := requestPage(method, url, header, payload, v)
+	return err
+}
+
+// requestPage performs the request, returning the URL of the next page given by the Link header, "" for the last page
+func requestPage(method, url string, header http.Header, payload interface{}, v interface{}) (string, error) {
+	var body bytes.Buffer
+	if payload != nil {
+		if err := json.NewEncoder(&body).Encode(payload); err != nil {
+			return "", err
+		}
+	}
+	req, err := http.NewRequest(method, url, &body)
+	if err != nil {
+		return "", err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// bypass the HTTP cache, which would serve lists lacking the issues just opened (e.g. GitHub sends max-age=60)
+	resp, err := transport.DoUncached(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("%s %s: %s", method, url, resp.Status)
+	}
+	var next string
+	if match := nextLink.FindStringSubmatch(resp.Header.Get("Link")); match != nil {
+		next = match[1]
+	}
+	if v == nil {
+		return next, nil
+	}
+	return next, json.NewDecoder(resp.Body).Decode(v)
+}
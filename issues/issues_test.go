@@ -0,0 +1,110 @@
+package issues
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/cache"
+	"github.com/simon04/aur-out-of-date/status"
+	"github.com/simon04/aur-out-of-date/transport"
+)
+
+type recorder struct {
+	open    []Issue
+	actions []string
+}
+
+func (r *recorder) Name() string {
+	return "recorder"
+}
+
+func (r *recorder) OpenIssues() ([]Issue, error) {
+	return r.open, nil
+}
+
+func (r *recorder) Create(title, body string) error {
+	r.actions = append(r.actions, "create "+title)
+	return nil
+}
+
+func (r *recorder) Close(issue Issue) error {
+	r.actions = append(r.actions, "close "+issue.Title)
+	return nil
+}
+
+func TestFormatter(t *testing.T) {
+	r := &recorder{open: []Issue{
+		{1, "Update foo to 1.1"},
+		{2, "Update bar to 2.0"},
+		{3, "Update baz to 3.0"},
+		{4, "Update foobar to 1.0"},
+	}}
+	f := NewFormatter(r)
+	f.Status(&status.Status{Package: "foo", Upstream: "1.2", Status: status.OutOfDate})
+	f.Status(&status.Status{Package: "bar", Upstream: "2.0", Status: status.OutOfDate})
+	f.Status(&status.Status{Package: "baz", Upstream: "3.0", Status: status.UpToDate})
+	f.Status(&status.Status{Package: "foobar", Status: status.Unknown})
+	f.Status(&status.Status{Package: "qux", Upstream: "4.0", Status: status.OutOfDate})
+	f.Finish(nil)
+	expected := []string{
+		"close Update foo to 1.1",
+		"create Update foo to 1.2",
+		"close Update baz to 3.0",
+		"create Update qux to 4.0",
+	}
+	if strings.Join(r.actions, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expecting %q, but got %q", expected, r.actions)
+	}
+}
+
+func TestFormatterDryRun(t *testing.T) {
+	r := &recorder{open: []Issue{{1, "Update foo to 1.1"}}}
+	f := NewFormatter(r)
+	f.DryRun = true
+	f.Status(&status.Status{Package: "foo", Upstream: "1.2", Status: status.OutOfDate})
+	f.Status(&status.Status{Package: "qux", Upstream: "4.0", Status: status.OutOfDate})
+	f.Finish(nil)
+	if len(r.actions) != 0 {
+		t.Errorf("Expecting no actions, but got %q", r.actions)
+	}
+}
+
+func TestFormatterSecondRun(t *testing.T) {
+	var opened []gitLabIssue
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var issue gitLabIssue
+			json.NewDecoder(r.Body).Decode(&issue)
+			issue.IID = len(opened) + 1
+			opened = append(opened, issue)
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		w.Header().Set("Cache-Control", "max-age=60")
+		json.NewEncoder(w).Encode(opened)
+	}))
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "aur-out-of-date")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defaultClient := http.DefaultClient
+	http.DefaultClient = &http.Client{Transport: transport.Cache(http.DefaultTransport, cache.Dir(dir))}
+	defer func() { http.DefaultClient = defaultClient }()
+	c := &GitLabConfig{URL: server.URL, Project: "simon04/aur-packages"}
+	for i := 0; i < 2; i++ {
+		f := NewFormatter(c)
+		f.Status(&status.Status{Package: "foo", Upstream: "1.2", Status: status.OutOfDate})
+		f.Finish(nil)
+	}
+	if len(opened) != 1 {
+		t.Errorf("Expecting the second run to see the issue opened by the first run, but got %v", opened)
+	}
+}
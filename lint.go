@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/simon04/aur-out-of-date/lint"
+)
+
+// lintPackages reports the issues of the local PKGBUILDs given as .SRCINFO paths (default .SRCINFO), returning their number
+func lintPackages(w io.Writer, paths []string) (int, error) {
+	if len(paths) == 0 {
+		paths = []string{".SRCINFO"}
+	}
+	packages, err := localPackages(paths, true)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, p := range packages {
+		for _, issue := range lint.Check(p, commandline.offline) {
+			fmt.Fprintln(w, issue)
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,96 @@
+// Package lint reports common issues of PKGBUILDs, such as dead homepages or insecure sources
+package lint
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/simon04/aur-out-of-date/pkg"
+)
+
+// Issue is a problem found in the PKGBUILD of a package
+type Issue struct {
+	Package string
+	// Check is the name of the failed check, e.g. "insecure-source"
+	Check   string
+	Message string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s: %s", i.Package, i.Check, i.Message)
+}
+
+// Check runs all checks for the package, dead-url requires network access unless offline
+func Check(p pkg.Pkg, offline bool) []Issue {
+	var issues []Issue
+	add := func(check, format string, args ...interface{}) {
+		issues = append(issues, Issue{Package: p.Name(), Check: check, Message: fmt.Sprintf(format, args...)})
+	}
+	if url := p.URL(); url != "" && !offline {
+		if err := checkURL(url); err != nil {
+			add("dead-url", "Homepage %s is unreachable: %v", url, err)
+		}
+	}
+	sources, err := p.Sources()
+	if err != nil {
+		add("sources", "Failed to obtain sources: %v", err)
+	}
+	for _, source := range sources {
+		if i := strings.Index(source, "::"); i >= 0 {
+			source = source[i+2:]
+		}
+		if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "git+http://") {
+			add("insecure-source", "Source %s is downloaded using http://", source)
+		}
+	}
+	if checksums, err := p.Checksums(); err == nil && len(checksums["md5sums"]) > 0 && len(checksums) == 1 {
+		add("md5sums-only", "Sources are only verified using md5sums, use sha256sums or b2sums")
+	}
+	for _, source := range hardcodedVersions(p.Content(), string(p.Version().Version)) {
+		add("hardcoded-version", "Source %s contains the version %s instead of $pkgver", source, p.Version().Version)
+	}
+	return issues
+}
+
+// checkURL issues a HEAD request for the homepage, falling back to GET for servers not supporting HEAD
+func checkURL(url string) error {
+	resp, err := http.DefaultClient.Head(url)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusForbidden) {
+		resp.Body.Close()
+		resp, err = http.DefaultClient.Get(url)
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone || resp.StatusCode >= 500 {
+		return fmt.Errorf("%s", resp.Status)
+	}
+	return nil
+}
+
+// sourceArray matches the source arrays of a PKGBUILD, including architecture specific ones such as source_x86_64
+var sourceArray = regexp.MustCompile(`(?m)^source(?:_\w+)?=\(([^)]*)\)`)
+
+// hardcodedVersions returns the sources of the PKGBUILD containing the literal version instead of $pkgver, which won't track updates
+func hardcodedVersions(content, pkgver string) []string {
+	// short versions such as 1 or 10 match too many unrelated parts of URLs
+	if content == "" || len(pkgver) < 3 {
+		return nil
+	}
+	var r []string
+	for _, array := range sourceArray.FindAllStringSubmatch(content, -1) {
+		for _, source := range strings.Fields(array[1]) {
+			source = strings.Trim(source, `"'`)
+			if strings.HasPrefix(source, "#") {
+				continue
+			}
+			if strings.Contains(source, pkgver) && !strings.Contains(source, "pkgver") {
+				r = append(r, source)
+			}
+		}
+	}
+	return r
+}
@@ -0,0 +1,56 @@
+package lint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/pkg"
+)
+
+func TestCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	for _, test := range []struct {
+		pkg      pkg.Pkg
+		expected []string
+	}{
+		{pkg.New("foo", "1.0", server.URL+"/", "https://example.com/foo-1.0.tar.gz"), nil},
+		{pkg.New("foo", "1.0", server.URL+"/foo", "http://example.com/foo-1.0.tar.gz", "foo.service"), []string{"dead-url", "insecure-source"}},
+	} {
+		issues := Check(test.pkg, false)
+		if len(issues) != len(test.expected) {
+			t.Errorf("Expecting %v, but got %v", test.expected, issues)
+			continue
+		}
+		for i := range issues {
+			if issues[i].Check != test.expected[i] {
+				t.Errorf("Expecting %v, but got %v", test.expected[i], issues[i])
+			}
+		}
+	}
+}
+
+func TestHardcodedVersions(t *testing.T) {
+	content := `pkgname=foo
+pkgver=1.2.3
+pkgrel=1
+source=("https://example.com/foo-$pkgver.tar.gz"
+        "https://example.com/foo-1.2.3-fix.patch"
+        foo.service)
+source_x86_64=("https://example.com/foo-bin-1.2.3.tar.gz")
+`
+	sources := hardcodedVersions(content, "1.2.3")
+	expected := []string{"https://example.com/foo-1.2.3-fix.patch", "https://example.com/foo-bin-1.2.3.tar.gz"}
+	if len(sources) != len(expected) || sources[0] != expected[0] || sources[1] != expected[1] {
+		t.Errorf("Expecting %q, but got %q", expected, sources)
+	}
+	if sources := hardcodedVersions(content, "1"); sources != nil {
+		t.Errorf("Expecting no sources for short versions, but got %q", sources)
+	}
+}
@@ -0,0 +1,113 @@
+// Package logging provides leveled diagnostic output on stderr, separate from the results on stdout
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level represents the verbosity of log messages
+type Level int
+
+const (
+	// Error messages are always shown
+	Error Level = iota
+	// Warn is the default level
+	Warn
+	// Info messages are shown using -v
+	Info
+	// Debug messages are shown using -vv
+	Debug
+)
+
+func (l Level) String() string {
+	switch l {
+	case Error:
+		return "error"
+	case Warn:
+		return "warn"
+	case Info:
+		return "info"
+	default:
+		return "debug"
+	}
+}
+
+var (
+	mutex                = sync.Mutex{}
+	level                = Warn
+	jsonFormat           = false
+	output     io.Writer = os.Stderr
+)
+
+// SetLevel sets the maximum level of messages to be logged
+func SetLevel(l Level) {
+	level = l
+}
+
+// SetJSON switches to logging one JSON object per line
+func SetJSON(enabled bool) {
+	jsonFormat = enabled
+}
+
+// Enabled determines whether messages of the given level are logged
+func Enabled(l Level) bool {
+	return l <= level
+}
+
+// Log writes the message along with key/value pairs if the level is enabled
+func Log(l Level, msg string, keyvals ...interface{}) {
+	if !Enabled(l) {
+		return
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	now := time.Now()
+	if jsonFormat {
+		entry := map[string]interface{}{
+			"time":  now.Format(time.RFC3339),
+			"level": l.String(),
+			"msg":   msg,
+		}
+		for i := 0; i+1 < len(keyvals); i += 2 {
+			value := keyvals[i+1]
+			if err, ok := value.(error); ok {
+				value = err.Error()
+			}
+			entry[fmt.Sprint(keyvals[i])] = value
+		}
+		json.NewEncoder(output).Encode(entry)
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %-5s %s", now.Format("15:04:05"), strings.ToUpper(l.String()), msg)
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		fmt.Fprintf(&b, " %v=%q", keyvals[i], fmt.Sprint(keyvals[i+1]))
+	}
+	fmt.Fprintln(output, b.String())
+}
+
+// Errorf logs a formatted message on level Error
+func Errorf(format string, args ...interface{}) {
+	Log(Error, fmt.Sprintf(format, args...))
+}
+
+// Warnf logs a formatted message on level Warn
+func Warnf(format string, args ...interface{}) {
+	Log(Warn, fmt.Sprintf(format, args...))
+}
+
+// Infof logs a formatted message on level Info
+func Infof(format string, args ...interface{}) {
+	Log(Info, fmt.Sprintf(format, args...))
+}
+
+// Debugf logs a formatted message on level Debug
+func Debugf(format string, args ...interface{}) {
+	Log(Debug, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLevels(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	output = out
+	SetLevel(Info)
+	Debugf("hidden %d", 1)
+	Infof("shown %d", 2)
+	actual := out.String()
+	if strings.Contains(actual, "hidden") || !strings.Contains(actual, "INFO  shown 2") {
+		t.Errorf("Unexpected '%s'", actual)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	output = out
+	SetLevel(Debug)
+	SetJSON(true)
+	defer SetJSON(false)
+	Log(Debug, "request failed", "url", "https://example.com/", "err", errors.New("timeout"))
+	var entry map[string]string
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["level"] != "debug" || entry["msg"] != "request failed" || entry["url"] != "https://example.com/" || entry["err"] != "timeout" {
+		t.Errorf("Unexpected '%v'", entry)
+	}
+}
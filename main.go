@@ -1,95 +1,889 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"os"
+	"os/signal"
 	"path"
 	"sort"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
-	"github.com/gregjones/httpcache"
-	"github.com/gregjones/httpcache/diskcache"
 	"github.com/mikkeloscar/aur"
 	"github.com/simon04/aur-out-of-date/action"
+	"github.com/simon04/aur-out-of-date/advisory"
+	"github.com/simon04/aur-out-of-date/aurweb"
+	"github.com/simon04/aur-out-of-date/cache"
 	"github.com/simon04/aur-out-of-date/config"
+	"github.com/simon04/aur-out-of-date/distro"
+	"github.com/simon04/aur-out-of-date/healthcheck"
+	"github.com/simon04/aur-out-of-date/logging"
 	"github.com/simon04/aur-out-of-date/pkg"
+	"github.com/simon04/aur-out-of-date/signature"
+	"github.com/simon04/aur-out-of-date/sources"
+	"github.com/simon04/aur-out-of-date/state"
 	"github.com/simon04/aur-out-of-date/status"
+	"github.com/simon04/aur-out-of-date/tracing"
+	"github.com/simon04/aur-out-of-date/transport"
 	"github.com/simon04/aur-out-of-date/upstream"
 )
 
 var conf *config.Config
 var statistics status.Statistics
+var formatter status.Formatter
+var checkErrors int
+var checkpoint *state.Checkpoint
+var lockfile *config.Lockfile
+var resultCache *state.ResultCache
+var nvchecker map[string]upstream.NvcheckerEntry
+
+// providers holds the providers pinned per package by the -providers mapping file
+var providers map[string]upstream.NvcheckerEntry
+
+// interrupted is cancelled on SIGINT/SIGTERM, runContext additionally on -run-timeout
+var interrupted = context.Background()
+var runContext = context.Background()
+var aborted bool
+
+// periodic is set in daemon and watch mode, which apply the check intervals configured per package or provider
+var periodic bool
+
+// tracer records spans if -otlp-endpoint is given, runSpan is the root span of the current run
+var tracer *tracing.Tracer
+var runSpan *tracing.Span
+
+// runMutex guards runContext and runSpan, which are read by the checks of the API while run replaces them
+var runMutex sync.RWMutex
+
+func setRun(ctx context.Context, span *tracing.Span) {
+	runMutex.Lock()
+	defer runMutex.Unlock()
+	runContext, runSpan = ctx, span
+}
+
+func currentRunContext() context.Context {
+	runMutex.RLock()
+	defer runMutex.RUnlock()
+	return runContext
+}
+
+func currentRunSpan() *tracing.Span {
+	runMutex.RLock()
+	defer runMutex.RUnlock()
+	return runSpan
+}
+
+// cassette records all HTTP interactions if -record is given
+var cassette *transport.Cassette
 
 var commandline struct {
-	user            string
-	config          string
-	remote          bool
-	local           bool
-	includeVcsPkgs  bool
-	printJSON       bool
-	printStatistics bool
-	flagOnAur       bool
-	updatePKGBUILD  bool
-}
-
-func version(pkg pkg.Pkg) (upstream.Version, error) {
+	user             string
+	config           string
+	remote           bool
+	local            bool
+	includeVcsPkgs   bool
+	printJSON        bool
+	output           string
+	printStatistics  bool
+	flagOnAur        bool
+	updatePKGBUILD   bool
+	assumeYes        bool
+	push             bool
+	dryRun           bool
+	testBuild        string
+	verifySignatures bool
+	mergeRequest     bool
+	jobs             int
+	jobsPerHost      int
+	cacheTTL         time.Duration
+	retries          int
+	nvchecker        string
+	timeout          time.Duration
+	runTimeout       time.Duration
+	proxy            string
+	subcommand       string
+	offline          bool
+	otlpEndpoint     string
+	record           string
+	cache            string
+	fromFile         string
+	ignoreFile       string
+	providers        string
+	filter           string
+	progress         bool
+	exclude          string
+	replay           string
+	providerSummary  bool
+	caFile           string
+	insecureHosts    string
+	rateLimit        string
+	listen           string
+	feed             string
+	nagiosWarning    int
+	nagiosCritical   int
+	exitCode         string
+	noColor          bool
+	onlyOutdated     bool
+	quiet            bool
+	verbose          bool
+	veryVerbose      bool
+	logFormat        string
+	changedOnly      bool
+	history          bool
+	historyMaxAge    time.Duration
+	badges           string
+	groupBy          bool
+	sort             string
+	interval         time.Duration
+	debugHTTP        string
+	debugHTTPBodies  bool
+	notifyDesktop    bool
+	perHost          string
+	summary          bool
+	printSrcinfo     bool
+	allSources       bool
+	checkSources     bool
+	checkChecksums   bool
+	followRedirects  int
+	minSeverity      string
+	unmaintainedDays int
+	advisories       bool
+	rewriteMoved     bool
+	compareDistros   bool
+	official         bool
+	repoMaintainer   string
+	syncDB           string
+	directives       bool
+	remoteDirectives bool
+	checkKeys        bool
+	checkHomepage    bool
+	changes          bool
+	spread           float64
+	dnsCache         time.Duration
+	pushgateway      string
+	pushgatewayJob   string
+	pushgatewayInst  string
+	healthcheck      string
+	resume           bool
+	incremental      time.Duration
+	outputFiles      string
+	upload           string
+	lockfile         string
+	updateLock       bool
+}
+
+// cacheTTL returns how long the cached upstream version of the package is reused,
+// the interval configured for the package or its provider in daemon and watch mode, -cache-ttl otherwise.
+// Without both, the packages are checked every -interval in daemon and watch mode, even if waking up more often.
+func cacheTTL(name, provider string) time.Duration {
+	if periodic {
+		if interval, err := conf.Interval(name, provider); err != nil {
+			logging.Warnf("Invalid interval of %s: %v", name, err)
+		} else if interval > 0 {
+			return interval
+		} else if commandline.cacheTTL == 0 {
+			return commandline.interval
+		}
+	}
+	return commandline.cacheTTL
+}
+
+// version determines the upstream version of the package along with the time it has been obtained
+func version(pkg pkg.Pkg) (upstream.Result, time.Time, error) {
+	if resultCache != nil {
+		r, ok := resultCache.Get(pkg.Name(), 0)
+		ttl := cacheTTL(pkg.Name(), r.Provider)
+		if ok && ((commandline.offline && ttl == 0) || (ttl > 0 && time.Since(r.Fetched) <= ttl)) {
+			logging.Debugf("Using cached upstream version %s of %s from %s", r.Version, pkg.Name(), r.Fetched.Format(time.RFC3339))
+			return r.Result, r.Fetched, nil
+		}
+		if ok && commandline.incremental > 0 && r.Unchanged(pkg.LastModified()) && time.Since(r.Fetched) <= commandline.incremental {
+			logging.Debugf("Using cached upstream version %s of %s, unchanged in the AUR since %s", r.Version, pkg.Name(), r.Fetched.Format(time.RFC3339))
+			return r.Result, r.Fetched, nil
+		}
+	}
+	if commandline.offline {
+		return upstream.Result{}, time.Now(), fmt.Errorf("No cached upstream version of %s: %w", pkg.Name(), transport.ErrOffline)
+	}
+	result, err := fetchVersion(pkg)
+	if err == nil {
+		result.Version, err = conf.ExtractResult(pkg.Name(), result)
+	}
+	if err == nil && resultCache != nil {
+		resultCache.Put(pkg.Name(), result, pkg.LastModified())
+	}
+	return result, time.Now(), err
+}
+
+func fetchVersion(pkg pkg.Pkg) (upstream.Result, error) {
+	_, fetch := upstreamSource(pkg)
+	return fetch()
+}
+
+// upstreamSource selects how to determine the upstream version of the package, describing the reason for the selection
+func upstreamSource(pkg pkg.Pkg) (string, func() (upstream.Result, error)) {
 	if script, ok := conf.Scripts[pkg.Name()]; ok {
-		return upstream.VersionForScript(script)
+		return fmt.Sprintf("script `%s` configured in scripts", script), func() (upstream.Result, error) {
+			return upstream.ResultForScript(script)
+		}
+	}
+	overrides := conf.Package(pkg.Name())
+	channel := overrides.Channel
+	origin := func(configured string) string {
+		if configured != "" {
+			return "configured in packages"
+		}
+		return "configured in the PKGBUILD"
+	}
+	// the provider mapping file pins take precedence over the url and provider directives
+	_, pinned := providers[pkg.Name()]
+	if url := overrides.URL; url != "" && (conf.Packages[pkg.Name()].URL != "" || !pinned) {
+		return fmt.Sprintf("URL %s %s", url, origin(conf.Packages[pkg.Name()].URL)), func() (upstream.Result, error) {
+			return upstream.ResultForURLInChannel(url, channel)
+		}
+	}
+	if id := overrides.Provider; id != "" && (conf.Packages[pkg.Name()].Provider != "" || !pinned) {
+		return fmt.Sprintf("provider %s %s", id, origin(conf.Packages[pkg.Name()].Provider)), func() (upstream.Result, error) {
+			entry, err := upstream.ParseIdentifier(id)
+			if err != nil {
+				return upstream.Result{}, err
+			}
+			return upstream.ResultForNvchecker(entry)
+		}
+	}
+	if watch := overrides.Watch; watch != "" {
+		return "watch file rules configured in packages", func() (upstream.Result, error) {
+			return upstream.ResultForWatch(watch, pkg.Name())
+		}
+	}
+	if file := watchFile(pkg); file != "" {
+		return fmt.Sprintf("watch file %s", file), func() (upstream.Result, error) {
+			watch, err := ioutil.ReadFile(file)
+			if err != nil {
+				return upstream.Result{}, fmt.Errorf("Failed to read watch file: %w", err)
+			}
+			return upstream.ResultForWatch(string(watch), pkg.Name())
+		}
+	}
+	if entry, ok := providers[pkg.Name()]; ok {
+		return fmt.Sprintf("%v source pinned in %s", entry["source"], commandline.providers), func() (upstream.Result, error) {
+			return upstream.ResultForNvchecker(entry)
+		}
+	}
+	if entry, ok := nvchecker[pkg.Name()]; ok {
+		return fmt.Sprintf("%v source configured in %s", entry["source"], commandline.nvchecker), func() (upstream.Result, error) {
+			return upstream.ResultForNvchecker(entry)
+		}
+	}
+	if len(conf.Plugins.Packages) > 0 || len(conf.Plugins.Hosts) > 0 {
+		sources, _ := pkg.Sources()
+		if plugin := conf.Plugin(pkg.Name(), append([]string{pkg.URL()}, sources...)...); plugin != "" {
+			return fmt.Sprintf("plugin `%s` configured in plugins", plugin), func() (upstream.Result, error) {
+				return upstream.ResultForPlugin(plugin, upstream.PluginRequest{
+					Name:    pkg.Name(),
+					Version: pkg.Version().String(),
+					URL:     pkg.URL(),
+					Sources: sources,
+					Channel: channel,
+				})
+			}
+		}
+	}
+	if chain := conf.FallbackChain(pkg.Name()); len(chain) > 0 {
+		return fmt.Sprintf("fallback chain %s", strings.Join(chain, " → ")), func() (upstream.Result, error) {
+			return upstream.ResultForChain(chain, pkg, channel)
+		}
+	}
+	reason := "provider matching the URL or the first source"
+	if channel != "" {
+		reason += ", release channel " + channel
+	}
+	return reason, func() (upstream.Result, error) {
+		return upstream.ResultForPkgInChannel(pkg, channel)
 	}
-	return upstream.VersionForPkg(pkg)
 }
 
 func handlePackage(pkg pkg.Pkg) status.Status {
+	readDirectives(pkg)
 
 	pkgVersion := pkg.Version()
 	s := status.Status{
 		Package:          pkg.Name(),
 		FlaggedOutOfDate: pkg.OutOfDate(),
 		Version:          pkgVersion.String(),
+		URL:              pkg.URL(),
+		Maintainer:       pkg.Maintainer(),
+		PKGBUILD:         pkg.LocalPKGBUILD(),
+	}
+	if lastModified := pkg.LastModified(); !lastModified.IsZero() {
+		s.LastModified = &lastModified
+	}
+
+	if skip, err := conf.Skip(pkg.Name(), time.Now()); err != nil {
+		logging.Warnf("Failed to read the skip of %s: %v", pkg.Name(), err)
+	} else if skip != nil {
+		s.Status = status.Unknown
+		s.Reason = "skipped"
+		s.Message = skip.Message()
+		return s
 	}
 
-	upstreamVersion, err := version(pkg)
+	span := tracer.Start(currentRunSpan(), "check "+pkg.Name(), tracing.Internal)
+	defer span.End()
+	span.SetAttribute("aur.package", pkg.Name())
+	span.SetAttribute("aur.version", s.Version)
+	start := time.Now()
+	result, checked, err := version(pkg)
+	s.Duration = time.Since(start)
+	s.Provider = result.Provider
+	s.CheckedAt = checked
+	if result.Ecosystem != nil {
+		s.Purl = result.Ecosystem.Purl()
+	}
+	span.SetAttribute("upstream.provider", result.Provider)
+	span.SetError(err)
 	if err != nil {
+		logging.Log(logging.Info, "Failed to determine upstream version", "pkg", pkg.Name(), "provider", result.Provider, "err", err)
+		reason := upstream.ReasonOf(err)
 		s.Status = status.Unknown
+		if reason == upstream.Gone {
+			s.Status = status.UpstreamGone
+		}
+		s.Reason = string(reason)
 		s.Message = err.Error()
-		statistics.Unknown++
+		s.Error = err.Error()
+		checkCurrentSources(pkg, &s)
+		checkKeys(pkg, &s)
+		checkHomepage(pkg, &s)
+		compareDistros(&s)
 		return s
 	}
+	upstreamVersion := result.Version
+	s.ReleaseURL = result.ReleaseURL
+	s.ReleaseNotes = result.ReleaseNotes
+	if !result.Released.IsZero() {
+		s.Released = &result.Released
+	}
 
-	s.Ignored = conf.IsIgnored(pkg.Name(), upstreamVersion)
-	s.Compare(upstreamVersion)
-	statistics.Update(s.Status)
+	lockfile.Observe(pkg.Name(), upstreamVersion)
+	locked := !commandline.updateLock && lockfile.Locked(pkg.Name(), upstreamVersion)
+	s.Ignored = conf.IsIgnored(pkg.Name(), upstreamVersion) || locked
+	if max := conf.Cap(pkg.Name(), upstreamVersion); max != nil {
+		s.CompareCapped(upstreamVersion, max.Version, max.Reason)
+	} else {
+		s.Compare(upstreamVersion)
+	}
+	if locked && s.Status == status.Unknown {
+		s.Message += fmt.Sprintf(" (locked in %s)", commandline.lockfile)
+	}
+	if (s.Status == status.OutOfDate || s.Status == status.FlaggedOutOfDate) && commandline.advisories && !commandline.offline {
+		lookupAdvisories(pkg, result, &s)
+	}
+	if min := conf.MinSeverity(pkg.Name(), commandline.minSeverity); min != "" {
+		if err := s.ApplyMinSeverity(min); err != nil {
+			logging.Warnf("Invalid min_severity of %s: %v", pkg.Name(), err)
+		}
+	}
+	if (s.Status == status.OutOfDate || s.Status == status.FlaggedOutOfDate) && commandline.changes && !commandline.offline {
+		summarizeChanges(pkg, result, &s)
+	}
+	if s.Status == status.OutOfDate && commandline.verifySignatures {
+		verifySignature(pkg, &s)
+	}
+	checkCurrentSources(pkg, &s)
+	checkKeys(pkg, &s)
+	checkHomepage(pkg, &s)
+	compareDistros(&s)
+	if result.MovedTo != "" {
+		s.MovedFrom, s.MovedTo = result.MovedFrom, result.MovedTo
+		s.Message += fmt.Sprintf(" (upstream moved to %s)", result.MovedTo)
+	}
+	s.MarkUnmaintained(commandline.unmaintainedDays)
+	if commandline.allSources && !commandline.offline {
+		s.Components = components(pkg, result)
+	}
+	span.SetAttribute("upstream.version", upstreamVersion.String())
+	span.SetAttribute("aur.status", string(s.Status))
+	if commandline.offline {
+		s.Cached = &checked
+		s.Message += fmt.Sprintf(" (offline, upstream version from %s)", checked.Format("2006-01-02 15:04"))
+	}
 	return s
 }
 
-func handlePackages(vcsPackages bool, packages []pkg.Pkg, err error) {
+// watchFile returns the debian/watch file next to the local PKGBUILD, "" if none
+func watchFile(pkg pkg.Pkg) string {
+	if pkg.LocalPKGBUILD() == "" {
+		return ""
+	}
+	file := path.Join(path.Dir(pkg.LocalPKGBUILD()), "debian", "watch")
+	if _, err := os.Stat(file); err != nil {
+		return ""
+	}
+	return file
+}
+
+// readDirectives reads the overrides of the "# aur-out-of-date:" comments in the PKGBUILD for -directives,
+// fetching the PKGBUILD of AUR packages only for -remote-directives
+func readDirectives(pkg pkg.Pkg) {
+	if !commandline.directives || (pkg.LocalPKGBUILD() == "" && (!commandline.remoteDirectives || commandline.offline)) {
+		return
+	}
+	directives, err := config.ParseDirectives(pkg.Content())
+	if err != nil {
+		logging.Warnf("Failed to read the directives in the PKGBUILD of %s: %v", pkg.Name(), err)
+		return
+	}
+	conf.SetDirectives(pkg.Name(), directives)
+}
+
+// compareDistros appends the versions shipped by other distributions for -compare-distros
+func compareDistros(s *status.Status) {
+	if !commandline.compareDistros || commandline.offline {
+		return
+	}
+	versions, err := distro.Versions(s.Package)
+	if err != nil {
+		logging.Log(logging.Info, "Failed to look up versions of other distributions", "pkg", s.Package, "err", err)
+		return
+	} else if len(versions) == 0 {
+		return
+	}
+	s.Distros = versions
+	var shipped []string
+	for _, v := range versions {
+		shipped = append(shipped, v.Distribution+" "+v.Version)
+	}
+	s.Message += " (" + strings.Join(shipped, ", ") + ")"
+}
+
+// lookupAdvisories marks the package as high priority if the upstream version fixes security advisories affecting the packaged version
+func lookupAdvisories(pkg pkg.Pkg, result upstream.Result, s *status.Status) {
+	if result.Ecosystem == nil {
+		return
+	}
+	version, latest := string(pkg.Version().Version), string(s.Upstream)
+	if result.Ecosystem.Name == "GIT" && strings.HasPrefix(latest, "v") && !strings.HasPrefix(version, "v") {
+		// Git tags are compared literally
+		version = "v" + version
+	}
+	fixed, err := advisory.Fixed(result.Ecosystem.Name, result.Ecosystem.Package, version, latest)
+	if err != nil {
+		logging.Log(logging.Info, "Failed to look up security advisories", "pkg", s.Package, "err", err)
+		return
+	} else if len(fixed) == 0 {
+		return
+	}
+	for _, v := range fixed {
+		s.Advisories = append(s.Advisories, v.Name())
+	}
+	s.HighPriority = true
+	s.Message += " (high priority, fixes " + strings.Join(s.Advisories, ", ") + ")"
+}
+
+// summarizeChanges lists the commits between the packaged and the upstream tag of GitHub and GitLab repositories for -changes
+func summarizeChanges(pkg pkg.Pkg, result upstream.Result, s *status.Status) {
+	if result.Ecosystem == nil || result.Ecosystem.Name != "GIT" {
+		return
+	}
+	from, to := upstream.Tags(result, string(pkg.Version().Version))
+	changes, err := upstream.Changes(result.Ecosystem.Package, from, to)
+	if err != nil {
+		logging.Log(logging.Info, "Failed to summarize changes", "pkg", pkg.Name(), "err", err)
+		return
+	}
+	s.Changes = changes
+}
+
+// newSources returns the sources of all architectures for the new upstream version
+func newSources(p pkg.Pkg, version string) ([]string, error) {
+	byArch, err := pkg.SourcesForPkgver(p, version)
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	var arches []string
+	for arch := range byArch {
+		arches = append(arches, arch)
+	}
+	sort.Strings(arches)
+	var r []string
+	for _, arch := range arches {
+		r = append(r, byArch[arch]...)
+	}
+	return r, nil
+}
+
+// verifySignature verifies the PGP signature of the upstream version, setting the status BAD-SIGNATURE on failure
+func verifySignature(pkg pkg.Pkg, s *status.Status) {
+	keys, err := pkg.ValidPGPKeys()
+	if err != nil {
+		logging.Warnf("Failed to obtain validpgpkeys of %s: %v", pkg.Name(), err)
+		return
+	}
+	sources, err := newSources(pkg, s.Upstream.String())
+	if err != nil {
+		logging.Warnf("Failed to obtain sources of %s: %v", pkg.Name(), err)
+		return
+	}
+	err = signature.Verify(sources, keys)
+	if err == signature.ErrNoSignature {
+		return
+	} else if err != nil {
+		logging.Log(logging.Info, "Failed to verify signature", "pkg", pkg.Name(), "err", err)
+		s.Status = status.BadSignature
+		s.Message = fmt.Sprintf("Package %s should be updated to %s, but the signature could not be verified", pkg.Name(), s.Upstream.String())
+		s.Error = err.Error()
+	}
+}
+
+// checkKeys warns about revoked, expired or soon expiring validpgpkeys for -check-keys
+func checkKeys(pkg pkg.Pkg, s *status.Status) {
+	if !commandline.checkKeys || commandline.offline {
+		return
+	}
+	keys, err := pkg.ValidPGPKeys()
+	if err != nil {
+		logging.Warnf("Failed to obtain validpgpkeys of %s: %v", pkg.Name(), err)
+		return
+	}
+	warnings, err := signature.CheckKeys(keys)
+	if err != nil {
+		logging.Log(logging.Info, "Failed to check validpgpkeys", "pkg", pkg.Name(), "err", err)
+	}
+	if len(warnings) == 0 {
+		return
+	}
+	for _, warning := range warnings {
+		logging.Warnf("%s: %s", pkg.Name(), warning)
+	}
+	s.KeyWarnings = warnings
+	s.Message += " (" + strings.Join(warnings, ", ") + ")"
+}
+
+// checkHomepage reports a permanently redirected or dead homepage for -check-homepage
+func checkHomepage(pkg pkg.Pkg, s *status.Status) {
+	if !commandline.checkHomepage || commandline.offline {
+		return
+	}
+	if warning := sources.CheckHomepage(pkg.URL()); warning != "" {
+		logging.Warnf("%s: %s", pkg.Name(), warning)
+		s.HomepageWarning = warning
+		s.Message += " (" + warning + ")"
+	}
+}
+
+// checkCurrentSources checks the sources of the current version for -check-sources and -check-checksums,
+// unless a new upstream version explains changes
+func checkCurrentSources(pkg pkg.Pkg, s *status.Status) {
+	if !(commandline.checkSources || commandline.checkChecksums) || commandline.offline || (s.Status != status.UpToDate && s.Status != status.Unknown) {
+		return
+	}
+	list, err := pkg.Sources()
+	if err != nil {
+		logging.Warnf("Failed to obtain sources of %s: %v", pkg.Name(), err)
+		return
+	}
+	pkgver := string(pkg.Version().Version)
+	// keep the error of an unknown upstream version
+	report := func(statusType status.StatusType, message string) {
+		if s.Status == status.Unknown && s.Message != "" {
+			message += "; " + s.Message
+		}
+		s.Status = statusType
+		s.Message = message
+	}
+	if commandline.checkSources {
+		if gone := sources.FindGone(list, pkgver); len(gone) > 0 {
+			var urls []string
+			for _, g := range gone {
+				urls = append(urls, fmt.Sprintf("%s (%s)", g.URL, g.Status))
+			}
+			report(status.SourceGone, "source gone: "+strings.Join(urls, ", "))
+			return
+		}
+	}
+	if commandline.checkChecksums {
+		checksums, err := pkg.Checksums()
+		if err != nil {
+			logging.Warnf("Failed to obtain checksums of %s: %v", pkg.Name(), err)
+			return
+		}
+		if drift := sources.FindDrift(list, checksums, pkgver); len(drift) > 0 {
+			var urls []string
+			for _, d := range drift {
+				urls = append(urls, fmt.Sprintf("%s (%s %s, expected %s)", d.URL, strings.TrimSuffix(d.Name, "s"), d.Actual, d.Expected))
+			}
+			report(status.ChecksumMismatch, fmt.Sprintf("checksum changed at version %s, upstream re-tagged or regenerated: %s", pkgver, strings.Join(urls, ", ")))
+		}
+	}
+}
+
+func handlePackages(vcsPackages bool, packages []pkg.Pkg, err error) {
+	if err != nil && currentRunContext().Err() != nil {
+		abort(len(packages))
+		return
+	} else if err != nil && periodic {
+		// retry in the next run
+		logging.Errorf("Failed to obtain packages: %v", err)
+		checkErrors++
+		return
+	} else if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to obtain packages:", err)
+		os.Exit(1)
 	}
 	sort.Slice(packages, func(i, j int) bool { return strings.Compare(packages[i].Name(), packages[j].Name()) == -1 })
+	var checked []pkg.Pkg
 	for _, pkg := range packages {
-		if vcsPackages == pkg.IsVcs() {
-			s := handlePackage(pkg)
-			if commandline.printJSON {
-				s.PrintJSONTextSequence()
-			} else {
-				s.Print()
+		if vcsPackages == pkg.IsVcs() && selected(pkg.Name()) {
+			checked = append(checked, pkg)
+		}
+	}
+	if runProgress != nil {
+		runProgress.total += len(checked)
+	}
+	results, completed := checkPackages(checked)
+	streaming := streamResults()
+	for n := range checked {
+		i := n
+		if streaming {
+			select {
+			case i = <-completed:
+			case <-currentRunContext().Done():
 			}
-			if s.Status == status.OutOfDate && commandline.flagOnAur {
-				action.FlagOnAur(pkg, s.Upstream)
+		}
+		pkg := checked[i]
+		_, resumed := checkpoint.Lookup(pkg.Name(), pkg.Version().String())
+		s, ok := receive(results[i], pkg.Name())
+		runProgress.clear()
+		if !ok {
+			abort(len(checked) - n)
+			return
+		}
+		runProgress.advance()
+		statistics.Update(s.Status)
+		if s.Unmaintained {
+			statistics.Unmaintained++
+		}
+		if s.Error != "" {
+			checkErrors++
+		}
+		formatter.Status(&s)
+		if commandline.subcommand == "triage" && s.Status != status.UpToDate {
+			triageItems = append(triageItems, triageItem{pkg, s})
+		}
+		if resumed {
+			// the actions have been performed by the interrupted run
+			continue
+		}
+		if s.Status == status.OutOfDate && commandline.flagOnAur {
+			action.FlagOnAur(pkg, s.Upstream)
+		}
+		if s.MovedTo != "" && commandline.rewriteMoved {
+			action.RewriteMoved(pkg, s.MovedFrom, s.MovedTo)
+		}
+		if s.Status == status.OutOfDate && commandline.updatePKGBUILD && action.UpdatePKGBUILD(pkg, s.Upstream) {
+			if commandline.push {
+				action.PublishPKGBUILD(pkg, s.Upstream)
+			} else if commandline.mergeRequest {
+				action.OpenMergeRequest(pkg, s.Upstream, mergeRequesters()...)
+			}
+		}
+		if err := checkpoint.Add(&s); err != nil {
+			logging.Warnf("Failed to record checkpoint: %v", err)
+		}
+	}
+}
+
+// streamResults reports whether the results are handled as soon as their check finishes instead of in order,
+// i.e. for the streaming formats ndjson and json-seq unless sorted or grouped
+func streamResults() bool {
+	return (commandline.output == "ndjson" || commandline.output == "json-seq") && commandline.sort == "" && !commandline.groupBy
+}
+
+// checkPackages checks the packages using -jobs workers, returning a channel per package to receive the status in order
+// and a channel receiving the indices of the packages in the order their checks finish
+func checkPackages(packages []pkg.Pkg) ([]chan status.Status, <-chan int) {
+	results := make([]chan status.Status, len(packages))
+	completed := make(chan int, len(packages))
+	indices := make(chan int, len(packages))
+	for i := range packages {
+		results[i] = make(chan status.Status, 1)
+		indices <- i
+	}
+	close(indices)
+	jobs := commandline.jobs
+	if jobs < 1 {
+		jobs = 1
+	}
+	checkpoint := checkpoint
+	spread := newSpreader(len(packages))
+	for w := 0; w < jobs; w++ {
+		go func() {
+			for i := range indices {
+				if s, ok := checkpoint.Lookup(packages[i].Name(), packages[i].Version().String()); ok {
+					results[i] <- s
+					completed <- i
+				} else if currentRunContext().Err() == nil && spread.wait(currentRunContext()) {
+					results[i] <- handlePackage(packages[i])
+					completed <- i
+				}
 			}
-			if s.Status == status.OutOfDate && commandline.updatePKGBUILD {
-				action.UpdatePKGBUILD(pkg, s.Upstream)
+		}()
+	}
+	return results, completed
+}
+
+// abort reports that the run has been aborted before checking all packages
+func abort(unchecked int) {
+	if !aborted {
+		logging.Warnf("Aborted: %v", currentRunContext().Err())
+	}
+	aborted = true
+	if unchecked > 0 {
+		logging.Infof("Skipped checking %d packages, resume using -resume", unchecked)
+	}
+}
+
+// mergeRequesters returns the configured issue trackers supporting merge requests
+func mergeRequesters() []action.MergeRequester {
+	var r []action.MergeRequester
+	for _, tracker := range conf.Issues.Trackers() {
+		if m, ok := tracker.(action.MergeRequester); ok {
+			r = append(r, m)
+		}
+	}
+	return r
+}
+
+// pingHealthcheck sends the event to the -healthcheck URL, if any
+func pingHealthcheck(event healthcheck.Event, body string) {
+	if commandline.healthcheck == "" {
+		return
+	}
+	if err := healthcheck.Ping(commandline.healthcheck, event, body); err != nil {
+		logging.Warnf("Failed to ping healthcheck: %v", err)
+	}
+}
+
+// run checks all packages given on the command line
+func run(printStatistics bool) {
+	statistics = status.Statistics{}
+	checkErrors = 0
+	aborted = false
+	setRun(interrupted, tracer.Start(nil, "aur-out-of-date", tracing.Internal))
+	defer func() { setRun(interrupted, runSpan) }()
+	start := time.Now()
+	pingHealthcheck(healthcheck.Start, "")
+	var err error
+	if checkpoint, err = state.OpenCheckpoint(path.Join(state.Dir(), "checkpoint.ndjson"), commandline.resume); err != nil {
+		logging.Warnf("Failed to open checkpoint: %v", err)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			pingHealthcheck(healthcheck.Fail, fmt.Sprint(r))
+			panic(r)
+		}
+	}()
+	runProgress = nil
+	if commandline.progress && (commandline.subcommand == "" || commandline.subcommand == "watch") && commandline.listen == "" && !commandline.verbose && !commandline.veryVerbose && commandline.debugHTTP != "-" && isTerminal(os.Stderr) {
+		runProgress = &progress{w: os.Stderr}
+	}
+	if commandline.runTimeout > 0 {
+		ctx, cancel := context.WithTimeout(interrupted, commandline.runTimeout)
+		defer cancel()
+		setRun(ctx, runSpan)
+	}
+	if commandline.repoMaintainer != "" {
+		packages, err := pkg.NewMaintainerPkgs(commandline.repoMaintainer)
+		handlePackages(commandline.includeVcsPkgs, packages, err)
+	} else if commandline.syncDB != "" {
+		packages, err := pkg.NewSyncDBPkgs(commandline.syncDB)
+		handlePackages(commandline.includeVcsPkgs, packages, err)
+	} else if commandline.official {
+		if names, err := packageArgs(); err != nil {
+			handlePackages(false, nil, err)
+		} else {
+			packages, err := pkg.NewOfficialPkgs(names)
+			handlePackages(false, packages, err)
+			handlePackages(true, packages, err)
+		}
+	} else if commandline.user != "" {
+		packages, err := aur.SearchBy(commandline.user, aur.Maintainer)
+		handlePackages(commandline.includeVcsPkgs, pkg.NewRemotePkgs(packages), err)
+	} else if commandline.remote {
+		pkgs, err := packageArgs()
+		if err != nil {
+			handlePackages(false, nil, err)
+		}
+		for len(pkgs) > 0 && !aborted {
+			limit := 100
+			if len(pkgs) < limit {
+				limit = len(pkgs)
 			}
+			packages, err := aur.Info(pkgs[:limit])
+			handlePackages(false, pkg.NewRemotePkgs(packages), err)
+			handlePackages(true, pkg.NewRemotePkgs(packages), err)
+			pkgs = pkgs[limit:]
+		}
+	} else if commandline.local {
+		if paths, err := packageArgs(); err != nil {
+			handlePackages(false, nil, err)
+		} else {
+			packages, err := localPackages(paths, commandline.includeVcsPkgs)
+			handlePackages(false, packages, err)
+			handlePackages(true, packages, err)
+		}
+	}
+	if resultCache != nil {
+		if err := resultCache.Save(); err != nil {
+			logging.Errorf("Failed to save upstream versions: %v", err)
 		}
 	}
+	if printStatistics {
+		formatter.Finish(&statistics)
+	} else {
+		formatter.Finish(nil)
+	}
+	if commandline.summary {
+		fmt.Fprintln(os.Stderr, statistics.Summary(time.Since(start)))
+	}
+	if err := checkpoint.Close(!aborted); err != nil {
+		logging.Warnf("Failed to close checkpoint: %v", err)
+	}
+	if commandline.updateLock {
+		if err := lockfile.Write(); err != nil {
+			logging.Errorf("Failed to write lockfile: %v", err)
+		}
+	}
+	if aborted {
+		pingHealthcheck(healthcheck.Fail, "Aborted: "+statistics.Summary(time.Since(start)))
+	} else {
+		pingHealthcheck(healthcheck.Success, statistics.Summary(time.Since(start)))
+	}
+	runSpan.End()
+	if err := tracer.Export(commandline.otlpEndpoint); err != nil {
+		logging.Warnf("%v", err)
+	}
+	if cassette != nil {
+		if err := cassette.Save(commandline.record); err != nil {
+			logging.Errorf("Failed to save cassette: %v", err)
+		}
+	}
+	check := state.Check{Finished: time.Now(), Statistics: &statistics, Errors: checkErrors, Aborted: aborted}
+	if err := state.Save(path.Join(state.Dir(), "last-check.json"), check); err != nil {
+		logging.Errorf("Failed to save last check: %v", err)
+	}
 }
 
 func main() {
 	configDir, _ := os.UserConfigDir()
-	defaultConfigFile := path.Join(configDir, "aur-out-of-date", "config.json")
+	defaultConfigFile := config.DefaultFile(path.Join(configDir, "aur-out-of-date"))
 	flag.StringVar(&commandline.user, "user", "", "AUR username")
 	flag.StringVar(&commandline.config, "config", defaultConfigFile, "Config file")
 	flag.BoolVar(&commandline.remote, "pkg", false, "AUR package name(s)")
@@ -98,13 +892,103 @@ func main() {
 	flag.BoolVar(&commandline.printStatistics, "statistics", false, "Print summary statistics")
 	flag.BoolVar(&commandline.flagOnAur, "flag", false, "Flag out-of-date on AUR")
 	flag.BoolVar(&commandline.updatePKGBUILD, "update", false, "Update pkgver/pkgrel in local PKGBUILD files")
-	flag.BoolVar(&commandline.printJSON, "json", false, "Generate JSON Text Sequences (RFC 7464)")
+	flag.BoolVar(&commandline.assumeYes, "yes", false, "Do not prompt before -flag and -update")
+	flag.BoolVar(&commandline.mergeRequest, "merge-request", false, "Open a merge request on the repositories configured in issues for packages updated by -update")
+	flag.BoolVar(&commandline.push, "push", false, "Commit and push packages updated by -update to AUR")
+	flag.BoolVar(&commandline.dryRun, "dry-run", false, "Perform all checks, but only print the actions of -flag, -update, -push, -merge-request, notifications and issues")
+	flag.BoolVar(&commandline.verifySignatures, "verify-signatures", false, "Verify the PGP signature of new upstream versions against validpgpkeys using gpg")
+	flag.StringVar(&commandline.testBuild, "test-build", "", "Command to test build packages before -push, e.g. \"makepkg --nobuild\"")
+	flag.BoolVar(&commandline.printJSON, "json", false, "Generate JSON Text Sequences (RFC 7464), same as -o json-seq")
+	flag.StringVar(&commandline.output, "o", "text", "Output format ("+strings.Join(status.Formats, ", ")+")")
+	flag.StringVar(&commandline.lockfile, "lockfile", "", "Only report upstream versions newer than those locked in the given file, e.g. versions.lock committed for CI")
+	flag.BoolVar(&commandline.updateLock, "update-lock", false, "Accept the upstream versions found by writing them to -lockfile")
+	flag.StringVar(&commandline.upload, "upload", "", "Upload the JSON results after each run using PUT to an http(s):// URL (e.g. WebDAV) or to s3://bucket/key, {hostname} is replaced")
+	flag.StringVar(&commandline.outputFiles, "output-files", "", "Additionally write the results to files as comma-separated format=file pairs, e.g. json=results.json,html=report.html")
+	flag.StringVar(&commandline.listen, "listen", "", "Serve Prometheus metrics on the given address (e.g. :9110) and re-check periodically")
+	flag.DurationVar(&commandline.interval, "interval", time.Hour, "Interval between checks in daemon and watch mode or when serving metrics")
+	flag.Float64Var(&commandline.spread, "spread", 0, "Spread the checks over the given fraction of -interval (e.g. 0.8) in daemon and watch mode instead of checking all packages at once")
+	flag.StringVar(&commandline.feed, "feed", "", "Update Atom feed file with newly out-of-date packages")
+	flag.DurationVar(&commandline.incremental, "incremental", 0, "Reuse upstream versions obtained within the given duration (e.g. 24h) for packages unchanged in the AUR since, 0 to disable")
+	flag.BoolVar(&commandline.resume, "resume", false, "Resume the previous run if it has been interrupted, only checking the packages not finished")
+	flag.StringVar(&commandline.healthcheck, "healthcheck", "", "Ping the given URL (e.g. https://hc-ping.com/<uuid>) on start, success and failure of each run")
+	flag.StringVar(&commandline.pushgateway, "pushgateway", "", "Push the metrics of each run to the Prometheus Pushgateway at the given URL")
+	flag.StringVar(&commandline.pushgatewayJob, "pushgateway-job", "aur-out-of-date", "Job label of the metrics pushed using -pushgateway")
+	flag.StringVar(&commandline.pushgatewayInst, "pushgateway-instance", "", "Instance label of the metrics pushed using -pushgateway, omitted if empty")
+	flag.IntVar(&commandline.nagiosWarning, "w", 1, "Number of out-of-date packages resulting in WARNING for -o nagios (0 to disable)")
+	flag.IntVar(&commandline.nagiosCritical, "c", 0, "Number of out-of-date packages resulting in CRITICAL for -o nagios (0 to disable)")
+	flag.StringVar(&commandline.exitCode, "exit-code", "out-of-date", "Exit code policy: out-of-date (exit 4), error (exit 5), any, never")
+	flag.BoolVar(&commandline.noColor, "no-color", false, "Disable colors (also disabled by NO_COLOR or if output is not a terminal)")
+	flag.BoolVar(&commandline.onlyOutdated, "only-outdated", false, "Do not print up-to-date packages")
+	flag.BoolVar(&commandline.quiet, "quiet", false, "Only print out-of-date packages (implies -only-outdated, hides unknown packages)")
+	flag.BoolVar(&commandline.verbose, "v", false, "Log diagnostic messages to stderr")
+	flag.BoolVar(&commandline.veryVerbose, "vv", false, "Log debug messages (e.g. HTTP requests) to stderr")
+	flag.StringVar(&commandline.logFormat, "log-format", "text", "Log format (text, json)")
+	flag.BoolVar(&commandline.changedOnly, "changed-only", false, "Only print packages whose status changed since the last run")
+	flag.BoolVar(&commandline.history, "history", false, "Record the observed versions in history.jsonl in the state directory, see the history and stats subcommands")
+	flag.DurationVar(&commandline.historyMaxAge, "history-max-age", 365*24*time.Hour, "Drop recorded versions observed longer ago than the given duration, except for the latest one of each package, 0 to keep all")
+	flag.StringVar(&commandline.badges, "badges", "", "Write an SVG status badge per package to the given directory")
+	flag.BoolVar(&commandline.groupBy, "group-by-maintainer", false, "Group packages by maintainer")
+	flag.StringVar(&commandline.sort, "sort", "", "Sort the packages ("+strings.Join(status.SortKeys, ", ")+"), default is the order of checking")
+	flag.IntVar(&commandline.jobs, "jobs", 8, "Number of packages to check concurrently")
+	flag.IntVar(&commandline.jobsPerHost, "jobs-per-host", 4, "Maximum number of concurrent requests per host")
+	flag.DurationVar(&commandline.cacheTTL, "cache-ttl", 0, "Reuse upstream versions obtained within the given duration (e.g. 1h), 0 to disable")
+	flag.IntVar(&commandline.retries, "retries", 2, "Number of retries for failed HTTP requests (network errors, 429, 5xx)")
+	flag.StringVar(&commandline.nvchecker, "nvchecker", "", "Read the upstream sources of packages from an nvchecker TOML configuration")
+	flag.DurationVar(&commandline.timeout, "timeout", 30*time.Second, "Timeout of each HTTP request including retries, 0 to disable")
+	flag.DurationVar(&commandline.runTimeout, "run-timeout", 0, "Abort checking after the given duration and print the partial results, 0 to disable")
+	flag.DurationVar(&commandline.dnsCache, "dns-cache", 5*time.Minute, "Cache DNS lookups for the given duration, 0 to disable")
+	flag.StringVar(&commandline.proxy, "proxy", "", "Proxy URL (http, https, socks5), default from HTTP_PROXY/HTTPS_PROXY/NO_PROXY")
+	flag.StringVar(&commandline.caFile, "ca-file", "", "Trust the PEM encoded CA certificates in the given file in addition to the system ones")
+	flag.StringVar(&commandline.insecureHosts, "insecure-skip-verify", "", "DANGEROUS: Skip TLS certificate verification for the given comma-separated hosts")
+	flag.StringVar(&commandline.rateLimit, "rate-limit", "aur.archlinux.org=1", "Maximum requests per second per host as comma-separated host=rate pairs")
+	if len(os.Args) > 1 && contains(subcommands, os.Args[1]) {
+		commandline.subcommand = os.Args[1]
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
+	flag.BoolVar(&commandline.offline, "offline", false, "Report the upstream versions cached by previous runs (of any age) without network access")
+	flag.StringVar(&commandline.otlpEndpoint, "otlp-endpoint", os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"), "Export traces of check runs to the OpenTelemetry collector using OTLP/HTTP, e.g. http://localhost:4318")
+	flag.BoolVar(&commandline.providerSummary, "provider-summary", false, "Print the number of checks, errors and the p95 latency per provider to stderr")
+	flag.StringVar(&commandline.cache, "cache", "", "Cache HTTP responses and upstream versions in the given directory or Redis server (redis://[:password@]host[:port][/db]), default is the cache directory")
+	flag.StringVar(&commandline.ignoreFile, "ignore-file", path.Join(configDir, "aur-out-of-date", "ignore"), "Ignore file with a package per line, optionally snoozed up to a version (foo <= 2.5)")
+	flag.StringVar(&commandline.providers, "providers", path.Join(configDir, "aur-out-of-date", "providers"), "Provider mapping file pinning packages to a provider (ripgrep = github:BurntSushi/ripgrep)")
+	flag.StringVar(&commandline.filter, "filter", "", "Only check packages whose name matches the regular expression, e.g. 'python-.*'")
+	flag.StringVar(&commandline.exclude, "exclude", "", "Do not check packages whose name matches the regular expression, e.g. '.*-git'")
+	flag.BoolVar(&commandline.progress, "progress", true, "Show the progress on stderr if it is a terminal (disable using -progress=false)")
+	flag.StringVar(&commandline.fromFile, "from-file", "", "Read the AUR package names (or .SRCINFO files for -local) from the given file, one per line (use - as argument for stdin)")
+	flag.StringVar(&commandline.record, "record", "", "Record all HTTP interactions to the given cassette file, e.g. as test fixture")
+	flag.StringVar(&commandline.replay, "replay", "", "Serve all HTTP requests from the given cassette file recorded using -record")
+	flag.Var(optionalFile{&commandline.debugHTTP}, "debug-http", "Dump the headers of all HTTP requests and responses with redacted credentials to stderr, or to the given file using -debug-http=FILE")
+	flag.BoolVar(&commandline.debugHTTPBodies, "debug-http-bodies", false, "Include the request and response bodies in -debug-http")
+	flag.BoolVar(&commandline.notifyDesktop, "notify-desktop", false, "Send desktop notifications about out-of-date packages, e.g. using aur-out-of-date watch")
+	flag.StringVar(&commandline.perHost, "per-host", "", "Maximum request rate to each host not listed in -rate-limit, e.g. 5/s or 60/m")
+	flag.BoolVar(&commandline.summary, "summary", true, "Print a summary line with the number of packages per status and the duration to stderr (disable using -summary=false)")
+	flag.BoolVar(&commandline.printSrcinfo, "printsrcinfo", false, "With -local, evaluate the PKGBUILD next to each given file (or directory) using makepkg --printsrcinfo, sandboxed using bwrap (required)")
+	flag.BoolVar(&commandline.allSources, "all-sources", false, "Check every source mapping to a provider (e.g. bundled libraries) and report their status below the package")
+	flag.BoolVar(&commandline.changes, "changes", false, "Summarize the commits between the packaged and the upstream tag of GitHub and GitLab repositories")
+	flag.BoolVar(&commandline.checkKeys, "check-keys", false, "Warn about validpgpkeys which are revoked, expired or expire within 30 days according to the keyserver")
+	flag.BoolVar(&commandline.checkHomepage, "check-homepage", false, "Issue a HEAD request for the url= homepage and warn about permanent redirects (homepage moved) and dead pages (404, 410)")
+	flag.BoolVar(&commandline.checkSources, "check-sources", false, "Issue HEAD requests for the sources of the current version and report sources deleted upstream (404, 410) as SOURCE-GONE")
+	flag.BoolVar(&commandline.checkChecksums, "check-checksums", false, "Download the sources of the current version and report checksums differing from the PKGBUILD (re-tagged upstream) as CHECKSUM-MISMATCH")
+	flag.IntVar(&commandline.followRedirects, "follow-redirects", 3, "Number of redirects to follow using HEAD requests to detect the provider of unsupported URLs (vanity domains, shortlinks), 0 to disable")
+	flag.StringVar(&commandline.minSeverity, "min-severity", "", "Only report updates of at least the given level ("+strings.Join(status.Severities, ", ")+"), e.g. minor to ignore patch updates")
+	flag.IntVar(&commandline.unmaintainedDays, "unmaintained-days", 0, "Mark packages not updated on AUR for the given number of days although upstream is active as possibly unmaintained, 0 to disable")
+	flag.BoolVar(&commandline.advisories, "advisories", false, "Look up security advisories fixed by the upstream version using OSV, mark such updates as high priority")
+	flag.BoolVar(&commandline.rewriteMoved, "rewrite-moved", false, "Rewrite the location of upstream repositories which have been renamed or transferred in local PKGBUILD files")
+	flag.BoolVar(&commandline.official, "official", false, "Official repository package name(s), looked up using archlinux.org")
+	flag.StringVar(&commandline.repoMaintainer, "repo-maintainer", "", "Check the official repository packages maintained by the given Arch Linux packager")
+	flag.StringVar(&commandline.syncDB, "sync-db", "", "Check the packages of the given pacman sync database, e.g. /var/lib/pacman/sync/extra.db")
+	flag.BoolVar(&commandline.directives, "directives", true, "Read per-package overrides from \"# aur-out-of-date:\" comments in local PKGBUILDs")
+	flag.BoolVar(&commandline.remoteDirectives, "remote-directives", false, "With -directives, also fetch the PKGBUILD of AUR packages for each check to read their directives")
+	flag.BoolVar(&commandline.compareDistros, "compare-distros", false, "Show the versions shipped by nixpkgs, Homebrew and Debian unstable using Repology")
 	flag.Parse()
 
-	// cache HTTP requests (RFC 7234)
-	cacheDir, _ := os.UserCacheDir()
-	cacheDir = path.Join(cacheDir, "aur-out-of-date")
-	http.DefaultClient = httpcache.NewTransport(diskcache.New(cacheDir)).Client()
+	if commandline.subcommand == "completion" {
+		if err := printCompletion(os.Stdout, flag.Arg(0)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	if c, err := config.FromFile(commandline.config); err != nil {
 		fmt.Fprintln(os.Stderr, "Failed to read config:", err)
@@ -112,37 +996,336 @@ func main() {
 	} else {
 		conf = c
 	}
+	if err := conf.Apply(flag.CommandLine); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to apply config:", err)
+		os.Exit(1)
+	}
+	if rules, err := config.FromIgnoreFile(commandline.ignoreFile); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to read ignore file:", err)
+		os.Exit(1)
+	} else {
+		conf.IgnoreRules = rules
+	}
+	if commandline.updateLock && commandline.lockfile == "" {
+		fmt.Fprintln(os.Stderr, "-update-lock requires -lockfile")
+		os.Exit(1)
+	} else if commandline.lockfile != "" {
+		l, err := config.ReadLockfile(commandline.lockfile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to read lockfile:", err)
+			os.Exit(1)
+		}
+		lockfile = l
+	}
 
-	if commandline.user != "" {
-		packages, err := aur.SearchBy(commandline.user, aur.Maintainer)
-		handlePackages(commandline.includeVcsPkgs, pkg.NewRemotePkgs(packages), err)
-	} else if commandline.remote {
-		pkgs := flag.Args()
-		for len(pkgs) > 0 {
-			limit := 100
-			if len(pkgs) < limit {
-				limit = len(pkgs)
-			}
-			packages, err := aur.Info(pkgs[:limit])
-			handlePackages(false, pkg.NewRemotePkgs(packages), err)
-			handlePackages(true, pkg.NewRemotePkgs(packages), err)
-			pkgs = pkgs[limit:]
+	if commandline.veryVerbose {
+		logging.SetLevel(logging.Debug)
+	} else if commandline.verbose {
+		logging.SetLevel(logging.Info)
+	}
+	logging.SetJSON(commandline.logFormat == "json")
+	action.AssumeYes = commandline.assumeYes
+	action.DryRun = commandline.dryRun
+	action.TestBuild = commandline.testBuild
+	upstream.MaxRedirects = commandline.followRedirects
+
+	status.Colors = !commandline.noColor && os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout)
+
+	if commandline.subcommand == "history" {
+		if err := printHistory(os.Stdout, flag.Args(), commandline.output); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
 		}
-	} else if commandline.local {
-		packages, err := pkg.NewLocalPkgs(flag.Args(), commandline.includeVcsPkgs)
-		handlePackages(false, packages, err)
-		handlePackages(true, packages, err)
+		return
+	} else if commandline.subcommand == "stats" {
+		if err := printStats(os.Stdout, flag.Args(), commandline.output); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if commandline.printJSON {
+		commandline.output = "json-seq"
+	}
+	if f, err := newFormatter(os.Stdout, commandline.output); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	} else {
+		formatter = f
+	}
+	// cache HTTP requests (RFC 7234)
+	var backend cache.Cache
+	if strings.HasPrefix(commandline.cache, "redis://") {
+		c, err := cache.Open(commandline.cache)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		backend = c
+	}
+	httpCache := cache.Dir(state.CacheDir())
+	if backend != nil {
+		httpCache = cache.Prefix(backend, "aur-out-of-date:http:")
+	} else if commandline.cache != "" {
+		httpCache = cache.Dir(commandline.cache)
+	}
+	base, err := transport.Base(commandline.proxy)
+	if err == nil {
+		transport.CacheDNS(base, commandline.dnsCache)
+	}
+	if err == nil && commandline.caFile != "" {
+		err = transport.AddCA(base, commandline.caFile)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	var insecureHosts []string
+	if commandline.insecureHosts != "" {
+		insecureHosts = strings.Split(commandline.insecureHosts, ",")
+		logging.Warnf("Skipping TLS certificate verification for %s", commandline.insecureHosts)
+	}
+	credentials, err := transport.ReadNetrc(transport.NetrcFile())
+	if err != nil {
+		logging.Warnf("%v", err)
+		credentials = map[string]transport.Credentials{}
+	}
+	for host, c := range conf.Auth {
+		credentials[host] = c
+	}
+	authenticated := transport.Auth(transport.Insecure(base, insecureHosts...), credentials)
+	http.DefaultTransport = transport.UserAgent(transport.Headers(authenticated, conf.RequestHeaders()), transport.UserAgentWithContact(conf.Contact))
+	if conf.AUR.Username != "" {
+		action.AURWeb = aurweb.NewClient(conf.AUR, path.Join(state.Dir(), "aurweb-session.json"))
+	}
+	rates, err := transport.ParseRates(commandline.rateLimit)
+	if err == nil && commandline.perHost != "" {
+		rates["*"], err = transport.ParseRate(commandline.perHost)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if commandline.offline {
+		http.DefaultTransport = transport.Offline()
+		commandline.retries = 0
+		commandline.cacheTTL = 0
+	}
+	var rt http.RoundTripper
+	if commandline.replay != "" {
+		c, err := transport.LoadCassette(commandline.replay)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to read cassette:", err)
+			os.Exit(1)
+		}
+		rt = transport.Replay(c)
 	} else {
+		rt = transport.RateLimit(http.DefaultTransport, rates)
+		rt = transport.Retry(rt, commandline.retries, time.Second)
+		rt = transport.Cache(rt, httpCache)
+	}
+	if commandline.record != "" {
+		cassette = &transport.Cassette{}
+		rt = transport.Record(rt, cassette)
+	}
+	rt = transport.PerHostLimit(rt, commandline.jobsPerHost)
+	rt = transport.WithContext(rt, currentRunContext)
+	if commandline.otlpEndpoint != "" {
+		tracer = tracing.New("aur-out-of-date")
+	}
+	rt = transport.Trace(rt, tracer, currentRunSpan)
+	if commandline.offline {
+		rt = transport.OnlyIfCached(rt)
+	}
+	rt = transport.Log(rt)
+	if w, err := debugHTTPWriter(); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to open HTTP debug file:", err)
+		os.Exit(1)
+	} else if w != nil {
+		rt = transport.Dump(rt, w, commandline.debugHTTPBodies)
+	}
+	http.DefaultClient = &http.Client{Transport: rt, Timeout: commandline.timeout}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupted = ctx
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		cancel()
+		<-signals
+		os.Exit(130)
+	}()
+
+	cached := commandline.cacheTTL > 0 || commandline.offline || conf.MinInterval() > 0 || commandline.incremental > 0
+	if cached && backend != nil {
+		resultCache = state.NewResultCache(cache.Prefix(backend, "aur-out-of-date:result:"))
+	} else if cached {
+		resultsFile := path.Join(state.Dir(), "results.json")
+		if commandline.cache != "" {
+			resultsFile = path.Join(commandline.cache, "results.json")
+		}
+		c, err := state.LoadResultCache(resultsFile)
+		if err != nil {
+			logging.Warnf("Failed to read cached upstream versions: %v", err)
+		}
+		resultCache = c
+	}
+
+	if commandline.nvchecker != "" {
+		entries, err := config.FromNvchecker(commandline.nvchecker)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to read nvchecker config:", err)
+			os.Exit(1)
+		}
+		nvchecker = entries
+	}
+	if entries, err := config.FromProviderFile(commandline.providers); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to read provider mapping:", err)
+		os.Exit(1)
+	} else {
+		providers = entries
+	}
+
+	if err := compilePatterns(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	switch commandline.exitCode {
+	case "out-of-date", "error", "any", "never":
+	default:
+		fmt.Fprintln(os.Stderr, "Unknown exit code policy:", commandline.exitCode)
+		os.Exit(1)
+	}
+	if commandline.spread < 0 || commandline.spread > 1 {
+		fmt.Fprintln(os.Stderr, "-spread must be between 0 and 1:", commandline.spread)
+		os.Exit(1)
+	}
+	if commandline.minSeverity != "" && !contains(status.Severities, commandline.minSeverity) {
+		fmt.Fprintln(os.Stderr, "Unknown severity:", commandline.minSeverity)
+		os.Exit(1)
+	}
+
+	stdin := false
+	for _, arg := range flag.Args() {
+		if arg == "-" {
+			stdin = true
+			packages, err := readPackageList(os.Stdin)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Failed to read package list:", err)
+				os.Exit(1)
+			}
+			stdinPackages = packages
+			break
+		}
+	}
+	if commandline.subcommand == "explain" {
+		packages, err := explainPackages(flag.Args())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		explain(os.Stdout, packages)
+		return
+	} else if commandline.subcommand == "lint" {
+		n, err := lintPackages(os.Stdout, flag.Args())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		} else if n > 0 {
+			os.Exit(4)
+		}
+		return
+	} else if commandline.subcommand == "init" {
+		if commandline.user == "" {
+			fmt.Fprintln(os.Stderr, "Usage: aur-out-of-date init -user <name>")
+			os.Exit(1)
+		}
+		if err := initConfig(commandline.config, commandline.user); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	} else if commandline.subcommand == "check-url" {
+		if flag.NArg() != 1 {
+			fmt.Fprintln(os.Stderr, "Usage: aur-out-of-date check-url <url>")
+			os.Exit(1)
+		}
+		if err := checkURL(os.Stdout, flag.Arg(0)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	official := commandline.official || commandline.repoMaintainer != "" || commandline.syncDB != ""
+	if official && (commandline.flagOnAur || commandline.push || commandline.mergeRequest) {
+		fmt.Fprintln(os.Stderr, "-flag, -push and -merge-request are not supported for official packages")
+		os.Exit(1)
+	}
+	if commandline.user == "" && !commandline.remote && !commandline.local && !official && (commandline.fromFile != "" || stdin) {
+		commandline.remote = true
+	}
+	if commandline.user == "" && !commandline.remote && !commandline.local && !official {
 		fmt.Fprintln(os.Stderr, "Either -user or -pkg or -local is required!")
 		flag.Usage()
 		os.Exit(1)
 	}
-	if commandline.printStatistics && commandline.printJSON {
-		statistics.PrintJSONTextSequence()
-	} else if commandline.printStatistics {
-		statistics.Print()
+
+	if commandline.listen != "" {
+		serveMetrics(commandline.listen)
+		return
+	} else if commandline.subcommand == "daemon" {
+		daemon()
+		return
+	} else if commandline.subcommand == "watch" {
+		watchPackages()
+		return
 	}
-	if statistics.OutOfDate > 0 {
-		os.Exit(4)
+
+	run(commandline.printStatistics)
+	if commandline.subcommand == "triage" && !aborted {
+		triage(os.Stdout)
+		return
+	}
+	if aborted {
+		os.Exit(1)
+	}
+	if nagios != nil {
+		os.Exit(nagios.ExitCode(&statistics))
+	}
+	os.Exit(exitCode(commandline.exitCode))
+}
+
+// isTerminal determines whether f refers to a terminal (character device)
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// exitCode determines the process exit code according to the given policy
+func exitCode(policy string) int {
+	// a bad signature, gone sources, re-tagged sources or a gone upstream require action just like a new upstream version
+	outOfDate := statistics.Failing() > 0
+	failed := checkErrors > 0
+	switch policy {
+	case "never":
+		return 0
+	case "error":
+		if failed {
+			return 5
+		}
+	case "any":
+		if outOfDate {
+			return 4
+		} else if failed {
+			return 5
+		}
+	default:
+		if outOfDate {
+			return 4
+		}
 	}
+	return 0
 }
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestExitCode(t *testing.T) {
+	defer func() { statistics, checkErrors = status.Statistics{}, 0 }()
+	upToDate := status.Statistics{UpToDate: 3, FlaggedOutOfDate: 1, Unknown: 1}
+	outOfDate := status.Statistics{UpToDate: 3, OutOfDate: 1}
+	gone := status.Statistics{UpToDate: 3, UpstreamGone: 1}
+	for _, test := range []struct {
+		policy      string
+		statistics  status.Statistics
+		checkErrors int
+		expected    int
+	}{
+		{"out-of-date", upToDate, 0, 0},
+		{"out-of-date", upToDate, 1, 0},
+		{"out-of-date", outOfDate, 0, 4},
+		{"out-of-date", gone, 0, 4},
+		{"out-of-date", status.Statistics{BadSignature: 1}, 0, 4},
+		{"out-of-date", status.Statistics{SourceGone: 1}, 0, 4},
+		{"out-of-date", status.Statistics{ChecksumMismatch: 1}, 0, 4},
+		{"error", upToDate, 0, 0},
+		{"error", outOfDate, 0, 0},
+		{"error", upToDate, 1, 5},
+		{"error", outOfDate, 1, 5},
+		{"any", upToDate, 0, 0},
+		{"any", outOfDate, 0, 4},
+		{"any", upToDate, 1, 5},
+		{"any", outOfDate, 1, 4},
+		{"never", outOfDate, 1, 0},
+		{"", outOfDate, 0, 4},
+	} {
+		statistics, checkErrors = test.statistics, test.checkErrors
+		if actual := exitCode(test.policy); actual != test.expected {
+			t.Errorf("Expecting exit code %d for policy %q, %+v and %d errors, but got %d", test.expected, test.policy, test.statistics, test.checkErrors, actual)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mikkeloscar/aur"
+	"github.com/simon04/aur-out-of-date/logging"
+	"github.com/simon04/aur-out-of-date/pkg"
+	"github.com/simon04/aur-out-of-date/server"
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+// checkPackage checks a single package given on the command line for the API
+func checkPackage(name string) (*status.Status, error) {
+	configMutex.RLock()
+	defer configMutex.RUnlock()
+	var packages []pkg.Pkg
+	var err error
+	if commandline.local {
+		var paths []string
+		if paths, err = packageArgs(); err == nil {
+			packages, err = localPackages(paths, true)
+		}
+	} else {
+		var info []aur.Pkg
+		info, err = aur.Info([]string{name})
+		packages = pkg.NewRemotePkgs(info)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range packages {
+		if p.Name() == name {
+			s := handlePackage(p)
+			return &s, nil
+		}
+	}
+	return nil, server.ErrUnknownPackage
+}
+
+// serveMetrics periodically checks all packages and exposes the results as Prometheus metrics and a dashboard
+func serveMetrics(addr string) {
+	var mutex sync.Mutex
+	var metrics []byte
+	results := &server.Results{}
+	go repeat(func() {
+		buf := bytes.NewBuffer(nil)
+		f, err := newFormatter(buf, "prometheus")
+		if err != nil {
+			logging.Errorf("Failed to create formatter: %v", err)
+			return
+		}
+		formatter = status.MultiFormatter(f, results)
+		run(true)
+		mutex.Lock()
+		metrics = buf.Bytes()
+		mutex.Unlock()
+	}, watch(5*time.Second, configFiles()...))
+
+	http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if metrics == nil {
+			http.Error(w, "First check is still running", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		w.Write(metrics)
+	})
+	http.Handle("/", server.Dashboard(results))
+	api := server.API(results, checkPackage)
+	http.Handle("/packages", api)
+	http.Handle("/packages/", api)
+	http.Handle("/check", api)
+	http.Handle("/badge/", server.Badges(results))
+	health := server.Health(results, 3*commandline.interval)
+	http.Handle("/healthz", health)
+	http.Handle("/readyz", health)
+	logging.Infof("Serving Prometheus metrics on %s/metrics, the API on %s/packages and the dashboard on %s/", addr, addr, addr)
+	if err := http.ListenAndServe(addr, nil); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,48 @@
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// DesktopConfig configures freedesktop.org desktop notifications
+type DesktopConfig struct {
+	// Urgency is one of low, normal, critical
+	Urgency string `json:"urgency"`
+	// Timeout is the expiration timeout in milliseconds
+	Timeout int `json:"timeout"`
+}
+
+// execCommand is replaced in tests
+var execCommand = exec.Command
+
+// Name implements Notifier
+func (c *DesktopConfig) Name() string {
+	return "desktop"
+}
+
+// args returns the notify-send arguments, which sends the notification via D-Bus
+func (c *DesktopConfig) args(m *Message) []string {
+	args := []string{"--app-name=aur-out-of-date", "--icon=software-update-available"}
+	if c.Urgency != "" {
+		args = append(args, "--urgency="+c.Urgency)
+	}
+	if c.Timeout > 0 {
+		args = append(args, fmt.Sprintf("--expire-time=%d", c.Timeout))
+	}
+	var body []string
+	for _, s := range m.Packages {
+		body = append(body, fmt.Sprintf("%s %s → %s", s.Package, s.Version, s.Upstream))
+	}
+	return append(args, "--", m.Subject, strings.Join(body, "\n"))
+}
+
+// Notify implements Notifier
+func (c *DesktopConfig) Notify(m *Message) error {
+	output, err := execCommand("notify-send", c.args(m)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("Failed to run notify-send: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package notify
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestDesktop(t *testing.T) {
+	var command []string
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		command = append([]string{name}, arg...)
+		return exec.Command("true")
+	}
+	defer func() { execCommand = exec.Command }()
+
+	c := &DesktopConfig{Urgency: "low"}
+	m := &Message{
+		Subject: "aur-out-of-date: 2 packages are out-of-date",
+		Packages: []*status.Status{
+			{Package: "foo", Version: "1.0-1", Upstream: "1.1"},
+			{Package: "bar", Version: "2-1", Upstream: "3"},
+		},
+	}
+	if err := c.Notify(m); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"notify-send", "--app-name=aur-out-of-date", "--icon=software-update-available", "--urgency=low", "--",
+		"aur-out-of-date: 2 packages are out-of-date", "foo 1.0-1 → 1.1\nbar 2-1 → 3"}
+	if strings.Join(command, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expecting %q, but got %q", expected, command)
+	}
+}
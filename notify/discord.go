@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"fmt"
+)
+
+// DiscordConfig configures notifications via a Discord webhook
+type DiscordConfig struct {
+	WebhookURL string `json:"webhook_url"`
+}
+
+type discordEmbed struct {
+	Title       string `json:"title"`
+	URL         string `json:"url,omitempty"`
+	Description string `json:"description"`
+	Color       int    `json:"color"`
+}
+
+type discordMessage struct {
+	Username string         `json:"username"`
+	Content  string         `json:"content"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+const (
+	// discordMaxEmbeds is the maximum number of embeds per message
+	discordMaxEmbeds = 10
+	discordColorRed  = 0xe05d44
+)
+
+// Name implements Notifier
+func (c *DiscordConfig) Name() string {
+	return "discord"
+}
+
+func discordPayload(m *Message) discordMessage {
+	// API documentation: https://discord.com/developers/docs/resources/webhook#execute-webhook
+	msg := discordMessage{Username: "aur-out-of-date", Content: m.Subject}
+	for i, s := range m.Packages {
+		if i == discordMaxEmbeds {
+			msg.Content += fmt.Sprintf(" (showing %d of %d)", discordMaxEmbeds, len(m.Packages))
+			break
+		}
+		msg.Embeds = append(msg.Embeds, discordEmbed{
+			Title:       s.Package,
+			URL:         s.ReleaseURL,
+			Description: fmt.Sprintf("`%s` → `%s`", s.Version, s.Upstream),
+			Color:       discordColorRed,
+		})
+	}
+	return msg
+}
+
+// Notify implements Notifier
+func (c *DiscordConfig) Notify(m *Message) error {
+	req, _, err := newJSONRequest("POST", c.WebhookURL, discordPayload(m))
+	if err != nil {
+		return err
+	}
+	return doRequest(req)
+}
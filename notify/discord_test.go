@@ -0,0 +1,39 @@
+package notify
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestDiscordPayload(t *testing.T) {
+	var packages []*status.Status
+	for i := 0; i < 12; i++ {
+		packages = append(packages, &status.Status{Package: fmt.Sprintf("foo%d", i), Version: "1.0-1", Upstream: "1.1"})
+	}
+	msg := discordPayload(&Message{Subject: "aur-out-of-date: 12 packages are out-of-date", Packages: packages})
+	if len(msg.Embeds) != discordMaxEmbeds {
+		t.Errorf("Expecting %d embeds, but got %d", discordMaxEmbeds, len(msg.Embeds))
+	}
+	if expected := "aur-out-of-date: 12 packages are out-of-date (showing 10 of 12)"; msg.Content != expected {
+		t.Errorf("Expecting %s, but got %s", expected, msg.Content)
+	}
+	if expected := "`1.0-1` → `1.1`"; msg.Embeds[0].Description != expected {
+		t.Errorf("Expecting %s, but got %s", expected, msg.Embeds[0].Description)
+	}
+}
+
+func TestDiscord(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://discord.com").
+		Post("/api/webhooks/123/abc").
+		BodyString(`"username":"aur-out-of-date"`).
+		Reply(http.StatusNoContent)
+	c := &DiscordConfig{WebhookURL: "https://discord.com/api/webhooks/123/abc"}
+	if err := c.Notify(&Message{Subject: "aur-out-of-date: foo should be updated to 1.1"}); err != nil {
+		t.Error(err)
+	}
+}
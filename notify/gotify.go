@@ -0,0 +1,36 @@
+package notify
+
+import (
+	"strings"
+)
+
+// GotifyConfig configures push notifications via Gotify
+type GotifyConfig struct {
+	Server string `json:"server"`
+	// Token is the application token
+	Token    string `json:"token"`
+	Priority int    `json:"priority"`
+}
+
+type gotifyMessage struct {
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+	Priority int    `json:"priority"`
+}
+
+// Name implements Notifier
+func (c *GotifyConfig) Name() string {
+	return "gotify"
+}
+
+// Notify implements Notifier
+func (c *GotifyConfig) Notify(m *Message) error {
+	// API documentation: https://gotify.net/api-docs#/message/createMessage
+	msg := gotifyMessage{Title: m.Subject, Message: m.Text(), Priority: c.Priority}
+	req, _, err := newJSONRequest("POST", strings.TrimSuffix(c.Server, "/")+"/message", msg)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Gotify-Key", c.Token)
+	return doRequest(req)
+}
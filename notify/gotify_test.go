@@ -0,0 +1,31 @@
+package notify
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestGotify(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://gotify.example.org").
+		Post("/message").
+		MatchHeader("X-Gotify-Key", "^AbCdEf$").
+		BodyString(`"title":"aur-out-of-date: foo should be updated to 1.1"`).
+		BodyString(`"priority":5`).
+		Reply(http.StatusOK)
+
+	c := &GotifyConfig{Server: "https://gotify.example.org/", Token: "AbCdEf", Priority: 5}
+	m := &Message{
+		Subject:  "aur-out-of-date: foo should be updated to 1.1",
+		Packages: []*status.Status{{Package: "foo", Version: "1.0-1", Message: "has 1.0 but 1.1 is available"}},
+	}
+	if err := c.Notify(m); err != nil {
+		t.Error(err)
+	}
+	if !gock.IsDone() {
+		t.Error("Expecting the Gotify message to be sent")
+	}
+}
@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// IRCConfig configures notifications to an IRC channel
+type IRCConfig struct {
+	// Server is the address of the IRC server, such as irc.libera.chat:6697
+	Server   string `json:"server"`
+	TLS      bool   `json:"tls"`
+	Nick     string `json:"nick"`
+	Password string `json:"password"`
+	Channel  string `json:"channel"`
+}
+
+// Name implements Notifier
+func (c *IRCConfig) Name() string {
+	return "irc"
+}
+
+// Notify implements Notifier
+func (c *IRCConfig) Notify(m *Message) error {
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	var conn net.Conn
+	var err error
+	if c.TLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", c.Server, nil)
+	} else {
+		conn, err = dialer.Dial("tcp", c.Server)
+	}
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(time.Minute))
+	return c.session(conn, ircLines(m))
+}
+
+// ircLines renders the message as lines to be sent to the channel
+func ircLines(m *Message) []string {
+	lines := []string{m.Subject}
+	for _, s := range m.Packages {
+		line := fmt.Sprintf("[%s][%s] %s", s.Package, s.Version, s.Message)
+		if s.ReleaseURL != "" {
+			line += " " + s.ReleaseURL
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// session registers, joins the channel, sends the lines, and quits
+func (c *IRCConfig) session(conn io.ReadWriter, lines []string) error {
+	r := bufio.NewReader(conn)
+	send := func(format string, a ...interface{}) error {
+		_, err := fmt.Fprintf(conn, format+"\r\n", a...)
+		return err
+	}
+	// wait reads from the server until one of the given commands/numerics is received
+	wait := func(commands ...string) error {
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return err
+			}
+			line = strings.TrimRight(line, "\r\n")
+			fields := strings.Fields(line)
+			if len(fields) > 0 && fields[0] == "PING" {
+				if err := send("PONG %s", strings.Join(fields[1:], " ")); err != nil {
+					return err
+				}
+				continue
+			}
+			if len(fields) > 0 && fields[0] == "ERROR" {
+				return fmt.Errorf("IRC server error: %s", line)
+			}
+			if len(fields) < 2 {
+				continue
+			}
+			switch fields[1] {
+			case "432", "433", "464", "465", "471", "473", "474", "475":
+				return fmt.Errorf("IRC server error: %s", line)
+			}
+			for _, command := range commands {
+				if fields[1] == command {
+					return nil
+				}
+			}
+		}
+	}
+
+	if c.Password != "" {
+		if err := send("PASS %s", c.Password); err != nil {
+			return err
+		}
+	}
+	if err := send("NICK %s", c.Nick); err != nil {
+		return err
+	}
+	if err := send("USER %s 0 * :aur-out-of-date", c.Nick); err != nil {
+		return err
+	}
+	if err := wait("001"); err != nil {
+		return fmt.Errorf("Failed to register on IRC server: %w", err)
+	}
+	if err := send("JOIN %s", c.Channel); err != nil {
+		return err
+	}
+	if err := wait("366"); err != nil {
+		return fmt.Errorf("Failed to join IRC channel %s: %w", c.Channel, err)
+	}
+	for _, line := range lines {
+		if err := send("PRIVMSG %s :%s", c.Channel, line); err != nil {
+			return err
+		}
+	}
+	return send("QUIT :aur-out-of-date")
+}
@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestIRCLines(t *testing.T) {
+	m := &Message{
+		Subject:  "aur-out-of-date: foo should be updated to 1.1",
+		Packages: []*status.Status{{Package: "foo", Version: "1.0-1", Message: "has 1.0 but 1.1 is available", ReleaseURL: "https://example.com/foo"}},
+	}
+	lines := ircLines(m)
+	if len(lines) != 2 || lines[1] != "[foo][1.0-1] has 1.0 but 1.1 is available https://example.com/foo" {
+		t.Errorf("Unexpected lines %q", lines)
+	}
+}
+
+func TestIRCSession(t *testing.T) {
+	client, server := net.Pipe()
+	received := make(chan []string)
+	go func() {
+		defer server.Close()
+		var lines []string
+		r := bufio.NewScanner(server)
+		for r.Scan() {
+			line := r.Text()
+			lines = append(lines, line)
+			switch {
+			case strings.HasPrefix(line, "USER "):
+				fmt.Fprint(server, "PING :irc.example.org\r\n")
+			case strings.HasPrefix(line, "PONG "):
+				fmt.Fprint(server, ":irc.example.org 001 aurbot :Welcome\r\n")
+			case strings.HasPrefix(line, "JOIN "):
+				fmt.Fprint(server, ":aurbot!aurbot@example.org JOIN #archlinux-aur\r\n")
+				fmt.Fprint(server, ":irc.example.org 366 aurbot #archlinux-aur :End of /NAMES list.\r\n")
+			case strings.HasPrefix(line, "QUIT "):
+				received <- lines
+				return
+			}
+		}
+	}()
+
+	c := &IRCConfig{Nick: "aurbot", Channel: "#archlinux-aur"}
+	if err := c.session(client, []string{"foo should be updated"}); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"NICK aurbot",
+		"USER aurbot 0 * :aur-out-of-date",
+		"PONG :irc.example.org",
+		"JOIN #archlinux-aur",
+		"PRIVMSG #archlinux-aur :foo should be updated",
+		"QUIT :aur-out-of-date",
+	}
+	if actual := <-received; strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expecting %q, but got %q", expected, actual)
+	}
+}
+
+func TestIRCSessionNickInUse(t *testing.T) {
+	client, server := net.Pipe()
+	go func() {
+		defer server.Close()
+		r := bufio.NewScanner(server)
+		for r.Scan() {
+			if strings.HasPrefix(r.Text(), "USER ") {
+				fmt.Fprint(server, ":irc.example.org 433 * aurbot :Nickname is already in use\r\n")
+			}
+		}
+	}()
+	c := &IRCConfig{Nick: "aurbot", Channel: "#archlinux-aur"}
+	if err := c.session(client, nil); err == nil {
+		t.Error("Expecting an error, but got none")
+	}
+	client.Close()
+}
@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MatrixConfig configures notifications to a Matrix room
+type MatrixConfig struct {
+	Homeserver  string `json:"homeserver"`
+	AccessToken string `json:"access_token"`
+	RoomID      string `json:"room_id"`
+}
+
+type matrixMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format"`
+	FormattedBody string `json:"formatted_body"`
+}
+
+// Name implements Notifier
+func (c *MatrixConfig) Name() string {
+	return "matrix"
+}
+
+func (c *MatrixConfig) sendURL(txnID string) string {
+	// API documentation: https://spec.matrix.org/v1.8/client-server-api/#put_matrixclientv3roomsroomidsendeventtypetxnid
+	return fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		strings.TrimSuffix(c.Homeserver, "/"), url.PathEscape(c.RoomID), url.PathEscape(txnID))
+}
+
+// Notify implements Notifier
+func (c *MatrixConfig) Notify(m *Message) error {
+	msg := matrixMessage{
+		MsgType:       "m.text",
+		Body:          m.Subject + "\n\n" + m.Text(),
+		Format:        "org.matrix.custom.html",
+		FormattedBody: m.HTML(),
+	}
+	txnID := fmt.Sprintf("aur-out-of-date-%d", time.Now().UnixNano())
+	req, _, err := newJSONRequest("PUT", c.sendURL(txnID), msg)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+	return doRequest(req)
+}
@@ -0,0 +1,31 @@
+package notify
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestMatrix(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://matrix.example.org").
+		Put("/_matrix/client/v3/rooms/!room:example.org/send/m.room.message/aur-out-of-date-[0-9]+").
+		MatchHeader("Authorization", "^Bearer token$").
+		BodyString(`"msgtype":"m.text","body":"aur-out-of-date: foo should be updated to 1.1`).
+		Reply(http.StatusOK).
+		JSON(map[string]string{"event_id": "$event"})
+
+	c := &MatrixConfig{Homeserver: "https://matrix.example.org/", AccessToken: "token", RoomID: "!room:example.org"}
+	m := &Message{
+		Subject:  "aur-out-of-date: foo should be updated to 1.1",
+		Packages: []*status.Status{{Package: "foo", Version: "1.0-1", Upstream: "1.1", Status: status.OutOfDate}},
+	}
+	if err := c.Notify(m); err != nil {
+		t.Error(err)
+	}
+	if !gock.IsDone() {
+		t.Error("Expecting the Matrix message to be sent")
+	}
+}
@@ -0,0 +1,258 @@
+// Package notify sends notifications about out-of-date packages
+package notify
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"strings"
+
+	"github.com/simon04/aur-out-of-date/logging"
+	"github.com/simon04/aur-out-of-date/state"
+	"github.com/simon04/aur-out-of-date/status"
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+// Config holds the configuration of all notifiers
+type Config struct {
+	// Repeat notifies about all out-of-date packages on every run, instead of new upstream versions only
+	Repeat bool `json:"repeat"`
+	// PerPackage sends one notification per package instead of a digest per run
+	PerPackage bool `json:"per_package"`
+	// DigestThreshold sends one notification per package for runs with at most this many out-of-date packages
+	DigestThreshold int `json:"digest_threshold"`
+
+	SMTP     *SMTPConfig      `json:"smtp"`
+	Webhook  []*WebhookConfig `json:"webhook"`
+	Matrix   *MatrixConfig    `json:"matrix"`
+	Telegram *TelegramConfig  `json:"telegram"`
+	IRC      *IRCConfig       `json:"irc"`
+	Desktop  *DesktopConfig   `json:"desktop"`
+	Ntfy     *NtfyConfig      `json:"ntfy"`
+	Gotify   *GotifyConfig    `json:"gotify"`
+	Slack    *SlackConfig     `json:"slack"`
+	Discord  *DiscordConfig   `json:"discord"`
+	XMPP     *XMPPConfig      `json:"xmpp"`
+}
+
+// MaxReleaseNotes is the number of characters of the release notes included per package
+var MaxReleaseNotes = 1000
+
+// releaseNotes returns the release notes of the package, truncated to MaxReleaseNotes
+func releaseNotes(s *status.Status) string {
+	notes := strings.TrimSpace(strings.Replace(s.ReleaseNotes, "\r\n", "\n", -1))
+	if runes := []rune(notes); len(runes) > MaxReleaseNotes {
+		notes = strings.TrimSpace(string(runes[:MaxReleaseNotes])) + " …"
+	}
+	return notes
+}
+
+// Message is a notification about out-of-date packages
+type Message struct {
+	Subject    string
+	Packages   []*status.Status
+	Statistics *status.Statistics
+}
+
+// Text renders the message body as plain text
+func (m *Message) Text() string {
+	var b strings.Builder
+	for _, s := range m.Packages {
+		fmt.Fprintf(&b, "[%s][%s] %s", s.Package, s.Version, s.Message)
+		if s.ReleaseURL != "" {
+			fmt.Fprintf(&b, " %s", s.ReleaseURL)
+		}
+		fmt.Fprintln(&b)
+		for _, change := range s.Changes {
+			fmt.Fprintf(&b, "  - %s\n", change)
+		}
+		if notes := releaseNotes(s); notes != "" {
+			fmt.Fprintf(&b, "\n    %s\n\n", strings.Replace(notes, "\n", "\n    ", -1))
+		}
+	}
+	if m.Statistics != nil {
+		fmt.Fprintf(&b, "\n%d up-to-date, %d flagged out-of-date, %d out-of-date, %d unknown\n",
+			m.Statistics.UpToDate, m.Statistics.FlaggedOutOfDate, m.Statistics.OutOfDate, m.Statistics.Unknown)
+	}
+	return b.String()
+}
+
+// HTML renders the message body as HTML list
+func (m *Message) HTML() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<strong>%s</strong><ul>", html.EscapeString(m.Subject))
+	for _, s := range m.Packages {
+		fmt.Fprintf(&b, "<li><code>%s</code> %s", html.EscapeString(s.Package), html.EscapeString(s.Message))
+		if s.ReleaseURL != "" {
+			fmt.Fprintf(&b, ` (<a href="%s">release notes</a>)`, html.EscapeString(s.ReleaseURL))
+		}
+		if len(s.Changes) > 0 {
+			fmt.Fprint(&b, "<ul>")
+			for _, change := range s.Changes {
+				fmt.Fprintf(&b, "<li>%s</li>", html.EscapeString(change))
+			}
+			fmt.Fprint(&b, "</ul>")
+		}
+		if notes := releaseNotes(s); notes != "" {
+			fmt.Fprintf(&b, "<blockquote>%s</blockquote>", strings.Replace(html.EscapeString(notes), "\n", "<br>", -1))
+		}
+		fmt.Fprint(&b, "</li>")
+	}
+	fmt.Fprint(&b, "</ul>")
+	return b.String()
+}
+
+// Notifier sends messages to a notification channel
+type Notifier interface {
+	Name() string
+	Notify(m *Message) error
+}
+
+// Notifiers returns the notifiers enabled in the configuration
+func (c *Config) Notifiers() []Notifier {
+	var notifiers []Notifier
+	if c.SMTP != nil {
+		notifiers = append(notifiers, c.SMTP)
+	}
+	for _, webhook := range c.Webhook {
+		notifiers = append(notifiers, webhook)
+	}
+	if c.Matrix != nil {
+		notifiers = append(notifiers, c.Matrix)
+	}
+	if c.Telegram != nil {
+		notifiers = append(notifiers, c.Telegram)
+	}
+	if c.IRC != nil {
+		notifiers = append(notifiers, c.IRC)
+	}
+	if c.Desktop != nil {
+		notifiers = append(notifiers, c.Desktop)
+	}
+	if c.Ntfy != nil {
+		notifiers = append(notifiers, c.Ntfy)
+	}
+	if c.Gotify != nil {
+		notifiers = append(notifiers, c.Gotify)
+	}
+	if c.Slack != nil {
+		notifiers = append(notifiers, c.Slack)
+	}
+	if c.Discord != nil {
+		notifiers = append(notifiers, c.Discord)
+	}
+	if c.XMPP != nil {
+		notifiers = append(notifiers, c.XMPP)
+	}
+	return notifiers
+}
+
+// Formatter collects out-of-date packages and notifies about them once all packages have been checked
+type Formatter struct {
+	notifiers []Notifier
+	packages  []*status.Status
+	upToDate  []string
+	// PerPackage sends one message per package instead of a digest message
+	PerPackage bool
+	// DigestThreshold sends one message per package if at most this many packages are out-of-date
+	DigestThreshold int
+	// StateFile, if set, records the notified upstream versions in order to notify about new ones only
+	StateFile string
+	// DryRun prints the messages instead of sending them, and leaves StateFile untouched
+	DryRun bool
+}
+
+// notified maps package names to the notified upstream version
+type notified map[string]upstream.Version
+
+// NewFormatter returns a status.Formatter sending notifications using the given notifiers
+func NewFormatter(notifiers ...Notifier) *Formatter {
+	return &Formatter{notifiers: notifiers}
+}
+
+// Status implements status.Formatter
+func (f *Formatter) Status(s *status.Status) {
+	if s.Status == status.OutOfDate || s.Status == status.FlaggedOutOfDate {
+		f.packages = append(f.packages, s)
+	} else if s.Status == status.UpToDate {
+		f.upToDate = append(f.upToDate, s.Package)
+	}
+}
+
+// unnotified returns the packages not yet notified according to the state file
+func (f *Formatter) unnotified(history notified) []*status.Status {
+	var packages []*status.Status
+	for _, s := range f.packages {
+		if version, ok := history[s.Package]; !ok || version != s.Upstream {
+			packages = append(packages, s)
+		}
+	}
+	return packages
+}
+
+// Finish implements status.Formatter
+func (f *Formatter) Finish(statistics *status.Statistics) {
+	packages := f.packages
+	history := notified{}
+	if f.StateFile != "" {
+		if err := state.Load(f.StateFile, &history); err != nil {
+			logging.Warnf("Failed to read notified versions from %s: %v", f.StateFile, err)
+		}
+		packages = f.unnotified(history)
+	}
+	if len(packages) == 0 && len(f.packages) > 0 {
+		logging.Infof("Skipping notification about %d already notified packages", len(f.packages))
+	}
+	for _, m := range f.messages(packages, statistics) {
+		if f.DryRun {
+			for _, n := range f.notifiers {
+				fmt.Fprintf(os.Stderr, "Would send %s notification: %s\n", n.Name(), m.Subject)
+			}
+			continue
+		}
+		sent := false
+		for _, n := range f.notifiers {
+			if err := n.Notify(m); err != nil {
+				logging.Errorf("Failed to send %s notification: %v", n.Name(), err)
+			} else {
+				logging.Infof("Sent %s notification: %s", n.Name(), m.Subject)
+				sent = true
+			}
+		}
+		if sent {
+			for _, s := range m.Packages {
+				history[s.Package] = s.Upstream
+			}
+		}
+	}
+	if f.StateFile == "" || f.DryRun {
+		return
+	}
+	for _, name := range f.upToDate {
+		delete(history, name)
+	}
+	if err := state.Save(f.StateFile, history); err != nil {
+		logging.Errorf("Failed to save notified versions to %s: %v", f.StateFile, err)
+	}
+}
+
+// messages returns a single digest message, or one message per package
+func (f *Formatter) messages(packages []*status.Status, statistics *status.Statistics) []*Message {
+	if len(packages) == 0 {
+		return nil
+	} else if !f.PerPackage && len(packages) > f.DigestThreshold {
+		return []*Message{{Subject: subject(packages), Packages: packages, Statistics: statistics}}
+	}
+	var messages []*Message
+	for _, s := range packages {
+		messages = append(messages, &Message{Subject: subject([]*status.Status{s}), Packages: []*status.Status{s}})
+	}
+	return messages
+}
+
+func subject(packages []*status.Status) string {
+	if len(packages) == 1 {
+		return fmt.Sprintf("aur-out-of-date: %s should be updated to %s", packages[0].Package, packages[0].Upstream.String())
+	}
+	return fmt.Sprintf("aur-out-of-date: %d packages are out-of-date", len(packages))
+}
@@ -0,0 +1,189 @@
+package notify
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+type recorder struct {
+	messages []*Message
+}
+
+func (r *recorder) Name() string {
+	return "recorder"
+}
+
+func (r *recorder) Notify(m *Message) error {
+	r.messages = append(r.messages, m)
+	return nil
+}
+
+func TestFormatter(t *testing.T) {
+	r := &recorder{}
+	f := NewFormatter(r)
+	f.Status(&status.Status{Package: "foo", Version: "1.0-1", Upstream: "1.1", Status: status.OutOfDate, Message: "should be updated to 1.1", ReleaseURL: "https://github.com/foo/foo/releases/tag/v1.1"})
+	f.Status(&status.Status{Package: "bar", Version: "1.0-1", Upstream: "1.0", Status: status.UpToDate})
+	f.Finish(&status.Statistics{UpToDate: 1, OutOfDate: 1})
+	if len(r.messages) != 1 {
+		t.Fatalf("Expecting 1 message, but got %d", len(r.messages))
+	}
+	m := r.messages[0]
+	if m.Subject != "aur-out-of-date: foo should be updated to 1.1" {
+		t.Errorf("Unexpected subject '%s'", m.Subject)
+	}
+	expected := "[foo][1.0-1] should be updated to 1.1 https://github.com/foo/foo/releases/tag/v1.1\n" +
+		"\n1 up-to-date, 0 flagged out-of-date, 1 out-of-date, 0 unknown\n"
+	if m.Text() != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, m.Text())
+	}
+}
+
+func TestFormatterNothingToNotify(t *testing.T) {
+	r := &recorder{}
+	f := NewFormatter(r)
+	f.Status(&status.Status{Package: "bar", Status: status.UpToDate})
+	f.Finish(nil)
+	if len(r.messages) != 0 {
+		t.Errorf("Expecting no message, but got %d", len(r.messages))
+	}
+}
+
+func TestFormatterDigestThreshold(t *testing.T) {
+	foo := &status.Status{Package: "foo", Upstream: "1.1", Status: status.OutOfDate}
+	bar := &status.Status{Package: "bar", Upstream: "2", Status: status.OutOfDate}
+	for _, test := range []struct {
+		perPackage bool
+		threshold  int
+		expected   int
+	}{
+		{false, 0, 1},
+		{false, 1, 1},
+		{false, 2, 2},
+		{true, 0, 2},
+	} {
+		r := &recorder{}
+		f := NewFormatter(r)
+		f.PerPackage = test.perPackage
+		f.DigestThreshold = test.threshold
+		f.Status(foo)
+		f.Status(bar)
+		f.Finish(nil)
+		if len(r.messages) != test.expected {
+			t.Errorf("Expecting %d messages for %+v, but got %d", test.expected, test, len(r.messages))
+		}
+	}
+}
+
+func TestFormatterStateFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aur-out-of-date")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := path.Join(dir, "notified.json")
+
+	r := &recorder{}
+	run := func(statuses ...*status.Status) {
+		f := NewFormatter(r)
+		f.StateFile = filename
+		for _, s := range statuses {
+			f.Status(s)
+		}
+		f.Finish(nil)
+	}
+	foo11 := &status.Status{Package: "foo", Upstream: "1.1", Status: status.OutOfDate}
+	foo12 := &status.Status{Package: "foo", Upstream: "1.2", Status: status.OutOfDate}
+	bar2 := &status.Status{Package: "bar", Upstream: "2", Status: status.OutOfDate}
+	bar2UpToDate := &status.Status{Package: "bar", Upstream: "2", Status: status.UpToDate}
+
+	run(foo11)
+	run(foo11)
+	run(foo12, bar2)
+	run(foo12, bar2UpToDate)
+	run(foo12, bar2)
+	if len(r.messages) != 3 {
+		t.Fatalf("Expecting 3 messages, but got %d", len(r.messages))
+	}
+	expected := []string{
+		"aur-out-of-date: foo should be updated to 1.1",
+		"aur-out-of-date: 2 packages are out-of-date",
+		"aur-out-of-date: bar should be updated to 2",
+	}
+	for i, m := range r.messages {
+		if m.Subject != expected[i] {
+			t.Errorf("Expecting '%s', but got '%s'", expected[i], m.Subject)
+		}
+	}
+}
+
+func TestMessageHTML(t *testing.T) {
+	m := &Message{
+		Subject:  "aur-out-of-date: foo should be updated to 1.1",
+		Packages: []*status.Status{{Package: "foo", Message: "has 1.0 but 1.1 is available", ReleaseURL: "https://example.com/?a=1&b=2"}},
+	}
+	expected := `<strong>aur-out-of-date: foo should be updated to 1.1</strong><ul><li><code>foo</code> has 1.0 but 1.1 is available (<a href="https://example.com/?a=1&amp;b=2">release notes</a>)</li></ul>`
+	if actual := m.HTML(); actual != expected {
+		t.Errorf("Expecting %s, but got %s", expected, actual)
+	}
+}
+
+func TestMessageChanges(t *testing.T) {
+	m := &Message{
+		Subject:  "aur-out-of-date: foo should be updated to 1.1",
+		Packages: []*status.Status{{Package: "foo", Version: "1.0-1", Message: "should be updated to 1.1", Changes: []string{"Release 1.1", "Fix <script>"}}},
+	}
+	expected := "[foo][1.0-1] should be updated to 1.1\n  - Release 1.1\n  - Fix <script>\n"
+	if actual := m.Text(); actual != expected {
+		t.Errorf("Expecting %q, but got %q", expected, actual)
+	}
+	expected = `<strong>aur-out-of-date: foo should be updated to 1.1</strong><ul><li><code>foo</code> should be updated to 1.1<ul><li>Release 1.1</li><li>Fix &lt;script&gt;</li></ul></li></ul>`
+	if actual := m.HTML(); actual != expected {
+		t.Errorf("Expecting %s, but got %s", expected, actual)
+	}
+}
+
+func TestMessageReleaseNotes(t *testing.T) {
+	m := &Message{
+		Subject:  "aur-out-of-date: foo should be updated to 1.1",
+		Packages: []*status.Status{{Package: "foo", Version: "1.0-1", Message: "should be updated to 1.1", ReleaseNotes: "## Security\r\n- Fix <CVE>"}},
+	}
+	expected := "[foo][1.0-1] should be updated to 1.1\n\n    ## Security\n    - Fix <CVE>\n\n"
+	if actual := m.Text(); actual != expected {
+		t.Errorf("Expecting %q, but got %q", expected, actual)
+	}
+	expected = `<strong>aur-out-of-date: foo should be updated to 1.1</strong><ul><li><code>foo</code> should be updated to 1.1<blockquote>## Security<br>- Fix &lt;CVE&gt;</blockquote></li></ul>`
+	if actual := m.HTML(); actual != expected {
+		t.Errorf("Expecting %s, but got %s", expected, actual)
+	}
+	m.Packages[0].ReleaseNotes = strings.Repeat("x", MaxReleaseNotes+1)
+	if notes := releaseNotes(m.Packages[0]); notes != strings.Repeat("x", MaxReleaseNotes)+" …" {
+		t.Errorf("Expecting truncated release notes, but got %q", notes)
+	}
+}
+
+func TestFormatterDryRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aur-out-of-date")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := path.Join(dir, "notified.json")
+
+	r := &recorder{}
+	f := NewFormatter(r)
+	f.StateFile = filename
+	f.DryRun = true
+	f.Status(&status.Status{Package: "foo", Upstream: "1.1", Status: status.OutOfDate})
+	f.Finish(nil)
+	if len(r.messages) != 0 {
+		t.Errorf("Expecting no messages, but got %d", len(r.messages))
+	}
+	if _, err := os.Stat(filename); !os.IsNotExist(err) {
+		t.Errorf("Expecting %s not to be written, but got %v", filename, err)
+	}
+}
@@ -0,0 +1,47 @@
+package notify
+
+import (
+	"net/http"
+	"strings"
+)
+
+// NtfyConfig configures push notifications via ntfy
+type NtfyConfig struct {
+	// Server defaults to https://ntfy.sh
+	Server string `json:"server"`
+	Topic  string `json:"topic"`
+	// Token is an optional access token
+	Token    string `json:"token"`
+	Priority string `json:"priority"`
+}
+
+// Name implements Notifier
+func (c *NtfyConfig) Name() string {
+	return "ntfy"
+}
+
+func (c *NtfyConfig) topicURL() string {
+	server := c.Server
+	if server == "" {
+		server = "https://ntfy.sh"
+	}
+	return strings.TrimSuffix(server, "/") + "/" + c.Topic
+}
+
+// Notify implements Notifier
+func (c *NtfyConfig) Notify(m *Message) error {
+	// API documentation: https://docs.ntfy.sh/publish/
+	req, err := http.NewRequest("POST", c.topicURL(), strings.NewReader(m.Text()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Title", m.Subject)
+	req.Header.Set("Tags", "package")
+	if c.Priority != "" {
+		req.Header.Set("Priority", c.Priority)
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	return doRequest(req)
+}
@@ -0,0 +1,31 @@
+package notify
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestNtfy(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://ntfy.sh").
+		Post("/aur-packages").
+		MatchHeader("Title", "^aur-out-of-date: foo should be updated to 1.1$").
+		MatchHeader("Priority", "^high$").
+		BodyString(`\[foo\]\[1.0-1\] has 1.0 but 1.1 is available`).
+		Reply(http.StatusOK)
+
+	c := &NtfyConfig{Topic: "aur-packages", Priority: "high"}
+	m := &Message{
+		Subject:  "aur-out-of-date: foo should be updated to 1.1",
+		Packages: []*status.Status{{Package: "foo", Version: "1.0-1", Message: "has 1.0 but 1.1 is available"}},
+	}
+	if err := c.Notify(m); err != nil {
+		t.Error(err)
+	}
+	if !gock.IsDone() {
+		t.Error("Expecting the ntfy message to be sent")
+	}
+}
@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"fmt"
+)
+
+// SlackConfig configures notifications via a Slack incoming webhook
+type SlackConfig struct {
+	WebhookURL string `json:"webhook_url"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackBlock struct {
+	Type string     `json:"type"`
+	Text *slackText `json:"text,omitempty"`
+}
+
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+// slackMaxBlocks is the maximum number of blocks per message
+const slackMaxBlocks = 50
+
+// Name implements Notifier
+func (c *SlackConfig) Name() string {
+	return "slack"
+}
+
+func slackPayload(m *Message) slackMessage {
+	// API documentation: https://api.slack.com/reference/block-kit/blocks
+	msg := slackMessage{
+		Text:   m.Subject,
+		Blocks: []slackBlock{{Type: "header", Text: &slackText{"plain_text", m.Subject}}},
+	}
+	for i, s := range m.Packages {
+		if len(msg.Blocks) == slackMaxBlocks-1 {
+			text := fmt.Sprintf("… and %d more", len(m.Packages)-i)
+			msg.Blocks = append(msg.Blocks, slackBlock{Type: "section", Text: &slackText{"mrkdwn", text}})
+			break
+		}
+		text := fmt.Sprintf("*%s* `%s` → `%s`", s.Package, s.Version, s.Upstream)
+		if s.ReleaseURL != "" {
+			text += fmt.Sprintf(" <%s|release notes>", s.ReleaseURL)
+		}
+		msg.Blocks = append(msg.Blocks, slackBlock{Type: "section", Text: &slackText{"mrkdwn", text}})
+	}
+	return msg
+}
+
+// Notify implements Notifier
+func (c *SlackConfig) Notify(m *Message) error {
+	req, _, err := newJSONRequest("POST", c.WebhookURL, slackPayload(m))
+	if err != nil {
+		return err
+	}
+	return doRequest(req)
+}
@@ -0,0 +1,40 @@
+package notify
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestSlackPayload(t *testing.T) {
+	var packages []*status.Status
+	for i := 0; i < 60; i++ {
+		packages = append(packages, &status.Status{Package: fmt.Sprintf("foo%d", i), Version: "1.0-1", Upstream: "1.1"})
+	}
+	packages[0].ReleaseURL = "https://example.com/foo"
+	msg := slackPayload(&Message{Subject: "aur-out-of-date: 60 packages are out-of-date", Packages: packages})
+	if len(msg.Blocks) != slackMaxBlocks {
+		t.Errorf("Expecting %d blocks, but got %d", slackMaxBlocks, len(msg.Blocks))
+	}
+	if expected := "*foo0* `1.0-1` → `1.1` <https://example.com/foo|release notes>"; msg.Blocks[1].Text.Text != expected {
+		t.Errorf("Expecting %s, but got %s", expected, msg.Blocks[1].Text.Text)
+	}
+	if expected := "… and 12 more"; msg.Blocks[49].Text.Text != expected {
+		t.Errorf("Expecting %s, but got %s", expected, msg.Blocks[49].Text.Text)
+	}
+}
+
+func TestSlack(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://hooks.slack.com").
+		Post("/services/T000/B000/XXX").
+		BodyString(`"type":"header"`).
+		Reply(http.StatusOK)
+	c := &SlackConfig{WebhookURL: "https://hooks.slack.com/services/T000/B000/XXX"}
+	if err := c.Notify(&Message{Subject: "aur-out-of-date: foo should be updated to 1.1"}); err != nil {
+		t.Error(err)
+	}
+}
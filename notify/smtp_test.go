@@ -0,0 +1,31 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestSMTPMail(t *testing.T) {
+	c := SMTPConfig{Host: "mail.example.com", From: "aur@example.com", To: []string{"a@example.com", "b@example.com"}}
+	m := &Message{
+		Subject:  "aur-out-of-date: foo should be updated to 1.1",
+		Packages: []*status.Status{{Package: "foo", Version: "1.0-1", Message: "should be updated to 1.1"}},
+	}
+	actual := string(c.mail(m, time.Date(2021, 3, 16, 12, 0, 0, 0, time.UTC)))
+	expected := "From: aur@example.com\r\n" +
+		"To: a@example.com, b@example.com\r\n" +
+		"Subject: aur-out-of-date: foo should be updated to 1.1\r\n" +
+		"Date: Tue, 16 Mar 2021 12:00:00 +0000\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"\r\n" +
+		"[foo][1.0-1] should be updated to 1.1\r\n"
+	if actual != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, actual)
+	}
+	if c.addr() != "mail.example.com:587" {
+		t.Errorf("Expecting default port 587, but got %s", c.addr())
+	}
+}
@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// TelegramConfig configures notifications using a Telegram bot
+type TelegramConfig struct {
+	Token  string `json:"token"`
+	ChatID string `json:"chat_id"`
+}
+
+type telegramMessage struct {
+	ChatID                string `json:"chat_id"`
+	Text                  string `json:"text"`
+	ParseMode             string `json:"parse_mode"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+}
+
+// Name implements Notifier
+func (c *TelegramConfig) Name() string {
+	return "telegram"
+}
+
+func (c *TelegramConfig) sendURL() string {
+	// API documentation: https://core.telegram.org/bots/api#sendmessage
+	return fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", c.Token)
+}
+
+// telegramText renders the message using the limited HTML subset supported by Telegram
+func telegramText(m *Message) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<b>%s</b>\n", html.EscapeString(m.Subject))
+	for _, s := range m.Packages {
+		fmt.Fprintf(&b, "\n• <code>%s</code> %s", html.EscapeString(s.Package), html.EscapeString(s.Message))
+		if s.ReleaseURL != "" {
+			fmt.Fprintf(&b, ` (<a href="%s">release notes</a>)`, html.EscapeString(s.ReleaseURL))
+		}
+	}
+	return b.String()
+}
+
+// Notify implements Notifier
+func (c *TelegramConfig) Notify(m *Message) error {
+	msg := telegramMessage{
+		ChatID:                c.ChatID,
+		Text:                  telegramText(m),
+		ParseMode:             "HTML",
+		DisableWebPagePreview: true,
+	}
+	req, _, err := newJSONRequest("POST", c.sendURL(), msg)
+	if err != nil {
+		return err
+	}
+	return doRequest(req)
+}
@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestTelegramText(t *testing.T) {
+	m := &Message{
+		Subject:  "aur-out-of-date: 2 packages are out-of-date",
+		Packages: []*status.Status{{Package: "foo", Message: "has 1.0 but 1.1 is available"}, {Package: "bar", Message: "has 2 but 3 is available", ReleaseURL: "https://example.com/bar"}},
+	}
+	expected := "<b>aur-out-of-date: 2 packages are out-of-date</b>\n\n• <code>foo</code> has 1.0 but 1.1 is available\n• <code>bar</code> has 2 but 3 is available (<a href=\"https://example.com/bar\">release notes</a>)"
+	if actual := telegramText(m); actual != expected {
+		t.Errorf("Expecting %q, but got %q", expected, actual)
+	}
+}
+
+func TestTelegram(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.telegram.org").
+		Post("/bot123:abc/sendMessage").
+		BodyString(`"chat_id":"-10042"`).
+		BodyString(`"parse_mode":"HTML"`).
+		Reply(http.StatusOK).
+		JSON(map[string]bool{"ok": true})
+
+	c := &TelegramConfig{Token: "123:abc", ChatID: "-10042"}
+	m := &Message{
+		Subject:  "aur-out-of-date: foo should be updated to 1.1",
+		Packages: []*status.Status{{Package: "foo", Version: "1.0-1", Upstream: "1.1", Status: status.OutOfDate}},
+	}
+	if err := c.Notify(m); err != nil {
+		t.Error(err)
+	}
+	if !gock.IsDone() {
+		t.Error("Expecting the Telegram message to be sent")
+	}
+}
@@ -0,0 +1,97 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+// WebhookConfig configures a generic webhook receiving JSON payloads
+type WebhookConfig struct {
+	URL string `json:"url"`
+	// Secret is used to sign the payload using HMAC-SHA256 (header X-Signature-256)
+	Secret string `json:"secret"`
+	// PerPackage posts one payload per out-of-date package
+	PerPackage bool `json:"per_package"`
+	// Summary posts one payload per run (default if PerPackage is unset)
+	Summary bool `json:"summary"`
+}
+
+type webhookSummary struct {
+	Type       string             `json:"type"`
+	Subject    string             `json:"subject"`
+	Packages   []*status.Status   `json:"packages"`
+	Statistics *status.Statistics `json:"statistics,omitempty"`
+}
+
+// Name implements Notifier
+func (c *WebhookConfig) Name() string {
+	return "webhook"
+}
+
+// Notify implements Notifier
+func (c *WebhookConfig) Notify(m *Message) error {
+	if c.PerPackage {
+		for _, s := range m.Packages {
+			s.Type = "package"
+			if err := c.post(s); err != nil {
+				return err
+			}
+		}
+	}
+	if c.Summary || !c.PerPackage {
+		return c.post(webhookSummary{"summary", m.Subject, m.Packages, m.Statistics})
+	}
+	return nil
+}
+
+// signature computes the HMAC-SHA256 of the payload
+func (c *WebhookConfig) signature(payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(c.Secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c *WebhookConfig) post(v interface{}) error {
+	req, payload, err := newJSONRequest("POST", c.URL, v)
+	if err != nil {
+		return err
+	}
+	if c.Secret != "" {
+		req.Header.Set("X-Signature-256", c.signature(payload))
+	}
+	return doRequest(req)
+}
+
+// newJSONRequest returns a request having v encoded as JSON body
+func newJSONRequest(method, url string, v interface{}) (*http.Request, []byte, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := http.NewRequest(method, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, payload, nil
+}
+
+// doRequest performs the request and fails for non-2xx responses
+func doRequest(req *http.Request) error {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: %s", req.Method, req.URL, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package notify
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestWebhook(t *testing.T) {
+	defer gock.Off()
+	c := &WebhookConfig{URL: "https://example.com/hook", Secret: "secret", PerPackage: true, Summary: true}
+	gock.New("https://example.com").
+		Post("/hook").
+		MatchHeader("X-Signature-256", "^sha256=[0-9a-f]{64}$").
+		BodyString(`"type":"package","name":"foo"`).
+		Reply(http.StatusNoContent)
+	gock.New("https://example.com").
+		Post("/hook").
+		BodyString(`"type":"summary","subject":"aur-out-of-date: foo should be updated to 1.1"`).
+		Reply(http.StatusOK)
+
+	m := &Message{
+		Subject:  "aur-out-of-date: foo should be updated to 1.1",
+		Packages: []*status.Status{{Package: "foo", Version: "1.0-1", Upstream: "1.1", Status: status.OutOfDate}},
+	}
+	if err := c.Notify(m); err != nil {
+		t.Error(err)
+	}
+	if !gock.IsDone() {
+		t.Error("Expecting all webhook requests to be sent")
+	}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	c := &WebhookConfig{Secret: "It's a Secret to Everybody"}
+	// example from https://docs.github.com/en/webhooks/using-webhooks/validating-webhook-deliveries
+	expected := "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+	if actual := c.signature([]byte("Hello, World!")); actual != expected {
+		t.Errorf("Expecting %s, but got %s", expected, actual)
+	}
+}
+
+func TestWebhookFailure(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://example.com").Post("/hook").Reply(http.StatusInternalServerError)
+	c := &WebhookConfig{URL: "https://example.com/hook"}
+	if err := c.Notify(&Message{}); err == nil {
+		t.Error("Expecting an error, but got none")
+	}
+}
@@ -0,0 +1,195 @@
+package notify
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// XMPPConfig configures notifications via XMPP (Jabber)
+type XMPPConfig struct {
+	JID       string `json:"jid"`
+	Password  string `json:"password"`
+	Recipient string `json:"recipient"`
+	// Server is the address of the XMPP server, defaults to the JID domain on port 5222
+	Server string `json:"server"`
+}
+
+const (
+	xmppNSStream = "http://etherx.jabber.org/streams"
+	xmppNSTLS    = "urn:ietf:params:xml:ns:xmpp-tls"
+	xmppNSSASL   = "urn:ietf:params:xml:ns:xmpp-sasl"
+	xmppNSBind   = "urn:ietf:params:xml:ns:xmpp-bind"
+)
+
+type xmppFeatures struct {
+	XMLName    xml.Name  `xml:"http://etherx.jabber.org/streams features"`
+	StartTLS   *struct{} `xml:"urn:ietf:params:xml:ns:xmpp-tls starttls"`
+	Mechanisms []string  `xml:"urn:ietf:params:xml:ns:xmpp-sasl mechanisms>mechanism"`
+	Bind       *struct{} `xml:"urn:ietf:params:xml:ns:xmpp-bind bind"`
+}
+
+// xmppStream is a minimal XMPP client stream (RFC 6120)
+type xmppStream struct {
+	w   io.Writer
+	dec *xml.Decoder
+}
+
+// Name implements Notifier
+func (c *XMPPConfig) Name() string {
+	return "xmpp"
+}
+
+func (c *XMPPConfig) user() (local, domain string) {
+	jid := c.JID
+	if i := strings.Index(jid, "/"); i >= 0 {
+		jid = jid[:i]
+	}
+	if i := strings.Index(jid, "@"); i >= 0 {
+		return jid[:i], jid[i+1:]
+	}
+	return "", jid
+}
+
+func (c *XMPPConfig) addr() string {
+	if c.Server != "" {
+		return c.Server
+	}
+	_, domain := c.user()
+	if _, addrs, err := net.LookupSRV("xmpp-client", "tcp", domain); err == nil && len(addrs) > 0 {
+		return net.JoinHostPort(strings.TrimSuffix(addrs[0].Target, "."), fmt.Sprint(addrs[0].Port))
+	}
+	return net.JoinHostPort(domain, "5222")
+}
+
+// Notify implements Notifier
+func (c *XMPPConfig) Notify(m *Message) error {
+	_, domain := c.user()
+	conn, err := net.DialTimeout("tcp", c.addr(), 30*time.Second)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(time.Minute))
+
+	// negotiate STARTTLS before sending any credentials
+	x := newXMPPStream(conn)
+	features, err := x.open(domain)
+	if err != nil {
+		return err
+	}
+	if features.StartTLS == nil {
+		return fmt.Errorf("XMPP server %s does not support STARTTLS", domain)
+	}
+	fmt.Fprintf(conn, "<starttls xmlns='%s'/>", xmppNSTLS)
+	if start, err := x.next(); err != nil {
+		return err
+	} else if start.Name.Local != "proceed" {
+		return fmt.Errorf("XMPP server %s refused STARTTLS", domain)
+	}
+	tlsConn := tls.Client(conn, &tls.Config{ServerName: domain})
+	if err := tlsConn.Handshake(); err != nil {
+		return err
+	}
+	return c.session(tlsConn, m.Subject+"\n\n"+m.Text())
+}
+
+func newXMPPStream(rw io.ReadWriter) *xmppStream {
+	return &xmppStream{w: rw, dec: xml.NewDecoder(bufio.NewReader(rw))}
+}
+
+// open (re-)opens the stream and returns the stream features
+func (x *xmppStream) open(domain string) (*xmppFeatures, error) {
+	fmt.Fprintf(x.w, "<?xml version='1.0'?><stream:stream to='%s' xmlns='jabber:client' xmlns:stream='%s' version='1.0'>", xmlEscape(domain), xmppNSStream)
+	start, err := x.next()
+	if err != nil {
+		return nil, err
+	} else if start.Name.Space != xmppNSStream || start.Name.Local != "stream" {
+		return nil, fmt.Errorf("Unexpected XMPP element <%s>", start.Name.Local)
+	}
+	start, err = x.next()
+	if err != nil {
+		return nil, err
+	}
+	var features xmppFeatures
+	if err := x.dec.DecodeElement(&features, start); err != nil {
+		return nil, err
+	}
+	return &features, nil
+}
+
+// next returns the next start element
+func (x *xmppStream) next() (*xml.StartElement, error) {
+	for {
+		t, err := x.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := t.(type) {
+		case xml.StartElement:
+			return &t, nil
+		case xml.EndElement:
+			if t.Name.Space == xmppNSStream && t.Name.Local == "stream" {
+				return nil, fmt.Errorf("XMPP stream closed by server")
+			}
+		}
+	}
+}
+
+// session authenticates, binds a resource, and sends the message on an encrypted connection
+func (c *XMPPConfig) session(rw io.ReadWriter, body string) error {
+	local, domain := c.user()
+	x := newXMPPStream(rw)
+	features, err := x.open(domain)
+	if err != nil {
+		return err
+	}
+	plain := false
+	for _, mechanism := range features.Mechanisms {
+		plain = plain || mechanism == "PLAIN"
+	}
+	if !plain {
+		return fmt.Errorf("XMPP server %s does not support SASL PLAIN", domain)
+	}
+	credentials := base64.StdEncoding.EncodeToString([]byte("\x00" + local + "\x00" + c.Password))
+	fmt.Fprintf(rw, "<auth xmlns='%s' mechanism='PLAIN'>%s</auth>", xmppNSSASL, credentials)
+	start, err := x.next()
+	if err != nil {
+		return err
+	} else if start.Name.Local != "success" {
+		return fmt.Errorf("Failed to authenticate %s on XMPP server", c.JID)
+	}
+	x.dec.Skip()
+
+	x = newXMPPStream(rw)
+	if _, err := x.open(domain); err != nil {
+		return err
+	}
+	fmt.Fprintf(rw, "<iq type='set' id='bind1'><bind xmlns='%s'><resource>aur-out-of-date</resource></bind></iq>", xmppNSBind)
+	start, err = x.next()
+	if err != nil {
+		return err
+	}
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "type" && attr.Value != "result" {
+			return fmt.Errorf("Failed to bind XMPP resource")
+		}
+	}
+	x.dec.Skip()
+
+	fmt.Fprintf(rw, "<message to='%s' type='chat'><body>%s</body></message>", xmlEscape(c.Recipient), xmlEscape(body))
+	_, err = fmt.Fprint(rw, "</stream:stream>")
+	return err
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
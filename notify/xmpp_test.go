@@ -0,0 +1,62 @@
+package notify
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestXMPPSession(t *testing.T) {
+	client, server := net.Pipe()
+	received := make(chan string)
+	go func() {
+		defer server.Close()
+		var b strings.Builder
+		buf := make([]byte, 4096)
+		authenticated := false
+		for {
+			n, err := server.Read(buf)
+			if err != nil {
+				return
+			}
+			chunk := string(buf[:n])
+			b.WriteString(chunk)
+			switch {
+			case strings.HasPrefix(chunk, "<?xml") && !authenticated:
+				fmt.Fprint(server, `<?xml version='1.0'?><stream:stream xmlns='jabber:client' xmlns:stream='http://etherx.jabber.org/streams' id='1' from='example.org' version='1.0'>`+
+					`<stream:features><mechanisms xmlns='urn:ietf:params:xml:ns:xmpp-sasl'><mechanism>SCRAM-SHA-1</mechanism><mechanism>PLAIN</mechanism></mechanisms></stream:features>`)
+			case strings.HasPrefix(chunk, "<auth"):
+				authenticated = true
+				fmt.Fprint(server, `<success xmlns='urn:ietf:params:xml:ns:xmpp-sasl'/>`)
+			case strings.HasPrefix(chunk, "<?xml"):
+				fmt.Fprint(server, `<?xml version='1.0'?><stream:stream xmlns='jabber:client' xmlns:stream='http://etherx.jabber.org/streams' id='2' from='example.org' version='1.0'>`+
+					`<stream:features><bind xmlns='urn:ietf:params:xml:ns:xmpp-bind'/></stream:features>`)
+			case strings.HasPrefix(chunk, "<iq"):
+				fmt.Fprint(server, `<iq type='result' id='bind1'><bind xmlns='urn:ietf:params:xml:ns:xmpp-bind'><jid>bot@example.org/aur-out-of-date</jid></bind></iq>`)
+			case strings.HasPrefix(chunk, "</stream:stream>"):
+				received <- b.String()
+				return
+			}
+		}
+	}()
+
+	c := &XMPPConfig{JID: "bot@example.org", Password: "secret", Recipient: "maintainer@example.org"}
+	if err := c.session(client, "foo <1.0> should be updated"); err != nil {
+		t.Fatal(err)
+	}
+	actual := <-received
+	if !strings.Contains(actual, "<auth xmlns='urn:ietf:params:xml:ns:xmpp-sasl' mechanism='PLAIN'>AGJvdABzZWNyZXQ=</auth>") {
+		t.Errorf("Expecting SASL PLAIN authentication, but got %s", actual)
+	}
+	if !strings.Contains(actual, "<message to='maintainer@example.org' type='chat'><body>foo &lt;1.0&gt; should be updated</body></message>") {
+		t.Errorf("Expecting message, but got %s", actual)
+	}
+}
+
+func TestXMPPUser(t *testing.T) {
+	c := &XMPPConfig{JID: "bot@example.org/resource"}
+	if local, domain := c.user(); local != "bot" || domain != "example.org" {
+		t.Errorf("Unexpected user %s@%s", local, domain)
+	}
+}
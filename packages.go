@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/simon04/aur-out-of-date/pkg"
+)
+
+// stdinPackages holds the packages read from stdin if "-" is given on the command line
+var stdinPackages []string
+
+// includePattern and excludePattern select the packages to check according to -filter and -exclude
+var includePattern, excludePattern *regexp.Regexp
+
+// compilePatterns compiles -filter and -exclude, matching whole package names
+func compilePatterns() error {
+	compile := func(flag, expr string) (*regexp.Regexp, error) {
+		if expr == "" {
+			return nil, nil
+		}
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("Invalid -%s: %w", flag, err)
+		}
+		return re, nil
+	}
+	include, err := compile("filter", commandline.filter)
+	if err != nil {
+		return err
+	}
+	exclude, err := compile("exclude", commandline.exclude)
+	if err != nil {
+		return err
+	}
+	includePattern, excludePattern = include, exclude
+	return nil
+}
+
+// selected determines whether the package is to be checked according to -filter and -exclude
+func selected(name string) bool {
+	return (includePattern == nil || includePattern.MatchString(name)) && (excludePattern == nil || !excludePattern.MatchString(name))
+}
+
+// readPackageList reads a package name (or .SRCINFO file) from the first field of each line, skipping empty lines and # comments.
+// This accepts plain lists as well as the output of tools such as "aur vercmp" (foo 1.0 -> 1.1) or "aur repo --list" (foo\t1.0-1).
+func readPackageList(r io.Reader) ([]string, error) {
+	var packages []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		packages = append(packages, fields[0])
+	}
+	return packages, scanner.Err()
+}
+
+// readPackageFile reads the package list from the file
+func readPackageFile(filename string) ([]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readPackageList(f)
+}
+
+// localPackages reads the local packages from the .SRCINFO files, or evaluates their PKGBUILDs for -printsrcinfo
+func localPackages(paths []string, includeVcsPkgs bool) ([]pkg.Pkg, error) {
+	if commandline.printSrcinfo {
+		return pkg.NewEvaluatedPkgs(paths, includeVcsPkgs)
+	}
+	return pkg.NewLocalPkgs(paths, includeVcsPkgs)
+}
+
+// packageArgs returns the packages given on the command line, read from stdin ("-") and from -from-file (read again on every run)
+func packageArgs() ([]string, error) {
+	var packages []string
+	for _, arg := range flag.Args() {
+		if arg == "-" {
+			packages = append(packages, stdinPackages...)
+		} else {
+			packages = append(packages, arg)
+		}
+	}
+	if commandline.fromFile != "" {
+		list, err := readPackageFile(commandline.fromFile)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read package list: %w", err)
+		}
+		packages = append(packages, list...)
+	}
+	return packages, nil
+}
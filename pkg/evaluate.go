@@ -0,0 +1,69 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	pkgbuild "github.com/mikkeloscar/gopkgbuild"
+)
+
+// execCommand and lookPath are replaced in tests
+var execCommand = exec.Command
+var lookPath = exec.LookPath
+
+// srcinfoCommand returns the command evaluating the PKGBUILD in dir using makepkg --printsrcinfo, sandboxed using bubblewrap:
+// without network access, with only /usr, /etc and dir mounted read-only, an empty /tmp as home and a minimal environment
+func srcinfoCommand(dir string) (*exec.Cmd, error) {
+	bwrap, err := lookPath("bwrap")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to evaluate %s/PKGBUILD, install bubblewrap (bwrap) to sandbox makepkg: %w", dir, err)
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	return execCommand(bwrap,
+		"--ro-bind", "/usr", "/usr", "--ro-bind", "/etc", "/etc", "--ro-bind", dir, dir,
+		"--symlink", "usr/bin", "/bin", "--symlink", "usr/bin", "/sbin", "--symlink", "usr/lib", "/lib", "--symlink", "usr/lib", "/lib64",
+		"--dev", "/dev", "--proc", "/proc", "--tmpfs", "/tmp",
+		"--clearenv", "--setenv", "PATH", "/usr/bin", "--setenv", "HOME", "/tmp", "--setenv", "LC_ALL", "C",
+		"--unshare-all", "--die-with-parent", "--chdir", dir, "makepkg", "--printsrcinfo"), nil
+}
+
+// NewEvaluatedPkgs creates a Pkg slice from paths to PKGBUILD or .SRCINFO files (or their directories),
+// evaluating each PKGBUILD using makepkg --printsrcinfo
+func NewEvaluatedPkgs(paths []string, includeVcsPkgs bool) ([]Pkg, error) {
+	var r []Pkg
+	for _, p := range paths {
+		dir := p
+		if stat, err := os.Stat(p); err != nil {
+			return nil, err
+		} else if !stat.IsDir() {
+			dir = path.Dir(p)
+		}
+		cmd, err := srcinfoCommand(dir)
+		if err != nil {
+			return nil, err
+		}
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		output, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to run makepkg --printsrcinfo in %s: %w\n%s", dir, err, strings.TrimSpace(stderr.String()))
+		}
+		pkg, err := pkgbuild.ParseSRCINFOContent(output)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse .SRCINFO of %s/PKGBUILD: %w", dir, err)
+		}
+		if pkg.IsDevel() && !includeVcsPkgs {
+			continue
+		}
+		r = append(r, &localPkg{pkg, path.Join(dir, ".SRCINFO")})
+	}
+	return r, nil
+}
@@ -0,0 +1,54 @@
+package pkg
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestNewEvaluatedPkgs(t *testing.T) {
+	var command []string
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		command = append([]string{name}, arg...)
+		return exec.Command("printf", "pkgbase = foo\n\tpkgver = 1.2\n\tpkgrel = 1\n\turl = https://github.com/foo/foo\n\tarch = x86_64\n\tsource_x86_64 = https://github.com/foo/foo/releases/download/v1.2/foo-x86_64.tar.gz\n\npkgname = foo\n")
+	}
+	lookPath = func(file string) (string, error) { return "/usr/bin/bwrap", nil }
+	defer func() { execCommand, lookPath = exec.Command, exec.LookPath }()
+
+	packages, err := NewEvaluatedPkgs([]string{"."}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packages) != 1 || packages[0].Name() != "foo" || packages[0].Version().String() != "1.2-1" {
+		t.Fatalf("Unexpected packages %v", packages)
+	}
+	if sources, _ := packages[0].Sources(); len(sources) != 1 || !strings.HasSuffix(sources[0], "foo-x86_64.tar.gz") {
+		t.Errorf("Expecting the architecture specific source, but got %q", sources)
+	}
+	if packages[0].LocalPKGBUILD() != "PKGBUILD" {
+		t.Errorf("Unexpected PKGBUILD %s", packages[0].LocalPKGBUILD())
+	}
+	wd, _ := os.Getwd()
+	if joined := strings.Join(command, " "); !strings.HasPrefix(joined, "/usr/bin/bwrap --ro-bind /usr /usr --ro-bind /etc /etc --ro-bind "+wd+" "+wd+" ") ||
+		!strings.HasSuffix(joined, "--unshare-all --die-with-parent --chdir "+wd+" makepkg --printsrcinfo") || strings.Contains(joined, "--ro-bind / /") {
+		t.Errorf("Expecting a sandboxed makepkg, but got %q", command)
+	}
+
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		return exec.Command("false")
+	}
+	if _, err := NewEvaluatedPkgs([]string{"."}, false); err == nil {
+		t.Error("Expecting an error for failing makepkg")
+	}
+
+	command = nil
+	lookPath = func(file string) (string, error) { return "", errors.New("not found") }
+	if _, err := NewEvaluatedPkgs([]string{"."}, false); err == nil || !strings.Contains(err.Error(), "bwrap") {
+		t.Errorf("Expecting an error without bwrap, but got %v", err)
+	}
+	if command != nil {
+		t.Errorf("Expecting makepkg not to run without sandbox, but got %q", command)
+	}
+}
@@ -0,0 +1,153 @@
+package pkg
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	pkgbuild "github.com/mikkeloscar/gopkgbuild"
+)
+
+// variable matches $name and ${name} references, as well as the removal of a literal prefix or suffix, such as ${pkgname#python-}
+var variable = regexp.MustCompile(`\$(?:\{(\w+)(?:(##?|%%?)([^}*?\[]*))?\}|(\w+))`)
+
+// assignment matches the assignment of custom variables in a PKGBUILD, such as _owner=foo or _name="${pkgname#python-}"
+var assignment = regexp.MustCompile(`(?m)^[ \t]*(_\w+)=(?:"([^"]*)"|'([^']*)'|([^\s"'()]*))[ \t]*(?:#.*)?$`)
+
+// variables returns the values of the standard PKGBUILD variables
+func variables(pkg *pkgbuild.PKGBUILD) map[string]string {
+	vars := map[string]string{
+		"pkgbase": pkg.Pkgbase,
+		"pkgver":  string(pkg.Pkgver),
+		"pkgrel":  string(pkg.Pkgrel),
+		"epoch":   strconv.Itoa(pkg.Epoch),
+	}
+	if len(pkg.Pkgnames) > 0 {
+		vars["pkgname"] = pkg.Pkgnames[0]
+	}
+	if vars["pkgbase"] == "" {
+		vars["pkgbase"] = vars["pkgname"]
+	}
+	vars["url"] = expand(pkg.URL, vars)
+	return vars
+}
+
+// customVariables adds the custom variables (prefixed by _) assigned in the PKGBUILD content to vars
+func customVariables(content string, vars map[string]string) {
+	for _, match := range assignment.FindAllStringSubmatch(content, -1) {
+		vars[match[1]] = expand(match[2]+match[3]+match[4], vars)
+	}
+}
+
+// expand replaces the references of known variables in s, leaving unknown ones untouched
+func expand(s string, vars map[string]string) string {
+	if !strings.Contains(s, "$") {
+		return s
+	}
+	return variable.ReplaceAllStringFunc(s, func(ref string) string {
+		match := variable.FindStringSubmatch(ref)
+		value, ok := vars[match[1]+match[4]]
+		if !ok {
+			return ref
+		} else if strings.HasPrefix(match[2], "#") {
+			return strings.TrimPrefix(value, match[3])
+		} else if strings.HasPrefix(match[2], "%") {
+			return strings.TrimSuffix(value, match[3])
+		}
+		return value
+	})
+}
+
+// expandAll replaces the variable references in values, reading custom variables from the PKGBUILD content only if needed
+func expandAll(pkg *pkgbuild.PKGBUILD, values []string, content func() string) []string {
+	var vars map[string]string
+	custom := false
+	var r []string
+	for _, value := range values {
+		if !strings.Contains(value, "$") {
+			r = append(r, value)
+			continue
+		}
+		if vars == nil {
+			vars = variables(pkg)
+		}
+		value = expand(value, vars)
+		if strings.Contains(value, "$_") || strings.Contains(value, "${_") {
+			if !custom {
+				customVariables(content(), vars)
+				custom = true
+			}
+			value = expand(value, vars)
+		}
+		r = append(r, value)
+	}
+	return r
+}
+
+// sourceArray matches the source arrays of a PKGBUILD, such as source=(…) or source_x86_64=(…)
+var sourceArray = regexp.MustCompile(`(?m)^[ \t]*source(?:_(\w+))?=\(([^)]*)\)`)
+
+// SourcesForPkgver returns the sources of the PKGBUILD by architecture ("" for source, "x86_64" for source_x86_64)
+// with the variables expanded for the given pkgver, so that only the positions referencing $pkgver change
+func SourcesForPkgver(p Pkg, pkgver string) (map[string][]string, error) {
+	content := p.Content()
+	matches := sourceArray.FindAllStringSubmatch(content, -1)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("No source array found in the PKGBUILD of %s", p.Name())
+	}
+	vars := map[string]string{
+		"pkgname": p.Name(),
+		"pkgbase": p.Base(),
+		"pkgver":  pkgver,
+		"pkgrel":  "1",
+		"epoch":   strconv.Itoa(int(p.Version().Epoch)),
+		"url":     p.URL(),
+	}
+	customVariables(content, vars)
+	r := map[string][]string{}
+	for _, match := range matches {
+		for _, source := range arrayValues(match[2]) {
+			if source = expand(source, vars); strings.Contains(source, "$") {
+				return nil, fmt.Errorf("Failed to expand source %s of %s", source, p.Name())
+			}
+			r[match[1]] = append(r[match[1]], source)
+		}
+	}
+	return r, nil
+}
+
+// arrayValues splits the body of a bash array into its values, removing quotes and comments
+func arrayValues(body string) []string {
+	var r []string
+	var value strings.Builder
+	inValue, inComment := false, false
+	var quote rune
+	for _, c := range body {
+		switch {
+		case inComment:
+			inComment = c != '\n'
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			value.WriteRune(c)
+		case c == '\'' || c == '"':
+			quote, inValue = c, true
+		case c == '#' && !inValue:
+			inComment = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inValue {
+				r = append(r, value.String())
+				value.Reset()
+				inValue = false
+			}
+		default:
+			value.WriteRune(c)
+			inValue = true
+		}
+	}
+	if inValue {
+		r = append(r, value.String())
+	}
+	return r
+}
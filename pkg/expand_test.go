@@ -0,0 +1,139 @@
+package pkg
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+)
+
+func TestExpandSources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aur-out-of-date")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	srcinfo := `pkgbase = python-foo
+	pkgver = 1.2
+	pkgrel = 1
+	url = https://github.com/$_owner/${_name}
+	arch = any
+	source = https://github.com/$_owner/$_name/archive/v$pkgver.tar.gz
+	source = $pkgname.patch
+	source = https://example.org/$_unknown/${pkgver%.*}.tar.gz
+
+pkgname = python-foo
+`
+	pkgbuild := `# Maintainer: Jane Doe
+_owner=example # the GitHub organization
+_name="${pkgname#python-}"
+_name2='foo'
+pkgname=python-foo
+`
+	if err := ioutil.WriteFile(path.Join(dir, ".SRCINFO"), []byte(srcinfo), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(dir, "PKGBUILD"), []byte(pkgbuild), 0644); err != nil {
+		t.Fatal(err)
+	}
+	packages, err := NewLocalPkgs([]string{path.Join(dir, ".SRCINFO")}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources, _ := packages[0].Sources()
+	expected := []string{
+		"https://github.com/example/foo/archive/v1.2.tar.gz",
+		"python-foo.patch",
+		"https://example.org/$_unknown/${pkgver%.*}.tar.gz",
+	}
+	if !reflect.DeepEqual(sources, expected) {
+		t.Errorf("Expecting %q, but got %q", expected, sources)
+	}
+	if url := packages[0].URL(); url != "https://github.com/example/foo" {
+		t.Errorf("Unexpected URL %s", url)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	vars := map[string]string{"pkgname": "foo", "_owner": "example"}
+	customVariables("_name2='bar'\n_repo=\"$_owner/$pkgname\"\n", vars)
+	if actual := expand("https://github.com/$_repo/releases/${_name2}-$pkgver", vars); actual != "https://github.com/example/foo/releases/bar-$pkgver" {
+		t.Errorf("Unexpected expansion %s", actual)
+	}
+	if actual := expand("${pkgname%o}-${_owner#ex}-${pkgname%%o}", vars); actual != "fo-ample-fo" {
+		t.Errorf("Unexpected expansion %s", actual)
+	}
+}
+
+func TestSourcesForPkgver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aur-out-of-date")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	srcinfo := "pkgbase = foo\n\tpkgver = 1.0\n\tpkgrel = 1\n\turl = https://example.com/foo\n\tarch = x86_64\n" +
+		"\tsource = https://example.com/foo-1.0.tar.gz\n\tsource = https://example.com/libbar-11.0.2.tar.gz\n" +
+		"\tsource_x86_64 = https://example.com/foo-1.0-x86_64.bin\n\npkgname = foo\n"
+	pkgbuild := "pkgname=foo\npkgver=1.0\npkgrel=1\n_tag=v$pkgver\n" +
+		"source=(\"https://example.com/$pkgname-$pkgver.tar.gz\" # release tarball\n" +
+		"        'https://example.com/libbar-11.0.2.tar.gz')\n" +
+		"source_x86_64=(\"https://example.com/$_tag/foo-${pkgver}-x86_64.bin\")\n"
+	if err := ioutil.WriteFile(path.Join(dir, ".SRCINFO"), []byte(srcinfo), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(dir, "PKGBUILD"), []byte(pkgbuild), 0644); err != nil {
+		t.Fatal(err)
+	}
+	packages, err := NewLocalPkgs([]string{path.Join(dir, ".SRCINFO")}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources, err := SourcesForPkgver(packages[0], "1.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string][]string{
+		"":       {"https://example.com/foo-1.1.tar.gz", "https://example.com/libbar-11.0.2.tar.gz"},
+		"x86_64": {"https://example.com/v1.1/foo-1.1-x86_64.bin"},
+	}
+	if !reflect.DeepEqual(sources, expected) {
+		t.Errorf("Expecting %q, but got %q", expected, sources)
+	}
+
+	if err := ioutil.WriteFile(path.Join(dir, "PKGBUILD"), []byte("source=(\"https://example.com/${pkgver//./_}.tar.gz\")\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SourcesForPkgver(packages[0], "1.1"); err == nil {
+		t.Error("Expecting an error for unsupported expansions")
+	}
+}
+
+func TestSourcesForPkgverSplitPkg(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aur-out-of-date")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	srcinfo := "pkgbase = python-foo\n\tpkgver = 1.0\n\tpkgrel = 1\n\tarch = any\n" +
+		"\tsource = https://example.com/python-foo-1.0.tar.gz\n\npkgname = python-foo-cli\n\npkgname = python-foo-docs\n"
+	pkgbuild := "pkgbase=python-foo\npkgname=(python-foo-cli python-foo-docs)\npkgver=1.0\npkgrel=1\n" +
+		"source=(\"https://example.com/$pkgbase-$pkgver.tar.gz\")\n"
+	if err := ioutil.WriteFile(path.Join(dir, ".SRCINFO"), []byte(srcinfo), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(dir, "PKGBUILD"), []byte(pkgbuild), 0644); err != nil {
+		t.Fatal(err)
+	}
+	packages, err := NewLocalPkgs([]string{path.Join(dir, ".SRCINFO")}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources, err := SourcesForPkgver(packages[0], "1.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"https://example.com/python-foo-1.1.tar.gz"}; !reflect.DeepEqual(sources[""], expected) {
+		t.Errorf("Expecting %q, but got %q", expected, sources[""])
+	}
+}
@@ -1,8 +1,12 @@
 package pkg
 
 import (
+	"bufio"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"strings"
+	"time"
 
 	pkgbuild "github.com/mikkeloscar/gopkgbuild"
 )
@@ -32,6 +36,13 @@ func (p *localPkg) Name() string {
 	return p.pkg.Pkgnames[0]
 }
 
+func (p *localPkg) Base() string {
+	if p.pkg.Pkgbase == "" {
+		return p.Name()
+	}
+	return p.pkg.Pkgbase
+}
+
 func (p *localPkg) Version() *pkgbuild.CompleteVersion {
 	return &pkgbuild.CompleteVersion{
 		Epoch:   uint8(p.pkg.Epoch),
@@ -49,13 +60,56 @@ func (p *localPkg) LocalPKGBUILD() string {
 }
 
 func (p *localPkg) URL() string {
-	return p.pkg.URL
+	return expandAll(p.pkg, []string{p.pkg.URL}, p.Content)[0]
 }
 
 func (p *localPkg) Sources() ([]string, error) {
-	return p.pkg.Source, nil
+	return expandAll(p.pkg, p.pkg.Source, p.Content), nil
+}
+
+// content returns the content of the local PKGBUILD, empty if unavailable
+func (p *localPkg) Content() string {
+	if p.path == "" {
+		return ""
+	}
+	content, err := ioutil.ReadFile(p.LocalPKGBUILD())
+	if err != nil {
+		return ""
+	}
+	return string(content)
+}
+
+func (p *localPkg) ValidPGPKeys() ([]string, error) {
+	return p.pkg.Validpgpkeys, nil
+}
+
+func (p *localPkg) Checksums() (map[string][]string, error) {
+	return checksums(p.pkg), nil
 }
 
 func (p *localPkg) OutOfDate() bool {
 	return false
 }
+
+func (p *localPkg) LastModified() time.Time {
+	return time.Time{}
+}
+
+func (p *localPkg) Maintainer() string {
+	if p.path == "" {
+		return ""
+	}
+	f, err := os.Open(p.LocalPKGBUILD())
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "# Maintainer:") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "# Maintainer:"))
+		}
+	}
+	return ""
+}
@@ -64,6 +64,9 @@ func TestSplitPkg(t *testing.T) {
 	if len(pkgs) != 1 {
 		t.Errorf("Found %d pkgs!", len(pkgs))
 	}
+	if base := pkgs[0].Base(); base != "python-mwclient" {
+		t.Errorf("Expecting pkgbase python-mwclient, but got %s", base)
+	}
 	sources, err := pkgs[0].Sources()
 	if err != nil {
 		t.Error(err)
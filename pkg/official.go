@@ -0,0 +1,297 @@
+package pkg
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	pkgbuild "github.com/mikkeloscar/gopkgbuild"
+	"github.com/simon04/aur-out-of-date/logging"
+)
+
+// archwebURL is the package search API of archlinux.org
+var archwebURL = "https://archlinux.org/packages/search/json/"
+
+// packagingURL is the base URL of the Git repositories of official packages
+var packagingURL = "https://gitlab.archlinux.org/archlinux/packaging/packages/"
+
+type archwebResponse struct {
+	Results  []archwebPkg `json:"results"`
+	NumPages int          `json:"num_pages"`
+	Page     int          `json:"page"`
+}
+
+type archwebPkg struct {
+	Name        string   `json:"pkgname"`
+	Base        string   `json:"pkgbase"`
+	Version     string   `json:"pkgver"`
+	Release     string   `json:"pkgrel"`
+	Epoch       int      `json:"epoch"`
+	URL         string   `json:"url"`
+	Maintainers []string `json:"maintainers"`
+	LastUpdate  string   `json:"last_update"`
+	FlagDate    *string  `json:"flag_date"`
+}
+
+// NewOfficialPkgs looks up the official repository packages by name using archweb
+func NewOfficialPkgs(names []string) ([]Pkg, error) {
+	var r []Pkg
+	for _, name := range names {
+		packages, err := archwebSearch(url.Values{"name": {name}})
+		if err != nil {
+			return nil, err
+		} else if len(packages) == 0 {
+			return nil, fmt.Errorf("Package %s not found in official repositories", name)
+		}
+		r = append(r, packages...)
+	}
+	return r, nil
+}
+
+// NewMaintainerPkgs looks up the official repository packages maintained by the Arch Linux packager using archweb
+func NewMaintainerPkgs(maintainer string) ([]Pkg, error) {
+	return archwebSearch(url.Values{"maintainer": {maintainer}})
+}
+
+// archwebSearch returns the packages found using archweb, one per pkgbase
+func archwebSearch(query url.Values) ([]Pkg, error) {
+	var r []Pkg
+	seen := map[string]bool{}
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		resp, err := http.Get(archwebURL + "?" + query.Encode())
+		if err != nil {
+			return nil, fmt.Errorf("Failed to search official packages: %w", err)
+		}
+		var result archwebResponse
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("Failed to search official packages: %s", resp.Status)
+		} else if err != nil {
+			return nil, fmt.Errorf("Failed to parse official packages: %w", err)
+		}
+		for _, p := range result.Results {
+			if seen[p.Base] {
+				continue
+			}
+			seen[p.Base] = true
+			version := p.Version + "-" + p.Release
+			if p.Epoch > 0 {
+				version = strconv.Itoa(p.Epoch) + ":" + version
+			}
+			official := &officialPkg{base: p.Base, version: version, url: p.URL, flagged: p.FlagDate != nil}
+			if len(p.Maintainers) > 0 {
+				official.maintainer = p.Maintainers[0]
+			}
+			official.lastModified, _ = time.Parse(time.RFC3339, p.LastUpdate)
+			r = append(r, official)
+		}
+		if page >= result.NumPages {
+			return r, nil
+		}
+	}
+}
+
+// NewSyncDBPkgs reads the packages of a (gzip compressed) pacman sync database such as /var/lib/pacman/sync/extra.db, one per pkgbase
+func NewSyncDBPkgs(filename string) ([]Pkg, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read sync database: %w", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read sync database %s (only gzip compression is supported): %w", filename, err)
+	}
+	var r []Pkg
+	seen := map[string]bool{}
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return r, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("Failed to read sync database %s: %w", filename, err)
+		}
+		if path.Base(header.Name) != "desc" {
+			continue
+		}
+		content, err := ioutil.ReadAll(archive)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read sync database %s: %w", filename, err)
+		}
+		p := parseDesc(content)
+		if !seen[p.base] {
+			seen[p.base] = true
+			r = append(r, p)
+		}
+	}
+}
+
+// packagerName strips the email address from a packager such as "Jane Doe <jane@archlinux.org>"
+var packagerName = regexp.MustCompile(`\s*<[^>]*>$`)
+
+// parseDesc parses the desc file of a package in a sync database
+func parseDesc(content []byte) *officialPkg {
+	fields := map[string]string{}
+	var key string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "%") && strings.HasSuffix(line, "%") {
+			key = strings.Trim(line, "%")
+		} else if line != "" && fields[key] == "" {
+			fields[key] = line
+		}
+	}
+	p := &officialPkg{base: fields["BASE"], version: fields["VERSION"], url: fields["URL"], maintainer: packagerName.ReplaceAllString(fields["PACKAGER"], "")}
+	if p.base == "" {
+		p.base = fields["NAME"]
+	}
+	if buildDate, err := strconv.ParseInt(fields["BUILDDATE"], 10, 64); err == nil {
+		p.lastModified = time.Unix(buildDate, 0)
+	}
+	return p
+}
+
+// officialPkg is a package of the official repositories, its .SRCINFO is obtained from its packaging repository
+type officialPkg struct {
+	base         string
+	version      string
+	url          string
+	maintainer   string
+	flagged      bool
+	lastModified time.Time
+}
+
+func (p *officialPkg) Name() string {
+	return p.base
+}
+
+func (p *officialPkg) Base() string {
+	return p.base
+}
+
+func (p *officialPkg) Version() *pkgbuild.CompleteVersion {
+	version, _ := pkgbuild.NewCompleteVersion(p.version)
+	return version
+}
+
+func (p *officialPkg) IsVcs() bool {
+	pkgbuild := pkgbuild.PKGBUILD{
+		Pkgnames: []string{p.Name()},
+	}
+	return pkgbuild.IsDevel()
+}
+
+func (p *officialPkg) LocalPKGBUILD() string {
+	return ""
+}
+
+func (p *officialPkg) URL() string {
+	return p.url
+}
+
+// projectPlus, projectInvalid and projectRepeated convert a pkgbase to the path of its packaging repository
+var projectPlus = regexp.MustCompile(`([a-zA-Z0-9]+)\+([a-zA-Z]+)`)
+var projectInvalid = regexp.MustCompile(`[^a-zA-Z0-9_\-.]`)
+var projectRepeated = regexp.MustCompile(`[_\-]{2,}`)
+
+// gitLabProjectPath converts the pkgbase to the path of its packaging repository, as of devtools
+func gitLabProjectPath(pkgbase string) string {
+	project := projectPlus.ReplaceAllString(pkgbase, "$1-$2")
+	project = strings.Replace(project, "+", "plus", -1)
+	project = projectInvalid.ReplaceAllString(project, "-")
+	project = projectRepeated.ReplaceAllString(project, "-")
+	if project == "tree" {
+		project = "unix-tree"
+	}
+	return project
+}
+
+// fetch obtains a file of the packaging repository
+func (p *officialPkg) fetch(file string) ([]byte, error) {
+	url := packagingURL + gitLabProjectPath(p.base) + "/-/raw/main/" + file
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s", resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// content fetches the PKGBUILD, empty if unavailable
+func (p *officialPkg) Content() string {
+	content, err := p.fetch("PKGBUILD")
+	if err != nil {
+		logging.Log(logging.Info, "Failed to fetch PKGBUILD", "pkg", p.base, "err", err)
+		return ""
+	}
+	return string(content)
+}
+
+// srcinfo fetches and parses the .SRCINFO
+func (p *officialPkg) srcinfo() (*pkgbuild.PKGBUILD, error) {
+	content, err := p.fetch(".SRCINFO")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch .SRCINFO for %s: %w", p.base, err)
+	}
+	pkg, err := pkgbuild.ParseSRCINFOContent(content)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse .SRCINFO for %s: %w", p.base, err)
+	}
+	return pkg, nil
+}
+
+func (p *officialPkg) Sources() ([]string, error) {
+	pkg, err := p.srcinfo()
+	if err != nil {
+		return nil, err
+	}
+	return expandAll(pkg, pkg.Source, p.Content), nil
+}
+
+func (p *officialPkg) ValidPGPKeys() ([]string, error) {
+	pkg, err := p.srcinfo()
+	if err != nil {
+		return nil, err
+	}
+	return pkg.Validpgpkeys, nil
+}
+
+func (p *officialPkg) Checksums() (map[string][]string, error) {
+	pkg, err := p.srcinfo()
+	if err != nil {
+		return nil, err
+	}
+	return checksums(pkg), nil
+}
+
+func (p *officialPkg) OutOfDate() bool {
+	return p.flagged
+}
+
+func (p *officialPkg) Maintainer() string {
+	return p.maintainer
+}
+
+func (p *officialPkg) LastModified() time.Time {
+	return p.lastModified
+}
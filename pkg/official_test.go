@@ -0,0 +1,99 @@
+package pkg
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/h2non/gock"
+)
+
+func TestNewMaintainerPkgs(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://archlinux.org/").
+		Get("/packages/search/json/").
+		MatchParam("maintainer", "jane").
+		MatchParam("page", "1").
+		Reply(http.StatusOK).
+		JSON(map[string]interface{}{"num_pages": 2, "page": 1, "results": []map[string]interface{}{
+			{"pkgname": "python-requests", "pkgbase": "python-requests", "pkgver": "2.31.0", "pkgrel": "1", "epoch": 0,
+				"url": "https://requests.readthedocs.io/", "maintainers": []string{"jane"}, "last_update": "2023-06-01T12:00:00Z"},
+		}})
+	gock.New("https://archlinux.org/").
+		Get("/packages/search/json/").
+		MatchParam("maintainer", "jane").
+		MatchParam("page", "2").
+		Reply(http.StatusOK).
+		JSON(map[string]interface{}{"num_pages": 2, "page": 2, "results": []map[string]interface{}{
+			{"pkgname": "gtk3", "pkgbase": "gtk3", "pkgver": "3.24.38", "pkgrel": "2", "epoch": 1,
+				"url": "https://www.gtk.org/", "maintainers": []string{"jane"}, "flag_date": "2023-07-01T00:00:00Z"},
+			{"pkgname": "gtk3-demos", "pkgbase": "gtk3", "pkgver": "3.24.38", "pkgrel": "2", "epoch": 1},
+		}})
+
+	packages, err := NewMaintainerPkgs("jane")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packages) != 2 {
+		t.Fatalf("Expecting 2 packages, but got %v", packages)
+	}
+	if p := packages[0]; p.Name() != "python-requests" || p.Version().String() != "2.31.0-1" || p.URL() != "https://requests.readthedocs.io/" || p.OutOfDate() || p.Maintainer() != "jane" || p.LastModified().Year() != 2023 {
+		t.Errorf("Unexpected package %v", p)
+	}
+	if p := packages[1]; p.Name() != "gtk3" || p.Version().String() != "1:3.24.38-2" || !p.OutOfDate() {
+		t.Errorf("Unexpected package %v", p)
+	}
+}
+
+func TestNewSyncDBPkgs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syncdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "extra.db")
+	f, err := os.Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	archive := tar.NewWriter(gz)
+	for name, desc := range map[string]string{
+		"ripgrep-14.1.0-1/desc": "%FILENAME%\nripgrep-14.1.0-1-x86_64.pkg.tar.zst\n\n%NAME%\nripgrep\n\n%BASE%\nripgrep\n\n%VERSION%\n14.1.0-1\n\n" +
+			"%URL%\nhttps://github.com/BurntSushi/ripgrep\n\n%BUILDDATE%\n1704067200\n\n%PACKAGER%\nJane Doe <jane@archlinux.org>\n\n",
+	} {
+		archive.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(desc))})
+		archive.Write([]byte(desc))
+	}
+	archive.Close()
+	gz.Close()
+	f.Close()
+
+	packages, err := NewSyncDBPkgs(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packages) != 1 {
+		t.Fatalf("Expecting 1 package, but got %v", packages)
+	}
+	if p := packages[0]; p.Name() != "ripgrep" || p.Version().String() != "14.1.0-1" || p.URL() != "https://github.com/BurntSushi/ripgrep" || p.Maintainer() != "Jane Doe" || p.LastModified().Unix() != 1704067200 {
+		t.Errorf("Unexpected package %v", p)
+	}
+}
+
+func TestGitLabProjectPath(t *testing.T) {
+	for pkgbase, expected := range map[string]string{
+		"ripgrep":    "ripgrep",
+		"libsigc++":  "libsigcplusplus",
+		"gtk2+extra": "gtk2-extra",
+		"tree":       "unix-tree",
+	} {
+		if actual := gitLabProjectPath(pkgbase); actual != expected {
+			t.Errorf("Expecting %s for %s, but got %s", expected, pkgbase, actual)
+		}
+	}
+}
@@ -1,19 +1,53 @@
+// Package pkg reads Arch Linux packages from the AUR or from local .SRCINFO files
 package pkg
 
 import (
+	"time"
+
 	pkgbuild "github.com/mikkeloscar/gopkgbuild"
 )
 
 // Pkg is an interface representing an Arch Linux package.
 type Pkg interface {
 	Name() string
+	// Base returns the pkgbase, which names the AUR Git repository
+	Base() string
 	Version() *pkgbuild.CompleteVersion
 	// IsVcs checks whether pkg ends in -bzr or -git or -svn
 	IsVcs() bool
 	LocalPKGBUILD() string
 	URL() string
 	Sources() ([]string, error)
+	// Content returns the PKGBUILD, empty if unavailable
+	Content() string
+	// ValidPGPKeys returns the fingerprints of validpgpkeys
+	ValidPGPKeys() ([]string, error)
+	// Checksums returns the checksum arrays of the sources by name, e.g. "sha256sums"
+	Checksums() (map[string][]string, error)
 	OutOfDate() bool
+	// Maintainer returns the AUR maintainer, or the first "# Maintainer:" of a local PKGBUILD
+	Maintainer() string
+	// LastModified returns the time of the last AUR update, zero if unknown
+	LastModified() time.Time
+}
+
+// checksums returns the non-empty checksum arrays of the PKGBUILD by name
+func checksums(pkg *pkgbuild.PKGBUILD) map[string][]string {
+	r := map[string][]string{}
+	for name, sums := range map[string][]string{
+		"md5sums":    pkg.Md5sums,
+		"sha1sums":   pkg.Sha1sums,
+		"sha224sums": pkg.Sha224sums,
+		"sha256sums": pkg.Sha256sums,
+		"sha384sums": pkg.Sha384sums,
+		"sha512sums": pkg.Sha512sums,
+		"b2sums":     pkg.B2sums,
+	} {
+		if len(sums) > 0 {
+			r[name] = sums
+		}
+	}
+	return r
 }
 
 // New creates a Pkg from the given parameters. Mainly used for testing.
@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"time"
 
 	"github.com/mikkeloscar/aur"
 	pkgbuild "github.com/mikkeloscar/gopkgbuild"
+	"github.com/simon04/aur-out-of-date/logging"
 )
 
 // NewRemotePkgs creates a Pkg slice from information returned from AUR RPC.
@@ -26,6 +28,10 @@ func (p *remotePkg) Name() string {
 	return p.pkg.Name
 }
 
+func (p *remotePkg) Base() string {
+	return p.pkg.PackageBase
+}
+
 func (p *remotePkg) Version() *pkgbuild.CompleteVersion {
 	version, _ := pkgbuild.NewCompleteVersion(p.pkg.Version)
 	return version
@@ -47,7 +53,49 @@ func (p *remotePkg) URL() string {
 }
 
 func (p *remotePkg) Sources() ([]string, error) {
-	resp, err := http.Get("https://aur.archlinux.org/cgit/aur.git/plain/.SRCINFO?h=" + p.pkg.PackageBase)
+	pkg, err := p.srcinfo()
+	if err != nil {
+		return nil, err
+	}
+	return expandAll(pkg, pkg.Source, p.Content), nil
+}
+
+// content fetches the PKGBUILD from AUR, empty if unavailable
+func (p *remotePkg) Content() string {
+	url := "https://aur.archlinux.org/cgit/aur.git/plain/PKGBUILD?h=" + p.pkg.PackageBase
+	resp, err := http.Get(url)
+	if err != nil {
+		logging.Log(logging.Info, "Failed to fetch PKGBUILD", "pkg", p.pkg.Name, "err", err)
+		return ""
+	}
+	defer resp.Body.Close()
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return ""
+	}
+	return string(content)
+}
+
+func (p *remotePkg) ValidPGPKeys() ([]string, error) {
+	pkg, err := p.srcinfo()
+	if err != nil {
+		return nil, err
+	}
+	return pkg.Validpgpkeys, nil
+}
+
+func (p *remotePkg) Checksums() (map[string][]string, error) {
+	pkg, err := p.srcinfo()
+	if err != nil {
+		return nil, err
+	}
+	return checksums(pkg), nil
+}
+
+// srcinfo fetches and parses the .SRCINFO from AUR
+func (p *remotePkg) srcinfo() (*pkgbuild.PKGBUILD, error) {
+	url := "https://aur.archlinux.org/cgit/aur.git/plain/.SRCINFO?h=" + p.pkg.PackageBase
+	resp, err := http.Get(url)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to fetch .SRCINFO for %s: %w", p.pkg.Name, err)
 	}
@@ -60,9 +108,20 @@ func (p *remotePkg) Sources() ([]string, error) {
 	if err != nil {
 		return nil, fmt.Errorf("Failed to parse .SRCINFO for %s: %w", p.pkg.Name, err)
 	}
-	return pkg.Source, nil
+	return pkg, nil
 }
 
 func (p *remotePkg) OutOfDate() bool {
 	return p.pkg.OutOfDate > 0
 }
+
+func (p *remotePkg) Maintainer() string {
+	return p.pkg.Maintainer
+}
+
+func (p *remotePkg) LastModified() time.Time {
+	if p.pkg.LastModified == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(p.pkg.LastModified), 0)
+}
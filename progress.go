@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+// progress shows the number of checked packages on stderr while checking interactively, nil if disabled
+type progress struct {
+	w       io.Writer
+	total   int
+	checked int
+	shown   bool
+}
+
+// runProgress is the progress of the current run if enabled by -progress
+var runProgress *progress
+
+// show prints the progress line, including the package being waited for since the given time, if any
+func (p *progress) show(waiting string, since time.Time) {
+	if p == nil {
+		return
+	}
+	line := fmt.Sprintf("%d/%d checked, %d out-of-date", p.checked, p.total, statistics.OutOfDate)
+	if waiting != "" {
+		line += fmt.Sprintf(", waiting for %s (%s)", waiting, time.Since(since).Round(time.Second))
+	}
+	fmt.Fprint(p.w, "\r\x1b[K"+line)
+	p.shown = true
+}
+
+// advance counts a checked package
+func (p *progress) advance() {
+	if p != nil {
+		p.checked++
+	}
+}
+
+// clear removes the progress line, e.g. before printing a status
+func (p *progress) clear() {
+	if p == nil || !p.shown {
+		return
+	}
+	fmt.Fprint(p.w, "\r\x1b[K")
+	p.shown = false
+}
+
+// receive waits for the status of the package, showing the progress meanwhile, false if the run has been aborted
+func receive(result <-chan status.Status, name string) (status.Status, bool) {
+	var tick <-chan time.Time
+	if runProgress != nil {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	started := time.Now()
+	runProgress.show("", started)
+	for {
+		select {
+		case s := <-result:
+			return s, currentRunContext().Err() == nil
+		case <-currentRunContext().Done():
+			return status.Status{}, false
+		case <-tick:
+			runProgress.show(name, started)
+		}
+	}
+}
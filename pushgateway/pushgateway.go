@@ -0,0 +1,110 @@
+// Package pushgateway pushes the metrics of a run to a Prometheus Pushgateway, e.g. for cron jobs which cannot be scraped
+package pushgateway
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/logging"
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+// now is replaced in tests
+var now = time.Now
+
+// Writer collects the statuses of a run and pushes the run metrics once finished
+type Writer struct {
+	url        string
+	job        string
+	instance   string
+	started    time.Time
+	statistics status.Statistics
+	errors     int
+}
+
+// New returns a Writer pushing to the Pushgateway at the given URL, grouped by job and instance (omitted if empty)
+func New(url, job, instance string) *Writer {
+	return &Writer{url: strings.TrimSuffix(url, "/"), job: job, instance: instance, started: now()}
+}
+
+// Status implements status.Formatter
+func (w *Writer) Status(s *status.Status) {
+	w.statistics.Update(s.Status)
+	if s.Error != "" {
+		w.errors++
+	}
+}
+
+// Finish implements status.Formatter
+func (w *Writer) Finish(statistics *status.Statistics) {
+	if err := w.push(); err != nil {
+		logging.Errorf("Failed to push metrics to %s: %v", w.url, err)
+	}
+}
+
+// groupURL returns the URL of the grouping key, see https://github.com/prometheus/pushgateway#url
+func (w *Writer) groupURL() string {
+	u := w.url + "/metrics/job/" + url.PathEscape(w.job)
+	if w.instance != "" {
+		u += "/instance/" + url.PathEscape(w.instance)
+	}
+	return u
+}
+
+// metrics returns the run metrics in the Prometheus text exposition format
+func (w *Writer) metrics() []byte {
+	buf := bytes.NewBuffer(nil)
+	s := w.statistics
+	fmt.Fprintln(buf, "# HELP aur_packages Number of AUR packages per status.")
+	fmt.Fprintln(buf, "# TYPE aur_packages gauge")
+	for _, c := range []struct {
+		status status.StatusType
+		n      int
+	}{
+		{status.UpToDate, s.UpToDate},
+		{status.FlaggedOutOfDate, s.FlaggedOutOfDate},
+		{status.OutOfDate, s.OutOfDate},
+		{status.Unknown, s.Unknown},
+		{status.BadSignature, s.BadSignature},
+		{status.SourceGone, s.SourceGone},
+		{status.UpstreamGone, s.UpstreamGone},
+		{status.ChecksumMismatch, s.ChecksumMismatch},
+	} {
+		fmt.Fprintf(buf, "aur_packages{status=%q} %d\n", c.status, c.n)
+	}
+	fmt.Fprintln(buf, "# HELP aur_packages_out_of_date Number of out-of-date AUR packages (including flagged ones).")
+	fmt.Fprintln(buf, "# TYPE aur_packages_out_of_date gauge")
+	fmt.Fprintf(buf, "aur_packages_out_of_date %d\n", s.OutOfDate+s.FlaggedOutOfDate)
+	fmt.Fprintln(buf, "# HELP aur_check_errors Number of AUR packages whose upstream version could not be determined.")
+	fmt.Fprintln(buf, "# TYPE aur_check_errors gauge")
+	fmt.Fprintf(buf, "aur_check_errors %d\n", w.errors)
+	fmt.Fprintln(buf, "# HELP aur_run_duration_seconds Duration of the last run.")
+	fmt.Fprintln(buf, "# TYPE aur_run_duration_seconds gauge")
+	fmt.Fprintf(buf, "aur_run_duration_seconds %g\n", now().Sub(w.started).Seconds())
+	fmt.Fprintln(buf, "# HELP aur_run_timestamp_seconds Time of the last run.")
+	fmt.Fprintln(buf, "# TYPE aur_run_timestamp_seconds gauge")
+	fmt.Fprintf(buf, "aur_run_timestamp_seconds %d\n", now().Unix())
+	return buf.Bytes()
+}
+
+// push replaces the metrics of the grouping key using PUT
+func (w *Writer) push() error {
+	req, err := http.NewRequest("PUT", w.groupURL(), bytes.NewReader(w.metrics()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: %s", req.Method, req.URL, resp.Status)
+	}
+	return nil
+}
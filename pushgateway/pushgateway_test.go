@@ -0,0 +1,48 @@
+package pushgateway
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestPush(t *testing.T) {
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.EscapedPath()
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer server.Close()
+	started := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return started }
+	defer func() { now = time.Now }()
+
+	w := New(server.URL+"/", "aur-out-of-date", "my host")
+	now = func() time.Time { return started.Add(41 * time.Second) }
+	w.Status(&status.Status{Package: "foo", Status: status.OutOfDate})
+	w.Status(&status.Status{Package: "bar", Status: status.Unknown, Error: "timeout"})
+	w.Status(&status.Status{Package: "baz", Status: status.UpToDate})
+	w.Finish(nil)
+
+	if method != "PUT" || path != "/metrics/job/aur-out-of-date/instance/my%20host" {
+		t.Errorf("Expecting PUT /metrics/job/aur-out-of-date/instance/my%%20host, but got %s %s", method, path)
+	}
+	for _, expected := range []string{
+		`aur_packages{status="UP-TO-DATE"} 1`,
+		`aur_packages{status="OUT-OF-DATE"} 1`,
+		`aur_packages_out_of_date 1`,
+		`aur_check_errors 1`,
+		`aur_run_duration_seconds 41`,
+		`aur_run_timestamp_seconds 1577836841`,
+	} {
+		if !strings.Contains(body, expected+"\n") {
+			t.Errorf("Expecting '%s' in '%s'", expected, body)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/config"
+	"github.com/simon04/aur-out-of-date/logging"
+)
+
+// watch polls the modification time of the files, sending on the returned channel if one of them changed or on SIGHUP
+func watch(interval time.Duration, files ...string) <-chan struct{} {
+	changed := make(chan struct{}, 1)
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	modified := func() []time.Time {
+		times := make([]time.Time, len(files))
+		for i, file := range files {
+			if info, err := os.Stat(file); err == nil {
+				times[i] = info.ModTime()
+			}
+		}
+		return times
+	}
+	go func() {
+		last := modified()
+		for {
+			select {
+			case <-time.After(interval):
+			case <-hangup:
+				last = nil
+			case <-interrupted.Done():
+				return
+			}
+			current := modified()
+			for i := range current {
+				if last == nil || !current[i].Equal(last[i]) {
+					select {
+					case changed <- struct{}{}:
+					default:
+					}
+					break
+				}
+			}
+			last = current
+		}
+	}()
+	return changed
+}
+
+// configMutex guards the config state, i.e. conf, nvchecker, providers and the flags set by settings, which reloadConfig
+// replaces while the checks of the API read it. Runs need no lock, as the config is only reloaded between them.
+var configMutex sync.RWMutex
+
+// reloadConfig reads the config file, the nvchecker configuration, the ignore file and the provider mapping again, keeping the current ones on errors
+func reloadConfig() error {
+	c, err := config.FromFile(commandline.config)
+	if err != nil {
+		return fmt.Errorf("Failed to read config: %w", err)
+	}
+	entries := nvchecker
+	if commandline.nvchecker != "" {
+		if entries, err = config.FromNvchecker(commandline.nvchecker); err != nil {
+			return fmt.Errorf("Failed to read nvchecker config: %w", err)
+		}
+	}
+	mapping, err := config.FromProviderFile(commandline.providers)
+	if err != nil {
+		return fmt.Errorf("Failed to read provider mapping: %w", err)
+	}
+	rules, err := config.FromIgnoreFile(commandline.ignoreFile)
+	if err != nil {
+		return fmt.Errorf("Failed to read ignore file: %w", err)
+	}
+	c.IgnoreRules = rules
+	configMutex.Lock()
+	defer configMutex.Unlock()
+	if err := c.Replace(conf, flag.CommandLine); err != nil {
+		return fmt.Errorf("Failed to apply config: %w", err)
+	}
+	if err := compilePatterns(); err != nil {
+		// restore the flags of the current config
+		conf.Replace(c, flag.CommandLine)
+		compilePatterns()
+		return err
+	}
+	conf = c
+	nvchecker = entries
+	providers = mapping
+	logging.Infof("Reloaded config %s", commandline.config)
+	return nil
+}
+
+// configFiles returns the files to watch for changes in daemon mode
+func configFiles() []string {
+	files := []string{commandline.config, commandline.ignoreFile, commandline.providers}
+	if commandline.nvchecker != "" {
+		files = append(files, commandline.nvchecker)
+	}
+	if commandline.fromFile != "" {
+		files = append(files, commandline.fromFile)
+	}
+	return files
+}
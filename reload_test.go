@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/config"
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestReloadConfigDuringCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aur-out-of-date")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	srcinfo := path.Join(dir, ".SRCINFO")
+	if err := ioutil.WriteFile(srcinfo, []byte("pkgbase = foo\n\tpkgver = 1.0\n\tpkgrel = 1\n\turl = https://example.com/foo\n\tarch = any\n\npkgname = foo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	packageFile := path.Join(dir, "packages.txt")
+	if err := ioutil.WriteFile(packageFile, []byte(srcinfo+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	configFile := path.Join(dir, "config.toml")
+	if err := ioutil.WriteFile(configFile, []byte("[settings]\nlocal = true\n\n[packages.foo]\nskip = { reason = \"pinned\" }\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	flags := flag.CommandLine
+	defer func() { flag.CommandLine = flags }()
+	flag.CommandLine = flag.NewFlagSet("aur-out-of-date", flag.ContinueOnError)
+	flag.BoolVar(&commandline.local, "local", false, "")
+	saved := commandline
+	defer func() { commandline = saved }()
+	commandline.config, commandline.fromFile = configFile, packageFile
+	c, err := config.FromFile(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Replace(nil, flag.CommandLine); err != nil {
+		t.Fatal(err)
+	}
+	conf = c
+	defer func() { conf = nil }()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			if err := reloadConfig(); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		s, err := checkPackage("foo")
+		if err != nil {
+			t.Fatal(err)
+		} else if s.Status != status.Unknown || s.Reason != "skipped" {
+			t.Errorf("Expecting foo to be skipped, but got %v", s)
+		}
+	}
+	wg.Wait()
+}
@@ -1,3 +1,4 @@
+// Package rfc7464 encodes JSON Text Sequences (RFC 7464)
 package rfc7464
 
 import (
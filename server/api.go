@@ -0,0 +1,98 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+// ErrUnknownPackage is returned by a Checker for packages not being tracked
+var ErrUnknownPackage = errors.New("Unknown package")
+
+// Checker checks a single package by name
+type Checker func(name string) (*status.Status, error)
+
+type packagesResponse struct {
+	Checked  *time.Time         `json:"checked,omitempty"`
+	Packages []*status.Status   `json:"packages"`
+	Stats    *status.Statistics `json:"statistics,omitempty"`
+}
+
+type checkRequest struct {
+	Name string `json:"name"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// API returns a handler serving the results as JSON:
+// GET /packages, GET /packages/{name}, and POST /check re-checking the tracked package {"name": …} using check
+func API(results *Results, check Checker) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/packages", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, errorResponse{"Method not allowed"})
+			return
+		}
+		packages, statistics, finished := results.Last()
+		response := packagesResponse{Packages: packages, Stats: statistics}
+		if response.Packages == nil {
+			response.Packages = []*status.Status{}
+		}
+		if !finished.IsZero() {
+			response.Checked = &finished
+		}
+		writeJSON(w, http.StatusOK, response)
+	})
+	mux.HandleFunc("/packages/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, errorResponse{"Method not allowed"})
+			return
+		}
+		name := strings.TrimPrefix(r.URL.Path, "/packages/")
+		if s := results.Get(name); s != nil {
+			writeJSON(w, http.StatusOK, s)
+		} else {
+			writeJSON(w, http.StatusNotFound, errorResponse{ErrUnknownPackage.Error() + " " + name})
+		}
+	})
+	mux.HandleFunc("/check", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, errorResponse{"Method not allowed"})
+			return
+		}
+		var request checkRequest
+		if name := r.URL.Query().Get("name"); name != "" {
+			request.Name = name
+		} else if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Name == "" {
+			writeJSON(w, http.StatusBadRequest, errorResponse{`Expecting {"name": "…"} or ?name=…`})
+			return
+		}
+		if results.Get(request.Name) == nil {
+			writeJSON(w, http.StatusNotFound, errorResponse{ErrUnknownPackage.Error() + " " + request.Name})
+			return
+		}
+		s, err := check(request.Name)
+		if errors.Is(err, ErrUnknownPackage) {
+			writeJSON(w, http.StatusNotFound, errorResponse{err.Error() + " " + request.Name})
+			return
+		} else if err != nil {
+			writeJSON(w, http.StatusInternalServerError, errorResponse{err.Error()})
+			return
+		}
+		results.Update(s)
+		writeJSON(w, http.StatusOK, s)
+	})
+	return mux
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestAPI(t *testing.T) {
+	results := &Results{}
+	results.Status(&status.Status{Package: "foo", Version: "1.0-1", Upstream: "1.1", Status: status.OutOfDate})
+	results.Status(&status.Status{Package: "bar", Version: "2.0-1", Upstream: "2.0", Status: status.UpToDate})
+	results.Finish(&status.Statistics{OutOfDate: 1, UpToDate: 1})
+	handler := API(results, func(name string) (*status.Status, error) {
+		if name == "bar" {
+			return nil, ErrUnknownPackage
+		}
+		return &status.Status{Package: "foo", Version: "1.1-1", Upstream: "1.1", Status: status.UpToDate}, nil
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/packages", nil))
+	var response packagesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Packages) != 2 || response.Checked == nil || response.Stats.OutOfDate != 1 {
+		t.Errorf("Expecting 2 packages, but got %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/packages/foo", nil))
+	if w.Code != 200 || !strings.Contains(w.Body.String(), `"status":"OUT-OF-DATE"`) {
+		t.Errorf("Expecting foo to be out-of-date, but got %d %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "/check", strings.NewReader(`{"name": "foo"}`)))
+	if w.Code != 200 || !strings.Contains(w.Body.String(), `"status":"UP-TO-DATE"`) {
+		t.Errorf("Expecting foo to be up-to-date, but got %d %s", w.Code, w.Body.String())
+	}
+	if s := results.Get("foo"); s == nil || s.Status != status.UpToDate {
+		t.Errorf("Expecting foo to be updated, but got %v", s)
+	}
+
+	for _, c := range []struct {
+		method, path, body string
+		code               int
+	}{
+		{"GET", "/packages/baz", "", 404},
+		{"POST", "/check?name=baz", "", 404},
+		{"POST", "/check?name=bar", "", 404},
+		{"POST", "/check", "{}", 400},
+		{"GET", "/check", "", 405},
+		{"DELETE", "/packages", "", 405},
+	} {
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(c.method, c.path, strings.NewReader(c.body)))
+		if w.Code != c.code {
+			t.Errorf("Expecting %d for %s %s, but got %d %s", c.code, c.method, c.path, w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/simon04/aur-out-of-date/badge"
+)
+
+// Badges returns a handler serving GET /badge/{name}.svg, the SVG badge of the package in the last results
+func Badges(results *Results) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		name := strings.TrimPrefix(r.URL.Path, "/badge/")
+		if !strings.HasSuffix(name, ".svg") {
+			http.NotFound(w, r)
+			return
+		}
+		name = strings.TrimSuffix(name, ".svg")
+		code := http.StatusOK
+		var svg string
+		if s := results.Get(name); s != nil {
+			svg = badge.ForStatus(s)
+		} else {
+			code = http.StatusNotFound
+			svg = badge.SVG("upstream", "not tracked", badge.Grey)
+		}
+		etag := fmt.Sprintf(`"%x"`, sha1.Sum([]byte(svg)))
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("ETag", etag)
+		if code == http.StatusOK && r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.WriteHeader(code)
+		w.Write([]byte(svg))
+	})
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestBadges(t *testing.T) {
+	results := &Results{}
+	results.Status(&status.Status{Package: "foo", Version: "1.0-1", Upstream: "1.1", Status: status.OutOfDate})
+	results.Finish(nil)
+	handler := Badges(results)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/badge/foo.svg", nil))
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "image/svg+xml" || !strings.Contains(w.Body.String(), "out of date: 1.1") {
+		t.Errorf("Unexpected badge %d %v %s", w.Code, w.Header(), w.Body.String())
+	}
+
+	req := httptest.NewRequest("GET", "/badge/foo.svg", nil)
+	req.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("Expecting 304 Not Modified, but got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/badge/bar.svg", nil))
+	if w.Code != http.StatusNotFound || !strings.Contains(w.Body.String(), "not tracked") {
+		t.Errorf("Unexpected badge %d %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/badge/foo", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expecting 404 Not Found, but got %d", w.Code)
+	}
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+type dashboardPage struct {
+	Finished   time.Time
+	Packages   []*status.Status
+	Statistics *status.Statistics
+	Statuses   []status.StatusType
+	Status     string
+	Query      string
+}
+
+var dashboardTemplate = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"lower": func(s status.StatusType) string { return strings.ToLower(string(s)) },
+	"time": func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format("2006-01-02")
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>aur-out-of-date</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: .3em .6em; border-bottom: 1px solid #ddd; vertical-align: top; }
+th { background: #f4f4f4; }
+form { margin-bottom: 1em; }
+.badge { display: inline-block; padding: .1em .5em; border-radius: .3em; color: #fff; font-size: .85em; background: #777; }
+.badge.up-to-date { background: #2e7d32; }
+.badge.out-of-date, .badge.flagged-out-of-date, .badge.bad-signature, .badge.upstream-gone { background: #c62828; }
+.badge.unknown { background: #9e9e9e; }
+.error { color: #c62828; font-size: .85em; }
+</style>
+</head>
+<body>
+<h1>aur-out-of-date</h1>
+{{if .Finished.IsZero}}<p>The first check is still running.</p>{{else}}
+<p>Last checked on {{.Finished.Format "2006-01-02 15:04 MST"}}{{with .Statistics}}: {{.UpToDate}} up-to-date, {{.FlaggedOutOfDate}} flagged out-of-date, {{.OutOfDate}} out-of-date, {{.Unknown}} unknown{{end}}</p>
+{{end}}
+<form>
+<input type="search" name="q" value="{{.Query}}" placeholder="Package or maintainer">
+<select name="status">
+<option value="">All</option>
+{{- range .Statuses}}
+<option{{if eq (print .) $.Status}} selected{{end}}>{{.}}</option>
+{{- end}}
+</select>
+<button>Filter</button>
+</form>
+<table>
+<thead><tr><th>Package</th><th>Maintainer</th><th>Version</th><th>Upstream</th><th>Provider</th><th>Status</th><th>Message</th><th>Released</th><th>Checked</th></tr></thead>
+<tbody>
+{{- range .Packages}}
+<tr>
+<td><a href="https://aur.archlinux.org/packages/{{.Package}}">{{.Package}}</a></td>
+<td>{{.Maintainer}}</td>
+<td>{{.Version}}</td>
+<td>{{if .ReleaseURL}}<a href="{{.ReleaseURL}}">{{.Upstream.String}}</a>{{else}}{{.Upstream.String}}{{end}}</td>
+<td>{{.Provider}}</td>
+<td><span class="badge {{lower .Status}}">{{.Label}}</span></td>
+<td>{{.Message}}{{if .Error}}<div class="error">{{.Error}}</div>{{end}}</td>
+<td>{{time .Released}}</td>
+<td>{{if not .CheckedAt.IsZero}}{{.CheckedAt.Format "15:04"}}{{end}}</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+</body>
+</html>
+`))
+
+// filter returns the statuses matching the status type (if any) and whose package or maintainer contains query
+func filter(packages []*status.Status, statusType string, query string) []*status.Status {
+	var filtered []*status.Status
+	query = strings.ToLower(query)
+	for _, s := range packages {
+		if statusType != "" && string(s.Status) != statusType {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(s.Package), query) && !strings.Contains(strings.ToLower(s.Maintainer), query) {
+			continue
+		}
+		filtered = append(filtered, s)
+	}
+	return filtered
+}
+
+// Dashboard returns a handler serving an HTML page listing the results, filtered by the query parameters status and q
+func Dashboard(results *Results) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		packages, statistics, finished := results.Last()
+		page := dashboardPage{
+			Finished:   finished,
+			Statistics: statistics,
+			Statuses:   []status.StatusType{status.OutOfDate, status.FlaggedOutOfDate, status.BadSignature, status.SourceGone, status.UpstreamGone, status.ChecksumMismatch, status.Unknown, status.UpToDate},
+			Status:     r.URL.Query().Get("status"),
+			Query:      r.URL.Query().Get("q"),
+		}
+		page.Packages = filter(packages, page.Status, page.Query)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := dashboardTemplate.Execute(w, page); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestDashboard(t *testing.T) {
+	results := &Results{}
+	handler := Dashboard(results)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if !strings.Contains(w.Body.String(), "The first check is still running.") {
+		t.Errorf("Expecting pending first check, but got %s", w.Body.String())
+	}
+
+	results.Status(&status.Status{Package: "foo", Maintainer: "simon04", Version: "1.0-1", Upstream: "1.1", Provider: "github", Status: status.OutOfDate, Message: "should be updated to 1.1"})
+	results.Status(&status.Status{Package: "bar", Version: "2.0-1", Status: status.Unknown, Message: "No release found", Error: "connection <refused>"})
+	results.Finish(&status.Statistics{OutOfDate: 1, Unknown: 1})
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	actual := w.Body.String()
+	for _, expected := range []string{
+		`<a href="https://aur.archlinux.org/packages/foo">foo</a>`,
+		`<span class="badge out-of-date">OUT-OF-DATE</span>`,
+		`<div class="error">connection &lt;refused&gt;</div>`,
+		`0 up-to-date, 0 flagged out-of-date, 1 out-of-date, 1 unknown`,
+	} {
+		if !strings.Contains(actual, expected) {
+			t.Errorf("Expecting '%s' in '%s'", expected, actual)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/?status=UNKNOWN&q=ba", nil))
+	actual = w.Body.String()
+	if !strings.Contains(actual, "packages/bar") || strings.Contains(actual, "packages/foo") {
+		t.Errorf("Expecting bar only, but got %s", actual)
+	}
+	if !strings.Contains(actual, `<option selected>UNKNOWN</option>`) {
+		t.Errorf("Expecting UNKNOWN to be selected, but got %s", actual)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/favicon.ico", nil))
+	if w.Code != 404 {
+		t.Errorf("Expecting 404, but got %d", w.Code)
+	}
+}
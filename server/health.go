@@ -0,0 +1,57 @@
+package server
+
+import (
+	"net/http"
+	"runtime"
+	"time"
+)
+
+type healthResponse struct {
+	Status     string     `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	Uptime     float64    `json:"uptime_seconds"`
+	Checked    *time.Time `json:"checked,omitempty"`
+	Packages   int        `json:"packages"`
+	Goroutines int        `json:"goroutines"`
+	Memory     uint64     `json:"memory_bytes"`
+}
+
+// Health returns a handler for liveness and readiness probes serving JSON including basic runtime stats:
+// GET /healthz succeeds while the process is serving, GET /readyz succeeds once a check has completed within maxAge (0 for any age)
+func Health(results *Results, maxAge time.Duration) http.Handler {
+	started := time.Now()
+	response := func(state string) healthResponse {
+		packages, _, finished := results.Last()
+		var memory runtime.MemStats
+		runtime.ReadMemStats(&memory)
+		r := healthResponse{
+			Status:     state,
+			Uptime:     time.Since(started).Seconds(),
+			Packages:   len(packages),
+			Goroutines: runtime.NumGoroutine(),
+			Memory:     memory.Sys,
+		}
+		if !finished.IsZero() {
+			r.Checked = &finished
+		}
+		return r
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, response("ok"))
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		h := response("ready")
+		if h.Checked == nil {
+			h.Status, h.Error = "not ready", "First check is still running"
+		} else if maxAge > 0 && time.Since(*h.Checked) > maxAge {
+			h.Status, h.Error = "not ready", "Last check completed more than "+maxAge.String()+" ago"
+		}
+		if h.Error != "" {
+			writeJSON(w, http.StatusServiceUnavailable, h)
+			return
+		}
+		writeJSON(w, http.StatusOK, h)
+	})
+	return mux
+}
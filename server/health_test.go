@@ -0,0 +1,42 @@
+package server
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestHealth(t *testing.T) {
+	results := &Results{}
+	handler := Health(results, time.Hour)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/healthz", nil))
+	if w.Code != 200 || !strings.Contains(w.Body.String(), `"status":"ok"`) || !strings.Contains(w.Body.String(), `"goroutines":`) {
+		t.Errorf("Expecting healthy, but got %d %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+	if w.Code != 503 {
+		t.Errorf("Expecting not ready before the first check, but got %d %s", w.Code, w.Body.String())
+	}
+
+	results.Status(&status.Status{Package: "foo", Status: status.UpToDate})
+	results.Finish(nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+	if w.Code != 200 || !strings.Contains(w.Body.String(), `"packages":1`) {
+		t.Errorf("Expecting ready, but got %d %s", w.Code, w.Body.String())
+	}
+
+	results.finished = time.Now().Add(-2 * time.Hour)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+	if w.Code != 503 {
+		t.Errorf("Expecting not ready after a stale check, but got %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,78 @@
+// Package server serves the results of periodic checks over HTTP
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+// Results is a status.Formatter keeping the statuses of the last completed run
+type Results struct {
+	mutex      sync.Mutex
+	pending    []*status.Status
+	packages   []*status.Status
+	statistics *status.Statistics
+	finished   time.Time
+}
+
+// Status implements status.Formatter
+func (r *Results) Status(s *status.Status) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.pending = append(r.pending, s)
+}
+
+// Finish implements status.Formatter
+func (r *Results) Finish(statistics *status.Statistics) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.packages = r.pending
+	r.pending = nil
+	// copy the statistics, which are reset by the next run
+	r.statistics = nil
+	if statistics != nil {
+		copied := *statistics
+		r.statistics = &copied
+	}
+	r.finished = time.Now()
+}
+
+// Last returns the statuses and statistics of the last completed run and its time, zero if none has completed yet
+func (r *Results) Last() ([]*status.Status, *status.Statistics, time.Time) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.packages, r.statistics, r.finished
+}
+
+// Update replaces the status of a single package in the results of the last run
+func (r *Results) Update(s *status.Status) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	packages := make([]*status.Status, 0, len(r.packages)+1)
+	replaced := false
+	for _, p := range r.packages {
+		if p.Package == s.Package {
+			p = s
+			replaced = true
+		}
+		packages = append(packages, p)
+	}
+	if !replaced {
+		packages = append(packages, s)
+	}
+	r.packages = packages
+}
+
+// Get returns the status of the package in the results of the last run, nil if unknown
+func (r *Results) Get(name string) *status.Status {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, s := range r.packages {
+		if s.Package == name {
+			return s
+		}
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestResultsCopyStatistics(t *testing.T) {
+	results := &Results{}
+	statistics := &status.Statistics{OutOfDate: 1}
+	results.Finish(statistics)
+	*statistics = status.Statistics{}
+	if _, s, _ := results.Last(); s.OutOfDate != 1 {
+		t.Errorf("Expecting the statistics of the last run, but got %+v", s)
+	}
+}
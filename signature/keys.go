@@ -0,0 +1,98 @@
+package signature
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Keyserver is queried for the validpgpkeys using the machine readable HKP index
+var Keyserver = "https://keyserver.ubuntu.com"
+
+// ExpiryWarning is the time before the expiry of a key to warn about it
+var ExpiryWarning = 30 * 24 * time.Hour
+
+// now is replaced in tests
+var now = time.Now
+
+// Key is the state of a public key as published on the keyserver
+type Key struct {
+	Fingerprint string
+	Expires     time.Time
+	Revoked     bool
+	Expired     bool
+}
+
+// CheckKeys looks up the validpgpkeys on the keyserver, returning warnings for keys which are revoked, expired or expire soon.
+// Keys failing to be looked up are skipped, their errors are combined.
+func CheckKeys(fingerprints []string) ([]string, error) {
+	var warnings, errs []string
+	for _, fingerprint := range fingerprints {
+		key, err := LookupKey(fingerprint)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if warning := key.warning(); warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+	if len(errs) > 0 {
+		return warnings, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return warnings, nil
+}
+
+// warning describes a revoked, expired or soon expiring key, "" otherwise
+func (k Key) warning() string {
+	switch {
+	case k.Revoked:
+		return fmt.Sprintf("validpgpkeys %s is revoked", k.Fingerprint)
+	case k.Expired || (!k.Expires.IsZero() && !now().Before(k.Expires)):
+		return fmt.Sprintf("validpgpkeys %s expired on %s", k.Fingerprint, k.Expires.Format("2006-01-02"))
+	case !k.Expires.IsZero() && now().Add(ExpiryWarning).After(k.Expires):
+		return fmt.Sprintf("validpgpkeys %s expires on %s", k.Fingerprint, k.Expires.Format("2006-01-02"))
+	}
+	return ""
+}
+
+// LookupKey obtains the expiry and revocation of the key with the fingerprint from the keyserver
+func LookupKey(fingerprint string) (Key, error) {
+	fingerprint = strings.ToUpper(strings.Replace(fingerprint, " ", "", -1))
+	u := Keyserver + "/pks/lookup?op=index&options=mr&fingerprint=on&search=0x" + url.QueryEscape(fingerprint)
+	resp, err := http.Get(u)
+	if err != nil {
+		return Key{}, fmt.Errorf("Failed to look up key %s: %w", fingerprint, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return Key{}, fmt.Errorf("Key %s not found on %s", fingerprint, Keyserver)
+	} else if resp.StatusCode != http.StatusOK {
+		return Key{}, fmt.Errorf("Failed to look up key %s: %s", fingerprint, resp.Status)
+	}
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		// pub:<fingerprint>:<algorithm>:<length>:<creation>:<expiration>:<flags>
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) < 7 || fields[0] != "pub" || !strings.HasSuffix(strings.ToUpper(fields[1]), fingerprint) {
+			continue
+		}
+		key := Key{
+			Fingerprint: fingerprint,
+			Revoked:     strings.Contains(fields[6], "r"),
+			Expired:     strings.Contains(fields[6], "e"),
+		}
+		if expires, err := strconv.ParseInt(fields[5], 10, 64); err == nil && expires > 0 {
+			key.Expires = time.Unix(expires, 0).UTC()
+		}
+		return key, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return Key{}, fmt.Errorf("Failed to look up key %s: %w", fingerprint, err)
+	}
+	return Key{}, fmt.Errorf("Key %s not found on %s", fingerprint, Keyserver)
+}
@@ -0,0 +1,77 @@
+package signature
+
+import (
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+)
+
+func TestCheckKeys(t *testing.T) {
+	now = func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+	defer gock.Off()
+	for fingerprint, line := range map[string]string{
+		"A2FF3A36AAA56654109064AB19802F8B0D70FC30": "pub:A2FF3A36AAA56654109064AB19802F8B0D70FC30:1:4096:1500000000::",
+		"6A5B4D5C3E2F1A0B9C8D7E6F5A4B3C2D1E0F9A8B": "pub:6A5B4D5C3E2F1A0B9C8D7E6F5A4B3C2D1E0F9A8B:1:4096:1500000000:1705000000:",
+		"0123456789ABCDEF0123456789ABCDEF01234567": "pub:0123456789ABCDEF0123456789ABCDEF01234567:1:4096:1500000000::r",
+	} {
+		gock.New("https://keyserver.ubuntu.com").
+			Get("/pks/lookup").
+			MatchParam("search", "0x"+fingerprint).
+			Reply(200).
+			BodyString("info:1:1\n" + line + "\nuid:Jane Doe <jane@example.com>:1500000000::\n")
+	}
+
+	warnings, err := CheckKeys([]string{
+		"a2ff 3a36 aaa5 6654 1090  64ab 1980 2f8b 0d70 fc30",
+		"6A5B4D5C3E2F1A0B9C8D7E6F5A4B3C2D1E0F9A8B",
+		"0123456789ABCDEF0123456789ABCDEF01234567",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"validpgpkeys 6A5B4D5C3E2F1A0B9C8D7E6F5A4B3C2D1E0F9A8B expires on 2024-01-11",
+		"validpgpkeys 0123456789ABCDEF0123456789ABCDEF01234567 is revoked",
+	}
+	if len(warnings) != len(expected) {
+		t.Fatalf("Expecting %q, but got %q", expected, warnings)
+	}
+	for i := range expected {
+		if warnings[i] != expected[i] {
+			t.Errorf("Expecting %q, but got %q", expected[i], warnings[i])
+		}
+	}
+}
+
+func TestCheckKeysError(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://keyserver.ubuntu.com").
+		Get("/pks/lookup").
+		MatchParam("search", "0xA2FF3A36AAA56654109064AB19802F8B0D70FC30").
+		Reply(404)
+	gock.New("https://keyserver.ubuntu.com").
+		Get("/pks/lookup").
+		MatchParam("search", "0x0123456789ABCDEF0123456789ABCDEF01234567").
+		Reply(200).
+		BodyString("pub:0123456789ABCDEF0123456789ABCDEF01234567:1:4096:1500000000::r\n")
+
+	warnings, err := CheckKeys([]string{"A2FF3A36AAA56654109064AB19802F8B0D70FC30", "0123456789ABCDEF0123456789ABCDEF01234567"})
+	if err == nil {
+		t.Error("Expecting an error for the unknown key")
+	}
+	if len(warnings) != 1 || warnings[0] != "validpgpkeys 0123456789ABCDEF0123456789ABCDEF01234567 is revoked" {
+		t.Errorf("Expecting the revoked key to be checked after the unknown one, but got %q", warnings)
+	}
+}
+
+func TestLookupKeyNotFound(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://keyserver.ubuntu.com").
+		Get("/pks/lookup").
+		Reply(404)
+	if _, err := LookupKey("A2FF3A36AAA56654109064AB19802F8B0D70FC30"); err == nil {
+		t.Error("Expecting an error for an unknown key")
+	}
+}
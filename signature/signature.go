@@ -0,0 +1,146 @@
+// Package signature verifies PGP signatures of upstream releases against validpgpkeys
+package signature
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/simon04/aur-out-of-date/logging"
+	"github.com/simon04/aur-out-of-date/transport"
+)
+
+// ErrNoSignature is returned if the package does not have validpgpkeys and signature sources
+var ErrNoSignature = errors.New("No signature to verify")
+
+// signatureExtensions lists the file extensions of detached signatures
+var signatureExtensions = []string{".sig", ".asc", ".sign"}
+
+// signedSource is a detached signature and the signed file
+type signedSource struct {
+	signature string
+	file      string
+}
+
+// Verify downloads the signed sources (of the new version) and verifies them using gpg against the validpgpkeys
+func Verify(sources []string, keys []string) error {
+	signed := signedSources(sources)
+	if len(keys) == 0 || len(signed) == 0 {
+		return ErrNoSignature
+	}
+	for _, s := range signed {
+		if err := verify(s, keys); err != nil {
+			return fmt.Errorf("Failed to verify signature %s: %w", s.signature, err)
+		}
+		logging.Infof("Verified signature %s", s.signature)
+	}
+	return nil
+}
+
+// signedSources returns the HTTP sources having a detached signature
+func signedSources(sources []string) []signedSource {
+	var r []signedSource
+	for _, source := range sources {
+		if i := strings.Index(source, "::"); i >= 0 {
+			source = source[i+2:]
+		}
+		if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+			continue
+		}
+		for _, ext := range signatureExtensions {
+			if strings.HasSuffix(source, ext) {
+				r = append(r, signedSource{source, strings.TrimSuffix(source, ext)})
+			}
+		}
+	}
+	return r
+}
+
+func verify(s signedSource, keys []string) error {
+	signature, err := download(s.signature)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(signature)
+	file, err := download(s.file)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file)
+	cmd := exec.Command("gpg", "--batch", "--status-fd", "1", "--verify", signature, file)
+	output, err := cmd.Output()
+	if _, ok := err.(*exec.ExitError); err != nil && !ok {
+		return err
+	}
+	return checkStatus(string(output), keys)
+}
+
+// fullFingerprint matches a full (40 hex digits) fingerprint, short key IDs are not accepted in validpgpkeys
+var fullFingerprint = regexp.MustCompile(`^[0-9A-F]{40}$`)
+
+// checkStatus checks the gpg --status-fd output for valid signatures by the keys, which are required to equal
+// the full fingerprint like makepkg does; any bad, expired or revoked signature fails the check
+func checkStatus(output string, keys []string) error {
+	valid := map[string]bool{}
+	for _, key := range keys {
+		if key = strings.ToUpper(strings.Replace(key, " ", "", -1)); fullFingerprint.MatchString(key) {
+			valid[key] = true
+		}
+	}
+	var validsig string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[0] != "[GNUPG:]" {
+			continue
+		}
+		switch fields[1] {
+		case "BADSIG":
+			return fmt.Errorf("Bad signature by %s", fields[2])
+		case "EXPKEYSIG":
+			return fmt.Errorf("Signature by expired key %s", fields[2])
+		case "REVKEYSIG":
+			return fmt.Errorf("Signature by revoked key %s", fields[2])
+		case "NO_PUBKEY":
+			return fmt.Errorf("Public key %s not found, import it using gpg --recv-keys %s", fields[2], fields[2])
+		case "VALIDSIG":
+			fingerprint, primary := fields[2], fields[len(fields)-1]
+			if !valid[fingerprint] && !valid[primary] {
+				return fmt.Errorf("Signature by %s, which is not listed in validpgpkeys", primary)
+			}
+			validsig = primary
+		}
+	}
+	if validsig == "" {
+		return fmt.Errorf("No valid signature found")
+	}
+	return nil
+}
+
+// download writes the URL to a temporary file
+func download(url string) (string, error) {
+	logging.Infof("Downloading %s", url)
+	resp, err := transport.GetUncached(url)
+	if err != nil {
+		return "", fmt.Errorf("Failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Failed to download %s: %s", url, resp.Status)
+	}
+	f, err := ioutil.TempFile("", "aur-out-of-date-")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("Failed to download %s: %w", url, err)
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,63 @@
+package signature
+
+import (
+	"testing"
+)
+
+func TestSignedSources(t *testing.T) {
+	sources := []string{
+		"https://example.com/foo-1.1.tar.gz",
+		"https://example.com/foo-1.1.tar.gz.sig",
+		"bar-1.1.tar.xz::https://example.com/bar-1.1.tar.xz.asc",
+		"foo.service",
+		"git+https://example.com/foo.git",
+	}
+	signed := signedSources(sources)
+	expected := []signedSource{
+		{"https://example.com/foo-1.1.tar.gz.sig", "https://example.com/foo-1.1.tar.gz"},
+		{"https://example.com/bar-1.1.tar.xz.asc", "https://example.com/bar-1.1.tar.xz"},
+	}
+	if len(signed) != len(expected) {
+		t.Fatalf("Expecting %v, but got %v", expected, signed)
+	}
+	for i := range expected {
+		if signed[i] != expected[i] {
+			t.Errorf("Expecting %v, but got %v", expected[i], signed[i])
+		}
+	}
+}
+
+func TestVerifyNoSignature(t *testing.T) {
+	if err := Verify([]string{"https://example.com/foo-1.1.tar.gz"}, []string{"ABCDEF"}); err != ErrNoSignature {
+		t.Errorf("Expecting ErrNoSignature, but got %v", err)
+	}
+}
+
+func TestCheckStatus(t *testing.T) {
+	const fingerprint = "A2FF3A36AAA56654109064AB19802F8B0D70FC30"
+	valid := "[GNUPG:] NEWSIG\n" +
+		"[GNUPG:] GOODSIG 19802F8B0D70FC30 Jane Doe <jane@example.com>\n" +
+		"[GNUPG:] VALIDSIG 6A5B4D5C3E2F1A0B9C8D7E6F5A4B3C2D1E0F9A8B 2023-01-01 1672531200 0 4 0 1 10 00 " + fingerprint + "\n"
+	for _, test := range []struct {
+		output string
+		keys   []string
+		valid  bool
+	}{
+		{valid, []string{fingerprint}, true},
+		{valid, []string{"a2ff 3a36 aaa5 6654 1090  64ab 1980 2f8b 0d70 fc30"}, true},
+		{valid, []string{"0000000000000000000000000000000000000000"}, false},
+		{valid, []string{"19802F8B0D70FC30"}, false},
+		{valid, []string{""}, false},
+		{valid + "[GNUPG:] NEWSIG\n[GNUPG:] BADSIG 19802F8B0D70FC30 Jane Doe\n", []string{fingerprint}, false},
+		{valid + "[GNUPG:] NEWSIG\n[GNUPG:] VALIDSIG 0000000000000000000000000000000000000000 2023-01-01 1672531200 0 4 0 1 10 00 0000000000000000000000000000000000000000\n", []string{fingerprint}, false},
+		{"[GNUPG:] EXPKEYSIG 19802F8B0D70FC30 Jane Doe\n", []string{fingerprint}, false},
+		{"[GNUPG:] REVKEYSIG 19802F8B0D70FC30 Jane Doe\n", []string{fingerprint}, false},
+		{"[GNUPG:] BADSIG 19802F8B0D70FC30 Jane Doe\n", []string{fingerprint}, false},
+		{"[GNUPG:] ERRSIG 19802F8B0D70FC30 1 10 00 1672531200 9 -\n[GNUPG:] NO_PUBKEY 19802F8B0D70FC30\n", []string{fingerprint}, false},
+		{"", []string{fingerprint}, false},
+	} {
+		if err := checkStatus(test.output, test.keys); (err == nil) != test.valid {
+			t.Errorf("Expecting valid=%v for %q and %v, but got %v", test.valid, test.output, test.keys, err)
+		}
+	}
+}
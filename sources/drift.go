@@ -0,0 +1,95 @@
+package sources
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+
+	"github.com/simon04/aur-out-of-date/logging"
+	"github.com/simon04/aur-out-of-date/transport"
+)
+
+// algorithms lists the checksum arrays, strongest first; b2sums are computed using b2sum from coreutils
+var algorithms = []struct {
+	name    string
+	newHash func() hash.Hash
+}{
+	{"sha512sums", sha512.New},
+	{"sha384sums", sha512.New384},
+	{"sha256sums", sha256.New},
+	{"b2sums", nil},
+	{"sha224sums", sha256.New224},
+	{"sha1sums", sha1.New},
+	{"md5sums", md5.New},
+}
+
+// Drift is a source whose checksum differs from the PKGBUILD, i.e. upstream re-tagged or regenerated it
+type Drift struct {
+	URL      string
+	Name     string
+	Expected string
+	Actual   string
+}
+
+// FindDrift downloads the HTTP sources, with $pkgver substituted, and compares them against the strongest checksum array,
+// returning the sources whose checksum differs
+func FindDrift(sources []string, checksums map[string][]string, pkgver string) []Drift {
+	var drift []Drift
+	for _, algorithm := range algorithms {
+		sums := checksums[algorithm.name]
+		if len(sums) != len(sources) {
+			continue
+		}
+		for i, source := range sources {
+			urls := httpSources([]string{source}, pkgver)
+			if len(urls) == 0 || sums[i] == "SKIP" {
+				continue
+			}
+			sum, err := download(urls[0], algorithm.newHash)
+			if err != nil {
+				logging.Log(logging.Info, "Failed to compute checksum", "url", urls[0], "err", err)
+				continue
+			}
+			if !strings.EqualFold(sum, sums[i]) {
+				drift = append(drift, Drift{URL: urls[0], Name: algorithm.name, Expected: sums[i], Actual: sum})
+			}
+		}
+		return drift
+	}
+	return nil
+}
+
+// download computes the checksum of the URL using newHash, using b2sum if nil
+func download(url string, newHash func() hash.Hash) (string, error) {
+	logging.Infof("Downloading %s", url)
+	resp, err := transport.GetUncached(url)
+	if err != nil {
+		return "", fmt.Errorf("Failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Failed to download %s: %s", url, resp.Status)
+	}
+	if newHash == nil {
+		cmd := exec.Command("b2sum")
+		cmd.Stdin = resp.Body
+		output, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("Failed to compute b2sum of %s: %w", url, err)
+		}
+		return strings.Fields(string(output))[0], nil
+	}
+	h := newHash()
+	if _, err := io.Copy(h, resp.Body); err != nil {
+		return "", fmt.Errorf("Failed to download %s: %w", url, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,37 @@
+package sources
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFindDrift(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("foo"))
+	}))
+	defer server.Close()
+
+	const fooSha256 = "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+	sources := []string{
+		server.URL + "/foo-$pkgver.tar.gz",
+		server.URL + "/bar-1.0.tar.gz",
+		server.URL + "/baz-1.0.tar.gz",
+		"foo.service",
+	}
+	checksums := map[string][]string{
+		"md5sums":    {"0", "0", "0", "0"},
+		"sha256sums": {fooSha256, "0000", "SKIP", "1111"},
+	}
+	drift := FindDrift(sources, checksums, "1.0")
+	expected := []Drift{{server.URL + "/bar-1.0.tar.gz", "sha256sums", "0000", fooSha256}}
+	if len(drift) != len(expected) || drift[0] != expected[0] {
+		t.Errorf("Expecting %v, but got %v", expected, drift)
+	}
+}
+
+func TestFindDriftWithoutChecksums(t *testing.T) {
+	if drift := FindDrift([]string{"https://example.com/foo.tar.gz"}, map[string][]string{"sha256sums": {}}, "1.0"); drift != nil {
+		t.Errorf("Expecting no drift, but got %v", drift)
+	}
+}
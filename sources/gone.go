@@ -0,0 +1,48 @@
+// Package sources checks the current sources of packages for changes upstream
+package sources
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/simon04/aur-out-of-date/logging"
+)
+
+// Gone is a source which upstream deleted
+type Gone struct {
+	URL    string
+	Status string
+}
+
+// FindGone issues HEAD requests for the HTTP sources, with $pkgver substituted, and returns those responding 404 or 410
+func FindGone(sources []string, pkgver string) []Gone {
+	var gone []Gone
+	for _, url := range httpSources(sources, pkgver) {
+		resp, err := http.DefaultClient.Head(url)
+		if err != nil {
+			logging.Log(logging.Info, "Failed to check source", "url", url, "err", err)
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+			gone = append(gone, Gone{URL: url, Status: resp.Status})
+		}
+	}
+	return gone
+}
+
+// httpSources returns the HTTP(S) URLs of the sources, without filename prefix and with $pkgver substituted
+func httpSources(sources []string, pkgver string) []string {
+	var urls []string
+	for _, source := range sources {
+		if i := strings.Index(source, "::"); i >= 0 {
+			source = source[i+2:]
+		}
+		if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+			continue
+		}
+		source = strings.NewReplacer("${pkgver}", pkgver, "$pkgver", pkgver).Replace(source)
+		urls = append(urls, source)
+	}
+	return urls
+}
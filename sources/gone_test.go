@@ -0,0 +1,45 @@
+package sources
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFindGone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("Expecting HEAD request, but got %s", r.Method)
+		}
+		switch r.URL.Path {
+		case "/foo-1.0.tar.gz":
+			w.WriteHeader(http.StatusOK)
+		case "/bar-1.0.tar.gz":
+			w.WriteHeader(http.StatusGone)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	sources := []string{
+		server.URL + "/foo-$pkgver.tar.gz",
+		"bar.tar.gz::" + server.URL + "/bar-${pkgver}.tar.gz",
+		server.URL + "/baz-1.0.tar.gz",
+		"foo.service",
+		"git+https://example.com/foo.git",
+	}
+	gone := FindGone(sources, "1.0")
+	expected := []Gone{
+		{server.URL + "/bar-1.0.tar.gz", "410 Gone"},
+		{server.URL + "/baz-1.0.tar.gz", "404 Not Found"},
+	}
+	if len(gone) != len(expected) {
+		t.Fatalf("Expecting %v, but got %v", expected, gone)
+	}
+	for i := range expected {
+		if gone[i] != expected[i] {
+			t.Errorf("Expecting %v, but got %v", expected[i], gone[i])
+		}
+	}
+}
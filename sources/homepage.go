@@ -0,0 +1,65 @@
+package sources
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/simon04/aur-out-of-date/logging"
+)
+
+// maxHomepageRedirects is the number of permanent redirects followed to determine where the homepage moved to
+const maxHomepageRedirects = 3
+
+// CheckHomepage issues a HEAD request for the homepage (url=) and describes a permanent redirect ("homepage moved to …")
+// or a dead page ("homepage returns 404 Not Found"), "" if the homepage is fine or cannot be checked
+func CheckHomepage(homepage string) string {
+	if !strings.HasPrefix(homepage, "http://") && !strings.HasPrefix(homepage, "https://") {
+		return ""
+	}
+	client := *http.DefaultClient
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	location := homepage
+	for i := 0; i <= maxHomepageRedirects; i++ {
+		resp, err := client.Head(location)
+		if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusForbidden) {
+			// servers not supporting HEAD
+			resp.Body.Close()
+			resp, err = client.Get(location)
+		}
+		if err != nil {
+			logging.Log(logging.Info, "Failed to check homepage", "url", location, "err", err)
+			return ""
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+			if location != homepage {
+				return fmt.Sprintf("homepage moved to %s which returns %s", location, resp.Status)
+			}
+			return fmt.Sprintf("homepage returns %s", resp.Status)
+		}
+		target, err := resp.Location()
+		if (resp.StatusCode != http.StatusMovedPermanently && resp.StatusCode != http.StatusPermanentRedirect) || err != nil {
+			break
+		}
+		location = target.String()
+	}
+	if sameLocation(homepage, location) {
+		return ""
+	}
+	return fmt.Sprintf("homepage moved to %s", location)
+}
+
+// sameLocation reports whether the URLs only differ by a trailing slash, as added by many servers for directories
+func sameLocation(a, b string) bool {
+	ua, errA := url.Parse(a)
+	ub, errB := url.Parse(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	ua.Path, ub.Path = strings.TrimSuffix(ua.Path, "/"), strings.TrimSuffix(ub.Path, "/")
+	return ua.String() == ub.String()
+}
@@ -0,0 +1,53 @@
+package sources
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckHomepage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok", "/new/":
+			w.WriteHeader(http.StatusOK)
+		case "/docs":
+			http.Redirect(w, r, "/docs/", http.StatusMovedPermanently)
+		case "/docs/":
+			w.WriteHeader(http.StatusOK)
+		case "/old":
+			http.Redirect(w, r, "/older", http.StatusMovedPermanently)
+		case "/older":
+			http.Redirect(w, r, "/new/", http.StatusPermanentRedirect)
+		case "/temporary":
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+		case "/moved-dead":
+			http.Redirect(w, r, "/dead", http.StatusMovedPermanently)
+		case "/head":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := map[string]string{
+		"/ok":         "",
+		"/docs":       "",
+		"/temporary":  "",
+		"/head":       "",
+		"/old":        "homepage moved to " + server.URL + "/new/",
+		"/dead":       "homepage returns 404 Not Found",
+		"/moved-dead": "homepage moved to " + server.URL + "/dead which returns 404 Not Found",
+	}
+	for path, expected := range tests {
+		if warning := CheckHomepage(server.URL + path); warning != expected {
+			t.Errorf("Expecting %q for %s, but got %q", expected, path, warning)
+		}
+	}
+	if warning := CheckHomepage(""); warning != "" {
+		t.Errorf("Expecting no warning without homepage, but got %q", warning)
+	}
+}
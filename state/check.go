@@ -0,0 +1,15 @@
+package state
+
+import (
+	"time"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+// Check records a completed run, e.g. for monitoring that checks run by a systemd timer succeed
+type Check struct {
+	Finished   time.Time          `json:"finished"`
+	Statistics *status.Statistics `json:"statistics,omitempty"`
+	Errors     int                `json:"errors"`
+	Aborted    bool               `json:"aborted,omitempty"`
+}
@@ -0,0 +1,105 @@
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+// Checkpoint records the status of each package as soon as it has been handled, so that an interrupted run can be resumed.
+// The statuses are appended as JSON lines, as rewriting the whole file per package would be slow for large package sets.
+type Checkpoint struct {
+	mutex    sync.Mutex
+	filename string
+	file     *os.File
+	done     map[string]status.Status
+}
+
+// OpenCheckpoint starts recording to filename, keeping the statuses recorded by the previous (interrupted) run if resume is set
+func OpenCheckpoint(filename string, resume bool) (*Checkpoint, error) {
+	c := &Checkpoint{filename: filename, done: map[string]status.Status{}}
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	partial := false
+	if resume {
+		var err error
+		if partial, err = c.load(); err != nil {
+			return nil, err
+		}
+	} else {
+		flags |= os.O_TRUNC
+	}
+	if err := os.MkdirAll(path.Dir(filename), 0755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filename, flags, 0644)
+	if err != nil {
+		return nil, err
+	}
+	c.file = file
+	if partial {
+		// terminate the incomplete line so that it does not corrupt the next status
+		if _, err := file.Write([]byte{'\n'}); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// load reads the recorded statuses, ignoring an incomplete last line written during a crash (reported as partial)
+func (c *Checkpoint) load() (partial bool, err error) {
+	input, err := ioutil.ReadFile(c.filename)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	for _, line := range bytes.Split(input, []byte{'\n'}) {
+		var s status.Status
+		if err := json.Unmarshal(line, &s); err == nil && s.Package != "" {
+			c.done[s.Package] = s
+		}
+	}
+	return len(input) > 0 && input[len(input)-1] != '\n', nil
+}
+
+// Lookup returns the status recorded for the package in the given version by the resumed run, not by the current one
+func (c *Checkpoint) Lookup(name, version string) (status.Status, bool) {
+	if c == nil {
+		return status.Status{}, false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	s, ok := c.done[name]
+	return s, ok && s.Version == version
+}
+
+// Add records the status of a handled package
+func (c *Checkpoint) Add(s *status.Status) error {
+	if c == nil {
+		return nil
+	}
+	line, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	_, err = c.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close stops recording, removing the checkpoint if the run completed
+func (c *Checkpoint) Close(completed bool) error {
+	if c == nil {
+		return nil
+	}
+	if err := c.file.Close(); err != nil || !completed {
+		return err
+	}
+	return os.Remove(c.filename)
+}
@@ -0,0 +1,67 @@
+package state
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestCheckpoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := path.Join(dir, "checkpoint.ndjson")
+
+	c, err := OpenCheckpoint(filename, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Add(&status.Status{Package: "foo", Version: "1.0-1", Status: status.OutOfDate, Upstream: "1.1"})
+	c.Add(&status.Status{Package: "bar", Version: "2.0-1", Status: status.UpToDate})
+	c.Close(false)
+	// simulate a crash while writing
+	f, _ := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND, 0644)
+	f.WriteString(`{"name":"baz","vers`)
+	f.Close()
+
+	c, err = OpenCheckpoint(filename, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := c.Lookup("foo", "1.0-1"); !ok || s.Status != status.OutOfDate || s.Upstream != "1.1" {
+		t.Errorf("Expecting foo to be resumed as OUT-OF-DATE, but got %v", s)
+	}
+	if _, ok := c.Lookup("bar", "2.0-2"); ok {
+		t.Error("Expecting bar to be checked again for a new version")
+	}
+	if _, ok := c.Lookup("baz", ""); ok {
+		t.Error("Expecting the incomplete baz not to be resumed")
+	}
+	c.Add(&status.Status{Package: "baz", Version: "3.0-1", Status: status.UpToDate})
+	c.Close(false)
+	c, _ = OpenCheckpoint(filename, true)
+	if _, ok := c.Lookup("baz", "3.0-1"); !ok {
+		t.Error("Expecting baz to be resumed after the incomplete line")
+	}
+	if err := c.Close(true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filename); !os.IsNotExist(err) {
+		t.Errorf("Expecting the checkpoint to be removed after completion, but got %v", err)
+	}
+
+	c, _ = OpenCheckpoint(filename, false)
+	if _, ok := c.Lookup("foo", "1.0-1"); ok {
+		t.Error("Expecting a new run not to resume")
+	}
+	c.Close(true)
+	var nilCheckpoint *Checkpoint
+	if _, ok := nilCheckpoint.Lookup("foo", "1.0-1"); ok || nilCheckpoint.Add(&status.Status{}) != nil {
+		t.Error("Expecting a nil checkpoint to be disabled")
+	}
+}
@@ -0,0 +1,164 @@
+package state
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/logging"
+	"github.com/simon04/aur-out-of-date/status"
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+// Observation is a packaged and upstream version observed at a given time
+type Observation struct {
+	Time     time.Time         `json:"time"`
+	Package  string            `json:"name"`
+	Version  string            `json:"version"`
+	Upstream upstream.Version  `json:"upstream"`
+	Status   status.StatusType `json:"status"`
+	// Released is the upstream release date, if known
+	Released *time.Time `json:"released,omitempty"`
+}
+
+// ReadHistory reads all observations from the JSON Lines file in chronological order, none if it does not exist
+func ReadHistory(filename string) ([]Observation, error) {
+	f, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var observations []Observation
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var o Observation
+		if err := json.Unmarshal(scanner.Bytes(), &o); err != nil {
+			return nil, fmt.Errorf("Failed to parse %s:%d: %w", filename, line, err)
+		}
+		observations = append(observations, o)
+	}
+	return observations, scanner.Err()
+}
+
+// historyFormatter appends an observation per package whose packaged or upstream version changed
+type historyFormatter struct {
+	filename     string
+	maxAge       time.Duration
+	observations []Observation
+	latest       map[string]Observation
+	pending      []Observation
+}
+
+// RecordHistory returns a Formatter appending the observations of each run to the JSON Lines file.
+// A package is only recorded if its packaged or upstream version differs from its latest observation, failed checks are skipped.
+// Observations older than maxAge (unless 0) are dropped, except for the latest one of each package.
+func RecordHistory(filename string, maxAge time.Duration) status.Formatter {
+	h := &historyFormatter{filename: filename, maxAge: maxAge, latest: map[string]Observation{}}
+	observations, err := ReadHistory(filename)
+	if err != nil {
+		logging.Warnf("Failed to read history: %v", err)
+	}
+	for _, o := range observations {
+		h.latest[o.Package] = o
+	}
+	h.observations = observations
+	return h
+}
+
+func (h *historyFormatter) Status(s *status.Status) {
+	if s.Upstream == "" {
+		return
+	}
+	if latest, ok := h.latest[s.Package]; ok && latest.Version == s.Version && latest.Upstream == s.Upstream {
+		return
+	}
+	o := Observation{Time: s.CheckedAt, Package: s.Package, Version: s.Version, Upstream: s.Upstream, Status: s.Status, Released: s.Released}
+	if o.Time.IsZero() {
+		o.Time = time.Now()
+	}
+	h.latest[s.Package] = o
+	h.pending = append(h.pending, o)
+}
+
+func (h *historyFormatter) Finish(statistics *status.Statistics) {
+	if len(h.pending) == 0 {
+		return
+	}
+	observations := append(h.observations, h.pending...)
+	kept := observations
+	if h.maxAge > 0 {
+		kept = pruneHistory(observations, time.Now().Add(-h.maxAge))
+	}
+	var err error
+	if len(kept) < len(observations) {
+		err = h.rewrite(kept)
+	} else {
+		err = h.append()
+	}
+	if err != nil {
+		logging.Errorf("Failed to save history to %s: %v", h.filename, err)
+	}
+	h.observations, h.pending = kept, nil
+}
+
+// pruneHistory returns the observations made since cutoff along with the latest one of each package
+func pruneHistory(observations []Observation, cutoff time.Time) []Observation {
+	latest := map[string]int{}
+	for i, o := range observations {
+		latest[o.Package] = i
+	}
+	var kept []Observation
+	for i, o := range observations {
+		if !o.Time.Before(cutoff) || latest[o.Package] == i {
+			kept = append(kept, o)
+		}
+	}
+	return kept
+}
+
+// rewrite replaces the file by the given observations
+func (h *historyFormatter) rewrite(observations []Observation) error {
+	tmp := h.filename + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for _, o := range observations {
+		if err := enc.Encode(o); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, h.filename)
+}
+
+func (h *historyFormatter) append() error {
+	if err := os.MkdirAll(path.Dir(h.filename), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(h.filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for _, o := range h.pending {
+		if err := enc.Encode(o); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
@@ -0,0 +1,72 @@
+package state
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestRecordHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aur-out-of-date")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := path.Join(dir, "history.jsonl")
+
+	run := func(statuses ...status.Status) {
+		f := RecordHistory(filename, 0)
+		for i := range statuses {
+			f.Status(&statuses[i])
+		}
+		f.Finish(nil)
+	}
+	run(status.Status{Package: "foo", Version: "1.0-1", Upstream: "1.0", Status: status.UpToDate},
+		status.Status{Package: "bar", Version: "1.0-1", Status: status.Unknown})
+	run(status.Status{Package: "foo", Version: "1.0-1", Upstream: "1.0", Status: status.UpToDate})
+	run(status.Status{Package: "foo", Version: "1.0-1", Upstream: "1.1", Status: status.OutOfDate})
+	run(status.Status{Package: "foo", Version: "1.1-1", Upstream: "1.1", Status: status.UpToDate})
+
+	observations, err := ReadHistory(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(observations) != 3 {
+		t.Fatalf("Expecting 3 observations, but got %v", observations)
+	}
+	if o := observations[1]; o.Package != "foo" || o.Version != "1.0-1" || o.Upstream != "1.1" || o.Status != status.OutOfDate || o.Time.IsZero() {
+		t.Errorf("Expecting foo 1.0-1 to be out-of-date by 1.1, but got %v", o)
+	}
+}
+
+func TestRecordHistoryMaxAge(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aur-out-of-date")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := path.Join(dir, "history.jsonl")
+
+	run := func(s status.Status) {
+		f := RecordHistory(filename, 365*24*time.Hour)
+		f.Status(&s)
+		f.Finish(nil)
+	}
+	now := time.Now()
+	run(status.Status{Package: "foo", Version: "1.0-1", Upstream: "1.0", Status: status.UpToDate, CheckedAt: now.AddDate(-3, 0, 0)})
+	run(status.Status{Package: "bar", Version: "1.0-1", Upstream: "1.0", Status: status.UpToDate, CheckedAt: now.AddDate(-2, 0, 0)})
+	run(status.Status{Package: "foo", Version: "1.0-1", Upstream: "1.1", Status: status.OutOfDate, CheckedAt: now.AddDate(-2, 0, 0)})
+	run(status.Status{Package: "foo", Version: "1.1-1", Upstream: "1.1", Status: status.UpToDate, CheckedAt: now})
+
+	observations, err := ReadHistory(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(observations) != 2 || observations[0].Package != "bar" || observations[1].Version != "1.1-1" {
+		t.Errorf("Expecting the latest observations of bar and foo, but got %v", observations)
+	}
+}
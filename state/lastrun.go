@@ -0,0 +1,71 @@
+package state
+
+import (
+	"github.com/simon04/aur-out-of-date/logging"
+	"github.com/simon04/aur-out-of-date/status"
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+// LastRun holds the package statuses of the previous runs
+type LastRun struct {
+	Packages map[string]Entry `json:"packages"`
+}
+
+// Entry holds the status of a package
+type Entry struct {
+	Version  string            `json:"version"`
+	Upstream upstream.Version  `json:"upstream,omitempty"`
+	Status   status.StatusType `json:"status"`
+}
+
+func entryOf(s *status.Status) Entry {
+	return Entry{s.Version, s.Upstream, s.Status}
+}
+
+// lastRunFormatter records all statuses and optionally omits unchanged ones
+type lastRunFormatter struct {
+	status.Formatter
+	filename    string
+	changedOnly bool
+	previous    LastRun
+	current     LastRun
+}
+
+// RecordLastRun returns a Formatter persisting the statuses of this run to filename.
+// If changedOnly is set, only packages whose status changed since the last run are passed to f.
+func RecordLastRun(f status.Formatter, filename string, changedOnly bool) status.Formatter {
+	r := &lastRunFormatter{
+		Formatter:   f,
+		filename:    filename,
+		changedOnly: changedOnly,
+	}
+	if err := Load(filename, &r.previous); err != nil {
+		logging.Warnf("Failed to read last run from %s: %v", filename, err)
+	}
+	r.current.Packages = map[string]Entry{}
+	for name, entry := range r.previous.Packages {
+		r.current.Packages[name] = entry
+	}
+	return r
+}
+
+// Changed determines whether the package status differs from the last run
+func (r *lastRunFormatter) Changed(s *status.Status) bool {
+	previous, ok := r.previous.Packages[s.Package]
+	return !ok || previous != entryOf(s)
+}
+
+func (r *lastRunFormatter) Status(s *status.Status) {
+	changed := r.Changed(s)
+	r.current.Packages[s.Package] = entryOf(s)
+	if changed || !r.changedOnly {
+		r.Formatter.Status(s)
+	}
+}
+
+func (r *lastRunFormatter) Finish(statistics *status.Statistics) {
+	r.Formatter.Finish(statistics)
+	if err := Save(r.filename, r.current); err != nil {
+		logging.Errorf("Failed to save last run to %s: %v", r.filename, err)
+	}
+}
@@ -0,0 +1,52 @@
+package state
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestRecordLastRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aur-out-of-date")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := path.Join(dir, "last-run.json")
+
+	run := func(statuses ...status.Status) string {
+		out := bytes.NewBuffer(nil)
+		f, _ := status.NewFormatterWriter("csv", out)
+		f = RecordLastRun(f, filename, true)
+		for i := range statuses {
+			f.Status(&statuses[i])
+		}
+		f.Finish(nil)
+		return out.String()
+	}
+
+	actual := run(
+		status.Status{Package: "foo", Version: "1.0-1", Upstream: "1.1", Status: status.OutOfDate},
+		status.Status{Package: "bar", Version: "1.0-1", Upstream: "1.0", Status: status.UpToDate})
+	if !strings.Contains(actual, "foo") || !strings.Contains(actual, "bar") {
+		t.Errorf("Expecting all packages on first run, but got '%s'", actual)
+	}
+
+	actual = run(
+		status.Status{Package: "foo", Version: "1.0-1", Upstream: "1.2", Status: status.OutOfDate},
+		status.Status{Package: "bar", Version: "1.0-1", Upstream: "1.0", Status: status.UpToDate})
+	if !strings.Contains(actual, "foo") || strings.Contains(actual, "bar") {
+		t.Errorf("Expecting only foo (bumped again), but got '%s'", actual)
+	}
+
+	actual = run(status.Status{Package: "baz", Version: "2.0-1", Upstream: "2.0", Status: status.UpToDate})
+	actual = run(status.Status{Package: "foo", Version: "1.0-1", Upstream: "1.2", Status: status.OutOfDate})
+	if strings.Contains(actual, "foo") {
+		t.Errorf("Expecting foo to be remembered across partial runs, but got '%s'", actual)
+	}
+}
@@ -0,0 +1,87 @@
+package state
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/cache"
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+// CachedResult is an upstream result together with the time it has been obtained
+type CachedResult struct {
+	upstream.Result
+	Fetched time.Time `json:"fetched"`
+	// LastModified is the time of the last AUR update of the package when the result has been obtained
+	LastModified *time.Time `json:"last_modified,omitempty"`
+}
+
+// Unchanged determines whether the AUR package has not been updated since the result has been obtained
+func (r CachedResult) Unchanged(lastModified time.Time) bool {
+	return r.LastModified != nil && !lastModified.IsZero() && r.LastModified.Equal(lastModified)
+}
+
+// ResultCache persists upstream results per package, in a file or in a cache backend
+type ResultCache struct {
+	mutex    sync.Mutex
+	filename string
+	backend  cache.Cache
+	Packages map[string]CachedResult `json:"packages"`
+}
+
+// LoadResultCache reads the cached results from filename
+func LoadResultCache(filename string) (*ResultCache, error) {
+	c := &ResultCache{filename: filename}
+	err := Load(filename, c)
+	if c.Packages == nil {
+		c.Packages = map[string]CachedResult{}
+	}
+	return c, err
+}
+
+// NewResultCache returns a ResultCache storing the results in the backend, e.g. shared by several machines using Redis
+func NewResultCache(backend cache.Cache) *ResultCache {
+	return &ResultCache{backend: backend, Packages: map[string]CachedResult{}}
+}
+
+// Get returns the cached result for the package if it is younger than ttl
+func (c *ResultCache) Get(pkg string, ttl time.Duration) (CachedResult, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	r, ok := c.Packages[pkg]
+	if c.backend != nil {
+		value, found := c.backend.Get(pkg)
+		ok = found && json.Unmarshal(value, &r) == nil
+	}
+	if !ok || (ttl > 0 && time.Since(r.Fetched) > ttl) {
+		return CachedResult{}, false
+	}
+	return r, true
+}
+
+// Put caches the result for the package, lastModified is the time of its last AUR update (zero if unknown)
+func (c *ResultCache) Put(pkg string, result upstream.Result, lastModified time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	r := CachedResult{Result: result, Fetched: time.Now()}
+	if !lastModified.IsZero() {
+		r.LastModified = &lastModified
+	}
+	c.Packages[pkg] = r
+	if c.backend != nil {
+		if value, err := json.Marshal(c.Packages[pkg]); err == nil {
+			c.backend.Set(pkg, value)
+		}
+	}
+}
+
+// Save writes the cached results to the file, the backend is updated by Put already
+func (c *ResultCache) Save() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.backend != nil {
+		return nil
+	}
+	return Save(c.filename, c)
+}
@@ -0,0 +1,47 @@
+package state
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+func TestResultCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aur-out-of-date")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := path.Join(dir, "results.json")
+
+	c, err := LoadResultCache(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modified := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.Put("foo", upstream.Result{Version: "1.1", Provider: "github"}, modified)
+	c.Packages["bar"] = CachedResult{Result: upstream.Result{Version: "2"}, Fetched: time.Now().Add(-2 * time.Hour)}
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = LoadResultCache(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, ok := c.Get("foo", time.Hour); !ok || r.Version != "1.1" || r.Provider != "github" {
+		t.Errorf("Expecting cached foo 1.1, but got %v", r)
+	} else if !r.Unchanged(modified) || r.Unchanged(modified.Add(time.Hour)) || r.Unchanged(time.Time{}) {
+		t.Errorf("Expecting foo to be unchanged since %v only, but got %v", modified, r.LastModified)
+	}
+	if _, ok := c.Get("bar", time.Hour); ok {
+		t.Error("Expecting expired bar")
+	}
+	if _, ok := c.Get("baz", time.Hour); ok {
+		t.Error("Expecting no baz")
+	}
+}
@@ -0,0 +1,121 @@
+package state
+
+import (
+	"sort"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/status"
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+// Lag is the time between an upstream release and the AUR catching up with it (or a newer version)
+type Lag struct {
+	Package  string
+	Upstream upstream.Version
+	// Released is the upstream release date, the time the version has first been observed if unknown
+	Released time.Time
+	// Updated is the time the AUR package has first been observed up-to-date, zero if still pending
+	Updated time.Time
+}
+
+// Duration returns the lag, measured until now if still pending
+func (l Lag) Duration(now time.Time) time.Duration {
+	end := l.Updated
+	if end.IsZero() {
+		end = now
+	}
+	if d := end.Sub(l.Released); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// Lags determines the lag of all upstream versions observed in the chronological history.
+// Versions already packaged when a package was first observed are skipped since the time of the AUR update is unknown.
+func Lags(observations []Observation) []Lag {
+	var lags []Lag
+	pending := map[string][]int{}
+	seen := map[string]map[upstream.Version]bool{}
+	for _, o := range observations {
+		if seen[o.Package] == nil {
+			seen[o.Package] = map[upstream.Version]bool{}
+			if o.Status == status.UpToDate {
+				seen[o.Package][o.Upstream] = true
+				continue
+			}
+		}
+		if !seen[o.Package][o.Upstream] {
+			seen[o.Package][o.Upstream] = true
+			released := o.Time
+			if o.Released != nil {
+				released = *o.Released
+			}
+			lags = append(lags, Lag{Package: o.Package, Upstream: o.Upstream, Released: released})
+			pending[o.Package] = append(pending[o.Package], len(lags)-1)
+		}
+		if o.Status == status.UpToDate {
+			for _, i := range pending[o.Package] {
+				lags[i].Updated = o.Time
+			}
+			pending[o.Package] = nil
+		}
+	}
+	return lags
+}
+
+// LagStats summarizes the lags of a package, or of all packages if Package is empty
+type LagStats struct {
+	Package  string
+	Releases int
+	Pending  int
+	Mean     time.Duration
+	Median   time.Duration
+}
+
+// Staleness computes the mean and median lag per package (sorted by name) and overall, pending versions count until now
+func Staleness(lags []Lag, now time.Time) ([]LagStats, LagStats) {
+	durations := map[string][]time.Duration{}
+	var all []time.Duration
+	stats := map[string]*LagStats{}
+	overall := LagStats{}
+	for _, l := range lags {
+		s, ok := stats[l.Package]
+		if !ok {
+			s = &LagStats{Package: l.Package}
+			stats[l.Package] = s
+		}
+		s.Releases++
+		overall.Releases++
+		if l.Updated.IsZero() {
+			s.Pending++
+			overall.Pending++
+		}
+		d := l.Duration(now)
+		durations[l.Package] = append(durations[l.Package], d)
+		all = append(all, d)
+	}
+	var packages []LagStats
+	for name, s := range stats {
+		s.Mean, s.Median = meanMedian(durations[name])
+		packages = append(packages, *s)
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Package < packages[j].Package })
+	overall.Mean, overall.Median = meanMedian(all)
+	return packages, overall
+}
+
+func meanMedian(d []time.Duration) (time.Duration, time.Duration) {
+	if len(d) == 0 {
+		return 0, 0
+	}
+	sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+	var sum time.Duration
+	for _, duration := range d {
+		sum += duration
+	}
+	median := d[len(d)/2]
+	if len(d)%2 == 0 {
+		median = (d[len(d)/2-1] + d[len(d)/2]) / 2
+	}
+	return sum / time.Duration(len(d)), median
+}
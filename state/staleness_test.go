@@ -0,0 +1,41 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestStaleness(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2021, 3, d, 0, 0, 0, 0, time.UTC) }
+	released := day(2)
+	observations := []Observation{
+		{Time: day(1), Package: "foo", Version: "1.0-1", Upstream: "1.0", Status: status.UpToDate},
+		{Time: day(3), Package: "foo", Version: "1.0-1", Upstream: "1.1", Status: status.OutOfDate, Released: &released},
+		{Time: day(6), Package: "foo", Version: "1.1-1", Upstream: "1.1", Status: status.UpToDate},
+		{Time: day(7), Package: "foo", Version: "1.1-1", Upstream: "1.2", Status: status.OutOfDate},
+		{Time: day(8), Package: "foo", Version: "1.1-1", Upstream: "1.3", Status: status.OutOfDate},
+		{Time: day(9), Package: "foo", Version: "1.3-1", Upstream: "1.3", Status: status.UpToDate},
+		{Time: day(1), Package: "bar", Version: "2.0-1", Upstream: "2.1", Status: status.OutOfDate},
+	}
+	lags := Lags(observations)
+	if len(lags) != 4 {
+		t.Fatalf("Expecting 4 lags, but got %v", lags)
+	}
+	packages, overall := Staleness(lags, day(11))
+	if len(packages) != 2 || packages[0].Package != "bar" || packages[1].Package != "foo" {
+		t.Fatalf("Expecting stats for bar and foo, but got %v", packages)
+	}
+	// foo: 1.1 took 4 days, 1.2 2 days, 1.3 1 day
+	if foo := packages[1]; foo.Releases != 3 || foo.Pending != 0 || foo.Mean != 56*time.Hour || foo.Median != 48*time.Hour {
+		t.Errorf("Unexpected stats for foo: %+v", foo)
+	}
+	// bar: 2.1 pending for 10 days
+	if bar := packages[0]; bar.Releases != 1 || bar.Pending != 1 || bar.Median != 240*time.Hour {
+		t.Errorf("Unexpected stats for bar: %+v", bar)
+	}
+	if overall.Releases != 4 || overall.Pending != 1 || overall.Median != 72*time.Hour {
+		t.Errorf("Unexpected overall stats: %+v", overall)
+	}
+}
@@ -0,0 +1,59 @@
+// Package state persists data between runs of aur-out-of-date
+package state
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+)
+
+// Dir returns the directory used to persist state, i.e. $STATE_DIRECTORY (set by systemd) or $XDG_CACHE_HOME/aur-out-of-date
+func Dir() string {
+	if dir := systemdDirectory("STATE_DIRECTORY"); dir != "" {
+		return dir
+	}
+	return CacheDir()
+}
+
+// CacheDir returns the directory used to cache HTTP responses, i.e. $CACHE_DIRECTORY (set by systemd) or $XDG_CACHE_HOME/aur-out-of-date
+func CacheDir() string {
+	if dir := systemdDirectory("CACHE_DIRECTORY"); dir != "" {
+		return dir
+	}
+	cacheDir, _ := os.UserCacheDir()
+	return path.Join(cacheDir, "aur-out-of-date")
+}
+
+// systemdDirectory returns the first directory of the colon-separated environment variable
+func systemdDirectory(env string) string {
+	return strings.SplitN(os.Getenv(env), ":", 2)[0]
+}
+
+// Load reads the JSON file into v, leaving v untouched if the file does not exist
+func Load(filename string, v interface{}) error {
+	input, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return json.Unmarshal(input, v)
+}
+
+// Save writes v as JSON to the file, creating its directory as needed
+func Save(filename string, v interface{}) error {
+	output, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path.Dir(filename), 0755); err != nil {
+		return err
+	}
+	tmp := filename + ".tmp"
+	if err := ioutil.WriteFile(tmp, output, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
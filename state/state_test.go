@@ -0,0 +1,23 @@
+package state
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDir(t *testing.T) {
+	defer os.Unsetenv("STATE_DIRECTORY")
+	defer os.Unsetenv("CACHE_DIRECTORY")
+	os.Setenv("STATE_DIRECTORY", "/var/lib/aur-out-of-date:/var/lib/other")
+	os.Setenv("CACHE_DIRECTORY", "/var/cache/aur-out-of-date")
+	if dir := Dir(); dir != "/var/lib/aur-out-of-date" {
+		t.Errorf("Expecting /var/lib/aur-out-of-date, but got %s", dir)
+	}
+	if dir := CacheDir(); dir != "/var/cache/aur-out-of-date" {
+		t.Errorf("Expecting /var/cache/aur-out-of-date, but got %s", dir)
+	}
+	os.Unsetenv("STATE_DIRECTORY")
+	if dir := Dir(); dir != "/var/cache/aur-out-of-date" {
+		t.Errorf("Expecting /var/cache/aur-out-of-date, but got %s", dir)
+	}
+}
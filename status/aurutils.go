@@ -0,0 +1,20 @@
+package status
+
+import (
+	"fmt"
+	"io"
+)
+
+// aurutilsFormatter writes "name\tversion" for out-of-date packages, as of "aur repo --list" (aurutils)
+type aurutilsFormatter struct {
+	w io.Writer
+}
+
+func (f *aurutilsFormatter) Status(s *Status) {
+	if s.Status == OutOfDate || s.Status == FlaggedOutOfDate {
+		fmt.Fprintf(f.w, "%s\t%s\n", s.Package, s.Version)
+	}
+}
+
+func (f *aurutilsFormatter) Finish(statistics *Statistics) {
+}
@@ -0,0 +1,23 @@
+package status
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAurutilsFormatter(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	f, err := NewFormatterWriter("aurutils", out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Status(&Status{Package: "foo", Version: "1.0-1", Upstream: "1.1", Status: OutOfDate})
+	f.Status(&Status{Package: "bar", Version: "2.0-1", Upstream: "2.0", Status: UpToDate})
+	f.Status(&Status{Package: "baz", Version: "1:3.0-2", Upstream: "3.1", Status: FlaggedOutOfDate})
+	f.Status(&Status{Package: "qux", Version: "1.0-1", Status: Unknown})
+	f.Finish(nil)
+	expected := "foo\t1.0-1\nbaz\t1:3.0-2\n"
+	if out.String() != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, out.String())
+	}
+}
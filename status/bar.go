@@ -0,0 +1,57 @@
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// barFormatter summarizes the out-of-date packages for status bars such as waybar or i3blocks
+type barFormatter struct {
+	w        io.Writer
+	format   string
+	outdated []*Status
+}
+
+// waybarOutput is the JSON expected by custom waybar modules (return-type json)
+type waybarOutput struct {
+	Text    string `json:"text"`
+	Alt     string `json:"alt"`
+	Tooltip string `json:"tooltip"`
+	Class   string `json:"class"`
+}
+
+func (f *barFormatter) Status(s *Status) {
+	if s.Status == OutOfDate || s.Status == FlaggedOutOfDate {
+		f.outdated = append(f.outdated, s)
+	}
+}
+
+func (f *barFormatter) Finish(statistics *Statistics) {
+	var tooltip []string
+	for _, s := range f.outdated {
+		tooltip = append(tooltip, fmt.Sprintf("%s %s → %s", s.Package, s.Version, s.Upstream.String()))
+	}
+	class := "up-to-date"
+	if len(f.outdated) > 0 {
+		class = "out-of-date"
+	}
+	text := fmt.Sprintf("%d", len(f.outdated))
+	switch f.format {
+	case "i3blocks":
+		// full_text, short_text, color
+		color := "#00FF00"
+		if len(f.outdated) > 0 {
+			color = "#FF0000"
+		}
+		fmt.Fprintf(f.w, "%s AUR packages behind upstream\n%s\n%s\n", text, text, color)
+	default:
+		json.NewEncoder(f.w).Encode(waybarOutput{
+			Text:    text,
+			Alt:     class,
+			Tooltip: strings.Join(tooltip, "\n"),
+			Class:   class,
+		})
+	}
+}
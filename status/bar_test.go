@@ -0,0 +1,30 @@
+package status
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWaybarFormatter(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	f, _ := NewFormatterWriter("waybar", out)
+	f.Status(&Status{Package: "foo", Version: "1.0-1", Upstream: "1.1", Status: OutOfDate})
+	f.Status(&Status{Package: "bar", Version: "1.0-1", Upstream: "1.0", Status: UpToDate})
+	f.Status(&Status{Package: "baz", Version: "2.0-1", Upstream: "v2.1", Status: FlaggedOutOfDate})
+	f.Finish(nil)
+	expected := `{"text":"2","alt":"out-of-date","tooltip":"foo 1.0-1 → 1.1\nbaz 2.0-1 → 2.1","class":"out-of-date"}` + "\n"
+	if out.String() != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, out.String())
+	}
+}
+
+func TestI3blocksFormatter(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	f, _ := NewFormatterWriter("i3blocks", out)
+	f.Status(&Status{Package: "bar", Version: "1.0-1", Upstream: "1.0", Status: UpToDate})
+	f.Finish(nil)
+	expected := "0 AUR packages behind upstream\n0\n#00FF00\n"
+	if out.String() != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, out.String())
+	}
+}
@@ -0,0 +1,91 @@
+package status
+
+import (
+	"bufio"
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// codeQualityFormatter buffers all statuses and writes a GitLab Code Quality report, see
+// https://docs.gitlab.com/ee/ci/testing/code_quality.html#implement-a-custom-tool
+type codeQualityFormatter struct {
+	w      io.Writer
+	issues []codeQualityIssue
+}
+
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines codeQualityLines `json:"lines"`
+}
+
+type codeQualityLines struct {
+	Begin int `json:"begin"`
+}
+
+// codeQualitySeverity returns the severity of the status, "" if there is nothing to report
+func codeQualitySeverity(s *Status) string {
+	switch {
+	case s.Status == BadSignature || s.Status == SourceGone || s.Status == UpstreamGone || s.Status == ChecksumMismatch || s.HighPriority:
+		return "critical"
+	case s.Status == OutOfDate || s.Status == FlaggedOutOfDate:
+		return "major"
+	case s.Error != "":
+		return "info"
+	}
+	return ""
+}
+
+// pkgverLine returns the line number of pkgver= in the PKGBUILD, 1 if not found
+func pkgverLine(path string) int {
+	file, err := os.Open(path)
+	if err != nil {
+		return 1
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.HasPrefix(scanner.Text(), "pkgver=") {
+			return line
+		}
+	}
+	return 1
+}
+
+func (f *codeQualityFormatter) Status(s *Status) {
+	severity := codeQualitySeverity(s)
+	if severity == "" {
+		return
+	}
+	path := s.PKGBUILD
+	if path == "" {
+		path = s.Package + "/PKGBUILD"
+	}
+	f.issues = append(f.issues, codeQualityIssue{
+		Description: fmt.Sprintf("%s %s %s", s.Package, s.Version, s.Message),
+		CheckName:   "aur-out-of-date/" + strings.ToLower(string(s.Status)),
+		Fingerprint: fmt.Sprintf("%x", md5.Sum([]byte(s.Package+"\x00"+string(s.Status)+"\x00"+s.Upstream.String()))),
+		Severity:    severity,
+		Location:    codeQualityLocation{path, codeQualityLines{pkgverLine(path)}},
+	})
+}
+
+func (f *codeQualityFormatter) Finish(statistics *Statistics) {
+	if f.issues == nil {
+		f.issues = []codeQualityIssue{}
+	}
+	enc := json.NewEncoder(f.w)
+	enc.SetIndent("", "  ")
+	enc.Encode(f.issues)
+}
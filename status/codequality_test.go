@@ -0,0 +1,51 @@
+package status
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCodeQualityFormatter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "codequality")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	pkgbuild := filepath.Join(dir, "PKGBUILD")
+	ioutil.WriteFile(pkgbuild, []byte("# Maintainer: Jane\npkgname=foo\npkgver=1.0\npkgrel=1\n"), 0644)
+
+	out := bytes.NewBuffer(nil)
+	f, err := NewFormatterWriter("codequality", out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Status(&Status{Package: "foo", Version: "1.0-1", PKGBUILD: pkgbuild, Upstream: "1.1", Status: OutOfDate, Message: "should be updated to 1.1"})
+	f.Status(&Status{Package: "bar", Version: "1.0-1", Status: UpToDate, Message: "matches upstream version 1.0"})
+	f.Status(&Status{Package: "baz", Version: "1.0-1", Status: Unknown, Error: "No release found", Message: "No release found"})
+	f.Finish(nil)
+
+	var issues []codeQualityIssue
+	if err := json.Unmarshal(out.Bytes(), &issues); err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("Expecting 2 issues, but got %v", issues)
+	}
+	expected := codeQualityIssue{
+		Description: "foo 1.0-1 should be updated to 1.1",
+		CheckName:   "aur-out-of-date/out-of-date",
+		Fingerprint: issues[0].Fingerprint,
+		Severity:    "major",
+		Location:    codeQualityLocation{pkgbuild, codeQualityLines{3}},
+	}
+	if issues[0] != expected || len(expected.Fingerprint) != 32 {
+		t.Errorf("Expecting %v, but got %v", expected, issues[0])
+	}
+	if issues[1].Severity != "info" || issues[1].Location != (codeQualityLocation{"baz/PKGBUILD", codeQualityLines{1}}) {
+		t.Errorf("Unexpected issue %v", issues[1])
+	}
+}
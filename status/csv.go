@@ -0,0 +1,31 @@
+package status
+
+import (
+	"encoding/csv"
+	"io"
+	"time"
+)
+
+// csvFormatter writes one comma-separated line per package, see RFC 4180
+type csvFormatter struct {
+	w *csv.Writer
+}
+
+func newCSVFormatter(out io.Writer) *csvFormatter {
+	w := csv.NewWriter(out)
+	w.Write([]string{"package", "aur_version", "upstream_version", "status", "provider", "checked_at", "release_url"})
+	return &csvFormatter{w}
+}
+
+func (f *csvFormatter) Status(s *Status) {
+	checkedAt := s.CheckedAt
+	if checkedAt.IsZero() {
+		checkedAt = time.Now()
+	}
+	f.w.Write([]string{s.Package, s.Version, s.Upstream.String(), s.Label(), s.Provider, checkedAt.UTC().Format(time.RFC3339), s.ReleaseURL})
+	f.w.Flush()
+}
+
+func (f *csvFormatter) Finish(statistics *Statistics) {
+	f.w.Flush()
+}
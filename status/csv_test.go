@@ -0,0 +1,31 @@
+package status
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestCSVFormatter(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	statusWriter = out
+	f, err := NewFormatter("csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Status(&Status{
+		Package:    "foo",
+		Version:    "1.0-1",
+		Upstream:   "v1.1",
+		Provider:   "github",
+		ReleaseURL: "https://github.com/foo/foo/releases/tag/v1.1",
+		Status:     OutOfDate,
+		CheckedAt:  time.Date(2021, 3, 16, 12, 0, 0, 0, time.UTC),
+	})
+	f.Finish(nil)
+	expected := "package,aur_version,upstream_version,status,provider,checked_at,release_url\n" +
+		"foo,1.0-1,1.1,OUT-OF-DATE,github,2021-03-16T12:00:00Z,https://github.com/foo/foo/releases/tag/v1.1\n"
+	if out.String() != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, out.String())
+	}
+}
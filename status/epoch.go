@@ -0,0 +1,33 @@
+package status
+
+import (
+	"fmt"
+	"regexp"
+
+	pkgbuild "github.com/mikkeloscar/gopkgbuild"
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+// dateVersion matches versions based on dates, e.g. 20230115, 2023.01.15 or 2023-01
+var dateVersion = regexp.MustCompile(`^(19|20)\d\d([.-]?\d\d?)?([.-]?\d\d?)?($|[^0-9])`)
+
+// schemeChanged determines whether one version is based on a date and the other one is not, e.g. 20230115 and 1.2.0
+func schemeChanged(a, b string) bool {
+	return dateVersion.MatchString(a) != dateVersion.MatchString(b)
+}
+
+// suggestEpoch marks an upstream version comparing lower than the packaged version as a likely versioning scheme change,
+// if the scheme differs and (if both dates are known) it has been released after the last AUR update, returning false otherwise
+func (s *Status) suggestEpoch(pkgVersion *pkgbuild.CompleteVersion, upstreamVersion upstream.Version) bool {
+	releasedEarlier := s.Released != nil && s.LastModified != nil && !s.Released.After(*s.LastModified)
+	if !schemeChanged(string(pkgVersion.Version), upstreamVersion.String()) || releasedEarlier {
+		return false
+	}
+	s.Status = Unknown
+	s.Reason = "epoch-bump"
+	s.Upstream = upstreamVersion
+	s.SuggestedEpoch = int(pkgVersion.Epoch) + 1
+	s.Message = fmt.Sprintf("upstream version %v compares lower than %s: versioning scheme change, epoch bump likely needed (epoch=%d)",
+		upstreamVersion, pkgVersion.Version, s.SuggestedEpoch)
+	return true
+}
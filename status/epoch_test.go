@@ -0,0 +1,69 @@
+package status
+
+import (
+	"testing"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+func TestSchemeChanged(t *testing.T) {
+	for _, versions := range [][2]string{{"20230115", "1.2.0"}, {"2023.01.15", "1.0"}, {"2023-01", "0.9"}} {
+		if !schemeChanged(versions[0], versions[1]) || !schemeChanged(versions[1], versions[0]) {
+			t.Errorf("Expecting scheme change between %s and %s", versions[0], versions[1])
+		}
+	}
+	for _, versions := range [][2]string{{"20230115", "20221201"}, {"1.2.0", "0.9"}, {"2.0.2023", "1.0"}, {"1999", "2000"}} {
+		if schemeChanged(versions[0], versions[1]) {
+			t.Errorf("Expecting no scheme change between %s and %s", versions[0], versions[1])
+		}
+	}
+}
+
+func TestSuggestEpoch(t *testing.T) {
+	s := &Status{Package: "foo", Version: "20230115-1"}
+	s.Compare(upstream.Version("1.2.0"))
+	if s.Status != Unknown || s.Reason != "epoch-bump" || s.SuggestedEpoch != 1 || s.Label() != "UNKNOWN(epoch-bump)" {
+		t.Errorf("Expecting epoch bump to 1, but got %v", s)
+	}
+	expected := "upstream version 1.2.0 compares lower than 20230115: versioning scheme change, epoch bump likely needed (epoch=1)"
+	if s.Message != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, s.Message)
+	}
+
+	updated := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	released := updated.AddDate(0, 1, 0)
+	s = &Status{Package: "foo", Version: "1:20230115-1", LastModified: &updated, Released: &released}
+	s.Compare(upstream.Version("1.0"))
+	if s.SuggestedEpoch != 2 {
+		t.Errorf("Expecting epoch bump to 2 for a release after the AUR update, but got %v", s)
+	}
+	s = &Status{Package: "foo", Version: "5.0-1", LastModified: &updated, Released: &released}
+	s.Compare(upstream.Version("1.0"))
+	if s.SuggestedEpoch != 0 {
+		t.Errorf("Expecting no epoch bump for a release after the AUR update using the same scheme, but got %v", s)
+	}
+	s = &Status{Package: "foo", Version: "20230115-1", LastModified: &released, Released: &updated}
+	s.Compare(upstream.Version("1.0"))
+	if s.SuggestedEpoch != 0 {
+		t.Errorf("Expecting no epoch bump for a release before the AUR update, but got %v", s)
+	}
+
+	s = &Status{Package: "foo", Version: "0.35-1"}
+	s.Compare(upstream.Version("0.30"))
+	if s.Status != Unknown || s.SuggestedEpoch != 0 {
+		t.Errorf("Expecting no epoch bump, but got %v", s)
+	}
+}
+
+func TestCompareEpoch(t *testing.T) {
+	s := &Status{Package: "foo", Version: "1:2.0-1"}
+	s.Compare(upstream.Version("2.1"))
+	if s.Status != OutOfDate {
+		t.Errorf("Expecting %s for 2.1, but got %v", OutOfDate, s)
+	}
+	s.Compare(upstream.Version("2.0"))
+	if s.Status != UpToDate {
+		t.Errorf("Expecting %s for 2.0, but got %v", UpToDate, s)
+	}
+}
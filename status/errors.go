@@ -0,0 +1,89 @@
+package status
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// errorSummary prints the failed checks grouped by provider and reason once all packages have been checked
+type errorSummary struct {
+	Formatter
+	w      io.Writer
+	counts map[string]map[string]int
+}
+
+// ErrorSummary returns a Formatter passing all statuses to f and printing a line such as
+// "Errors: github: 12 rate-limited; pypi: 1 network; no provider: 23 packages" to w, if any check failed
+func ErrorSummary(f Formatter, w io.Writer) Formatter {
+	return &errorSummary{Formatter: f, w: w, counts: map[string]map[string]int{}}
+}
+
+func (f *errorSummary) Status(s *Status) {
+	if s.Error != "" {
+		provider, reason := s.Provider, s.Reason
+		if reason == "no-provider" || provider == "" {
+			provider = "no provider"
+		}
+		if reason == "" {
+			reason = "error"
+		}
+		if f.counts[provider] == nil {
+			f.counts[provider] = map[string]int{}
+		}
+		f.counts[provider][reason]++
+	}
+	f.Formatter.Status(s)
+}
+
+func (f *errorSummary) Finish(statistics *Statistics) {
+	f.Formatter.Finish(statistics)
+	if summary := f.summary(); summary != "" {
+		fmt.Fprintln(f.w, "Errors: "+summary)
+	}
+}
+
+// summary lists the providers with the most errors first, "" if no check failed
+func (f *errorSummary) summary() string {
+	type count struct {
+		name string
+		n    int
+	}
+	byCount := func(counts []count) {
+		sort.Slice(counts, func(i, j int) bool {
+			if counts[i].n != counts[j].n {
+				return counts[i].n > counts[j].n
+			}
+			return counts[i].name < counts[j].name
+		})
+	}
+	var providers []count
+	for provider, reasons := range f.counts {
+		n := 0
+		for _, c := range reasons {
+			n += c
+		}
+		providers = append(providers, count{provider, n})
+	}
+	byCount(providers)
+	var groups []string
+	for _, p := range providers {
+		var reasons []count
+		for reason, n := range f.counts[p.name] {
+			reasons = append(reasons, count{reason, n})
+		}
+		byCount(reasons)
+		var parts []string
+		for _, r := range reasons {
+			if r.name == "no-provider" && r.n == 1 {
+				r.name = "package"
+			} else if r.name == "no-provider" {
+				r.name = "packages"
+			}
+			parts = append(parts, fmt.Sprintf("%d %s", r.n, r.name))
+		}
+		groups = append(groups, p.name+": "+strings.Join(parts, ", "))
+	}
+	return strings.Join(groups, "; ")
+}
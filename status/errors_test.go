@@ -0,0 +1,31 @@
+package status
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestErrorSummary(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	f := ErrorSummary(Filter(&textFormatter{bytes.NewBuffer(nil)}), out)
+	for i := 0; i < 3; i++ {
+		f.Status(&Status{Package: "foo", Provider: "github", Status: Unknown, Error: "API rate limit exceeded", Reason: "rate-limited"})
+	}
+	f.Status(&Status{Package: "bar", Provider: "github", Status: UpstreamGone, Error: "No GitHub project found", Reason: "gone"})
+	f.Status(&Status{Package: "baz", Provider: "pypi", Status: Unknown, Error: "timeout", Reason: "network"})
+	f.Status(&Status{Package: "qux", Status: Unknown, Error: "No release found", Reason: "no-provider"})
+	f.Status(&Status{Package: "quux", Provider: "github", Status: UpToDate})
+	f.Finish(nil)
+	expected := "Errors: github: 3 rate-limited, 1 gone; no provider: 1 package; pypi: 1 network\n"
+	if actual := out.String(); actual != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, actual)
+	}
+
+	out.Reset()
+	f = ErrorSummary(Filter(&textFormatter{bytes.NewBuffer(nil)}), out)
+	f.Status(&Status{Package: "quux", Provider: "github", Status: UpToDate})
+	f.Finish(nil)
+	if out.Len() != 0 {
+		t.Errorf("Expecting no output without errors, but got '%s'", out.String())
+	}
+}
@@ -0,0 +1,81 @@
+package status
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/simon04/aur-out-of-date/logging"
+)
+
+type fileFormatter struct {
+	Formatter
+	f        *os.File
+	filename string
+}
+
+// FileFormatter returns the Formatter for the given output format writing to the file,
+// which is replaced atomically once finished so that readers never see a partial report
+func FileFormatter(format, filename string) (Formatter, error) {
+	f, err := ioutil.TempFile(path.Dir(filename), "."+path.Base(filename)+".*")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create %s: %w", filename, err)
+	}
+	formatter, err := NewFormatterWriter(format, f)
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	return &fileFormatter{formatter, f, filename}, nil
+}
+
+func (f *fileFormatter) Finish(statistics *Statistics) {
+	f.Formatter.Finish(statistics)
+	err := f.f.Chmod(0644)
+	if closeErr := f.f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.f.Name(), f.filename)
+	}
+	if err != nil {
+		os.Remove(f.f.Name())
+		logging.Warnf("Failed to write %s: %v", f.filename, err)
+	}
+}
+
+// discard removes the temporary file without replacing the file
+func (f *fileFormatter) discard() {
+	f.f.Close()
+	os.Remove(f.f.Name())
+}
+
+// OutputFiles returns the formatters for comma-separated format=file pairs, e.g. "json=results.json,html=report.html"
+func OutputFiles(s string) ([]Formatter, error) {
+	var formatters []Formatter
+	for _, pair := range strings.Split(s, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		formatter, err := outputFile(pair)
+		if err != nil {
+			for _, f := range formatters {
+				f.(*fileFormatter).discard()
+			}
+			return nil, err
+		}
+		formatters = append(formatters, formatter)
+	}
+	return formatters, nil
+}
+
+func outputFile(pair string) (Formatter, error) {
+	i := strings.Index(pair, "=")
+	if i < 0 {
+		return nil, fmt.Errorf("Failed to parse output file %s: expecting format=file", pair)
+	}
+	return FileFormatter(pair[:i], pair[i+1:])
+}
@@ -0,0 +1,52 @@
+package status
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+func TestOutputFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	results, report := path.Join(dir, "results.json"), path.Join(dir, "report.csv")
+	formatters, err := OutputFiles("json=" + results + ", csv=" + report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := MultiFormatter(formatters...)
+	s := Status{Package: "foo", Version: "1.0-1"}
+	s.Compare(upstream.Version("1.1"))
+	f.Status(&s)
+	if _, err := os.Stat(results); !os.IsNotExist(err) {
+		t.Errorf("Expecting %s to be written once finished", results)
+	}
+	f.Finish(nil)
+	for _, filename := range []string{results, report} {
+		content, err := ioutil.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		} else if !strings.Contains(string(content), "foo") {
+			t.Errorf("Expecting %s to contain foo, but got %q", filename, content)
+		}
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 2 {
+		t.Errorf("Expecting no temporary files, but got %v", files)
+	}
+
+	for _, invalid := range []string{"json", "unknown=" + results, "json=" + path.Join(dir, "missing", "results.json"), "csv=" + report + ",json"} {
+		if _, err := OutputFiles(invalid); err == nil {
+			t.Errorf("Expecting an error for %s", invalid)
+		}
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 2 {
+		t.Errorf("Expecting no temporary files after errors, but got %v", files)
+	}
+}
@@ -0,0 +1,175 @@
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Formatter outputs package statuses in a specific format
+type Formatter interface {
+	// Status is called for each package once its status is known
+	Status(s *Status)
+	// Finish is called after all packages have been handled; statistics is nil unless requested
+	Finish(statistics *Statistics)
+}
+
+// Formats lists the supported output formats
+var Formats = []string{"text", "json-seq", "json", "ndjson", "csv", "markdown", "html", "prometheus", "nagios", "junit", "github", "tap", "waybar", "i3blocks", "cyclonedx", "spdx", "codequality", "aurutils"}
+
+// NewFormatter returns the Formatter for the given output format writing to stdout
+func NewFormatter(format string) (Formatter, error) {
+	return NewFormatterWriter(format, statusWriter)
+}
+
+// NewFormatterWriter returns the Formatter for the given output format writing to w
+func NewFormatterWriter(format string, w io.Writer) (Formatter, error) {
+	switch format {
+	case "", "text":
+		return &textFormatter{w}, nil
+	case "json-seq":
+		return &jsonSeqFormatter{w}, nil
+	case "json":
+		return &jsonFormatter{w: w}, nil
+	case "ndjson":
+		return &ndjsonFormatter{json.NewEncoder(w)}, nil
+	case "csv":
+		return newCSVFormatter(w), nil
+	case "markdown":
+		return newMarkdownFormatter(w), nil
+	case "html":
+		return &htmlFormatter{w: w}, nil
+	case "prometheus":
+		return &prometheusFormatter{w: w}, nil
+	case "nagios":
+		return &NagiosFormatter{Warning: 1, w: w}, nil
+	case "junit":
+		return &junitFormatter{w: w}, nil
+	case "github":
+		return newGitHubFormatter(w), nil
+	case "tap":
+		return newTAPFormatter(w), nil
+	case "waybar", "i3blocks":
+		return &barFormatter{w: w, format: format}, nil
+	case "cyclonedx":
+		return &cycloneDXFormatter{w: w}, nil
+	case "spdx":
+		return &spdxFormatter{w: w}, nil
+	case "codequality":
+		return &codeQualityFormatter{w: w}, nil
+	case "aurutils":
+		return &aurutilsFormatter{w}, nil
+	}
+	return nil, fmt.Errorf("Unknown output format %s, supported formats: %v", format, Formats)
+}
+
+type textFormatter struct {
+	w io.Writer
+}
+
+func (f *textFormatter) Status(s *Status) {
+	s.Write(f.w)
+}
+
+func (f *textFormatter) Finish(statistics *Statistics) {
+	if statistics != nil {
+		statistics.Write(f.w)
+	}
+}
+
+type jsonSeqFormatter struct {
+	w io.Writer
+}
+
+func (f *jsonSeqFormatter) Status(s *Status) {
+	s.WriteJSONTextSequence(f.w)
+}
+
+func (f *jsonSeqFormatter) Finish(statistics *Statistics) {
+	if statistics != nil {
+		statistics.WriteJSONTextSequence(f.w)
+	}
+}
+
+// jsonFormatter buffers all statuses and writes a single JSON document
+type jsonFormatter struct {
+	w        io.Writer
+	packages []*Status
+}
+
+type jsonDocument struct {
+	Packages   []*Status   `json:"packages"`
+	Statistics *Statistics `json:"statistics,omitempty"`
+}
+
+func (f *jsonFormatter) Status(s *Status) {
+	s.Type = "package"
+	f.packages = append(f.packages, s)
+}
+
+func (f *jsonFormatter) Finish(statistics *Statistics) {
+	if statistics != nil {
+		statistics.Type = "statistics"
+	}
+	if f.packages == nil {
+		f.packages = []*Status{}
+	}
+	enc := json.NewEncoder(f.w)
+	enc.SetIndent("", "  ")
+	enc.Encode(jsonDocument{f.packages, statistics})
+}
+
+// ndjsonFormatter streams one JSON object per line (newline delimited JSON)
+type ndjsonFormatter struct {
+	enc *json.Encoder
+}
+
+func (f *ndjsonFormatter) Status(s *Status) {
+	s.Type = "package"
+	f.enc.Encode(s)
+}
+
+func (f *ndjsonFormatter) Finish(statistics *Statistics) {
+	if statistics != nil {
+		statistics.Type = "statistics"
+		f.enc.Encode(statistics)
+	}
+}
+
+type multiFormatter []Formatter
+
+// MultiFormatter returns a Formatter that duplicates its calls to all the given formatters
+func MultiFormatter(formatters ...Formatter) Formatter {
+	return multiFormatter(formatters)
+}
+
+func (m multiFormatter) Status(s *Status) {
+	for _, f := range m {
+		f.Status(s)
+	}
+}
+
+func (m multiFormatter) Finish(statistics *Statistics) {
+	for _, f := range m {
+		f.Finish(statistics)
+	}
+}
+
+type filterFormatter struct {
+	Formatter
+	hidden []StatusType
+}
+
+// Filter returns a Formatter that omits packages having one of the hidden statuses
+func Filter(f Formatter, hidden ...StatusType) Formatter {
+	return &filterFormatter{f, hidden}
+}
+
+func (f *filterFormatter) Status(s *Status) {
+	for _, h := range f.hidden {
+		if s.Status == h {
+			return
+		}
+	}
+	f.Formatter.Status(s)
+}
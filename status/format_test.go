@@ -0,0 +1,58 @@
+package status
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+func TestNDJSONFormatter(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	statusWriter = out
+	f, err := NewFormatter("ndjson")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := Status{Package: "foo", Version: "1.0-1", Provider: "github"}
+	s.Compare(upstream.Version("1.1"))
+	f.Status(&s)
+	f.Finish(&Statistics{OutOfDate: 1})
+	actual := out.String()
+	expected := `{"type":"package","name":"foo","message":"should be updated to 1.1","version":"1.0-1","upstream":"1.1","provider":"github","status":"OUT-OF-DATE"}` + "\n" +
+		`{"type":"statistics","up_to_date":0,"flagged_out_of_date":0,"out_of_date":1,"unknown":0}` + "\n"
+	if actual != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, actual)
+	}
+}
+
+func TestJSONFormatter(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	statusWriter = out
+	f, _ := NewFormatter("json")
+	f.Finish(nil)
+	expected := "{\n  \"packages\": []\n}\n"
+	if out.String() != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, out.String())
+	}
+}
+
+func TestUnknownFormatter(t *testing.T) {
+	if _, err := NewFormatter("foo"); err == nil {
+		t.Error("Expecting an error, but got none")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	f, _ := NewFormatterWriter("csv", out)
+	f = Filter(f, UpToDate, Unknown)
+	f.Status(&Status{Package: "foo", Status: UpToDate})
+	f.Status(&Status{Package: "bar", Status: Unknown})
+	f.Status(&Status{Package: "baz", Status: OutOfDate})
+	f.Finish(nil)
+	if strings.Count(out.String(), "\n") != 2 || !strings.Contains(out.String(), "baz") {
+		t.Errorf("Expecting only baz, but got '%s'", out.String())
+	}
+}
@@ -0,0 +1,57 @@
+package status
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// gitHubFormatter prints GitHub Actions workflow commands and writes a job summary, see
+// https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions
+type gitHubFormatter struct {
+	w       io.Writer
+	summary *bytes.Buffer
+	table   Formatter
+}
+
+func newGitHubFormatter(w io.Writer) *gitHubFormatter {
+	summary := bytes.NewBuffer(nil)
+	fmt.Fprintln(summary, "## aur-out-of-date")
+	fmt.Fprintln(summary)
+	return &gitHubFormatter{w, summary, newMarkdownFormatter(summary)}
+}
+
+var gitHubDataEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+var gitHubPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+
+func (f *gitHubFormatter) Status(s *Status) {
+	f.table.Status(s)
+	command := ""
+	switch {
+	case s.Status == OutOfDate || s.Status == FlaggedOutOfDate:
+		command = "warning"
+	case s.Error != "":
+		command = "error"
+	default:
+		return
+	}
+	title := gitHubPropertyEscaper.Replace(fmt.Sprintf("%s %s", s.Package, s.Version))
+	fmt.Fprintf(f.w, "::%s title=%s::%s\n", command, title, gitHubDataEscaper.Replace(s.Message+s.releaseURLSuffix()))
+}
+
+func (f *gitHubFormatter) Finish(statistics *Statistics) {
+	f.table.Finish(statistics)
+	filename := os.Getenv("GITHUB_STEP_SUMMARY")
+	if filename == "" {
+		return
+	}
+	file, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		fmt.Fprintf(f.w, "::error::%s\n", gitHubDataEscaper.Replace(err.Error()))
+		return
+	}
+	defer file.Close()
+	f.summary.WriteTo(file)
+}
@@ -0,0 +1,36 @@
+package status
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGitHubFormatter(t *testing.T) {
+	summary, err := ioutil.TempFile("", "aur-out-of-date")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(summary.Name())
+	os.Setenv("GITHUB_STEP_SUMMARY", summary.Name())
+	defer os.Unsetenv("GITHUB_STEP_SUMMARY")
+
+	out := bytes.NewBuffer(nil)
+	f, _ := NewFormatterWriter("github", out)
+	f.Status(&Status{Package: "foo", Version: "1.0-1", Status: OutOfDate, Message: "should be updated to 1.1"})
+	f.Status(&Status{Package: "bar", Version: "1.0-1", Status: UpToDate, Message: "matches upstream version 1.0"})
+	f.Status(&Status{Package: "baz", Version: "1.0-1", Status: Unknown, Error: "100% failed\nrate limit", Message: "100% failed\nrate limit"})
+	f.Finish(nil)
+
+	expected := "::warning title=foo 1.0-1::should be updated to 1.1\n" +
+		"::error title=baz 1.0-1::100%25 failed%0Arate limit\n"
+	if out.String() != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, out.String())
+	}
+	actual, _ := ioutil.ReadFile(summary.Name())
+	if !strings.Contains(string(actual), "| **foo** | **1.0-1** |") || !strings.Contains(string(actual), "| bar | 1.0-1 |") {
+		t.Errorf("Unexpected summary '%s'", actual)
+	}
+}
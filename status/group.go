@@ -0,0 +1,55 @@
+package status
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// groupFormatter buffers all statuses and passes them to the underlying Formatter grouped by maintainer
+type groupFormatter struct {
+	Formatter
+	w        io.Writer
+	packages []*Status
+}
+
+// GroupByMaintainer returns a Formatter that groups packages by maintainer.
+// If w is non-nil, a summary line per maintainer is written to w before the packages of the maintainer.
+func GroupByMaintainer(f Formatter, w io.Writer) Formatter {
+	return &groupFormatter{Formatter: f, w: w}
+}
+
+func (f *groupFormatter) Status(s *Status) {
+	f.packages = append(f.packages, s)
+}
+
+func (f *groupFormatter) Finish(statistics *Statistics) {
+	groups := map[string][]*Status{}
+	var maintainers []string
+	for _, s := range f.packages {
+		if _, ok := groups[s.Maintainer]; !ok {
+			maintainers = append(maintainers, s.Maintainer)
+		}
+		groups[s.Maintainer] = append(groups[s.Maintainer], s)
+	}
+	sort.Strings(maintainers)
+	for _, maintainer := range maintainers {
+		packages := groups[maintainer]
+		if f.w != nil {
+			var stats Statistics
+			for _, s := range packages {
+				stats.Update(s.Status)
+			}
+			name := maintainer
+			if name == "" {
+				name = "(orphan)"
+			}
+			fmt.Fprintf(f.w, "MAINTAINER %s: %d packages, %d up-to-date, %d flagged out-of-date, %d out-of-date, %d unknown\n",
+				name, len(packages), stats.UpToDate, stats.FlaggedOutOfDate, stats.OutOfDate, stats.Unknown)
+		}
+		for _, s := range packages {
+			f.Formatter.Status(s)
+		}
+	}
+	f.Formatter.Finish(statistics)
+}
@@ -0,0 +1,26 @@
+package status
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGroupByMaintainer(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	f, _ := NewFormatterWriter("tap", out)
+	f = GroupByMaintainer(f, out)
+	f.Status(&Status{Package: "foo", Maintainer: "simon04", Status: UpToDate})
+	f.Status(&Status{Package: "bar", Maintainer: "z3ntu", Version: "1.0-1", Upstream: "1.1", Status: OutOfDate})
+	f.Status(&Status{Package: "baz", Maintainer: "simon04", Version: "1.0-1", Upstream: "1.1", Status: OutOfDate})
+	f.Finish(nil)
+	expected := "TAP version 13\n" +
+		"MAINTAINER simon04: 2 packages, 1 up-to-date, 0 flagged out-of-date, 1 out-of-date, 0 unknown\n" +
+		"ok 1 - foo\n" +
+		"not ok 2 - baz (1.0-1 < 1.1)\n" +
+		"MAINTAINER z3ntu: 1 packages, 0 up-to-date, 0 flagged out-of-date, 1 out-of-date, 0 unknown\n" +
+		"not ok 3 - bar (1.0-1 < 1.1)\n" +
+		"1..3\n"
+	if out.String() != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, out.String())
+	}
+}
@@ -0,0 +1,85 @@
+package status
+
+import (
+	"html/template"
+	"io"
+	"strings"
+	"time"
+)
+
+// htmlFormatter buffers all statuses and writes a self-contained HTML report
+type htmlFormatter struct {
+	w        io.Writer
+	packages []*Status
+}
+
+type htmlReport struct {
+	Generated  time.Time
+	Packages   []*Status
+	Statistics *Statistics
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"lower": func(s StatusType) string { return strings.ToLower(string(s)) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>aur-out-of-date report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: .3em .6em; border-bottom: 1px solid #ddd; }
+th { cursor: pointer; user-select: none; background: #f4f4f4; }
+th:after { content: " \2195"; color: #aaa; }
+.badge { display: inline-block; padding: .1em .5em; border-radius: .3em; color: #fff; font-size: .85em; background: #777; }
+.badge.up-to-date { background: #2e7d32; }
+.badge.out-of-date, .badge.flagged-out-of-date, .badge.upstream-gone { background: #c62828; }
+.badge.unknown { background: #9e9e9e; }
+footer { margin-top: 1em; color: #777; font-size: .85em; }
+</style>
+</head>
+<body>
+<h1>aur-out-of-date report</h1>
+{{with .Statistics}}<p>{{.UpToDate}} up-to-date, {{.FlaggedOutOfDate}} flagged out-of-date, {{.OutOfDate}} out-of-date, {{.Unknown}} unknown</p>{{end}}
+<table id="report">
+<thead><tr><th>Package</th><th>Version</th><th>Upstream</th><th>Status</th><th>Message</th></tr></thead>
+<tbody>
+{{- range .Packages}}
+<tr>
+<td><a href="https://aur.archlinux.org/packages/{{.Package}}">{{.Package}}</a></td>
+<td>{{.Version}}</td>
+<td>{{if .ReleaseURL}}<a href="{{.ReleaseURL}}">{{.Upstream.String}}</a>{{else if .URL}}<a href="{{.URL}}">{{.Upstream.String}}</a>{{else}}{{.Upstream.String}}{{end}}</td>
+<td><span class="badge {{lower .Status}}">{{.Label}}</span></td>
+<td>{{.Message}}</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+<footer>Generated by <a href="https://github.com/simon04/aur-out-of-date">aur-out-of-date</a> on {{.Generated.Format "2006-01-02 15:04 MST"}}</footer>
+<script>
+document.querySelectorAll("#report th").forEach(function (th, column) {
+  var ascending = true;
+  th.addEventListener("click", function () {
+    var tbody = document.querySelector("#report tbody");
+    var rows = Array.prototype.slice.call(tbody.rows);
+    rows.sort(function (a, b) {
+      var x = a.cells[column].textContent, y = b.cells[column].textContent;
+      return (ascending ? 1 : -1) * x.localeCompare(y, undefined, {numeric: true});
+    });
+    ascending = !ascending;
+    rows.forEach(function (row) { tbody.appendChild(row); });
+  });
+});
+</script>
+</body>
+</html>
+`))
+
+func (f *htmlFormatter) Status(s *Status) {
+	f.packages = append(f.packages, s)
+}
+
+func (f *htmlFormatter) Finish(statistics *Statistics) {
+	htmlTemplate.Execute(f.w, htmlReport{time.Now(), f.packages, statistics})
+}
@@ -0,0 +1,30 @@
+package status
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestHTMLFormatter(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	statusWriter = out
+	f, err := NewFormatter("html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Status(&Status{Package: "foo", Version: "1.0-1", Upstream: "1.1", URL: "https://github.com/foo/foo", Status: OutOfDate, Message: "should be updated to <1.1>"})
+	f.Finish(&Statistics{OutOfDate: 1})
+	actual := out.String()
+	for _, expected := range []string{
+		`<a href="https://aur.archlinux.org/packages/foo">foo</a>`,
+		`<a href="https://github.com/foo/foo">1.1</a>`,
+		`<span class="badge out-of-date">OUT-OF-DATE</span>`,
+		`should be updated to &lt;1.1&gt;`,
+		`0 up-to-date, 0 flagged out-of-date, 1 out-of-date, 0 unknown`,
+	} {
+		if !strings.Contains(actual, expected) {
+			t.Errorf("Expecting '%s' in '%s'", expected, actual)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package status
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// junitFormatter buffers all statuses and writes a JUnit XML report with a test case per package
+type junitFormatter struct {
+	w        io.Writer
+	packages []*Status
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+}
+
+func (f *junitFormatter) Status(s *Status) {
+	f.packages = append(f.packages, s)
+}
+
+func (f *junitFormatter) Finish(statistics *Statistics) {
+	suite := junitTestSuite{Name: "aur-out-of-date", Cases: []junitTestCase{}}
+	for _, s := range f.packages {
+		c := junitTestCase{
+			Name:      s.Package,
+			ClassName: "aur-out-of-date." + s.Provider,
+			SystemOut: fmt.Sprintf("[%s][%s] %s", s.Package, s.Version, s.Message),
+		}
+		if s.ReleaseURL != "" {
+			c.SystemOut += "\n" + s.ReleaseURL
+		}
+		if s.Provider == "" {
+			c.ClassName = "aur-out-of-date"
+		}
+		switch {
+		case s.Status == OutOfDate || s.Status == FlaggedOutOfDate:
+			c.Failure = &junitMessage{s.Message, string(s.Status)}
+			suite.Failures++
+		case s.Error != "":
+			c.Error = &junitMessage{s.Error, s.Label()}
+			suite.Errors++
+		case s.Status == Unknown:
+			c.Skipped = &junitMessage{s.Message, s.Label()}
+			suite.Skipped++
+		}
+		suite.Cases = append(suite.Cases, c)
+	}
+	suite.Tests = len(suite.Cases)
+	output, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return
+	}
+	io.WriteString(f.w, xml.Header)
+	f.w.Write(output)
+	io.WriteString(f.w, "\n")
+}
@@ -0,0 +1,31 @@
+package status
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestJUnitFormatter(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	f, err := NewFormatterWriter("junit", out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Status(&Status{Package: "foo", Version: "1.0-1", Provider: "github", Status: OutOfDate, Message: "should be updated to 1.1"})
+	f.Status(&Status{Package: "bar", Version: "1.0-1", Provider: "pypi", Status: UpToDate, Message: "matches upstream version 1.0"})
+	f.Status(&Status{Package: "baz", Version: "1.0-1", Status: Unknown, Error: "No release found", Message: "No release found"})
+	f.Finish(nil)
+	actual := out.String()
+	for _, expected := range []string{
+		`<testsuite name="aur-out-of-date" tests="3" failures="1" errors="1" skipped="0">`,
+		`<testcase name="foo" classname="aur-out-of-date.github">`,
+		`<failure message="should be updated to 1.1" type="OUT-OF-DATE"></failure>`,
+		`<testcase name="bar" classname="aur-out-of-date.pypi">`,
+		`<error message="No release found" type="UNKNOWN"></error>`,
+	} {
+		if !strings.Contains(actual, expected) {
+			t.Errorf("Expecting '%s' in '%s'", expected, actual)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package status
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// markdownFormatter writes a Markdown table, with out-of-date packages in bold
+type markdownFormatter struct {
+	w io.Writer
+}
+
+func newMarkdownFormatter(w io.Writer) *markdownFormatter {
+	fmt.Fprintln(w, "| Package | Version | Upstream | Status | Message |")
+	fmt.Fprintln(w, "| --- | --- | --- | --- | --- |")
+	return &markdownFormatter{w}
+}
+
+func markdownEscape(s string) string {
+	s = strings.Replace(s, "|", "\\|", -1)
+	s = strings.Replace(s, "\n", " ", -1)
+	return s
+}
+
+func (f *markdownFormatter) Status(s *Status) {
+	cells := []string{s.Package, s.Version, s.Upstream.String(), s.Label(), s.Message}
+	for i, cell := range cells {
+		cell = markdownEscape(cell)
+		if i == 2 && cell != "" && s.ReleaseURL != "" {
+			cell = "[" + cell + "](" + s.ReleaseURL + ")"
+		}
+		if cell != "" && (s.Status == OutOfDate || s.Status == FlaggedOutOfDate) {
+			cell = "**" + cell + "**"
+		}
+		cells[i] = cell
+	}
+	fmt.Fprintf(f.w, "| %s |\n", strings.Join(cells, " | "))
+}
+
+func (f *markdownFormatter) Finish(statistics *Statistics) {
+	if statistics == nil {
+		return
+	}
+	fmt.Fprintln(f.w)
+	fmt.Fprintf(f.w, "%d up-to-date, %d flagged out-of-date, %d out-of-date, %d unknown\n",
+		statistics.UpToDate, statistics.FlaggedOutOfDate, statistics.OutOfDate, statistics.Unknown)
+}
@@ -0,0 +1,27 @@
+package status
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMarkdownFormatter(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	statusWriter = out
+	f, err := NewFormatter("markdown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Status(&Status{Package: "foo", Version: "1.0-1", Upstream: "1.1", Status: OutOfDate, Message: "should be updated to 1.1"})
+	f.Status(&Status{Package: "bar", Version: "2.0-1", Status: Unknown, Message: "a|b"})
+	f.Status(&Status{Package: "baz", Version: "2.0-1", Upstream: "2.0", ReleaseURL: "https://pypi.org/project/baz/2.0/", Status: UpToDate})
+	f.Finish(nil)
+	expected := "| Package | Version | Upstream | Status | Message |\n" +
+		"| --- | --- | --- | --- | --- |\n" +
+		"| **foo** | **1.0-1** | **1.1** | **OUT-OF-DATE** | **should be updated to 1.1** |\n" +
+		"| bar | 2.0-1 |  | UNKNOWN | a\\|b |\n" +
+		"| baz | 2.0-1 | [2.0](https://pypi.org/project/baz/2.0/) | UP-TO-DATE |  |\n"
+	if out.String() != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, out.String())
+	}
+}
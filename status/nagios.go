@@ -0,0 +1,70 @@
+package status
+
+import (
+	"fmt"
+	"io"
+)
+
+// Nagios plugin return codes, see https://nagios-plugins.org/doc/guidelines.html#AEN78
+const (
+	nagiosOK       = 0
+	nagiosWarning  = 1
+	nagiosCritical = 2
+)
+
+// NagiosFormatter prints a Nagios/Icinga compatible check result with performance data
+type NagiosFormatter struct {
+	// Warning is the number of out-of-date packages resulting in WARNING, 0 to disable
+	Warning int
+	// Critical is the number of out-of-date packages resulting in CRITICAL, 0 to disable
+	Critical int
+	w        io.Writer
+	outdated []*Status
+}
+
+// Status implements Formatter
+func (f *NagiosFormatter) Status(s *Status) {
+	switch s.Status {
+	case OutOfDate, FlaggedOutOfDate, BadSignature, SourceGone, UpstreamGone, ChecksumMismatch:
+		f.outdated = append(f.outdated, s)
+	}
+}
+
+// ExitCode returns the Nagios plugin return code for the given statistics
+func (f *NagiosFormatter) ExitCode(statistics *Statistics) int {
+	outOfDate := statistics.FlaggedOutOfDate + statistics.Failing()
+	if f.Critical > 0 && outOfDate >= f.Critical {
+		return nagiosCritical
+	} else if f.Warning > 0 && outOfDate >= f.Warning {
+		return nagiosWarning
+	}
+	return nagiosOK
+}
+
+func nagiosThreshold(threshold int) string {
+	if threshold <= 0 {
+		return ""
+	}
+	return fmt.Sprint(threshold)
+}
+
+// Finish implements Formatter
+func (f *NagiosFormatter) Finish(statistics *Statistics) {
+	if statistics == nil {
+		statistics = &Statistics{}
+		for _, s := range f.outdated {
+			statistics.Update(s.Status)
+		}
+	}
+	outOfDate := statistics.FlaggedOutOfDate + statistics.Failing()
+	total := statistics.Total()
+	label := [...]string{"OK", "WARNING", "CRITICAL"}[f.ExitCode(statistics)]
+	fmt.Fprintf(f.w, "%s - %d of %d packages out of date | out_of_date=%d;%s;%s;0;%d up_to_date=%d;;;0;%d unknown=%d;;;0;%d\n",
+		label, outOfDate, total,
+		outOfDate, nagiosThreshold(f.Warning), nagiosThreshold(f.Critical), total,
+		statistics.UpToDate, total,
+		statistics.Unknown, total)
+	for _, s := range f.outdated {
+		fmt.Fprintf(f.w, "%s %s %s%s\n", s.Package, s.Version, s.Message, s.releaseURLSuffix())
+	}
+}
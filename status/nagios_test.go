@@ -0,0 +1,39 @@
+package status
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNagiosFormatter(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	f := &NagiosFormatter{Warning: 1, Critical: 3, w: out}
+	f.Status(&Status{Package: "foo", Version: "1.0-1", Status: OutOfDate, Message: "should be updated to 1.1"})
+	stats := &Statistics{UpToDate: 10, OutOfDate: 1, Unknown: 1}
+	f.Finish(stats)
+	expected := "WARNING - 1 of 12 packages out of date | out_of_date=1;1;3;0;12 up_to_date=10;;;0;12 unknown=1;;;0;12\n" +
+		"foo 1.0-1 should be updated to 1.1\n"
+	if out.String() != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, out.String())
+	}
+	if code := f.ExitCode(stats); code != 1 {
+		t.Errorf("Expecting exit code 1, but got %d", code)
+	}
+	if code := f.ExitCode(&Statistics{OutOfDate: 2, FlaggedOutOfDate: 1}); code != 2 {
+		t.Errorf("Expecting exit code 2, but got %d", code)
+	}
+	if code := f.ExitCode(&Statistics{UpToDate: 3, BadSignature: 1}); code != 1 {
+		t.Errorf("Expecting exit code 1 for a bad signature, but got %d", code)
+	}
+	if code := f.ExitCode(&Statistics{SourceGone: 1, ChecksumMismatch: 1, UpstreamGone: 1}); code != 2 {
+		t.Errorf("Expecting exit code 2 for gone sources, re-tagged and gone upstreams, but got %d", code)
+	}
+	out.Reset()
+	f.Finish(&Statistics{UpToDate: 1, SourceGone: 1, Unknown: 1})
+	if expected := "WARNING - 1 of 3 packages out of date | out_of_date=1;1;3;0;3 up_to_date=1;;;0;3 unknown=1;;;0;3\n"; !bytes.HasPrefix(out.Bytes(), []byte(expected)) {
+		t.Errorf("Expecting '%s', but got '%s'", expected, out.String())
+	}
+	if code := f.ExitCode(&Statistics{UpToDate: 3}); code != 0 {
+		t.Errorf("Expecting exit code 0, but got %d", code)
+	}
+}
@@ -0,0 +1,101 @@
+package status
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// prometheusFormatter buffers all statuses and writes them in the Prometheus text exposition format
+type prometheusFormatter struct {
+	w        io.Writer
+	packages []*Status
+}
+
+func (f *prometheusFormatter) Status(s *Status) {
+	f.packages = append(f.packages, s)
+}
+
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func prometheusLabels(labels ...string) string {
+	var pairs []string
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, labels[i]+`="`+prometheusLabelEscaper.Replace(labels[i+1])+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func prometheusBool(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (f *prometheusFormatter) Finish(statistics *Statistics) {
+	fmt.Fprintln(f.w, "# HELP aur_package_out_of_date Whether the AUR package is out-of-date w.r.t. its upstream version.")
+	fmt.Fprintln(f.w, "# TYPE aur_package_out_of_date gauge")
+	for _, s := range f.packages {
+		outOfDate := s.Status == OutOfDate || s.Status == FlaggedOutOfDate
+		fmt.Fprintf(f.w, "aur_package_out_of_date%s %d\n", prometheusLabels(
+			"package", s.Package, "version", s.Version, "upstream", s.Upstream.String(), "provider", s.Provider, "status", string(s.Status)),
+			prometheusBool(outOfDate))
+	}
+	fmt.Fprintln(f.w, "# HELP aur_package_check_error Whether the upstream version of the AUR package could not be determined.")
+	fmt.Fprintln(f.w, "# TYPE aur_package_check_error gauge")
+	for _, s := range f.packages {
+		fmt.Fprintf(f.w, "aur_package_check_error%s %d\n", prometheusLabels("package", s.Package, "provider", s.Provider, "reason", s.Reason), prometheusBool(s.Error != ""))
+	}
+
+	requests := map[string]int{}
+	errors := map[string]int{}
+	for _, s := range f.packages {
+		if s.Provider == "" {
+			continue
+		}
+		requests[s.Provider]++
+		if s.Error != "" {
+			errors[s.Provider]++
+		}
+	}
+	var providers []string
+	for provider := range requests {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	fmt.Fprintln(f.w, "# HELP aur_provider_requests_total Number of upstream checks per provider.")
+	fmt.Fprintln(f.w, "# TYPE aur_provider_requests_total counter")
+	for _, provider := range providers {
+		fmt.Fprintf(f.w, "aur_provider_requests_total%s %d\n", prometheusLabels("provider", provider), requests[provider])
+	}
+	fmt.Fprintln(f.w, "# HELP aur_provider_errors_total Number of failed upstream checks per provider.")
+	fmt.Fprintln(f.w, "# TYPE aur_provider_errors_total counter")
+	for _, provider := range providers {
+		fmt.Fprintf(f.w, "aur_provider_errors_total%s %d\n", prometheusLabels("provider", provider), errors[provider])
+	}
+
+	stats := providerStats(f.packages)
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Provider < stats[j].Provider })
+	fmt.Fprintln(f.w, "# HELP aur_provider_duration_p95_seconds 95th percentile of the duration of upstream checks per provider.")
+	fmt.Fprintln(f.w, "# TYPE aur_provider_duration_p95_seconds gauge")
+	for _, p := range stats {
+		fmt.Fprintf(f.w, "aur_provider_duration_p95_seconds%s %g\n", prometheusLabels("provider", p.Provider), p.P95.Seconds())
+	}
+	fmt.Fprintln(f.w, "# HELP aur_provider_duration_seconds_total Total duration of upstream checks per provider.")
+	fmt.Fprintln(f.w, "# TYPE aur_provider_duration_seconds_total counter")
+	for _, p := range stats {
+		fmt.Fprintf(f.w, "aur_provider_duration_seconds_total%s %g\n", prometheusLabels("provider", p.Provider), p.Total.Seconds())
+	}
+
+	if statistics != nil {
+		fmt.Fprintln(f.w, "# HELP aur_packages Number of AUR packages per status.")
+		fmt.Fprintln(f.w, "# TYPE aur_packages gauge")
+		fmt.Fprintf(f.w, "aur_packages%s %d\n", prometheusLabels("status", string(UpToDate)), statistics.UpToDate)
+		fmt.Fprintf(f.w, "aur_packages%s %d\n", prometheusLabels("status", string(FlaggedOutOfDate)), statistics.FlaggedOutOfDate)
+		fmt.Fprintf(f.w, "aur_packages%s %d\n", prometheusLabels("status", string(OutOfDate)), statistics.OutOfDate)
+		fmt.Fprintf(f.w, "aur_packages%s %d\n", prometheusLabels("status", string(Unknown)), statistics.Unknown)
+		fmt.Fprintf(f.w, "aur_packages%s %d\n", prometheusLabels("status", string(UpstreamGone)), statistics.UpstreamGone)
+	}
+}
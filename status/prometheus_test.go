@@ -0,0 +1,42 @@
+package status
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrometheusFormatter(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	f, err := NewFormatterWriter("prometheus", out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Status(&Status{Package: "foo", Version: "1.0-1", Upstream: "1.1", Provider: "github", Status: OutOfDate, Duration: 1500 * time.Millisecond})
+	f.Status(&Status{Package: "bar", Version: "2.0-1", Provider: "github", Status: Unknown, Error: `rate "limit"`, Reason: "rate-limited"})
+	f.Finish(&Statistics{OutOfDate: 1, Unknown: 1})
+	actual := out.String()
+	for _, expected := range []string{
+		`aur_package_out_of_date{package="foo",version="1.0-1",upstream="1.1",provider="github",status="OUT-OF-DATE"} 1`,
+		`aur_package_out_of_date{package="bar",version="2.0-1",upstream="",provider="github",status="UNKNOWN"} 0`,
+		`aur_package_check_error{package="bar",provider="github",reason="rate-limited"} 1`,
+		`aur_provider_requests_total{provider="github"} 2`,
+		`aur_provider_errors_total{provider="github"} 1`,
+		`aur_provider_duration_p95_seconds{provider="github"} 1.5`,
+		`aur_provider_duration_seconds_total{provider="github"} 1.5`,
+		`aur_packages{status="OUT-OF-DATE"} 1`,
+	} {
+		if !strings.Contains(actual, expected+"\n") {
+			t.Errorf("Expecting '%s' in '%s'", expected, actual)
+		}
+	}
+}
+
+func TestPrometheusLabels(t *testing.T) {
+	actual := prometheusLabels("a", `x"y\z`)
+	expected := `{a="x\"y\\z"}`
+	if actual != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, actual)
+	}
+}
@@ -0,0 +1,79 @@
+package status
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// ProviderStats summarizes the upstream checks of a provider during a run
+type ProviderStats struct {
+	Provider string
+	Checks   int
+	Errors   int
+	P95      time.Duration
+	Total    time.Duration
+}
+
+// providerStats computes the statistics per provider, sorted by total duration (slowest first)
+func providerStats(packages []*Status) []ProviderStats {
+	durations := map[string][]time.Duration{}
+	errors := map[string]int{}
+	for _, s := range packages {
+		if s.Provider == "" {
+			continue
+		}
+		durations[s.Provider] = append(durations[s.Provider], s.Duration)
+		if s.Error != "" {
+			errors[s.Provider]++
+		}
+	}
+	var stats []ProviderStats
+	for provider, d := range durations {
+		sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+		p := ProviderStats{Provider: provider, Checks: len(d), Errors: errors[provider]}
+		p.P95 = d[int(math.Ceil(0.95*float64(len(d))))-1]
+		for _, duration := range d {
+			p.Total += duration
+		}
+		stats = append(stats, p)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Total != stats[j].Total {
+			return stats[i].Total > stats[j].Total
+		}
+		return stats[i].Provider < stats[j].Provider
+	})
+	return stats
+}
+
+// providerSummary prints a table of the provider statistics once all packages have been checked
+type providerSummary struct {
+	Formatter
+	w        io.Writer
+	packages []*Status
+}
+
+// ProviderSummary returns a Formatter passing all statuses to f and printing the checks, error rate and p95 latency per provider to w
+func ProviderSummary(f Formatter, w io.Writer) Formatter {
+	return &providerSummary{Formatter: f, w: w}
+}
+
+func (f *providerSummary) Status(s *Status) {
+	f.packages = append(f.packages, s)
+	f.Formatter.Status(s)
+}
+
+func (f *providerSummary) Finish(statistics *Statistics) {
+	f.Formatter.Finish(statistics)
+	tw := tabwriter.NewWriter(f.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROVIDER\tCHECKS\tERRORS\tP95\tTOTAL")
+	for _, p := range providerStats(f.packages) {
+		fmt.Fprintf(tw, "%s\t%d\t%d (%.0f%%)\t%s\t%s\n", p.Provider, p.Checks, p.Errors, 100*float64(p.Errors)/float64(p.Checks),
+			p.P95.Round(time.Millisecond), p.Total.Round(time.Millisecond))
+	}
+	tw.Flush()
+}
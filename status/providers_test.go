@@ -0,0 +1,25 @@
+package status
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestProviderSummary(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	f := ProviderSummary(Filter(&textFormatter{bytes.NewBuffer(nil)}), out)
+	for i := 1; i <= 20; i++ {
+		f.Status(&Status{Package: "foo", Provider: "github", Duration: time.Duration(i) * 100 * time.Millisecond})
+	}
+	f.Status(&Status{Package: "bar", Provider: "pypi", Duration: 50 * time.Millisecond, Error: "timeout"})
+	f.Status(&Status{Package: "baz", Duration: time.Second})
+	f.Finish(nil)
+	expected := `PROVIDER  CHECKS  ERRORS    P95   TOTAL
+github    20      0 (0%)    1.9s  21s
+pypi      1       1 (100%)  50ms  50ms
+`
+	if actual := out.String(); actual != expected {
+		t.Errorf("Expecting\n%s\nbut got\n%s", expected, actual)
+	}
+}
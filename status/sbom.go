@@ -0,0 +1,211 @@
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// aurPurl returns the package URL of the AUR package
+func aurPurl(s *Status) string {
+	return fmt.Sprintf("pkg:alpm/aur/%s@%s", purlEscape(s.Package), purlEscape(s.Version))
+}
+
+// purlEscape percent-encodes all characters but letters, digits and ".-_~" as required by the purl specification,
+// such as "c++-lib" or the epoch "1:2.0-1"
+func purlEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte(".-_~", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// cycloneDXFormatter buffers all statuses and writes a CycloneDX 1.4 JSON BOM with a component per package, see
+// https://cyclonedx.org/docs/1.4/json/
+type cycloneDXFormatter struct {
+	w          io.Writer
+	components []cycloneDXComponent
+}
+
+type cycloneDXBOM struct {
+	BOMFormat   string               `json:"bomFormat"`
+	SpecVersion string               `json:"specVersion"`
+	Version     int                  `json:"version"`
+	Metadata    cycloneDXMetadata    `json:"metadata"`
+	Components  []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string          `json:"timestamp"`
+	Tools     []cycloneDXTool `json:"tools"`
+}
+
+type cycloneDXTool struct {
+	Name string `json:"name"`
+}
+
+type cycloneDXComponent struct {
+	Type               string               `json:"type"`
+	BOMRef             string               `json:"bom-ref,omitempty"`
+	Name               string               `json:"name"`
+	Version            string               `json:"version,omitempty"`
+	Purl               string               `json:"purl,omitempty"`
+	ExternalReferences []cycloneDXReference `json:"externalReferences,omitempty"`
+	Pedigree           *cycloneDXPedigree   `json:"pedigree,omitempty"`
+	Properties         []cycloneDXProperty  `json:"properties,omitempty"`
+}
+
+type cycloneDXReference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type cycloneDXPedigree struct {
+	Ancestors []cycloneDXComponent `json:"ancestors"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func (f *cycloneDXFormatter) Status(s *Status) {
+	c := cycloneDXComponent{
+		Type:    "application",
+		BOMRef:  aurPurl(s),
+		Name:    s.Package,
+		Version: s.Version,
+		Purl:    aurPurl(s),
+		Properties: []cycloneDXProperty{
+			{"aur-out-of-date:status", s.Label()},
+		},
+	}
+	if s.URL != "" {
+		c.ExternalReferences = append(c.ExternalReferences, cycloneDXReference{"website", s.URL})
+	}
+	if s.ReleaseURL != "" {
+		c.ExternalReferences = append(c.ExternalReferences, cycloneDXReference{"release-notes", s.ReleaseURL})
+	}
+	if s.Purl != "" {
+		c.Pedigree = &cycloneDXPedigree{[]cycloneDXComponent{{Type: "library", Name: s.Package, Purl: s.Purl}}}
+	}
+	if s.Upstream != "" {
+		c.Properties = append(c.Properties, cycloneDXProperty{"aur-out-of-date:upstream", s.Upstream.String()})
+	}
+	if s.Provider != "" {
+		c.Properties = append(c.Properties, cycloneDXProperty{"aur-out-of-date:provider", s.Provider})
+	}
+	f.components = append(f.components, c)
+}
+
+func (f *cycloneDXFormatter) Finish(statistics *Statistics) {
+	bom := cycloneDXBOM{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.4",
+		Version:     1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: now().UTC().Format(time.RFC3339),
+			Tools:     []cycloneDXTool{{Name: "aur-out-of-date"}},
+		},
+		Components: f.components,
+	}
+	if bom.Components == nil {
+		bom.Components = []cycloneDXComponent{}
+	}
+	writeIndentedJSON(f.w, bom)
+}
+
+// spdxFormatter buffers all statuses and writes an SPDX 2.3 JSON document with a package per AUR package, see
+// https://spdx.github.io/spdx-spec/v2.3/
+type spdxFormatter struct {
+	w        io.Writer
+	packages []spdxPackage
+}
+
+type spdxDocument struct {
+	SPDXVersion       string           `json:"spdxVersion"`
+	DataLicense       string           `json:"dataLicense"`
+	SPDXID            string           `json:"SPDXID"`
+	Name              string           `json:"name"`
+	DocumentNamespace string           `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo `json:"creationInfo"`
+	Packages          []spdxPackage    `json:"packages"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	Homepage         string            `json:"homepage,omitempty"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+	Comment          string            `json:"comment,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+	Comment           string `json:"comment,omitempty"`
+}
+
+// spdxInvalid matches the characters not allowed in SPDX identifiers
+var spdxInvalid = regexp.MustCompile(`[^A-Za-z0-9.-]`)
+
+func (f *spdxFormatter) Status(s *Status) {
+	p := spdxPackage{
+		SPDXID:           "SPDXRef-Package-" + spdxInvalid.ReplaceAllString(s.Package, "-"),
+		Name:             s.Package,
+		VersionInfo:      s.Version,
+		DownloadLocation: "https://aur.archlinux.org/" + s.Package + ".git",
+		Homepage:         s.URL,
+		ExternalRefs:     []spdxExternalRef{{"PACKAGE-MANAGER", "purl", aurPurl(s), ""}},
+		Comment:          fmt.Sprintf("%s: %s", s.Label(), s.Message),
+	}
+	if s.Purl != "" {
+		p.ExternalRefs = append(p.ExternalRefs, spdxExternalRef{"PACKAGE-MANAGER", "purl", s.Purl, "upstream package"})
+	}
+	f.packages = append(f.packages, p)
+}
+
+func (f *spdxFormatter) Finish(statistics *Statistics) {
+	created := now().UTC()
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              "aur-out-of-date",
+		DocumentNamespace: fmt.Sprintf("https://github.com/simon04/aur-out-of-date/spdx/%d", created.UnixNano()),
+		CreationInfo: spdxCreationInfo{
+			Created:  created.Format(time.RFC3339),
+			Creators: []string{"Tool: aur-out-of-date"},
+		},
+		Packages: f.packages,
+	}
+	if doc.Packages == nil {
+		doc.Packages = []spdxPackage{}
+	}
+	writeIndentedJSON(f.w, doc)
+}
+
+// writeIndentedJSON writes v as indented JSON document
+func writeIndentedJSON(w io.Writer, v interface{}) {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
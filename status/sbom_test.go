@@ -0,0 +1,49 @@
+package status
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSBOMFormatters(t *testing.T) {
+	defer func() { now = time.Now }()
+	now = func() time.Time { return time.Date(2021, 3, 16, 12, 0, 0, 0, time.UTC) }
+	for format, expectations := range map[string][]string{
+		"cyclonedx": {
+			`"bomFormat": "CycloneDX"`,
+			`"timestamp": "2021-03-16T12:00:00Z"`,
+			`"purl": "pkg:alpm/aur/python-requests@2.25.0-1"`,
+			`"purl": "pkg:pypi/requests"`,
+			`"name": "aur-out-of-date:upstream",
+          "value": "2.25.1"`,
+			`"name": "c++-lib"`,
+			`"purl": "pkg:alpm/aur/c%2B%2B-lib@1%3A1.0-1"`,
+		},
+		"spdx": {
+			`"spdxVersion": "SPDX-2.3"`,
+			`"SPDXID": "SPDXRef-Package-python-requests"`,
+			`"referenceLocator": "pkg:pypi/requests"`,
+			`"comment": "OUT-OF-DATE: should be updated to 2.25.1"`,
+			`"SPDXID": "SPDXRef-Package-c---lib"`,
+			`"referenceLocator": "pkg:alpm/aur/c%2B%2B-lib@1%3A1.0-1"`,
+		},
+	} {
+		out := bytes.NewBuffer(nil)
+		f, err := NewFormatterWriter(format, out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Status(&Status{Package: "python-requests", Version: "2.25.0-1", URL: "https://requests.readthedocs.io/", Purl: "pkg:pypi/requests",
+			Provider: "pypi", Upstream: "2.25.1", Status: OutOfDate, Message: "should be updated to 2.25.1"})
+		f.Status(&Status{Package: "c++-lib", Version: "1:1.0-1", Status: Unknown, Message: "No release found"})
+		f.Finish(nil)
+		actual := out.String()
+		for _, expected := range expectations {
+			if !strings.Contains(actual, expected) {
+				t.Errorf("Expecting '%s' in '%s'", expected, actual)
+			}
+		}
+	}
+}
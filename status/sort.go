@@ -0,0 +1,162 @@
+package status
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SortKeys lists the supported sort keys
+var SortKeys = []string{"name", "status", "age", "severity"}
+
+// statusOrder ranks the most actionable status first
+var statusOrder = map[StatusType]int{
+	BadSignature:     0,
+	SourceGone:       1,
+	UpstreamGone:     2,
+	ChecksumMismatch: 3,
+	OutOfDate:        4,
+	FlaggedOutOfDate: 5,
+	Unknown:          6,
+	UpToDate:         7,
+}
+
+type lessFunc func(a, b *Status) bool
+
+func byName(a, b *Status) bool {
+	return a.Package < b.Package
+}
+
+func byStatus(a, b *Status) bool {
+	if statusOrder[a.Status] != statusOrder[b.Status] {
+		return statusOrder[a.Status] < statusOrder[b.Status]
+	}
+	return byName(a, b)
+}
+
+// byAge sorts the oldest upstream releases first, packages without release date last
+func byAge(a, b *Status) bool {
+	switch {
+	case a.Released != nil && b.Released != nil && !a.Released.Equal(*b.Released):
+		return a.Released.Before(*b.Released)
+	case a.Released != nil && b.Released == nil:
+		return true
+	case a.Released == nil && b.Released != nil:
+		return false
+	}
+	return byStatus(a, b)
+}
+
+// Bump levels returned by bumpLevel
+const (
+	noBump = iota
+	patchBump
+	minorBump
+	majorBump
+)
+
+// Severities lists the bump levels accepted by ApplyMinSeverity
+var Severities = []string{"patch", "minor", "major"}
+
+var severityLevels = map[string]int{"patch": patchBump, "minor": minorBump, "major": majorBump}
+
+var versionNumber = regexp.MustCompile(`\d+`)
+
+// bumpLevel classifies the update from Version to Upstream by the first differing version number
+func bumpLevel(s *Status) int {
+	version := s.Version
+	if i := strings.Index(version, ":"); i >= 0 {
+		version = version[i+1:]
+	}
+	if i := strings.LastIndex(version, "-"); i >= 0 {
+		version = version[:i]
+	}
+	current := versionNumber.FindAllString(version, -1)
+	latest := versionNumber.FindAllString(s.Upstream.String(), -1)
+	if len(latest) == 0 {
+		return noBump
+	}
+	for i := 0; i < len(current) || i < len(latest); i++ {
+		if i < len(current) && i < len(latest) && strings.TrimLeft(current[i], "0") == strings.TrimLeft(latest[i], "0") {
+			continue
+		}
+		switch i {
+		case 0:
+			return majorBump
+		case 1:
+			return minorBump
+		default:
+			return patchBump
+		}
+	}
+	return noBump
+}
+
+// ApplyMinSeverity reports out-of-date packages as up-to-date if the update is below the given bump level (patch, minor, major),
+// unless the update is high priority
+func (s *Status) ApplyMinSeverity(min string) error {
+	level, ok := severityLevels[min]
+	if !ok {
+		return fmt.Errorf("Unknown severity %s", min)
+	}
+	bump := bumpLevel(s)
+	if s.Status != OutOfDate || s.HighPriority || bump == noBump || bump >= level {
+		return nil
+	}
+	s.Status = UpToDate
+	s.Message = fmt.Sprintf("ignoring %s update to %v (min severity %s)", Severities[bump-1], s.Upstream, min)
+	return nil
+}
+
+// bySeverity sorts the most urgent packages first: by status, high priority updates,
+// major before minor before patch updates, then by age
+func bySeverity(a, b *Status) bool {
+	if statusOrder[a.Status] != statusOrder[b.Status] {
+		return statusOrder[a.Status] < statusOrder[b.Status]
+	}
+	if a.HighPriority != b.HighPriority {
+		return a.HighPriority
+	}
+	if bumpA, bumpB := bumpLevel(a), bumpLevel(b); bumpA != bumpB {
+		return bumpA > bumpB
+	}
+	return byAge(a, b)
+}
+
+// sortFormatter buffers all statuses and passes them sorted to the underlying Formatter
+type sortFormatter struct {
+	Formatter
+	less     lessFunc
+	packages []*Status
+}
+
+// Sort returns a Formatter that sorts the packages by the given key before passing them to f
+func Sort(f Formatter, key string) (Formatter, error) {
+	var less lessFunc
+	switch key {
+	case "name":
+		less = byName
+	case "status":
+		less = byStatus
+	case "age":
+		less = byAge
+	case "severity":
+		less = bySeverity
+	default:
+		return nil, fmt.Errorf("Unknown sort key %s, supported keys: %v", key, SortKeys)
+	}
+	return &sortFormatter{Formatter: f, less: less}, nil
+}
+
+func (f *sortFormatter) Status(s *Status) {
+	f.packages = append(f.packages, s)
+}
+
+func (f *sortFormatter) Finish(statistics *Statistics) {
+	sort.SliceStable(f.packages, func(i, j int) bool { return f.less(f.packages[i], f.packages[j]) })
+	for _, s := range f.packages {
+		f.Formatter.Status(s)
+	}
+	f.Formatter.Finish(statistics)
+}
@@ -0,0 +1,115 @@
+package status
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+func TestSort(t *testing.T) {
+	old := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	recent := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	packages := func() []*Status {
+		return []*Status{
+			{Package: "c", Status: UpToDate},
+			{Package: "b", Version: "1.0-1", Upstream: "2.0", Status: OutOfDate, Released: &recent},
+			{Package: "a", Status: Unknown},
+			{Package: "d", Version: "1.0-1", Upstream: "1.1", Status: OutOfDate, Released: &old},
+		}
+	}
+	for key, expected := range map[string]string{
+		"name":     "a b c d",
+		"status":   "b d a c",
+		"age":      "d b a c",
+		"severity": "b d a c",
+	} {
+		out := bytes.NewBuffer(nil)
+		f, _ := NewFormatterWriter("csv", out)
+		f, err := Sort(f, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range packages() {
+			f.Status(s)
+		}
+		f.Finish(nil)
+		actual := ""
+		for i, line := range bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))[1:] {
+			if i > 0 {
+				actual += " "
+			}
+			actual += string(line[:1])
+		}
+		if actual != expected {
+			t.Errorf("Expecting %s sorted by %s, but got %s", expected, key, actual)
+		}
+	}
+	if _, err := Sort(nil, "foo"); err == nil {
+		t.Error("Expecting an error, but got none")
+	}
+}
+
+func TestBumpLevel(t *testing.T) {
+	for _, test := range []struct {
+		version, upstream string
+		expected          int
+	}{
+		{"1.2.3-1", "2.0.0", majorBump},
+		{"1.2.3-1", "1.3.0", minorBump},
+		{"1:1.2.3-2", "1.2.4", patchBump},
+		{"1.2-1", "1.2.1", patchBump},
+		{"1.02-1", "1.2", noBump},
+		{"1.2.3-1", "", noBump},
+	} {
+		s := &Status{Version: test.version, Upstream: upstream.Version(test.upstream)}
+		if actual := bumpLevel(s); actual != test.expected {
+			t.Errorf("Expecting bump level %d for %s → %s, but got %d", test.expected, test.version, test.upstream, actual)
+		}
+	}
+}
+
+func TestApplyMinSeverity(t *testing.T) {
+	for _, test := range []struct {
+		upstream, min string
+		expected      StatusType
+	}{
+		{"1.2.4", "minor", UpToDate},
+		{"1.3.0", "minor", OutOfDate},
+		{"1.3.0", "major", UpToDate},
+		{"2.0.0", "major", OutOfDate},
+		{"1.2.4", "patch", OutOfDate},
+	} {
+		s := &Status{Version: "1.2.3-1"}
+		s.Compare(upstream.Version(test.upstream))
+		if err := s.ApplyMinSeverity(test.min); err != nil {
+			t.Fatal(err)
+		}
+		if s.Status != test.expected {
+			t.Errorf("Expecting %s for %s with min severity %s, but got %s (%s)", test.expected, test.upstream, test.min, s.Status, s.Message)
+		}
+	}
+	s := &Status{Version: "1.2.3-1"}
+	s.Compare(upstream.Version("1.2.4"))
+	s.ApplyMinSeverity("minor")
+	if expected := "ignoring patch update to 1.2.4 (min severity minor)"; s.Message != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, s.Message)
+	}
+	s = &Status{Version: "1.2.3-1", HighPriority: true}
+	s.Compare(upstream.Version("1.2.4"))
+	if s.ApplyMinSeverity("major"); s.Status != OutOfDate {
+		t.Errorf("Expecting a high priority update to stay out-of-date, but got %s (%s)", s.Status, s.Message)
+	}
+	if err := s.ApplyMinSeverity("huge"); err == nil {
+		t.Error("Expecting an error, but got none")
+	}
+}
+
+func TestSortHighPriority(t *testing.T) {
+	a := &Status{Package: "a", Version: "1.0-1", Upstream: "2.0", Status: OutOfDate}
+	b := &Status{Package: "b", Version: "1.0-1", Upstream: "1.0.1", Status: OutOfDate, HighPriority: true}
+	if !bySeverity(b, a) || bySeverity(a, b) {
+		t.Error("Expecting high priority patch update before major update")
+	}
+}
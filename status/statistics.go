@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/simon04/aur-out-of-date/rfc7464"
 )
@@ -17,6 +19,12 @@ type Statistics struct {
 	FlaggedOutOfDate int    `json:"flagged_out_of_date"`
 	OutOfDate        int    `json:"out_of_date"`
 	Unknown          int    `json:"unknown"`
+	BadSignature     int    `json:"bad_signature,omitempty"`
+	SourceGone       int    `json:"source_gone,omitempty"`
+	UpstreamGone     int    `json:"upstream_gone,omitempty"`
+	ChecksumMismatch int    `json:"checksum_mismatch,omitempty"`
+	// Unmaintained counts the packages marked possibly unmaintained, regardless of their status
+	Unmaintained int `json:"possibly_unmaintained,omitempty"`
 }
 
 // Update the statistics with another status
@@ -30,22 +38,89 @@ func (s *Statistics) Update(status StatusType) {
 		s.OutOfDate++
 	case Unknown:
 		s.Unknown++
+	case BadSignature:
+		s.BadSignature++
+	case SourceGone:
+		s.SourceGone++
+	case UpstreamGone:
+		s.UpstreamGone++
+	case ChecksumMismatch:
+		s.ChecksumMismatch++
 	}
 }
 
+// Failing returns the number of packages requiring an update or other action by the maintainer, besides those flagged out-of-date
+func (s *Statistics) Failing() int {
+	return s.OutOfDate + s.BadSignature + s.SourceGone + s.UpstreamGone + s.ChecksumMismatch
+}
+
+// Total returns the number of packages
+func (s *Statistics) Total() int {
+	return s.UpToDate + s.FlaggedOutOfDate + s.OutOfDate + s.Unknown + s.BadSignature + s.SourceGone + s.UpstreamGone + s.ChecksumMismatch
+}
+
+// Summary returns a single line such as "312 checked: 289 up-to-date, 14 out-of-date, 9 unknown, took 41s"
+func (s *Statistics) Summary(took time.Duration) string {
+	counts := []string{fmt.Sprintf("%d up-to-date", s.UpToDate)}
+	if s.FlaggedOutOfDate > 0 {
+		counts = append(counts, fmt.Sprintf("%d flagged out-of-date", s.FlaggedOutOfDate))
+	}
+	counts = append(counts, fmt.Sprintf("%d out-of-date", s.OutOfDate))
+	if s.BadSignature > 0 {
+		counts = append(counts, fmt.Sprintf("%d bad signature", s.BadSignature))
+	}
+	if s.SourceGone > 0 {
+		counts = append(counts, fmt.Sprintf("%d source gone", s.SourceGone))
+	}
+	if s.UpstreamGone > 0 {
+		counts = append(counts, fmt.Sprintf("%d upstream gone", s.UpstreamGone))
+	}
+	if s.ChecksumMismatch > 0 {
+		counts = append(counts, fmt.Sprintf("%d checksum mismatch", s.ChecksumMismatch))
+	}
+	counts = append(counts, fmt.Sprintf("%d unknown", s.Unknown))
+	if s.Unmaintained > 0 {
+		counts = append(counts, fmt.Sprintf("%d possibly unmaintained", s.Unmaintained))
+	}
+	counts = append(counts, "took "+took.Round(time.Second).String())
+	return fmt.Sprintf("%d checked: %s", s.Total(), strings.Join(counts, ", "))
+}
+
 // Print displays the statistics on the console
 func (s *Statistics) Print() {
-	fmt.Fprintln(statisticsWriter)
-	fmt.Fprintln(statisticsWriter, "STATISTICS")
-	fmt.Fprintf(statisticsWriter, "%s%22s %d \x1b[0m\n", UpToDate.color(), "["+UpToDate+"]", s.UpToDate)
-	fmt.Fprintf(statisticsWriter, "%s%22s %d \x1b[0m\n", FlaggedOutOfDate.color(), "["+FlaggedOutOfDate+"]", s.FlaggedOutOfDate)
-	fmt.Fprintf(statisticsWriter, "%s%22s %d \x1b[0m\n", OutOfDate.color(), "["+OutOfDate+"]", s.OutOfDate)
-	fmt.Fprintf(statisticsWriter, "%s%22s %d \x1b[0m\n", Unknown.color(), "["+Unknown+"]", s.Unknown)
-	fmt.Fprintf(statisticsWriter, "%s%22s %d \x1b[0m\n", Unknown.color(), "[TOTAL]", s.UpToDate+s.FlaggedOutOfDate+s.OutOfDate+s.Unknown)
+	s.Write(statisticsWriter)
+}
+
+// Write writes the statistics in human-readable form to w
+func (s *Statistics) Write(w io.Writer) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "STATISTICS")
+	fmt.Fprintf(w, "%s%22s %d%s\n", UpToDate.color(), "["+UpToDate+"]", s.UpToDate, colorReset())
+	fmt.Fprintf(w, "%s%22s %d%s\n", FlaggedOutOfDate.color(), "["+FlaggedOutOfDate+"]", s.FlaggedOutOfDate, colorReset())
+	fmt.Fprintf(w, "%s%22s %d%s\n", OutOfDate.color(), "["+OutOfDate+"]", s.OutOfDate, colorReset())
+	fmt.Fprintf(w, "%s%22s %d%s\n", Unknown.color(), "["+Unknown+"]", s.Unknown, colorReset())
+	if s.BadSignature > 0 {
+		fmt.Fprintf(w, "%s%22s %d%s\n", BadSignature.color(), "["+BadSignature+"]", s.BadSignature, colorReset())
+	}
+	if s.SourceGone > 0 {
+		fmt.Fprintf(w, "%s%22s %d%s\n", SourceGone.color(), "["+SourceGone+"]", s.SourceGone, colorReset())
+	}
+	if s.UpstreamGone > 0 {
+		fmt.Fprintf(w, "%s%22s %d%s\n", UpstreamGone.color(), "["+UpstreamGone+"]", s.UpstreamGone, colorReset())
+	}
+	if s.ChecksumMismatch > 0 {
+		fmt.Fprintf(w, "%s%22s %d%s\n", ChecksumMismatch.color(), "["+ChecksumMismatch+"]", s.ChecksumMismatch, colorReset())
+	}
+	fmt.Fprintf(w, "%s%22s %d%s\n", StatusType("TOTAL").color(), "[TOTAL]", s.Total(), colorReset())
 }
 
 // PrintJSONTextSequence outputs the statistics as JSON Text Sequences (RFC 7464)
 func (s *Statistics) PrintJSONTextSequence() {
+	s.WriteJSONTextSequence(statisticsWriter)
+}
+
+// WriteJSONTextSequence writes the statistics as JSON Text Sequences (RFC 7464) to w
+func (s *Statistics) WriteJSONTextSequence(w io.Writer) {
 	s.Type = "statistics"
-	rfc7464.NewEncoder(statisticsWriter).Encode(s)
+	rfc7464.NewEncoder(w).Encode(s)
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+	"time"
 )
 
 var stat = Statistics{
@@ -38,3 +39,22 @@ func TestStatisticsJSONOutput(t *testing.T) {
 		t.Errorf("Expecting '%s', but got '%s'", expected, actual)
 	}
 }
+
+func TestStatisticsSummary(t *testing.T) {
+	expected := "17 checked: 2 up-to-date, 3 flagged out-of-date, 5 out-of-date, 7 unknown, took 41s"
+	if actual := stat.Summary(41*time.Second + 300*time.Millisecond); actual != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, actual)
+	}
+	expected = "1 checked: 1 up-to-date, 0 out-of-date, 0 unknown, took 0s"
+	if actual := (&Statistics{UpToDate: 1}).Summary(0); actual != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, actual)
+	}
+	expected = "2 checked: 1 up-to-date, 0 out-of-date, 1 upstream gone, 0 unknown, took 0s"
+	if actual := (&Statistics{UpToDate: 1, UpstreamGone: 1}).Summary(0); actual != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, actual)
+	}
+	expected = "1 checked: 0 up-to-date, 1 out-of-date, 0 unknown, 1 possibly unmaintained, took 0s"
+	if actual := (&Statistics{OutOfDate: 1, Unmaintained: 1}).Summary(0); actual != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, actual)
+	}
+}
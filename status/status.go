@@ -1,17 +1,25 @@
+// Package status compares packaged and upstream versions and formats the results
 package status
 
 import (
 	"fmt"
 	"io"
 	"os"
+	"regexp"
+	"strings"
+	"time"
 
 	pkgbuild "github.com/mikkeloscar/gopkgbuild"
+	"github.com/simon04/aur-out-of-date/distro"
 	"github.com/simon04/aur-out-of-date/rfc7464"
 	"github.com/simon04/aur-out-of-date/upstream"
 )
 
 var statusWriter io.Writer = os.Stdout
 
+// Colors enables ANSI colors in the human-readable output
+var Colors = true
+
 // StatusType represens the package up-to-date state
 type StatusType string
 
@@ -27,18 +35,114 @@ const OutOfDate = StatusType("OUT-OF-DATE")
 // Unknown represents an unknown upstream version
 const Unknown = StatusType("UNKNOWN")
 
+// BadSignature means that the PGP signature of the upstream version could not be verified
+const BadSignature = StatusType("BAD-SIGNATURE")
+
+// SourceGone means that upstream deleted a source of the current version
+const SourceGone = StatusType("SOURCE-GONE")
+
+// UpstreamGone means that the upstream project has been deleted
+const UpstreamGone = StatusType("UPSTREAM-GONE")
+
+// ChecksumMismatch means that a source of the current version no longer matches its checksum, e.g. as upstream re-tagged
+const ChecksumMismatch = StatusType("CHECKSUM-MISMATCH")
+
 // Status holds the packaged and upstream version for a package
 type Status struct {
-	Type             string           `json:"type"`
-	Package          string           `json:"name"`
+	Type       string `json:"type"`
+	Package    string `json:"name"`
+	Maintainer string `json:"maintainer,omitempty"`
+	// PKGBUILD is the path of the local PKGBUILD, if any
+	PKGBUILD         string           `json:"pkgbuild,omitempty"`
 	Message          string           `json:"message"`
 	FlaggedOutOfDate bool             `json:"flagged,omitempty"`
 	Ignored          bool             `json:"ignored,omitempty"`
 	Version          string           `json:"version,omitempty"`
 	Upstream         upstream.Version `json:"upstream,omitempty"`
-	Status           StatusType       `json:"status"`
+	Latest           upstream.Version `json:"latest,omitempty"`
+	CapReason        string           `json:"cap_reason,omitempty"`
+	Provider         string           `json:"provider,omitempty"`
+	URL              string           `json:"url,omitempty"`
+	// Purl is the package URL of the upstream package, e.g. pkg:pypi/requests
+	Purl       string `json:"purl,omitempty"`
+	ReleaseURL string `json:"release_url,omitempty"`
+	// ReleaseNotes is the Markdown body of the upstream release, if known
+	ReleaseNotes string `json:"release_notes,omitempty"`
+	Error        string `json:"error,omitempty"`
+	// Reason categorizes the error, e.g. no-provider, rate-limited, network or gone
+	Reason       string     `json:"reason,omitempty"`
+	Status       StatusType `json:"status"`
+	Released     *time.Time `json:"released,omitempty"`
+	LastModified *time.Time `json:"last_modified,omitempty"`
+	// Unmaintained is set if the AUR package has not been updated for a long time although upstream is active
+	Unmaintained bool `json:"possibly_unmaintained,omitempty"`
+	// Advisories lists the security advisories (CVE identifiers if known) fixed by the upstream version
+	Advisories []string `json:"advisories,omitempty"`
+	// HighPriority is set if the upstream version fixes security advisories
+	HighPriority bool `json:"high_priority,omitempty"`
+	// MovedFrom and MovedTo are the old and the new location if the upstream repository has been renamed or transferred
+	MovedFrom string `json:"moved_from,omitempty"`
+	MovedTo   string `json:"moved_to,omitempty"`
+	// Distros holds the versions other distributions ship, see -compare-distros
+	Distros []distro.Version `json:"distros,omitempty"`
+	// KeyWarnings lists the validpgpkeys which are revoked, expired or expire soon, see -check-keys
+	KeyWarnings []string `json:"key_warnings,omitempty"`
+	// HomepageWarning describes a permanently redirected or dead homepage (url=), see -check-homepage
+	HomepageWarning string `json:"homepage_warning,omitempty"`
+	// Changes summarizes the commits between the packaged and the upstream tag, see -changes
+	Changes []string `json:"changes,omitempty"`
+	// SuggestedEpoch is the epoch needed if the upstream version compares lower due to a versioning scheme change
+	SuggestedEpoch int `json:"suggested_epoch,omitempty"`
+	// Cached is the time the upstream version has been obtained, if served from the cache in offline mode
+	Cached    *time.Time `json:"cached,omitempty"`
+	CheckedAt time.Time  `json:"-"`
+	// Duration is the time taken to determine the upstream version
+	Duration time.Duration `json:"-"`
+	// Components holds the status of further sources mapping to a provider, such as bundled libraries
+	Components []Component `json:"components,omitempty"`
+}
+
+// Component is the status of a further source of a package, up-to-date if the source contains the upstream version
+type Component struct {
+	Source   string           `json:"source"`
+	Provider string           `json:"provider,omitempty"`
+	Upstream upstream.Version `json:"upstream,omitempty"`
+	Status   StatusType       `json:"status"`
+	Message  string           `json:"message"`
+}
+
+// sourceVersion matches the version-looking tokens of a source URL, such as 1.2.3 and 64 in …/v1.2.3/foo-1.2.3-x86_64.tar.gz
+var sourceVersion = regexp.MustCompile(`(?i)\d+(?:\.\d+)*(?:[-.]?(?:alpha|beta|rc|pre|post|dev)\.?\d*)?`)
+
+// containsVersion reports whether a version-looking token of the source equals the version according to vercmp
+func containsVersion(source string, version upstream.Version) bool {
+	expected, err := pkgbuild.NewCompleteVersion(version.String())
+	if err != nil {
+		return false
+	}
+	for _, token := range sourceVersion.FindAllString(source, -1) {
+		if v, err := pkgbuild.NewCompleteVersion(token); err == nil && v.Equal(expected) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewComponent returns the status of the source compared to the upstream version
+func NewComponent(source string, result upstream.Result) Component {
+	c := Component{Source: source, Provider: result.Provider, Upstream: result.Version}
+	if containsVersion(source, result.Version) {
+		c.Status = UpToDate
+		c.Message = fmt.Sprintf("matches upstream version %s", result.Version.String())
+	} else {
+		c.Status = OutOfDate
+		c.Message = fmt.Sprintf("should be updated to %s", result.Version.String())
+	}
+	return c
 }
 
+var now = time.Now
+
 // Compare to upstream version and set message and status accordingly
 func (s *Status) Compare(upstreamVersion upstream.Version) {
 	pkgVersion, err := pkgbuild.NewCompleteVersion(s.Version)
@@ -62,6 +166,10 @@ func (s *Status) Compare(upstreamVersion upstream.Version) {
 		return
 	}
 	s.Upstream = upstreamVersion
+	if upstreamCompleteVersion.Epoch == 0 {
+		// upstream versions have no epoch, compare to pkgver only
+		upstreamCompleteVersion.Epoch = pkgVersion.Epoch
+	}
 
 	newer := upstreamCompleteVersion.Newer(pkgVersion)
 	if s.FlaggedOutOfDate {
@@ -76,13 +184,30 @@ func (s *Status) Compare(upstreamVersion upstream.Version) {
 	} else if upstreamCompleteVersion.Equal(pkgVersion) {
 		s.Status = UpToDate
 		s.Message = fmt.Sprintf("matches upstream version %v", upstreamVersion)
+	} else if s.suggestEpoch(pkgVersion, upstreamVersion) {
+		return
 	} else {
 		s.Status = Unknown
 		s.Message = fmt.Sprintf("upstream version is %v", upstreamVersion)
 	}
 }
 
+// CompareCapped compares to the maximum version instead of the (newer) upstream version
+func (s *Status) CompareCapped(upstreamVersion, maxVersion upstream.Version, reason string) {
+	s.Compare(maxVersion)
+	s.Latest = upstreamVersion
+	s.CapReason = reason
+	s.Message += fmt.Sprintf(" (capped, %v is available", upstreamVersion)
+	if reason != "" {
+		s.Message += ": " + reason
+	}
+	s.Message += ")"
+}
+
 func (status StatusType) color() string {
+	if !Colors {
+		return ""
+	}
 	switch status {
 	case UpToDate:
 		return "\x1b[32m"
@@ -90,19 +215,125 @@ func (status StatusType) color() string {
 		return "\x1b[31m"
 	case OutOfDate:
 		return "\x1b[31m"
+	case BadSignature:
+		return "\x1b[31m"
+	case SourceGone:
+		return "\x1b[31m"
+	case UpstreamGone:
+		return "\x1b[31m"
+	case ChecksumMismatch:
+		return "\x1b[31m"
+	case Unknown:
+		return "\x1b[33m"
 	default:
 		return "\x1b[37m"
 	}
 }
 
+func (status StatusType) glyph() string {
+	switch status {
+	case UpToDate:
+		return "✓"
+	case FlaggedOutOfDate:
+		return "⚑"
+	case OutOfDate:
+		return "✗"
+	default:
+		return "?"
+	}
+}
+
+func colorReset() string {
+	if !Colors {
+		return ""
+	}
+	return " \x1b[0m"
+}
+
+// Label returns the status including the reason of unknown results, e.g. UNKNOWN(rate-limited)
+func (s *Status) Label() string {
+	if s.Status == Unknown && s.Reason != "" {
+		return fmt.Sprintf("%s(%s)", s.Status, s.Reason)
+	}
+	return string(s.Status)
+}
+
 // Print displays the status on the console
 func (s *Status) Print() {
+	s.Write(statusWriter)
+}
+
+// Write writes the status in human-readable form to w
+func (s *Status) Write(w io.Writer) {
 	ansiColor := s.Status.color()
-	fmt.Fprintf(statusWriter, "%s%22s [%s][%s] %s \x1b[0m\n", ansiColor, "["+s.Status+"]", s.Package, s.Version, s.Message)
+	releaseURL := ""
+	if s.Status != UpToDate {
+		releaseURL = s.releaseURLSuffix()
+	}
+	fmt.Fprintf(w, "%s%s%21s [%s][%s] %s%s%s%s\n", ansiColor, s.Status.glyph(), "["+s.Label()+"]", s.Package, s.Version, s.Message, s.Age(), releaseURL, colorReset())
+	for _, c := range s.Components {
+		fmt.Fprintf(w, "%s%s%21s   └ %s %s (%s)%s\n", c.Status.color(), c.Status.glyph(), "["+c.Status+"]", c.Source, c.Message, c.Provider, colorReset())
+	}
+	for _, change := range s.Changes {
+		fmt.Fprintf(w, "%22s   · %s\n", "", change)
+	}
+}
+
+func (s *Status) releaseURLSuffix() string {
+	if s.ReleaseURL == "" {
+		return ""
+	}
+	return " " + s.ReleaseURL
+}
+
+func daysAgo(t time.Time) string {
+	days := int(now().Sub(t).Hours() / 24)
+	switch days {
+	case 0:
+		return "today"
+	case 1:
+		return "1 day ago"
+	default:
+		return fmt.Sprintf("%d days ago", days)
+	}
+}
+
+// Age describes when the upstream version was released and when the AUR package was updated
+func (s *Status) Age() string {
+	var ages []string
+	if s.Released != nil && s.Status != UpToDate {
+		ages = append(ages, "released "+daysAgo(*s.Released))
+	}
+	if s.LastModified != nil && s.Status != UpToDate {
+		ages = append(ages, "AUR updated "+daysAgo(*s.LastModified))
+	}
+	if len(ages) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(ages, ", ") + ")"
+}
+
+// MarkUnmaintained marks the package as possibly unmaintained if the AUR package has not been updated for the given number of days,
+// although upstream released a newer version or released after the last AUR update
+func (s *Status) MarkUnmaintained(days int) {
+	if days <= 0 || s.LastModified == nil || now().Sub(*s.LastModified) < time.Duration(days)*24*time.Hour {
+		return
+	}
+	active := s.Status == OutOfDate || s.Status == FlaggedOutOfDate || s.Released != nil && s.Released.After(*s.LastModified)
+	if !active {
+		return
+	}
+	s.Unmaintained = true
+	s.Message += fmt.Sprintf(" (possibly unmaintained, AUR not updated for %d days)", int(now().Sub(*s.LastModified).Hours()/24))
 }
 
 // PrintJSONTextSequence outputs the status as JSON Text Sequences (RFC 7464)
 func (s *Status) PrintJSONTextSequence() {
+	s.WriteJSONTextSequence(statusWriter)
+}
+
+// WriteJSONTextSequence writes the status as JSON Text Sequences (RFC 7464) to w
+func (s *Status) WriteJSONTextSequence(w io.Writer) {
 	s.Type = "package"
-	rfc7464.NewEncoder(statusWriter).Encode(s)
+	rfc7464.NewEncoder(w).Encode(s)
 }
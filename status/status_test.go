@@ -3,6 +3,7 @@ package status
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	"github.com/simon04/aur-out-of-date/upstream"
 )
@@ -31,13 +32,56 @@ func TestCompare(t *testing.T) {
 	}
 }
 
+func TestCompareCapped(t *testing.T) {
+	s.CompareCapped(upstream.Version("0.37"), upstream.Version("0.35"), "license change")
+	if s.Status != UpToDate {
+		t.Errorf("Expecting status to be %s", UpToDate)
+	}
+	expected := "matches upstream version 0.35 (capped, 0.37 is available: license change)"
+	if s.Message != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, s.Message)
+	}
+	s.Latest = ""
+	s.CapReason = ""
+}
+
 func TestStatusOutput(t *testing.T) {
 	s.Compare(upstream.Version("0.35"))
 	out := bytes.NewBuffer(nil)
 	statusWriter = out
 	s.Print()
 	actual := string(out.Bytes())
-	expected := "\x1b[32m          [UP-TO-DATE] [spectre-meltdown-checker][0.35-1] matches upstream version 0.35 \x1b[0m\n"
+	expected := "\x1b[32m✓         [UP-TO-DATE] [spectre-meltdown-checker][0.35-1] matches upstream version 0.35 \x1b[0m\n"
+	if actual != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, actual)
+	}
+}
+
+func TestAge(t *testing.T) {
+	now = func() time.Time { return time.Date(2021, 3, 16, 12, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+	released := time.Date(2021, 1, 28, 10, 0, 0, 0, time.UTC)
+	lastModified := time.Date(2020, 3, 16, 12, 0, 0, 0, time.UTC)
+	s := Status{Status: OutOfDate, Released: &released, LastModified: &lastModified}
+	expected := " (released 47 days ago, AUR updated 365 days ago)"
+	if s.Age() != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, s.Age())
+	}
+	s.Status = UpToDate
+	if s.Age() != "" {
+		t.Errorf("Expecting no age for up-to-date packages, but got '%s'", s.Age())
+	}
+}
+
+func TestStatusOutputNoColors(t *testing.T) {
+	Colors = false
+	defer func() { Colors = true }()
+	s.Compare(upstream.Version("0.37"))
+	out := bytes.NewBuffer(nil)
+	statusWriter = out
+	s.Print()
+	actual := string(out.Bytes())
+	expected := "✗        [OUT-OF-DATE] [spectre-meltdown-checker][0.35-1] should be updated to 0.37\n"
 	if actual != expected {
 		t.Errorf("Expecting '%s', but got '%s'", expected, actual)
 	}
@@ -54,3 +98,68 @@ func TestStatusJSONOutput(t *testing.T) {
 		t.Errorf("Expecting '%s', but got '%s'", expected, actual)
 	}
 }
+
+func TestComponents(t *testing.T) {
+	Colors = false
+	defer func() { Colors = true }()
+	c := Status{Package: "foo", Version: "1.0-1", Status: UpToDate, Message: "matches upstream version 1.0", Components: []Component{
+		NewComponent("https://github.com/bar/lib/archive/v0.9.tar.gz", upstream.Result{Provider: "github", Version: "0.9"}),
+		NewComponent("https://github.com/baz/lib/archive/v2.0.tar.gz", upstream.Result{Provider: "github", Version: "2.1"}),
+		NewComponent("https://github.com/qux/lib/archive/v1.20.tar.gz", upstream.Result{Provider: "github", Version: "1.2"}),
+	}}
+	out := bytes.NewBuffer(nil)
+	c.Write(out)
+	expected := "✓         [UP-TO-DATE] [foo][1.0-1] matches upstream version 1.0\n" +
+		"✓         [UP-TO-DATE]   └ https://github.com/bar/lib/archive/v0.9.tar.gz matches upstream version 0.9 (github)\n" +
+		"✗        [OUT-OF-DATE]   └ https://github.com/baz/lib/archive/v2.0.tar.gz should be updated to 2.1 (github)\n" +
+		"✗        [OUT-OF-DATE]   └ https://github.com/qux/lib/archive/v1.20.tar.gz should be updated to 1.2 (github)\n"
+	if out.String() != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, out.String())
+	}
+}
+
+func TestMarkUnmaintained(t *testing.T) {
+	now = func() time.Time { return time.Date(2021, 3, 16, 12, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+	lastModified := time.Date(2020, 3, 16, 12, 0, 0, 0, time.UTC)
+	released := time.Date(2021, 1, 28, 10, 0, 0, 0, time.UTC)
+	s := Status{Status: OutOfDate, Message: "should be updated to 1.1", LastModified: &lastModified}
+	s.MarkUnmaintained(400)
+	if s.Unmaintained {
+		t.Error("Expecting package updated 365 days ago not to be unmaintained after 400 days")
+	}
+	s.MarkUnmaintained(180)
+	expected := "should be updated to 1.1 (possibly unmaintained, AUR not updated for 365 days)"
+	if !s.Unmaintained || s.Message != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, s.Message)
+	}
+	s = Status{Status: UpToDate, LastModified: &lastModified}
+	s.MarkUnmaintained(180)
+	if s.Unmaintained {
+		t.Error("Expecting up-to-date package without upstream activity not to be unmaintained")
+	}
+	s.Released = &released
+	s.MarkUnmaintained(180)
+	if !s.Unmaintained {
+		t.Error("Expecting package with upstream release after the last AUR update to be unmaintained")
+	}
+}
+
+func TestLabel(t *testing.T) {
+	Colors = false
+	defer func() { Colors = true }()
+	u := &Status{Package: "foo", Version: "1.0-1", Status: Unknown, Reason: "rate-limited", Message: "API rate limit exceeded"}
+	if label := u.Label(); label != "UNKNOWN(rate-limited)" {
+		t.Errorf("Expecting UNKNOWN(rate-limited), but got %s", label)
+	}
+	out := bytes.NewBuffer(nil)
+	u.Write(out)
+	expected := "?[UNKNOWN(rate-limited)] [foo][1.0-1] API rate limit exceeded\n"
+	if actual := out.String(); actual != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, actual)
+	}
+	gone := &Status{Status: UpstreamGone, Reason: "gone"}
+	if label := gone.Label(); label != "UPSTREAM-GONE" {
+		t.Errorf("Expecting UPSTREAM-GONE, but got %s", label)
+	}
+}
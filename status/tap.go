@@ -0,0 +1,40 @@
+package status
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// tapFormatter writes the Test Anything Protocol, see https://testanything.org/tap-version-13-specification.html
+type tapFormatter struct {
+	w io.Writer
+	n int
+}
+
+func newTAPFormatter(w io.Writer) *tapFormatter {
+	fmt.Fprintln(w, "TAP version 13")
+	return &tapFormatter{w: w}
+}
+
+func (f *tapFormatter) Status(s *Status) {
+	f.n++
+	message := strings.Replace(s.Message, "\n", " ", -1)
+	switch {
+	case s.Status == OutOfDate || s.Status == FlaggedOutOfDate:
+		fmt.Fprintf(f.w, "not ok %d - %s (%s < %s)\n", f.n, s.Package, s.Version, s.Upstream.String())
+		if s.ReleaseURL != "" {
+			fmt.Fprintf(f.w, "  ---\n  release_url: %s\n  ...\n", s.ReleaseURL)
+		}
+	case s.Status == BadSignature || s.Status == SourceGone || s.Status == UpstreamGone || s.Status == ChecksumMismatch:
+		fmt.Fprintf(f.w, "not ok %d - %s (%s)\n", f.n, s.Package, message)
+	case s.Status == Unknown:
+		fmt.Fprintf(f.w, "ok %d - %s # SKIP %s\n", f.n, s.Package, message)
+	default:
+		fmt.Fprintf(f.w, "ok %d - %s\n", f.n, s.Package)
+	}
+}
+
+func (f *tapFormatter) Finish(statistics *Statistics) {
+	fmt.Fprintf(f.w, "1..%d\n", f.n)
+}
@@ -0,0 +1,23 @@
+package status
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTAPFormatter(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	f, _ := NewFormatterWriter("tap", out)
+	f.Status(&Status{Package: "foo", Version: "1.0-1", Upstream: "1.0", Status: UpToDate})
+	f.Status(&Status{Package: "bar", Version: "1.2-1", Upstream: "1.3", Status: OutOfDate})
+	f.Status(&Status{Package: "baz", Version: "1.0-1", Status: Unknown, Message: "No release found"})
+	f.Finish(nil)
+	expected := "TAP version 13\n" +
+		"ok 1 - foo\n" +
+		"not ok 2 - bar (1.2-1 < 1.3)\n" +
+		"ok 3 - baz # SKIP No release found\n" +
+		"1..3\n"
+	if out.String() != expected {
+		t.Errorf("Expecting '%s', but got '%s'", expected, out.String())
+	}
+}
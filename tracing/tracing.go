@@ -0,0 +1,213 @@
+// Package tracing records spans of check runs and exports them using OTLP/HTTP (JSON encoding)
+//
+// All methods are no-ops on a nil Tracer or Span, so that instrumented code does not need to check whether tracing is enabled.
+package tracing
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kind is the OTLP span kind
+type Kind int
+
+const (
+	// Internal spans represent internal operations, such as checking a package
+	Internal Kind = 1
+	// Client spans represent outgoing requests
+	Client Kind = 3
+)
+
+// Tracer collects ended spans until they are exported
+type Tracer struct {
+	service string
+	mutex   sync.Mutex
+	spans   []*Span
+	// Client is used to export spans, it must not be traced itself
+	Client *http.Client
+}
+
+// New returns a Tracer for the given service name
+func New(service string) *Tracer {
+	return &Tracer{service: service, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Span is a timed operation within a trace
+type Span struct {
+	tracer     *Tracer
+	traceID    [16]byte
+	id         [8]byte
+	parent     [8]byte
+	name       string
+	kind       Kind
+	start      time.Time
+	end        time.Time
+	mutex      sync.Mutex
+	attributes [][2]string
+	err        string
+}
+
+// Start starts a span as child of parent, or as root span of a new trace if parent is nil
+func (t *Tracer) Start(parent *Span, name string, kind Kind) *Span {
+	if t == nil {
+		return nil
+	}
+	s := &Span{tracer: t, name: name, kind: kind, start: time.Now()}
+	if parent != nil {
+		s.traceID = parent.traceID
+		s.parent = parent.id
+	} else {
+		rand.Read(s.traceID[:])
+	}
+	rand.Read(s.id[:])
+	return s
+}
+
+// SetAttribute records a string attribute
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.attributes = append(s.attributes, [2]string{key, value})
+}
+
+// SetError marks the span as failed
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.err = err.Error()
+}
+
+// End ends the span, handing it to the tracer for export
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	s.end = time.Now()
+	s.mutex.Unlock()
+	s.tracer.mutex.Lock()
+	defer s.tracer.mutex.Unlock()
+	s.tracer.spans = append(s.tracer.spans, s)
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID      string          `json:"traceId"`
+	SpanID       string          `json:"spanId"`
+	ParentSpanID string          `json:"parentSpanId,omitempty"`
+	Name         string          `json:"name"`
+	Kind         Kind            `json:"kind"`
+	Start        string          `json:"startTimeUnixNano"`
+	End          string          `json:"endTimeUnixNano"`
+	Attributes   []otlpAttribute `json:"attributes,omitempty"`
+	Status       otlpStatus      `json:"status"`
+}
+
+type otlpScopeSpans struct {
+	Scope struct {
+		Name string `json:"name"`
+	} `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResourceSpans struct {
+	Resource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	} `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+func (s *Span) otlp() otlpSpan {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	span := otlpSpan{
+		TraceID: hex.EncodeToString(s.traceID[:]),
+		SpanID:  hex.EncodeToString(s.id[:]),
+		Name:    s.name,
+		Kind:    s.kind,
+		Start:   strconv.FormatInt(s.start.UnixNano(), 10),
+		End:     strconv.FormatInt(s.end.UnixNano(), 10),
+		Status:  otlpStatus{Code: 1},
+	}
+	if s.parent != [8]byte{} {
+		span.ParentSpanID = hex.EncodeToString(s.parent[:])
+	}
+	for _, a := range s.attributes {
+		span.Attributes = append(span.Attributes, otlpAttribute{a[0], otlpValue{a[1]}})
+	}
+	if s.err != "" {
+		span.Status = otlpStatus{Code: 2, Message: s.err}
+	}
+	return span
+}
+
+func (t *Tracer) request(spans []*Span) otlpRequest {
+	var scope otlpScopeSpans
+	scope.Scope.Name = t.service
+	for _, s := range spans {
+		scope.Spans = append(scope.Spans, s.otlp())
+	}
+	var resource otlpResourceSpans
+	resource.Resource.Attributes = []otlpAttribute{{"service.name", otlpValue{t.service}}}
+	resource.ScopeSpans = []otlpScopeSpans{scope}
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{resource}}
+}
+
+// Export sends all ended spans to the OTLP/HTTP endpoint (such as http://localhost:4318) and discards them
+func (t *Tracer) Export(endpoint string) error {
+	if t == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	spans := t.spans
+	t.spans = nil
+	t.mutex.Unlock()
+	if len(spans) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(t.request(spans))
+	if err != nil {
+		return err
+	}
+	url := strings.TrimSuffix(endpoint, "/") + "/v1/traces"
+	resp, err := t.Client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("Failed to export %d spans to %s: %w", len(spans), url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("Failed to export %d spans to %s: %s", len(spans), url, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	var received otlpRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Unexpected request %s %s", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	tracer := New("aur-out-of-date")
+	root := tracer.Start(nil, "run", Internal)
+	child := tracer.Start(root, "GET", Client)
+	child.SetAttribute("http.url", "https://aur.archlinux.org/rpc")
+	child.SetError(errors.New("timeout"))
+	child.End()
+	root.End()
+	if err := tracer.Export(server.URL + "/"); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := received.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("Expecting 2 spans, but got %v", spans)
+	}
+	if spans[0].Name != "GET" || spans[0].TraceID != spans[1].TraceID || spans[0].ParentSpanID != spans[1].SpanID || spans[1].ParentSpanID != "" {
+		t.Errorf("Unexpected span hierarchy %v", spans)
+	}
+	if spans[0].Status.Code != 2 || spans[0].Status.Message != "timeout" || spans[0].Attributes[0].Value.StringValue != "https://aur.archlinux.org/rpc" {
+		t.Errorf("Unexpected span %v", spans[0])
+	}
+	if len(tracer.spans) != 0 {
+		t.Error("Expecting exported spans to be discarded")
+	}
+}
+
+func TestNil(t *testing.T) {
+	var tracer *Tracer
+	span := tracer.Start(nil, "run", Internal)
+	span.SetAttribute("key", "value")
+	span.End()
+	if err := tracer.Export("http://localhost:4318"); err != nil || span != nil {
+		t.Errorf("Expecting no-op for nil tracer, but got %v %v", span, err)
+	}
+}
@@ -0,0 +1,118 @@
+package transport
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Credentials authenticate requests to a host using a token (sent as bearer token) or basic auth
+type Credentials struct {
+	Token    string `json:"token,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// auth sets the Authorization header of requests according to the credentials of their host
+type auth struct {
+	next        http.RoundTripper
+	credentials map[string]Credentials
+}
+
+// Auth returns a RoundTripper authenticating requests lacking an Authorization header using the credentials
+// of the request host (host:port or host)
+func Auth(next http.RoundTripper, credentials map[string]Credentials) http.RoundTripper {
+	if len(credentials) == 0 {
+		return next
+	}
+	return &auth{next: next, credentials: credentials}
+}
+
+// RoundTrip implements http.RoundTripper
+func (a *auth) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Authorization") != "" {
+		return a.next.RoundTrip(req)
+	}
+	c, ok := a.credentials[req.URL.Host]
+	if !ok {
+		c, ok = a.credentials[req.URL.Hostname()]
+	}
+	if !ok {
+		return a.next.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	} else {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+	return a.next.RoundTrip(req)
+}
+
+// NetrcFile returns the path of the netrc file, $NETRC or ~/.netrc
+func NetrcFile() string {
+	if file := os.Getenv("NETRC"); file != "" {
+		return file
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".netrc")
+}
+
+// ReadNetrc reads the login and password per machine of the netrc file.
+// The default entry is ignored, so that credentials are never sent to arbitrary hosts.
+func ReadNetrc(file string) (map[string]Credentials, error) {
+	credentials := map[string]Credentials{}
+	content, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return credentials, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed to read %s: %w", file, err)
+	}
+	var machine string
+	var c Credentials
+	flush := func() {
+		if machine != "" {
+			credentials[machine] = c
+		}
+		machine, c = "", Credentials{}
+	}
+	lines := strings.Split(string(content), "\n")
+	for i := 0; i < len(lines); i++ {
+		fields := strings.Fields(lines[i])
+		for j := 0; j < len(fields); j++ {
+			next := func() string {
+				if j+1 < len(fields) {
+					j++
+					return fields[j]
+				}
+				return ""
+			}
+			switch fields[j] {
+			case "machine":
+				flush()
+				machine = next()
+			case "default":
+				flush()
+			case "login":
+				c.Username = next()
+			case "password":
+				c.Password = next()
+			case "macdef":
+				// skip the macro definition up to the next empty line
+				flush()
+				for i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" {
+					i++
+				}
+				j = len(fields)
+			}
+		}
+	}
+	flush()
+	return credentials, nil
+}
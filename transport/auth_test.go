@@ -0,0 +1,81 @@
+package transport
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAuth(t *testing.T) {
+	var authorization string
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		authorization = req.Header.Get("Authorization")
+		return &http.Response{StatusCode: http.StatusOK, Request: req}, nil
+	})
+	rt := Auth(next, map[string]Credentials{
+		"gitlab.example.com":   {Token: "glpat-secret"},
+		"git.example.org:3000": {Username: "jane", Password: "s3cret"},
+	})
+	for url, expected := range map[string]string{
+		"https://gitlab.example.com/api/v4/projects/foo%2Fbar/repository/tags": "Bearer glpat-secret",
+		"http://git.example.org:3000/api/v1/repos/foo/bar/releases":            "Basic amFuZTpzM2NyZXQ=",
+		"https://github.com/foo/bar":                                           "",
+	} {
+		req, _ := http.NewRequest("GET", url, nil)
+		if _, err := rt.RoundTrip(req); err != nil {
+			t.Fatal(err)
+		}
+		if authorization != expected {
+			t.Errorf("Expecting Authorization '%s' for %s, but got '%s'", expected, url, authorization)
+		}
+	}
+
+	req, _ := http.NewRequest("GET", "https://gitlab.example.com/", nil)
+	req.Header.Set("Authorization", "Bearer other")
+	rt.RoundTrip(req)
+	if authorization != "Bearer other" {
+		t.Errorf("Expecting Authorization set by the provider to be kept, but got '%s'", authorization)
+	}
+}
+
+func TestReadNetrc(t *testing.T) {
+	dir, err := ioutil.TempDir("", "netrc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, ".netrc")
+	content := `machine git.example.org
+  login jane
+  password s3cret
+macdef init
+cd /pub
+
+machine gitlab.example.com login bot password token123
+default login anonymous password guest
+`
+	if err := ioutil.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	credentials, err := ReadNetrc(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]Credentials{
+		"git.example.org":    {Username: "jane", Password: "s3cret"},
+		"gitlab.example.com": {Username: "bot", Password: "token123"},
+	}
+	if len(credentials) != len(expected) {
+		t.Errorf("Expecting %v, but got %v", expected, credentials)
+	}
+	for host, c := range expected {
+		if credentials[host] != c {
+			t.Errorf("Expecting %v for %s, but got %v", c, host, credentials[host])
+		}
+	}
+	if credentials, err := ReadNetrc(filepath.Join(dir, "missing")); err != nil || len(credentials) != 0 {
+		t.Errorf("Expecting no credentials for a missing file, but got %v (%v)", credentials, err)
+	}
+}
@@ -0,0 +1,32 @@
+package transport
+
+import (
+	"net/http"
+
+	"github.com/gregjones/httpcache"
+	"github.com/simon04/aur-out-of-date/cache"
+)
+
+// Cache returns a RoundTripper caching responses of next in c (RFC 7234), see cache.Dir and cache.Redis.
+// Stale responses are revalidated using their ETag/Last-Modified validators, and 304 responses are served from cache.
+func Cache(next http.RoundTripper, c cache.Cache) http.RoundTripper {
+	t := httpcache.NewTransport(c)
+	t.Transport = next
+	return t
+}
+
+// GetUncached issues a GET request using http.DefaultClient (with its transports and timeout) and "Cache-Control: no-store",
+// so that downloaded sources do not end up in the HTTP cache
+func GetUncached(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return DoUncached(req)
+}
+
+// DoUncached sends the request like GetUncached, for responses that have to be up-to-date such as those of APIs
+func DoUncached(req *http.Request) (*http.Response, error) {
+	req.Header.Set("Cache-Control", "no-store")
+	return http.DefaultClient.Do(req)
+}
@@ -0,0 +1,78 @@
+package transport
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/cache"
+)
+
+func TestCacheRevalidation(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("1.0"))
+	}))
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "aur-out-of-date")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	client := &http.Client{Transport: Cache(http.DefaultTransport, cache.Dir(dir))}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(body) != "1.0" {
+			t.Errorf("Expecting body 1.0, but got %s", body)
+		}
+		if i == 1 && resp.Header.Get("X-From-Cache") != "1" {
+			t.Error("Expecting response from cache")
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Expecting 2 requests, but got %d", requests)
+	}
+}
+
+func TestGetUncached(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Cache-Control", "max-age=3600")
+		w.Write([]byte("foo"))
+	}))
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "aur-out-of-date")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defaultClient := http.DefaultClient
+	http.DefaultClient = &http.Client{Transport: Cache(http.DefaultTransport, cache.Dir(dir))}
+	defer func() { http.DefaultClient = defaultClient }()
+	for i := 0; i < 2; i++ {
+		resp, err := GetUncached(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if requests != 2 {
+		t.Errorf("Expecting 2 requests bypassing the cache, but got %d", requests)
+	}
+}
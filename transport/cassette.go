@@ -0,0 +1,147 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sync"
+)
+
+// Cassette holds recorded HTTP interactions, e.g. as test fixture of a provider
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request along with its response; request headers are not recorded and credentials in the URL are redacted
+// to keep tokens out of fixtures
+type Interaction struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body"`
+}
+
+// LoadCassette reads the cassette from the JSON file
+func LoadCassette(filename string) (*Cassette, error) {
+	input, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var c Cassette
+	if err := json.Unmarshal(input, &c); err != nil {
+		return nil, fmt.Errorf("Failed to read cassette %s: %w", filename, err)
+	}
+	return &c, nil
+}
+
+// Save writes the cassette as JSON file
+func (c *Cassette) Save(filename string) error {
+	output, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, append(output, '\n'), 0644)
+}
+
+// recorder records all interactions of next in a cassette
+type recorder struct {
+	next     http.RoundTripper
+	mutex    sync.Mutex
+	cassette *Cassette
+}
+
+// Record returns a RoundTripper appending all interactions of next to the cassette
+func Record(next http.RoundTripper, c *Cassette) http.RoundTripper {
+	return &recorder{next: next, cassette: c}
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	i := Interaction{Method: req.Method, URL: redactURL(req.URL), Status: resp.StatusCode, Headers: map[string]string{}, Body: string(body)}
+	for _, key := range []string{"Content-Type", "Date", "ETag", "Last-Modified", "Link", "Location", "Retry-After"} {
+		if value := resp.Header.Get(key); value != "" {
+			i.Headers[key] = value
+		}
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, i)
+	return resp, nil
+}
+
+// sameURL compares the recorded URL with the redacted URL of a request, normalizing the query of older recordings
+func sameURL(recorded, requested string) bool {
+	if recorded == requested {
+		return true
+	}
+	u, err := url.Parse(recorded)
+	return err == nil && redactURL(u) == requested
+}
+
+// replayer serves the recorded responses of a cassette
+type replayer struct {
+	mutex    sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// Replay returns a RoundTripper serving the recorded responses of the cassette without network access.
+// Requests are matched by method and redacted URL, repeated requests are served the recorded responses in order (the last one repeatedly).
+func Replay(c *Cassette) http.RoundTripper {
+	return &replayer{cassette: c, used: make([]bool, len(c.Interactions))}
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	url := redactURL(req.URL)
+	match := -1
+	for i, interaction := range r.cassette.Interactions {
+		if interaction.Method != req.Method || !sameURL(interaction.URL, url) {
+			continue
+		}
+		match = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("No recorded interaction for %s %s", req.Method, url)
+	}
+	r.used[match] = true
+	i := r.cassette.Interactions[match]
+	resp := &http.Response{
+		Status:     fmt.Sprintf("%d %s", i.Status, http.StatusText(i.Status)),
+		StatusCode: i.Status,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(i.Body))),
+		Request:    req,
+	}
+	for key, value := range i.Headers {
+		resp.Header.Set(key, value)
+	}
+	return resp, nil
+}
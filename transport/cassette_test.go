@@ -0,0 +1,73 @@
+package transport
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestCassette(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Write([]byte(`{"version": "1.0"}`))
+	}))
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "aur-out-of-date")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := path.Join(dir, "testdata", "cassette.json")
+
+	c := &Cassette{}
+	client := &http.Client{Transport: Record(http.DefaultTransport, c)}
+	req, _ := http.NewRequest("GET", server.URL+"/version?private_token=secret", nil)
+	req.Header.Set("Authorization", "token secret")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != `{"version": "1.0"}` {
+		t.Errorf("Expecting the recorded body to be passed on, but got %s", body)
+	}
+	if err := c.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = LoadCassette(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Interactions[0].URL != server.URL+"/version?private_token=REDACTED" {
+		t.Errorf("Expecting the token to be redacted, but got %s", c.Interactions[0].URL)
+	}
+	if c.Interactions[0].Headers["Set-Cookie"] != "" {
+		t.Error("Expecting Set-Cookie not to be recorded")
+	}
+	client = &http.Client{Transport: Replay(c)}
+	for i := 0; i < 2; i++ {
+		resp, err = client.Get(server.URL + "/version?private_token=other")
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(body) != `{"version": "1.0"}` || resp.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expecting the recorded response, but got %s", body)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expecting 1 request to the server, but got %d", requests)
+	}
+	if _, err := client.Get(server.URL + "/other"); err == nil {
+		t.Error("Expecting an error for an unrecorded request")
+	}
+}
@@ -0,0 +1,53 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// contextual cancels requests once a shared context is done
+type contextual struct {
+	next http.RoundTripper
+	ctx  func() context.Context
+}
+
+// WithContext returns a RoundTripper cancelling requests once the context returned by ctx is done,
+// so that requests of libraries unaware of contexts are aborted as well
+func WithContext(next http.RoundTripper, ctx func() context.Context) http.RoundTripper {
+	return &contextual{next: next, ctx: ctx}
+}
+
+// cancelBody cancels the request context once the response body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// RoundTrip implements http.RoundTripper
+func (c *contextual) RoundTrip(req *http.Request) (*http.Response, error) {
+	shared := c.ctx()
+	if err := shared.Err(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(req.Context())
+	go func() {
+		select {
+		case <-shared.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	resp, err := c.next.RoundTrip(req.WithContext(ctx))
+	if err != nil || resp.Body == nil {
+		cancel()
+		return resp, err
+	}
+	resp.Body = &cancelBody{resp.Body, cancel}
+	return resp, nil
+}
@@ -0,0 +1,40 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestWithContext(t *testing.T) {
+	calls := 0
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: 200}, nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	rt := WithContext(next, func() context.Context { return ctx })
+
+	req, _ := http.NewRequest("GET", "https://aur.archlinux.org/rpc", nil)
+	if _, err := rt.RoundTrip(req); err != nil || calls != 1 {
+		t.Fatalf("Expecting 1 call without error, but got %d calls (%v)", calls, err)
+	}
+	cancel()
+	if _, err := rt.RoundTrip(req); err != context.Canceled || calls != 1 {
+		t.Errorf("Expecting context.Canceled without call, but got %d calls (%v)", calls, err)
+	}
+}
+
+func TestWithContextCancelsPending(t *testing.T) {
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	rt := WithContext(next, func() context.Context { return ctx })
+	go cancel()
+	req, _ := http.NewRequest("GET", "https://aur.archlinux.org/rpc", nil)
+	if _, err := rt.RoundTrip(req); err != context.Canceled {
+		t.Errorf("Expecting context.Canceled, but got %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package transport
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// lookupIPAddr is replaced in tests
+var lookupIPAddr = net.DefaultResolver.LookupIPAddr
+
+// dnsCache resolves host names once per TTL, as large runs send hundreds of requests to the same few hosts
+type dnsCache struct {
+	mutex   sync.Mutex
+	ttl     time.Duration
+	entries map[string]dnsEntry
+	dialer  *net.Dialer
+}
+
+type dnsEntry struct {
+	addrs   []net.IPAddr
+	expires time.Time
+}
+
+// CacheDNS lets t cache successful DNS lookups for the given duration, disabled for 0
+func CacheDNS(t *http.Transport, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	c := &dnsCache{
+		ttl:     ttl,
+		entries: map[string]dnsEntry{},
+		dialer:  &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second},
+	}
+	t.DialContext = c.dial
+}
+
+// lookup returns the cached addresses of the host, resolving it if missing or expired
+func (c *dnsCache) lookup(ctx context.Context, host string) ([]net.IPAddr, error) {
+	c.mutex.Lock()
+	entry, ok := c.entries[host]
+	c.mutex.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.addrs, nil
+	}
+	addrs, err := lookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	c.mutex.Lock()
+	c.entries[host] = dnsEntry{addrs, time.Now().Add(c.ttl)}
+	c.mutex.Unlock()
+	return addrs, nil
+}
+
+// fallbackDelay is the time after which the next address is dialed in parallel if the previous one has not connected yet,
+// as recommended by Happy Eyeballs (RFC 8305)
+var fallbackDelay = 300 * time.Millisecond
+
+type dialResult struct {
+	conn net.Conn
+	err  error
+}
+
+// dial connects to the first reachable address of the host, starting to dial the next address
+// (alternating between IPv6 and IPv4) after fallbackDelay or as soon as the previous one failed
+func (c *dnsCache) dial(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || net.ParseIP(host) != nil {
+		return c.dialer.DialContext(ctx, network, address)
+	}
+	addrs, err := c.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []string
+	for _, addr := range interleave(addrs) {
+		if (network == "tcp4" && addr.IP.To4() == nil) || (network == "tcp6" && addr.IP.To4() != nil) {
+			continue
+		}
+		candidates = append(candidates, net.JoinHostPort(addr.String(), port))
+	}
+	if len(candidates) == 0 {
+		return nil, &net.AddrError{Err: "no suitable address found", Addr: host}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make(chan dialResult, len(candidates))
+	next, pending := 0, 0
+	start := func() {
+		go func(address string) {
+			conn, err := c.dialer.DialContext(ctx, network, address)
+			results <- dialResult{conn, err}
+		}(candidates[next])
+		next++
+		pending++
+	}
+	start()
+	for pending > 0 {
+		var fallback <-chan time.Time
+		if next < len(candidates) {
+			timer := time.NewTimer(fallbackDelay)
+			defer timer.Stop()
+			fallback = timer.C
+		}
+		select {
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				go closeConns(results, pending)
+				return r.conn, nil
+			}
+			err = r.err
+			if next < len(candidates) {
+				start()
+			}
+		case <-fallback:
+			start()
+		}
+	}
+	return nil, err
+}
+
+// closeConns closes the connections of the dials which lost the race
+func closeConns(results <-chan dialResult, pending int) {
+	for i := 0; i < pending; i++ {
+		if r := <-results; r.conn != nil {
+			r.conn.Close()
+		}
+	}
+}
+
+// interleave alternates the addresses between the families, starting with the family of the first address
+func interleave(addrs []net.IPAddr) []net.IPAddr {
+	var first, second []net.IPAddr
+	for _, addr := range addrs {
+		if (addr.IP.To4() == nil) == (addrs[0].IP.To4() == nil) {
+			first = append(first, addr)
+		} else {
+			second = append(second, addr)
+		}
+	}
+	r := make([]net.IPAddr, 0, len(addrs))
+	for i := 0; i < len(first) || i < len(second); i++ {
+		if i < len(first) {
+			r = append(r, first[i])
+		}
+		if i < len(second) {
+			r = append(r, second[i])
+		}
+	}
+	return r
+}
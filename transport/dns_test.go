@@ -0,0 +1,74 @@
+package transport
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestCacheDNS(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	lookups := 0
+	lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		lookups++
+		return []net.IPAddr{{IP: net.ParseIP("127.0.0.1")}}, nil
+	}
+	defer func() { lookupIPAddr = net.DefaultResolver.LookupIPAddr }()
+
+	u, _ := url.Parse(server.URL)
+	base := &http.Transport{DisableKeepAlives: true}
+	CacheDNS(base, time.Minute)
+	for i := 0; i < 3; i++ {
+		resp, err := base.RoundTrip(mustRequest("http://example.test:" + u.Port() + "/"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if lookups != 1 {
+		t.Errorf("Expecting 1 lookup, but got %d", lookups)
+	}
+}
+
+func TestCacheDNSFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	// 192.0.2.1 (TEST-NET-1) does not respond, so dialing it only ends with the dialer timeout
+	lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		return []net.IPAddr{{IP: net.ParseIP("192.0.2.1")}, {IP: net.ParseIP("127.0.0.1")}}, nil
+	}
+	defer func() { lookupIPAddr = net.DefaultResolver.LookupIPAddr }()
+
+	u, _ := url.Parse(server.URL)
+	base := &http.Transport{DisableKeepAlives: true}
+	CacheDNS(base, time.Minute)
+	started := time.Now()
+	resp, err := base.RoundTrip(mustRequest("http://example.test:" + u.Port() + "/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("Expecting the fallback address to be dialed after %s, but took %s", fallbackDelay, elapsed)
+	}
+}
+
+func TestInterleave(t *testing.T) {
+	addrs := []net.IPAddr{{IP: net.ParseIP("::1")}, {IP: net.ParseIP("::2")}, {IP: net.ParseIP("127.0.0.1")}}
+	if r := interleave(addrs); r[0].String() != "::1" || r[1].String() != "127.0.0.1" || r[2].String() != "::2" {
+		t.Errorf("Expecting alternating families, but got %v", r)
+	}
+}
+
+func mustRequest(url string) *http.Request {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		panic(err)
+	}
+	return req
+}
@@ -0,0 +1,149 @@
+package transport
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redacted replaces credentials in dumps
+const redacted = "REDACTED"
+
+// secretHeaders lists the headers carrying credentials, in addition to those containing "token" or "key"
+var secretHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// secretParameter matches query parameters carrying credentials
+var secretParameter = regexp.MustCompile(`(?i)token|key|secret|password|signature`)
+
+// secretPath matches credentials in URL paths, i.e. Telegram bot tokens
+var secretPath = regexp.MustCompile(`/bot[^/]+/`)
+
+// secretField matches JSON string fields carrying credentials
+var secretField = regexp.MustCompile(`(?i)("[^"]*(?:token|secret|password)[^"]*"\s*:\s*)"[^"]*"`)
+
+// dumper writes requests and responses to w
+type dumper struct {
+	next   http.RoundTripper
+	w      io.Writer
+	bodies bool
+	mutex  sync.Mutex
+}
+
+// Dump returns a RoundTripper writing the headers (and bodies if bodies is set) of all requests and responses to w, redacting credentials
+func Dump(next http.RoundTripper, w io.Writer, bodies bool) http.RoundTripper {
+	return &dumper{next: next, w: w, bodies: bodies}
+}
+
+// RoundTrip implements http.RoundTripper
+func (d *dumper) RoundTrip(req *http.Request) (*http.Response, error) {
+	var dump bytes.Buffer
+	fmt.Fprintf(&dump, "> %s %s\n", req.Method, redactURL(req.URL))
+	writeHeaders(&dump, "> ", req.Header)
+	if d.bodies && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			writeBody(&dump, "> ", body)
+		}
+	}
+	start := time.Now()
+	resp, err := d.next.RoundTrip(req)
+	duration := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		fmt.Fprintf(&dump, "< failed after %s: %v\n", duration, err)
+	} else {
+		cached := ""
+		if resp.Header.Get("X-From-Cache") == "1" {
+			cached = ", cached"
+		}
+		fmt.Fprintf(&dump, "< %s (%s%s)\n", resp.Status, duration, cached)
+		writeHeaders(&dump, "< ", resp.Header)
+		if d.bodies {
+			body, readErr := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+			writeBody(&dump, "< ", ioutil.NopCloser(bytes.NewReader(body)))
+			if readErr != nil {
+				fmt.Fprintf(&dump, "< failed to read body: %v\n", readErr)
+			}
+		}
+	}
+	dump.WriteString("\n")
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.w.Write(dump.Bytes())
+	return resp, err
+}
+
+// writeHeaders writes the sorted headers, redacting credentials
+func writeHeaders(w io.Writer, prefix string, header http.Header) {
+	var keys []string
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range header[key] {
+			if isSecretHeader(key) {
+				value = redacted
+			}
+			fmt.Fprintf(w, "%s%s: %s\n", prefix, key, value)
+		}
+	}
+}
+
+func isSecretHeader(key string) bool {
+	for _, h := range secretHeaders {
+		if strings.EqualFold(key, h) {
+			return true
+		}
+	}
+	key = strings.ToLower(key)
+	return strings.Contains(key, "token") || strings.Contains(key, "key")
+}
+
+// writeBody writes the body separated by an empty line, redacting credentials in JSON fields
+func writeBody(w io.Writer, prefix string, body io.ReadCloser) {
+	defer body.Close()
+	content, err := ioutil.ReadAll(body)
+	if err != nil || len(content) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%s\n", strings.TrimSpace(prefix))
+	for _, line := range strings.Split(strings.TrimRight(redactBody(string(content)), "\n"), "\n") {
+		fmt.Fprintf(w, "%s%s\n", prefix, line)
+	}
+}
+
+// redactURL returns the URL with passwords, credentials in query parameters and Telegram bot tokens redacted
+func redactURL(u *url.URL) string {
+	r := *u
+	if r.User != nil {
+		if _, ok := r.User.Password(); ok {
+			r.User = url.UserPassword(r.User.Username(), redacted)
+		}
+	}
+	if r.RawQuery != "" {
+		query := r.Query()
+		for key := range query {
+			if secretParameter.MatchString(key) {
+				query.Set(key, redacted)
+			}
+		}
+		r.RawQuery = query.Encode()
+	}
+	r.Path = secretPath.ReplaceAllString(r.Path, "/bot"+redacted+"/")
+	r.RawPath = ""
+	return r.String()
+}
+
+// redactBody redacts credentials in JSON string fields such as "access_token"
+func redactBody(body string) string {
+	return secretField.ReplaceAllString(body, `$1"`+redacted+`"`)
+}
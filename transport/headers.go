@@ -0,0 +1,34 @@
+package transport
+
+import "net/http"
+
+// headers sets extra request headers per host
+type headers struct {
+	next    http.RoundTripper
+	headers map[string]http.Header
+}
+
+// Headers returns a RoundTripper setting the headers configured for the request host (host:port or host),
+// overriding headers set by providers such as Accept
+func Headers(next http.RoundTripper, hosts map[string]http.Header) http.RoundTripper {
+	if len(hosts) == 0 {
+		return next
+	}
+	return &headers{next: next, headers: hosts}
+}
+
+// RoundTrip implements http.RoundTripper
+func (h *headers) RoundTrip(req *http.Request) (*http.Response, error) {
+	header, ok := h.headers[req.URL.Host]
+	if !ok {
+		header, ok = h.headers[req.URL.Hostname()]
+	}
+	if !ok {
+		return h.next.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	for key, values := range header {
+		req.Header[http.CanonicalHeaderKey(key)] = values
+	}
+	return h.next.RoundTrip(req)
+}
@@ -0,0 +1,33 @@
+package transport
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestHeaders(t *testing.T) {
+	var header http.Header
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		header = req.Header
+		return &http.Response{StatusCode: http.StatusOK, Request: req}, nil
+	})
+	rt := Headers(next, map[string]http.Header{
+		"downloads.example.com": {"X-Api-Key": {"secret"}, "accept": {"application/json"}},
+	})
+	req, _ := http.NewRequest("GET", "https://downloads.example.com/versions", nil)
+	req.Header.Set("Accept", "text/html")
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+	if header.Get("X-Api-Key") != "secret" || header.Get("Accept") != "application/json" {
+		t.Errorf("Expecting configured headers, but got %v", header)
+	}
+	if req.Header.Get("Accept") != "text/html" {
+		t.Errorf("Expecting the original request to be unchanged, but got %v", req.Header)
+	}
+	req, _ = http.NewRequest("GET", "https://example.org/", nil)
+	rt.RoundTrip(req)
+	if header.Get("X-Api-Key") != "" {
+		t.Errorf("Expecting no headers for other hosts, but got %v", header)
+	}
+}
@@ -0,0 +1,46 @@
+// Package transport provides http.RoundTripper middleware used for all HTTP requests
+package transport
+
+import (
+	"net/http"
+	"sync"
+)
+
+// hostLimiter limits the number of concurrent requests per host
+type hostLimiter struct {
+	next  http.RoundTripper
+	limit int
+	mutex sync.Mutex
+	hosts map[string]chan struct{}
+}
+
+// PerHostLimit returns a RoundTripper allowing at most limit concurrent requests per host
+func PerHostLimit(next http.RoundTripper, limit int) http.RoundTripper {
+	if limit < 1 {
+		return next
+	}
+	return &hostLimiter{next: next, limit: limit, hosts: map[string]chan struct{}{}}
+}
+
+func (l *hostLimiter) semaphore(host string) chan struct{} {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	s, ok := l.hosts[host]
+	if !ok {
+		s = make(chan struct{}, l.limit)
+		l.hosts[host] = s
+	}
+	return s
+}
+
+// RoundTrip implements http.RoundTripper
+func (l *hostLimiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	s := l.semaphore(req.URL.Host)
+	select {
+	case s <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	defer func() { <-s }()
+	return l.next.RoundTrip(req)
+}
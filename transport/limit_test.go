@@ -0,0 +1,45 @@
+package transport
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestPerHostLimit(t *testing.T) {
+	var current, max int32
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		n := atomic.AddInt32(&current, 1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&current, -1)
+		return &http.Response{StatusCode: 200}, nil
+	})
+	rt := PerHostLimit(next, 2)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest("GET", "https://api.github.com/", nil)
+			rt.RoundTrip(req)
+		}()
+	}
+	wg.Wait()
+	if max != 2 {
+		t.Errorf("Expecting at most 2 concurrent requests, but got %d", max)
+	}
+}
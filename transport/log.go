@@ -0,0 +1,33 @@
+package transport
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/logging"
+)
+
+// logger logs requests and responses at debug level
+type logger struct {
+	next http.RoundTripper
+}
+
+// Log returns a RoundTripper logging requests and responses (status, duration, whether served from cache)
+func Log(next http.RoundTripper) http.RoundTripper {
+	return &logger{next: next}
+}
+
+// RoundTrip implements http.RoundTripper
+func (l *logger) RoundTrip(req *http.Request) (*http.Response, error) {
+	url := req.URL.String()
+	logging.Log(logging.Debug, "HTTP request", "method", req.Method, "url", url)
+	start := time.Now()
+	resp, err := l.next.RoundTrip(req)
+	duration := time.Since(start).Round(time.Millisecond).String()
+	if err != nil {
+		logging.Log(logging.Debug, "HTTP request failed", "url", url, "err", err, "duration", duration)
+		return resp, err
+	}
+	logging.Log(logging.Debug, "HTTP response", "url", url, "status", resp.StatusCode, "cached", resp.Header.Get("X-From-Cache") == "1", "duration", duration)
+	return resp, nil
+}
@@ -0,0 +1,39 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrOffline is returned for requests which would require network access in offline mode
+var ErrOffline = errors.New("Network access disabled in offline mode")
+
+type offline struct{}
+
+// Offline returns a RoundTripper failing all requests using ErrOffline
+func Offline() http.RoundTripper {
+	return offline{}
+}
+
+// RoundTrip implements http.RoundTripper
+func (offline) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, ErrOffline
+}
+
+// onlyIfCached requests responses from cache regardless of their age
+type onlyIfCached struct {
+	next http.RoundTripper
+}
+
+// OnlyIfCached returns a RoundTripper adding "Cache-Control: only-if-cached" to all requests,
+// so that Cache serves stale responses and never sends the request
+func OnlyIfCached(next http.RoundTripper) http.RoundTripper {
+	return &onlyIfCached{next: next}
+}
+
+// RoundTrip implements http.RoundTripper
+func (o *onlyIfCached) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Cache-Control", "only-if-cached")
+	return o.next.RoundTrip(req)
+}
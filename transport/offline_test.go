@@ -0,0 +1,51 @@
+package transport
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/cache"
+)
+
+func TestOffline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("1.0"))
+	}))
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "aur-out-of-date")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	online := &http.Client{Transport: Cache(http.DefaultTransport, cache.Dir(dir))}
+	resp, err := online.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	offline := &http.Client{Transport: OnlyIfCached(Cache(Offline(), cache.Dir(dir)))}
+	resp, err = offline.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "1.0" || resp.Header.Get("X-From-Cache") != "1" {
+		t.Errorf("Expecting cached body 1.0, but got %s", body)
+	}
+	resp, err = offline.Get(server.URL + "/uncached")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusGatewayTimeout {
+		t.Errorf("Expecting 504 for uncached response, but got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,37 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// MaxIdleConnsPerHost is the number of keep-alive connections per host, as concurrent checks hit the same few hosts
+var MaxIdleConnsPerHost = 16
+
+// Base returns a clone of http.DefaultTransport using the given proxy URL (http, https, socks5, socks5h),
+// or the proxy from HTTP_PROXY/HTTPS_PROXY/NO_PROXY if proxy is empty.
+// The transport is shared by all requests, reusing connections and attempting HTTP/2 even with a custom dialer or CA.
+func Base(proxy string) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.ForceAttemptHTTP2 = true
+	t.MaxIdleConns = 100
+	t.MaxIdleConnsPerHost = MaxIdleConnsPerHost
+	t.IdleConnTimeout = 90 * time.Second
+	if proxy == "" {
+		t.Proxy = http.ProxyFromEnvironment
+		return t, nil
+	}
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse proxy %s: %w", proxy, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("Unsupported proxy scheme %s", u.Scheme)
+	}
+	t.Proxy = http.ProxyURL(u)
+	return t, nil
+}
@@ -0,0 +1,26 @@
+package transport
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestBase(t *testing.T) {
+	for _, proxy := range []string{"http://proxy:3128", "socks5://127.0.0.1:1080"} {
+		rt, err := Base(proxy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, _ := http.NewRequest("GET", "https://api.github.com/", nil)
+		u, err := rt.Proxy(req)
+		if err != nil || u.String() != proxy {
+			t.Errorf("Expecting proxy %s, but got %v (%v)", proxy, u, err)
+		}
+	}
+	if rt, _ := Base(""); !rt.ForceAttemptHTTP2 || rt.MaxIdleConnsPerHost != MaxIdleConnsPerHost {
+		t.Errorf("Expecting HTTP/2 and %d idle connections per host, but got %v and %d", MaxIdleConnsPerHost, rt.ForceAttemptHTTP2, rt.MaxIdleConnsPerHost)
+	}
+	if _, err := Base("ftp://proxy"); err == nil {
+		t.Error("Expecting an error for unsupported proxy scheme")
+	}
+}
@@ -0,0 +1,109 @@
+package transport
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// bucket is a token bucket refilled at a constant rate
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter limits the request rate per host using token buckets
+type rateLimiter struct {
+	next    http.RoundTripper
+	rates   map[string]float64
+	mutex   sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+// RateLimit returns a RoundTripper sending at most rates[host] requests per second to host (with a burst of the same size),
+// the rate of the host * applies to all hosts not listed
+func RateLimit(next http.RoundTripper, rates map[string]float64) http.RoundTripper {
+	if len(rates) == 0 {
+		return next
+	}
+	return &rateLimiter{next: next, rates: rates, buckets: map[string]*bucket{}, now: time.Now}
+}
+
+// ParseRates parses comma-separated host=rate pairs, such as "aur.archlinux.org=1,api.github.com=5/s,*=60/m"
+func ParseRates(s string) (map[string]float64, error) {
+	rates := map[string]float64{}
+	for _, pair := range strings.Split(s, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		i := strings.Index(pair, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("Failed to parse rate limit %s: expecting host=rate", pair)
+		}
+		rate, err := ParseRate(pair[i+1:])
+		if err != nil {
+			return nil, err
+		}
+		rates[pair[:i]] = rate
+	}
+	return rates, nil
+}
+
+// ParseRate parses a rate in requests per second, optionally given per second, minute or hour, such as 5, 5/s, 60/m or 1000/h
+func ParseRate(s string) (float64, error) {
+	value, per := s, 1.0
+	for suffix, seconds := range map[string]float64{"/s": 1, "/m": 60, "/h": 3600} {
+		if strings.HasSuffix(s, suffix) {
+			value, per = strings.TrimSuffix(s, suffix), seconds
+		}
+	}
+	rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to parse rate limit %s: %w", s, err)
+	}
+	return rate / per, nil
+}
+
+// reserve takes a token for host and returns the delay until it is available
+func (l *rateLimiter) reserve(host string) time.Duration {
+	rate, ok := l.rates[host]
+	if !ok {
+		rate = l.rates["*"]
+	}
+	if rate <= 0 {
+		return 0
+	}
+	burst := math.Max(1, rate)
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	now := l.now()
+	b, ok := l.buckets[host]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[host] = b
+	}
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / rate * float64(time.Second))
+}
+
+// RoundTrip implements http.RoundTripper
+func (l *rateLimiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	if d := l.reserve(req.URL.Hostname()); d > 0 {
+		select {
+		case <-time.After(d):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+	return l.next.RoundTrip(req)
+}
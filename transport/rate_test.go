@@ -0,0 +1,53 @@
+package transport
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := RateLimit(nil, map[string]float64{"aur.archlinux.org": 1, "api.github.com": 2}).(*rateLimiter)
+	l.now = func() time.Time { return now }
+
+	expected := []time.Duration{0, time.Second, 2 * time.Second}
+	for i, e := range expected {
+		if d := l.reserve("aur.archlinux.org"); d != e {
+			t.Errorf("Expecting delay %v for request %d, but got %v", e, i, d)
+		}
+	}
+	expected = []time.Duration{0, 0, 500 * time.Millisecond}
+	for i, e := range expected {
+		if d := l.reserve("api.github.com"); d != e {
+			t.Errorf("Expecting delay %v for GitHub request %d, but got %v", e, i, d)
+		}
+	}
+	if d := l.reserve("example.org"); d != 0 {
+		t.Errorf("Expecting no delay for unlimited host, but got %v", d)
+	}
+	l.rates["*"] = 1
+	if d := l.reserve("gitlab.com") + l.reserve("gitlab.com"); d != time.Second {
+		t.Errorf("Expecting the default rate for unlisted hosts, but got delay %v", d)
+	}
+	now = now.Add(10 * time.Second)
+	if d := l.reserve("aur.archlinux.org"); d != 0 {
+		t.Errorf("Expecting no delay after refill, but got %v", d)
+	}
+}
+
+func TestParseRates(t *testing.T) {
+	rates, err := ParseRates("aur.archlinux.org=1, api.github.com=0.5, gitlab.com=2/s, *=60/m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := map[string]float64{"aur.archlinux.org": 1, "api.github.com": 0.5, "gitlab.com": 2, "*": 1}; !reflect.DeepEqual(rates, expected) {
+		t.Errorf("Expecting %v, but got %v", expected, rates)
+	}
+	if _, err := ParseRates("aur.archlinux.org"); err == nil {
+		t.Error("Expecting an error for missing rate")
+	}
+	if _, err := ParseRates("aur.archlinux.org=1/d"); err == nil {
+		t.Error("Expecting an error for unknown unit")
+	}
+}
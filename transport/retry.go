@@ -0,0 +1,90 @@
+package transport
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/logging"
+)
+
+// maxRetryAfter caps the delay requested by a Retry-After header
+const maxRetryAfter = time.Minute
+
+// retrier retries idempotent requests on network errors, 429 and 5xx responses
+type retrier struct {
+	next    http.RoundTripper
+	retries int
+	backoff time.Duration
+}
+
+// Retry returns a RoundTripper retrying failed GET/HEAD requests up to retries times,
+// waiting for Retry-After or an exponential backoff starting at backoff (with jitter)
+func Retry(next http.RoundTripper, retries int, backoff time.Duration) http.RoundTripper {
+	if retries < 1 {
+		return next
+	}
+	return &retrier{next: next, retries: retries, backoff: backoff}
+}
+
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode == http.StatusBadGateway ||
+		resp.StatusCode == http.StatusServiceUnavailable ||
+		resp.StatusCode == http.StatusGatewayTimeout ||
+		resp.StatusCode == http.StatusInternalServerError
+}
+
+// delay returns the time to wait before the given attempt (starting at 0)
+func (r *retrier) delay(resp *http.Response, attempt int) time.Duration {
+	if resp != nil {
+		if after := retryAfter(resp.Header.Get("Retry-After")); after > 0 {
+			return after
+		}
+	}
+	d := r.backoff << uint(attempt)
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// retryAfter parses the Retry-After header given in seconds or as HTTP date
+func retryAfter(value string) time.Duration {
+	var d time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		d = time.Duration(seconds) * time.Second
+	} else if t, err := http.ParseTime(value); err == nil {
+		d = time.Until(t)
+	}
+	if d > maxRetryAfter {
+		d = maxRetryAfter
+	}
+	return d
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *retrier) RoundTrip(req *http.Request) (*http.Response, error) {
+	if (req.Method != "GET" && req.Method != "HEAD") || req.Body != nil && req.Body != http.NoBody {
+		return r.next.RoundTrip(req)
+	}
+	for attempt := 0; ; attempt++ {
+		resp, err := r.next.RoundTrip(req)
+		if attempt == r.retries || !retryable(resp, err) {
+			return resp, err
+		}
+		d := r.delay(resp, attempt)
+		if err != nil {
+			logging.Log(logging.Info, "Retrying HTTP request", "url", req.URL.String(), "err", err, "delay", d.String())
+		} else {
+			logging.Log(logging.Info, "Retrying HTTP request", "url", req.URL.String(), "status", resp.StatusCode, "delay", d.String())
+			resp.Body.Close()
+		}
+		select {
+		case <-time.After(d):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch requests {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: Retry(http.DefaultTransport, 2, time.Millisecond)}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || requests != 3 {
+		t.Errorf("Expecting 200 after 3 requests, but got %d after %d", resp.StatusCode, requests)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: Retry(http.DefaultTransport, 1, time.Millisecond)}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadGateway || requests != 2 {
+		t.Errorf("Expecting 502 after 2 requests, but got %d after %d", resp.StatusCode, requests)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	if d := retryAfter("2"); d != 2*time.Second {
+		t.Errorf("Expecting 2s, but got %v", d)
+	}
+	if d := retryAfter("3600"); d != maxRetryAfter {
+		t.Errorf("Expecting %v, but got %v", maxRetryAfter, d)
+	}
+	if d := retryAfter(""); d != 0 {
+		t.Errorf("Expecting 0, but got %v", d)
+	}
+}
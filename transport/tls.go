@@ -0,0 +1,61 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// AddCA trusts the PEM encoded certificates in caFile in addition to the system certificates
+func AddCA(t *http.Transport, caFile string) error {
+	pem, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return fmt.Errorf("Failed to read CA file %s: %w", caFile, err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return fmt.Errorf("Failed to read CA file %s: no PEM certificates found", caFile)
+	}
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+	t.TLSClientConfig.RootCAs = pool
+	return nil
+}
+
+// insecureHosts sends requests to the given hosts using a transport skipping TLS certificate verification
+type insecureHosts struct {
+	secure   http.RoundTripper
+	insecure http.RoundTripper
+	hosts    map[string]bool
+}
+
+// Insecure returns a RoundTripper skipping TLS certificate verification for the given hosts only
+func Insecure(t *http.Transport, hosts ...string) http.RoundTripper {
+	if len(hosts) == 0 {
+		return t
+	}
+	insecure := t.Clone()
+	if insecure.TLSClientConfig == nil {
+		insecure.TLSClientConfig = &tls.Config{}
+	}
+	insecure.TLSClientConfig.InsecureSkipVerify = true
+	i := &insecureHosts{secure: t, insecure: insecure, hosts: map[string]bool{}}
+	for _, host := range hosts {
+		i.hosts[host] = true
+	}
+	return i
+}
+
+// RoundTrip implements http.RoundTripper
+func (i *insecureHosts) RoundTrip(req *http.Request) (*http.Response, error) {
+	if i.hosts[req.URL.Hostname()] {
+		return i.insecure.RoundTrip(req)
+	}
+	return i.secure.RoundTrip(req)
+}
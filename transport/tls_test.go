@@ -0,0 +1,53 @@
+package transport
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestAddCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "transport")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	caFile := path.Join(dir, "ca.pem")
+	if err := ioutil.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	if _, err := (&http.Transport{}).RoundTrip(req); err == nil {
+		t.Fatal("Expecting an error for an unknown CA")
+	}
+	rt := &http.Transport{}
+	if err := AddCA(rt, caFile); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Errorf("Expecting the added CA to be trusted, but got %v", err)
+	}
+	if err := AddCA(rt, path.Join(dir, "missing.pem")); err == nil {
+		t.Error("Expecting an error for a missing CA file")
+	}
+}
+
+func TestInsecure(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	if _, err := Insecure(&http.Transport{}, "git.example.org").RoundTrip(req); err == nil {
+		t.Error("Expecting an error for a host not skipping verification")
+	}
+	if _, err := Insecure(&http.Transport{}, req.URL.Hostname()).RoundTrip(req); err != nil {
+		t.Errorf("Expecting verification to be skipped, but got %v", err)
+	}
+}
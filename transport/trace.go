@@ -0,0 +1,51 @@
+package transport
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/simon04/aur-out-of-date/tracing"
+)
+
+// tracer records a client span per request
+type tracer struct {
+	next   http.RoundTripper
+	tracer *tracing.Tracer
+	parent func() *tracing.Span
+}
+
+// Trace returns a RoundTripper recording a client span per request as child of the span returned by parent
+func Trace(next http.RoundTripper, t *tracing.Tracer, parent func() *tracing.Span) http.RoundTripper {
+	if t == nil {
+		return next
+	}
+	return &tracer{next: next, tracer: t, parent: parent}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *tracer) RoundTrip(req *http.Request) (*http.Response, error) {
+	span := t.tracer.Start(t.parent(), "HTTP "+req.Method, tracing.Client)
+	defer span.End()
+	span.SetAttribute("http.method", req.Method)
+	span.SetAttribute("http.url", req.URL.String())
+	span.SetAttribute("net.peer.name", req.URL.Hostname())
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		span.SetError(err)
+		return resp, err
+	}
+	span.SetAttribute("http.status_code", strconv.Itoa(resp.StatusCode))
+	span.SetAttribute("http.cached", strconv.FormatBool(resp.Header.Get("X-From-Cache") == "1"))
+	if resp.StatusCode >= 400 {
+		span.SetError(&statusError{resp.Status})
+	}
+	return resp, nil
+}
+
+type statusError struct {
+	status string
+}
+
+func (e *statusError) Error() string {
+	return e.status
+}
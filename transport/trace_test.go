@@ -0,0 +1,24 @@
+package transport
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/tracing"
+)
+
+func TestTrace(t *testing.T) {
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 404, Status: "404 Not Found", Header: http.Header{}}, nil
+	})
+	if rt := Trace(next, nil, nil); rt == nil {
+		t.Fatal("Expecting next without tracer")
+	}
+	tracer := tracing.New("test")
+	root := tracer.Start(nil, "run", tracing.Internal)
+	rt := Trace(next, tracer, func() *tracing.Span { return root })
+	req, _ := http.NewRequest("GET", "https://api.github.com/repos/foo/bar", nil)
+	if resp, err := rt.RoundTrip(req); err != nil || resp.StatusCode != 404 {
+		t.Errorf("Expecting 404 response, but got %v %v", resp, err)
+	}
+}
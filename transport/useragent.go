@@ -0,0 +1,52 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strings"
+)
+
+// Version is the version of aur-out-of-date sent in the User-Agent,
+// set using -ldflags "-X github.com/simon04/aur-out-of-date/transport.Version=…" or obtained from the module build info
+var Version = moduleVersion()
+
+// DefaultUserAgent identifies aur-out-of-date to upstream hosts
+var DefaultUserAgent = UserAgentWithContact("")
+
+// moduleVersion returns the version of the main module if built using go install/go get, "dev" otherwise
+func moduleVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return strings.TrimPrefix(info.Main.Version, "v")
+	}
+	return "dev"
+}
+
+// UserAgentWithContact returns the User-Agent "aur-out-of-date/<version> (+<repository>; <contact>)", omitting an empty contact
+func UserAgentWithContact(contact string) string {
+	comment := "+https://github.com/simon04/aur-out-of-date"
+	if contact = strings.TrimSpace(contact); contact != "" {
+		comment += "; " + contact
+	}
+	return fmt.Sprintf("aur-out-of-date/%s (%s)", Version, comment)
+}
+
+// userAgent sets the User-Agent header of requests lacking one
+type userAgent struct {
+	next      http.RoundTripper
+	userAgent string
+}
+
+// UserAgent returns a RoundTripper setting the User-Agent header to ua unless already set
+func UserAgent(next http.RoundTripper, ua string) http.RoundTripper {
+	return &userAgent{next: next, userAgent: ua}
+}
+
+// RoundTrip implements http.RoundTripper
+func (u *userAgent) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", u.userAgent)
+	}
+	return u.next.RoundTrip(req)
+}
@@ -0,0 +1,37 @@
+package transport
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestUserAgent(t *testing.T) {
+	var agents []string
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		agents = append(agents, req.Header.Get("User-Agent"))
+		return &http.Response{StatusCode: 200, Header: http.Header{}}, nil
+	})
+	rt := Log(UserAgent(next, DefaultUserAgent))
+
+	req, _ := http.NewRequest("GET", "https://api.github.com/", nil)
+	rt.RoundTrip(req)
+	if req.Header.Get("User-Agent") != "" {
+		t.Error("Expecting the original request to be left untouched")
+	}
+	req.Header.Set("User-Agent", "custom")
+	rt.RoundTrip(req)
+	if len(agents) != 2 || agents[0] != DefaultUserAgent || agents[1] != "custom" {
+		t.Errorf("Unexpected User-Agent headers %v", agents)
+	}
+}
+
+func TestUserAgentWithContact(t *testing.T) {
+	defer func(version string) { Version = version }(Version)
+	Version = "3.2.0"
+	if ua := UserAgentWithContact(""); ua != "aur-out-of-date/3.2.0 (+https://github.com/simon04/aur-out-of-date)" {
+		t.Errorf("Unexpected User-Agent %q", ua)
+	}
+	if ua := UserAgentWithContact(" jane@example.com "); ua != "aur-out-of-date/3.2.0 (+https://github.com/simon04/aur-out-of-date; jane@example.com)" {
+		t.Errorf("Unexpected User-Agent %q", ua)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/simon04/aur-out-of-date/action"
+	"github.com/simon04/aur-out-of-date/config"
+	"github.com/simon04/aur-out-of-date/logging"
+	"github.com/simon04/aur-out-of-date/pkg"
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+// triageItem is a checked package along with its status
+type triageItem struct {
+	pkg    pkg.Pkg
+	status status.Status
+}
+
+// triageItems collects the packages to triage, i.e., not up-to-date, during the run of aur-out-of-date triage
+var triageItems []triageItem
+
+// upstreamOf returns the upstream version of the status, ? if unknown
+func upstreamOf(s status.Status) string {
+	if s.Upstream == "" {
+		return "?"
+	}
+	return s.Upstream.String()
+}
+
+// printDetails prints the details of the package
+func printDetails(w io.Writer, i int, item triageItem) {
+	s := item.status
+	fmt.Fprintf(w, "\n[%d/%d] %s %s → %s (%s)\n", i+1, len(triageItems), s.Package, s.Version, upstreamOf(s), s.Status)
+	fmt.Fprintf(w, "  %s\n", s.Message)
+	details := [][2]string{
+		{"Provider", s.Provider},
+		{"Upstream", s.URL},
+		{"Release notes", s.ReleaseURL},
+	}
+	if s.Error != s.Message {
+		details = append(details, [2]string{"Error", s.Error})
+	}
+	for _, field := range details {
+		if field[1] != "" {
+			fmt.Fprintf(w, "  %-14s %s\n", field[0]+":", field[1])
+		}
+	}
+	if s.Released != nil {
+		fmt.Fprintf(w, "  %-14s %s\n", "Released:", s.Released.Format("2006-01-02"))
+	}
+}
+
+// triage walks through the packages which are not up-to-date, offering to flag, ignore or bump each of them
+func triage(w io.Writer) {
+	if len(triageItems) == 0 {
+		fmt.Fprintln(w, "Nothing to triage, all packages are up-to-date.")
+		return
+	}
+	const help = "[n]ext, [p]revious, [f]lag on AUR, [i]gnore until the next version, [b]ump PKGBUILD, [o]pen release notes, [l]ist, [q]uit"
+	fmt.Fprintln(w, "\n"+help)
+	for i := 0; i < len(triageItems); {
+		item := triageItems[i]
+		printDetails(w, i, item)
+		fmt.Fprint(w, "> ")
+		var command string
+		if _, err := fmt.Scanln(&command); err != nil && err.Error() != "unexpected newline" {
+			return
+		}
+		switch strings.ToLower(command) {
+		case "", "n":
+			i++
+		case "p":
+			if i > 0 {
+				i--
+			}
+		case "f":
+			action.FlagOnAur(item.pkg, item.status.Upstream)
+		case "i":
+			rule := config.IgnoreRule{Operator: "<=", Version: item.status.Upstream}
+			if item.status.Upstream == "" {
+				rule = config.IgnoreRule{}
+			}
+			if err := config.AppendIgnoreRule(commandline.ignoreFile, item.pkg.Name(), rule); err != nil {
+				logging.Errorf("Failed to update ignore file: %v", err)
+				continue
+			}
+			conf.IgnoreRules[item.pkg.Name()] = append(conf.IgnoreRules[item.pkg.Name()], rule)
+			fmt.Fprintf(w, "Ignoring %s in %s\n", item.pkg.Name(), commandline.ignoreFile)
+			i++
+		case "b":
+			if item.pkg.LocalPKGBUILD() == "" {
+				fmt.Fprintln(w, "Bumping requires a local PKGBUILD (-local)")
+			} else if item.status.Upstream != "" && action.UpdatePKGBUILD(item.pkg, item.status.Upstream) && commandline.push {
+				action.PublishPKGBUILD(item.pkg, item.status.Upstream)
+			}
+		case "o":
+			url := item.status.ReleaseURL
+			if url == "" {
+				url = item.status.URL
+			}
+			if url == "" {
+				fmt.Fprintln(w, "No release notes known")
+			} else if err := exec.Command("xdg-open", url).Start(); err != nil {
+				fmt.Fprintln(w, url)
+			}
+		case "l":
+			for j, other := range triageItems {
+				fmt.Fprintf(w, "%3d  %-12s %s %s → %s\n", j+1, other.status.Status, other.status.Package, other.status.Version, upstreamOf(other.status))
+			}
+		case "q":
+			return
+		default:
+			fmt.Fprintln(w, help)
+		}
+	}
+}
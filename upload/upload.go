@@ -0,0 +1,146 @@
+// Package upload uploads the JSON results of each run to an HTTP endpoint, WebDAV or S3-compatible storage
+package upload
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/logging"
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+// now is replaced in tests
+var now = time.Now
+
+// Uploader is a status.Formatter uploading the results as JSON once finished
+type Uploader struct {
+	target string
+	buf    bytes.Buffer
+	status.Formatter
+}
+
+// New returns an Uploader for the target, an http(s):// URL receiving a PUT request (e.g. WebDAV)
+// or s3://bucket/key using the AWS_* environment variables; {hostname} is replaced by the hostname
+func New(target string) *Uploader {
+	hostname, _ := os.Hostname()
+	u := &Uploader{target: strings.Replace(target, "{hostname}", hostname, -1)}
+	u.Formatter, _ = status.NewFormatterWriter("json", &u.buf)
+	return u
+}
+
+// Finish uploads the results
+func (u *Uploader) Finish(statistics *status.Statistics) {
+	u.Formatter.Finish(statistics)
+	if err := u.upload(u.buf.Bytes()); err != nil {
+		logging.Errorf("Failed to upload results to %s: %v", u.target, err)
+	}
+}
+
+func (u *Uploader) upload(body []byte) error {
+	var req *http.Request
+	var err error
+	if strings.HasPrefix(u.target, "s3://") {
+		req, err = s3Request(u.target, body)
+	} else {
+		req, err = http.NewRequest("PUT", u.target, bytes.NewReader(body))
+	}
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: %s", req.Method, req.URL, resp.Status)
+	}
+	return nil
+}
+
+// s3Request returns the PUT request for s3://bucket/key signed using AWS Signature Version 4,
+// addressing AWS_ENDPOINT_URL (path-style, e.g. MinIO) if set, otherwise the AWS_REGION of Amazon S3 (default us-east-1)
+func s3Request(target string, body []byte) (*http.Request, error) {
+	parts := strings.SplitN(strings.TrimPrefix(target, "s3://"), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("Invalid S3 target %s, expecting s3://bucket/key", target)
+	}
+	bucket, key := parts[0], parts[1]
+	accessKey, secretKey := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKey == "" || secretKey == "" {
+		return nil, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required for %s", target)
+	}
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+	endpoint := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", bucket, region, escapePath(key))
+	if e := os.Getenv("AWS_ENDPOINT_URL"); e != "" {
+		endpoint = fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(e, "/"), bucket, escapePath(key))
+	}
+	req, err := http.NewRequest("PUT", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html
+	t := now().UTC()
+	date := t.Format("20060102")
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", t.Format("20060102T150405Z"))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	signed := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	values := []string{req.URL.Host, payloadHash, req.Header.Get("X-Amz-Date")}
+	if token := os.Getenv("AWS_SESSION_TOKEN"); token != "" {
+		req.Header.Set("X-Amz-Security-Token", token)
+		signed = append(signed, "x-amz-security-token")
+		values = append(values, token)
+	}
+	var headers strings.Builder
+	for i, name := range signed {
+		fmt.Fprintf(&headers, "%s:%s\n", name, values[i])
+	}
+	canonical := strings.Join([]string{"PUT", req.URL.EscapedPath(), "", headers.String(), strings.Join(signed, ";"), payloadHash}, "\n")
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", req.Header.Get("X-Amz-Date"), scope, sha256Hex([]byte(canonical))}, "\n")
+	signature := hex.EncodeToString(hmacSHA256(signingKey(secretKey, date, region, "s3"), stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", accessKey, scope, strings.Join(signed, ";"), signature))
+	return req, nil
+}
+
+// signingKey derives the key of AWS Signature Version 4 for the date (YYYYMMDD), region and service
+func signingKey(secretKey, date, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// escapePath percent-encodes the segments of the key as required by AWS, i.e. all but unreserved characters
+func escapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = strings.Replace(url.QueryEscape(segment), "+", "%20", -1)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,75 @@
+package upload
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/status"
+)
+
+func TestUploadPUT(t *testing.T) {
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	hostname, _ := os.Hostname()
+
+	u := New(server.URL + "/dav/{hostname}.json")
+	u.Status(&status.Status{Package: "foo", Version: "1.0-1", Status: status.OutOfDate})
+	u.Finish(nil)
+	if method != "PUT" || path != "/dav/"+hostname+".json" {
+		t.Errorf("Expecting PUT /dav/%s.json, but got %s %s", hostname, method, path)
+	}
+	if !strings.Contains(body, `"name": "foo"`) {
+		t.Errorf("Expecting the JSON results, but got %s", body)
+	}
+}
+
+func TestUploadS3(t *testing.T) {
+	var path, authorization, contentHash string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, authorization, contentHash = r.URL.EscapedPath(), r.Header.Get("Authorization"), r.Header.Get("X-Amz-Content-Sha256")
+	}))
+	defer server.Close()
+	for key, value := range map[string]string{"AWS_ENDPOINT_URL": server.URL, "AWS_ACCESS_KEY_ID": "AKIDEXAMPLE", "AWS_SECRET_ACCESS_KEY": "secret", "AWS_REGION": "eu-central-1"} {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+	now = func() time.Time { return time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	if err := New("s3://results/aur out-of-date.json").upload([]byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/results/aur%20out-of-date.json" {
+		t.Errorf("Expecting /results/aur%%20out-of-date.json, but got %s", path)
+	}
+	expected := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20200101/eu-central-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature="
+	if !strings.HasPrefix(authorization, expected) {
+		t.Errorf("Expecting %s…, but got %s", expected, authorization)
+	}
+	if contentHash != sha256Hex([]byte("{}")) {
+		t.Errorf("Expecting the SHA-256 of the body, but got %s", contentHash)
+	}
+	if err := New("s3://results").upload(nil); err == nil {
+		t.Error("Expecting an error for a target without key")
+	}
+}
+
+func TestSigningKey(t *testing.T) {
+	// https://docs.aws.amazon.com/general/latest/gr/signature-v4-examples.html
+	key := signingKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+	if actual := hex.EncodeToString(key); actual != "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d" {
+		t.Errorf("Unexpected signing key %s", actual)
+	}
+}
@@ -0,0 +1,44 @@
+package upstream
+
+import (
+	"fmt"
+	"net/url"
+)
+
+type anityaResponse struct {
+	Items []struct {
+		ID             int      `json:"id"`
+		Name           string   `json:"name"`
+		Version        string   `json:"version"`
+		StableVersions []string `json:"stable_versions"`
+	} `json:"items"`
+}
+
+// anitya is a project name on release-monitoring.org
+type anitya string
+
+func (a anitya) releasesURL() string {
+	// API documentation: https://release-monitoring.org/static/docs/api.html
+	return fmt.Sprintf("https://release-monitoring.org/api/v2/projects/?name=%s", url.QueryEscape(string(a)))
+}
+
+func (a anitya) name() string {
+	return "anitya"
+}
+
+func (a anitya) latestVersion() (Version, error) {
+	var response anityaResponse
+	if err := fetchJSON(a, &response); err != nil {
+		return "", fmt.Errorf("No Anitya project found for %v: %w", a, err)
+	}
+	for _, project := range response.Items {
+		if project.Name != string(a) {
+			continue
+		} else if len(project.StableVersions) > 0 {
+			return Version(project.StableVersions[0]), nil
+		} else if project.Version != "" {
+			return Version(project.Version), nil
+		}
+	}
+	return "", fmt.Errorf("No Anitya project found for %v on %s", a, a.releasesURL())
+}
@@ -0,0 +1,94 @@
+package upstream
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/simon04/aur-out-of-date/pkg"
+	"github.com/simon04/aur-out-of-date/transport"
+)
+
+// replay serves the HTTP requests of the test from the cassette testdata/<name>.json, recorded using -record
+func replay(t *testing.T, name string) func() {
+	t.Helper()
+	c, err := transport.LoadCassette(path.Join("testdata", name+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := http.DefaultClient
+	http.DefaultClient = &http.Client{Transport: transport.Replay(c)}
+	return func() { http.DefaultClient = client }
+}
+
+func TestCassetteNpm(t *testing.T) {
+	defer replay(t, "npm-webpack")()
+	testCassette(t, pkg.New("webpack", "5.0.0", "https://www.npmjs.com/package/webpack"), "5.75.0")
+}
+
+func TestCassettePython(t *testing.T) {
+	defer replay(t, "pypi-httpie")()
+	testCassette(t, pkg.New("httpie", "3.0.0", "", "https://files.pythonhosted.org/packages/source/h/httpie/httpie-3.0.0.tar.gz"), "3.2.1")
+}
+
+func TestCassetteGitHub(t *testing.T) {
+	defer replay(t, "github-cli")()
+	testCassette(t, pkg.New("github-cli", "2.0.0", "https://github.com/cli/cli"), "2.20.2")
+}
+
+func TestCassetteGitHubTags(t *testing.T) {
+	defer replay(t, "github-tags-neovim")()
+	os.Setenv("GITHUB_TAGS", "1")
+	defer os.Unsetenv("GITHUB_TAGS")
+	testCassette(t, pkg.New("neovim", "0.8.0", "https://github.com/neovim/neovim"), "0.8.1")
+}
+
+func TestCassetteGitHubAtom(t *testing.T) {
+	defer replay(t, "github-atom-ripgrep")()
+	os.Setenv("GITHUB_ATOM", "1")
+	defer os.Unsetenv("GITHUB_ATOM")
+	testCassette(t, pkg.New("ripgrep", "12.1.1", "https://github.com/BurntSushi/ripgrep"), "13.0.0")
+}
+
+func TestCassetteGitLab(t *testing.T) {
+	defer replay(t, "gitlab-fdroidserver")()
+	testCassette(t, pkg.New("fdroidserver", "2.1", "https://gitlab.com/fdroid/fdroidserver"), "2.1.1")
+}
+
+func TestCassetteRubyGems(t *testing.T) {
+	defer replay(t, "rubygems-rails")()
+	testCassette(t, pkg.New("ruby-rails", "7.0.3", "https://rubygems.org/gems/rails"), "7.0.4")
+}
+
+func TestCassetteCPAN(t *testing.T) {
+	defer replay(t, "cpan-moose")()
+	testCassette(t, pkg.New("perl-moose", "2.2200", "https://metacpan.org/release/Moose"), "2.2201")
+}
+
+func TestCassetteDebian(t *testing.T) {
+	defer replay(t, "debian-python3-defaults")()
+	testCassette(t, pkg.New("python3-defaults", "3.10.5", "", "http://ftp.debian.org/debian/pool/main/p/python3-defaults/python3-defaults_3.10.5-1.tar.gz"), "3.10.6")
+}
+
+func TestCassetteAnitya(t *testing.T) {
+	defer replay(t, "anitya-zstd")()
+	result, err := ResultForNvchecker(NvcheckerEntry{"source": "anitya", "anitya": "zstd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Version != "1.5.2" {
+		t.Errorf("Expecting version 1.5.2, but got %v", result.Version)
+	}
+}
+
+func testCassette(t *testing.T, p pkg.Pkg, expected string) {
+	t.Helper()
+	version, err := VersionForPkg(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version.String() != expected {
+		t.Errorf("Expecting version %v, but got %v", expected, version)
+	}
+}
@@ -0,0 +1,99 @@
+package upstream
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/simon04/aur-out-of-date/logging"
+	"github.com/simon04/aur-out-of-date/pkg"
+)
+
+// FallbackSteps lists the generic steps of a fallback chain, besides provider identifiers such as github:owner/repo:
+// the provider matching the URL or the first source, the tags of its GitHub repository, the Anitya project named as the package,
+// and scraping the homepage for tarballs of the package
+var FallbackSteps = []string{"default", "github-tags", "anitya", "scrape"}
+
+// ValidateChain checks the steps of a fallback chain
+func ValidateChain(steps []string) error {
+	for _, step := range steps {
+		if strings.Contains(step, ":") {
+			if _, err := ParseIdentifier(step); err != nil {
+				return err
+			}
+		} else if !isFallbackStep(step) {
+			return fmt.Errorf("Unknown fallback step %q (%s or a provider identifier)", step, strings.Join(FallbackSteps, ", "))
+		}
+	}
+	return nil
+}
+
+func isFallbackStep(step string) bool {
+	for _, s := range FallbackSteps {
+		if s == step {
+			return true
+		}
+	}
+	return false
+}
+
+// ResultForChain tries the steps of the fallback chain in order, proceeding to the next step only if the previous one found nothing.
+// Network errors and rate limits end the chain, since a later step would report a possibly less accurate version.
+func ResultForChain(steps []string, pkg pkg.Pkg, channel string) (Result, error) {
+	var errs []string
+	reason := NoProvider
+	for _, step := range steps {
+		result, err := resultForStep(step, pkg, channel)
+		if err == nil {
+			return result, nil
+		}
+		r := ReasonOf(err)
+		if r == Network || r == RateLimited {
+			return result, err
+		} else if reason == NoProvider {
+			reason = r
+		}
+		logging.Log(logging.Debug, "Fallback step found nothing", "pkg", pkg.Name(), "step", step, "err", err)
+		errs = append(errs, fmt.Sprintf("%s: %v", step, err))
+	}
+	return Result{}, withReason(reason, fmt.Errorf("No release found for %s using the fallback chain: %s", pkg.Name(), strings.Join(errs, "; ")))
+}
+
+// resultForStep determines the upstream version using a single step of a fallback chain, the release channel only applying to default
+func resultForStep(step string, pkg pkg.Pkg, channel string) (Result, error) {
+	name := strings.TrimSuffix(pkg.Name(), "-bin")
+	switch step {
+	case "default":
+		return ResultForPkgInChannel(pkg, channel)
+	case "github-tags":
+		sources, _ := pkg.Sources()
+		for _, url := range append([]string{pkg.URL()}, sources...) {
+			if g := parseGitHub(url); g != nil && (strings.Contains(url, "github.com") || strings.Contains(url, "github.io")) {
+				return resultFor(gitHubAPITags{gitHub: *g}, url, "")
+			}
+		}
+		return Result{}, withReason(NoProvider, fmt.Errorf("No GitHub repository found for %s", pkg.Name()))
+	case "anitya":
+		a := anitya(name)
+		return resultFor(a, a.releasesURL(), "")
+	case "scrape":
+		url := pkg.URL()
+		if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+			return Result{}, withReason(NoProvider, fmt.Errorf("No homepage to scrape for %s", pkg.Name()))
+		}
+		return resultFor(regexProvider{url: url, regex: tarballRegex(name)}, url, "")
+	}
+	if err := ValidateChain([]string{step}); err != nil {
+		return Result{}, err
+	}
+	entry, err := ParseIdentifier(step)
+	if err != nil {
+		return Result{}, err
+	}
+	return ResultForNvchecker(entry)
+}
+
+// tarballRegex matches links to release archives of the project, such as foo-1.2.3.tar.gz, capturing the version
+func tarballRegex(name string) string {
+	return `(?i)\b` + regexp.QuoteMeta(name) + `[-_]v?([0-9]+(?:\.[0-9]+)+)\.(?:tar\.(?:gz|bz2|xz|zst)|tgz|zip)\b`
+}
@@ -0,0 +1,101 @@
+package upstream
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/simon04/aur-out-of-date/pkg"
+)
+
+func TestChainGitHubTags(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.github.com").
+		Get("/repos/example/foo/releases/latest").
+		Reply(404).
+		JSON(map[string]string{"message": "Not Found"})
+	gock.New("https://api.github.com").
+		Get("/repos/example/foo/tags").
+		Reply(200).
+		JSON([]map[string]string{{"name": "v1.2"}})
+
+	p := pkg.New("foo", "1.0", "https://github.com/example/foo")
+	result, err := ResultForChain([]string{"default", "github-tags", "anitya"}, p, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Version.String() != "1.2" || result.Provider != "github-tags" {
+		t.Errorf("Expecting github-tags 1.2, but got %v", result)
+	}
+}
+
+func TestChainAnityaScrape(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://release-monitoring.org").
+		Get("/api/v2/projects/").
+		MatchParam("name", "bar").
+		Reply(200).
+		JSON(map[string]interface{}{"items": []interface{}{}})
+	gock.New("https://bar.example.org").
+		Get("/").
+		Reply(200).
+		BodyString(`<a href="dl/bar-2.0.tar.gz">2.0</a> <a href="dl/bar-2.1.tar.xz">2.1</a> <a href="dl/barbaz-3.0.zip">`)
+
+	p := pkg.New("bar-bin", "2.0", "https://bar.example.org/")
+	result, err := ResultForChain([]string{"github-tags", "anitya", "scrape"}, p, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Version != "2.1" || result.Provider != "regex" {
+		t.Errorf("Expecting regex 2.1, but got %v", result)
+	}
+
+	gock.New("https://release-monitoring.org").
+		Get("/api/v2/projects/").
+		MatchParam("name", "bar").
+		Reply(200).
+		JSON(map[string]interface{}{"items": []interface{}{map[string]interface{}{"name": "bar", "version": "2.2rc1", "stable_versions": []string{"2.1", "2.0"}}}})
+	result, err = ResultForChain([]string{"anitya:bar"}, p, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Version != "2.1" || result.Provider != "anitya" {
+		t.Errorf("Expecting anitya 2.1, but got %v", result)
+	}
+}
+
+func TestChainStopsOnNetworkError(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.github.com").
+		Get("/repos/example/foo/releases/latest").
+		ReplyError(errors.New("connection refused"))
+
+	p := pkg.New("foo", "1.0", "https://github.com/example/foo")
+	_, err := ResultForChain([]string{"default", "github-tags"}, p, "")
+	if reason := ReasonOf(err); reason != Network {
+		t.Errorf("Expecting network error, but got %v (%v)", reason, err)
+	}
+	if gock.HasUnmatchedRequest() {
+		t.Errorf("Expecting github-tags not to be tried, but got %v", gock.GetUnmatchedRequests())
+	}
+}
+
+func TestChainNothingFound(t *testing.T) {
+	p := pkg.New("foo", "1.0", "", "foo.patch")
+	_, err := ResultForChain([]string{"github-tags", "scrape"}, p, "")
+	if reason := ReasonOf(err); reason != NoProvider {
+		t.Errorf("Expecting no-provider, but got %v (%v)", reason, err)
+	}
+}
+
+func TestValidateChain(t *testing.T) {
+	if err := ValidateChain([]string{"default", "github:owner/repo", "anitya", "scrape"}); err != nil {
+		t.Error(err)
+	}
+	if err := ValidateChain([]string{"default", "foo"}); err == nil {
+		t.Error("Expecting an error for unknown step")
+	}
+	if err := ValidateChain([]string{"foo:bar"}); err == nil {
+		t.Error("Expecting an error for unknown provider identifier")
+	}
+}
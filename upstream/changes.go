@@ -0,0 +1,121 @@
+package upstream
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// MaxChanges is the number of commits Changes summarizes
+var MaxChanges = 10
+
+// changesProvider is implemented by providers able to list the commits between two tags
+type changesProvider interface {
+	changes(from, to string) ([]string, error)
+}
+
+// Changes summarizes the commits between the tags from and to of the Git repository (as given by Ecosystem.Package for GIT),
+// listing the first line of at most MaxChanges commit messages, newest first, followed by the number of omitted commits
+func Changes(repository, from, to string) ([]string, error) {
+	var p changesProvider
+	if g := parseGitHub(repository); g != nil && strings.HasPrefix(repository, "https://github.com/") {
+		p = *g
+	} else if u, err := url.Parse(repository); err == nil && u.Host != "" {
+		parts := strings.SplitN(strings.Trim(u.Path, "/"), "/", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Unsupported repository %s", repository)
+		}
+		p = gitLab{u.Host, parts[0], strings.TrimSuffix(parts[1], ".git")}
+	} else {
+		return nil, fmt.Errorf("Unsupported repository %s", repository)
+	}
+	messages, err := p.changes(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return summarizeChanges(messages), nil
+}
+
+// Tags returns the tags of the packaged and the new version, assuming the same prefix (such as v or release-) as the tag of the result
+func Tags(result Result, packaged string) (string, string) {
+	tag := result.Raw
+	if tag == "" || strings.ContainsAny(tag, " \t") {
+		tag = string(result.Version)
+	}
+	prefix := ""
+	if strings.HasSuffix(tag, string(result.Version)) {
+		prefix = strings.TrimSuffix(tag, string(result.Version))
+	}
+	return prefix + packaged, tag
+}
+
+// summarizeChanges returns the first lines of the commit messages (oldest first), newest first truncated to MaxChanges
+func summarizeChanges(messages []string) []string {
+	var r []string
+	for i := len(messages) - 1; i >= 0; i-- {
+		if len(r) == MaxChanges {
+			r = append(r, fmt.Sprintf("… and %d more commits", i+1))
+			break
+		}
+		line := strings.TrimSpace(strings.SplitN(messages[i], "\n", 2)[0])
+		r = append(r, line)
+	}
+	return r
+}
+
+type gitHubCompare struct {
+	Commits []struct {
+		Commit struct {
+			Message string `json:"message"`
+		} `json:"commit"`
+	} `json:"commits"`
+}
+
+func (g gitHub) changes(from, to string) ([]string, error) {
+	// API documentation: https://docs.github.com/en/rest/commits/commits#compare-two-commits
+	api := fmt.Sprintf("https://api.github.com/repos/%s/%s/compare/%s...%s", g.owner, g.repository, url.PathEscape(from), url.PathEscape(to))
+	var compare gitHubCompare
+	if err := g.request(api, &compare); err != nil {
+		return nil, fmt.Errorf("Failed to compare %s...%s of %s: %w", from, to, g, err)
+	}
+	var messages []string
+	for _, c := range compare.Commits {
+		messages = append(messages, c.Commit.Message)
+	}
+	return messages, nil
+}
+
+type gitLabCompare struct {
+	Commits []struct {
+		Title string `json:"title"`
+	} `json:"commits"`
+}
+
+func (g gitLab) changes(from, to string) ([]string, error) {
+	// API documentation: https://docs.gitlab.com/ee/api/repositories.html#compare-branches-tags-or-commits
+	api := fmt.Sprintf("https://%s/api/v4/projects/%s/repository/compare?from=%s&to=%s", g.domain, g.encoded(), url.QueryEscape(from), url.QueryEscape(to))
+	header := http.Header{}
+	if token := os.Getenv("GITLAB_TOKEN"); token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := get(api, header)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to compare %s...%s of %s: %w", from, to, g, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Failed to compare %s...%s of %s: %s", from, to, g, resp.Status)
+	}
+	var compare gitLabCompare
+	if err := json.NewDecoder(resp.Body).Decode(&compare); err != nil {
+		return nil, fmt.Errorf("Failed to compare %s...%s of %s: %w", from, to, g, err)
+	}
+	var messages []string
+	for _, c := range compare.Commits {
+		messages = append(messages, c.Title)
+	}
+	return messages, nil
+}
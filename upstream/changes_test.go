@@ -0,0 +1,69 @@
+package upstream
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/h2non/gock"
+)
+
+func TestChangesGitHub(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.github.com").
+		Get("/repos/BurntSushi/ripgrep/compare/12.1.0...12.1.1").
+		Reply(200).
+		BodyString(`{"commits": [{"commit": {"message": "fix: handle empty files\n\nFixes #1"}}, {"commit": {"message": "release 12.1.1"}}]}`)
+
+	changes, err := Changes("https://github.com/BurntSushi/ripgrep", "12.1.0", "12.1.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(changes, "|") != "release 12.1.1|fix: handle empty files" {
+		t.Errorf("Unexpected changes %q", changes)
+	}
+}
+
+func TestChangesGitLab(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://gitlab.gnome.org").
+		Get("/api/v4/projects/GNOME/gtk/repository/compare").
+		MatchParam("from", "4.0.0").
+		MatchParam("to", "4.0.1").
+		Reply(200).
+		BodyString(`{"commits": [{"title": "Fix a crash"}, {"title": "4.0.1"}]}`)
+
+	changes, err := Changes("https://gitlab.gnome.org/GNOME/gtk", "4.0.0", "4.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(changes, "|") != "4.0.1|Fix a crash" {
+		t.Errorf("Unexpected changes %q", changes)
+	}
+}
+
+func TestSummarizeChanges(t *testing.T) {
+	var messages []string
+	for i := 0; i < 12; i++ {
+		messages = append(messages, "commit")
+	}
+	changes := summarizeChanges(messages)
+	if len(changes) != MaxChanges+1 || changes[MaxChanges] != "… and 2 more commits" {
+		t.Errorf("Unexpected changes %q", changes)
+	}
+}
+
+func TestTags(t *testing.T) {
+	for _, test := range []struct {
+		result   Result
+		from, to string
+	}{
+		{Result{Version: "1.1", Raw: "v1.1"}, "v1.0", "v1.1"},
+		{Result{Version: "1.1", Raw: "release-1.1"}, "release-1.0", "release-1.1"},
+		{Result{Version: "1.1", Raw: "MyApp 1.1"}, "1.0", "1.1"},
+		{Result{Version: "1.1"}, "1.0", "1.1"},
+	} {
+		if from, to := Tags(test.result, "1.0"); from != test.from || to != test.to {
+			t.Errorf("Expecting %s...%s for %v, but got %s...%s", test.from, test.to, test.result, from, to)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package upstream
+
+import "fmt"
+
+// ChannelProvider is implemented by providers offering release channels (such as "prerelease" or an npm dist-tag)
+// besides the stable channel returned by Latest
+type ChannelProvider interface {
+	Provider
+	// LatestInChannel returns the latest upstream release in the channel
+	LatestInChannel(channel string) (Result, error)
+}
+
+// channelProvider is implemented by built-in providers offering release channels
+type channelProvider interface {
+	provider
+	latestInChannel(channel string) (Result, error)
+}
+
+// isStable reports whether channel denotes the stable channel
+func isStable(channel string) bool {
+	return channel == "" || channel == "stable"
+}
+
+// latestInChannel returns the latest release of the provider in the channel
+func latestInChannel(p Provider, channel string) (Result, error) {
+	if isStable(channel) {
+		return p.Latest()
+	} else if c, ok := p.(ChannelProvider); ok {
+		return c.LatestInChannel(channel)
+	}
+	return Result{Provider: p.Name()}, fmt.Errorf("Provider %s does not support the release channel %s", p.Name(), channel)
+}
@@ -0,0 +1,62 @@
+package upstream
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/simon04/aur-out-of-date/pkg"
+)
+
+func TestNpmChannel(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://registry.npmjs.org/").
+		Get("/-/package/webpack/dist-tags").
+		Persist().
+		Reply(http.StatusOK).
+		JSON(map[string]string{"latest": "5.88.2", "next": "6.0.0-beta.1"})
+
+	p := pkg.New("webpack", "0", "https://www.npmjs.com/package/webpack")
+	for channel, expected := range map[string]Version{"": "5.88.2", "stable": "5.88.2", "next": "6.0.0-beta.1"} {
+		result, err := ResultForPkgInChannel(p, channel)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Version != expected {
+			t.Errorf("Expecting %s in channel %q, but got %s", expected, channel, result.Version)
+		}
+	}
+	if _, err := ResultForPkgInChannel(p, "beta"); err == nil {
+		t.Error("Expecting an error for the missing dist-tag beta")
+	}
+}
+
+func TestGitHubPrereleaseChannel(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.github.com/").
+		Get("/repos/gogits/gogs/releases").
+		MatchParam("per_page", "20").
+		Reply(http.StatusOK).
+		JSON([]map[string]interface{}{
+			{"tag_name": "v0.14.0-rc1", "draft": true},
+			{"tag_name": "v0.13.0-beta.2", "prerelease": true},
+			{"tag_name": "v0.12.0"},
+		})
+
+	result, err := ResultForURLInChannel("https://github.com/gogits/gogs", "prerelease")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Version != "v0.13.0-beta.2" || result.Provider != "github" {
+		t.Errorf("Expecting github v0.13.0-beta.2, but got %v", result)
+	}
+	if _, err := ResultForURLInChannel("https://github.com/gogits/gogs", "nightly"); err == nil {
+		t.Error("Expecting an error for the unknown channel nightly")
+	}
+}
+
+func TestUnsupportedChannel(t *testing.T) {
+	if _, err := ResultForURLInChannel("https://pypi.org/project/httpie/", "prerelease"); err == nil {
+		t.Error("Expecting an error for a provider without channels")
+	}
+}
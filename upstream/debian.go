@@ -21,6 +21,14 @@ func (d debian) releasesURL() string {
 	return fmt.Sprintf("https://sources.debian.org/api/src/%s/", url.PathEscape(string(d)))
 }
 
+func (d debian) name() string {
+	return "debian"
+}
+
+func (d debian) releaseURL(version Version) string {
+	return fmt.Sprintf("https://tracker.debian.org/pkg/%s", url.PathEscape(string(d)))
+}
+
 func (d debian) latestVersion() (Version, error) {
 	var res debianResponse
 	if err := fetchJSON(d, &res); err != nil {
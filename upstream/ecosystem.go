@@ -0,0 +1,61 @@
+package upstream
+
+import "strings"
+
+// Ecosystem identifies the package of a Result in an ecosystem known to OSV (https://osv.dev), such as PyPI
+type Ecosystem struct {
+	// Name is the OSV ecosystem, e.g. "PyPI", or "GIT" for Git repositories
+	Name string
+	// Package is the package name, or the repository URL for GIT
+	Package string
+}
+
+// ecosystemProvider is implemented by providers whose packages are known to OSV
+type ecosystemProvider interface {
+	ecosystem() Ecosystem
+}
+
+func (p pypi) ecosystem() Ecosystem {
+	return Ecosystem{"PyPI", string(p)}
+}
+
+func (n npm) ecosystem() Ecosystem {
+	return Ecosystem{"npm", string(n)}
+}
+
+func (g rubygem) ecosystem() Ecosystem {
+	return Ecosystem{"RubyGems", string(g)}
+}
+
+func (d debian) ecosystem() Ecosystem {
+	return Ecosystem{"Debian", string(d)}
+}
+
+func (g gitHub) ecosystem() Ecosystem {
+	return Ecosystem{"GIT", "https://github.com/" + g.String()}
+}
+
+func (g gitLab) ecosystem() Ecosystem {
+	return Ecosystem{"GIT", "https://" + g.String()}
+}
+
+// Purl returns the package URL (https://github.com/package-url/purl-spec) without version, or "" for Git repositories on unknown hosts
+func (e Ecosystem) Purl() string {
+	switch e.Name {
+	case "PyPI":
+		return "pkg:pypi/" + strings.ToLower(strings.Replace(e.Package, "_", "-", -1))
+	case "npm":
+		return "pkg:npm/" + strings.Replace(e.Package, "@", "%40", 1)
+	case "RubyGems":
+		return "pkg:gem/" + e.Package
+	case "Debian":
+		return "pkg:deb/debian/" + e.Package
+	case "GIT":
+		for prefix, kind := range map[string]string{"https://github.com/": "github", "https://gitlab.com/": "gitlab"} {
+			if strings.HasPrefix(e.Package, prefix) {
+				return "pkg:" + kind + "/" + strings.ToLower(strings.TrimPrefix(e.Package, prefix))
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,22 @@
+package upstream
+
+import "testing"
+
+func TestEcosystemPurl(t *testing.T) {
+	for _, test := range []struct {
+		ecosystem Ecosystem
+		purl      string
+	}{
+		{Ecosystem{"PyPI", "Django_Filter"}, "pkg:pypi/django-filter"},
+		{Ecosystem{"npm", "@angular/cli"}, "pkg:npm/%40angular/cli"},
+		{Ecosystem{"RubyGems", "asciidoctor"}, "pkg:gem/asciidoctor"},
+		{Ecosystem{"Debian", "vim"}, "pkg:deb/debian/vim"},
+		{Ecosystem{"GIT", "https://github.com/BurntSushi/ripgrep"}, "pkg:github/burntsushi/ripgrep"},
+		{Ecosystem{"GIT", "https://gitlab.com/inkscape/inkscape"}, "pkg:gitlab/inkscape/inkscape"},
+		{Ecosystem{"GIT", "https://gitlab.gnome.org/GNOME/gtk"}, ""},
+	} {
+		if purl := test.ecosystem.Purl(); purl != test.purl {
+			t.Errorf("Expecting %s for %v, but got %s", test.purl, test.ecosystem, purl)
+		}
+	}
+}
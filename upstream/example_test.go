@@ -0,0 +1,19 @@
+package upstream_test
+
+import (
+	"fmt"
+
+	"github.com/simon04/aur-out-of-date/status"
+	"github.com/simon04/aur-out-of-date/upstream"
+)
+
+func Example() {
+	result, err := upstream.ResultForURL("https://github.com/simon04/aur-out-of-date")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	s := status.Status{Package: "aur-out-of-date", Version: "3.1.0-1"}
+	s.Compare(result.Version)
+	fmt.Println(s.Status, s.Message)
+}
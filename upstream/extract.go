@@ -0,0 +1,24 @@
+package upstream
+
+import "regexp"
+
+// plainVersion matches fields which are a version already, such as v2.4.1 or 7.0.0.post3
+var plainVersion = regexp.MustCompile(`^[vV]?\d[\w.+~-]*$`)
+
+// dottedVersion and numberVersion match version-looking tokens, preferring dotted ones such as 2.4.1 or 1.0rc1
+var dottedVersion = regexp.MustCompile(`(?i)\b[v]?(\d+(?:\.\d+)+(?:[-.]?(?:alpha|beta|rc|pre|post|dev)\.?\d*|[a-z])?)\b`)
+var numberVersion = regexp.MustCompile(`(?i)\b[v]?(\d+)\b`)
+
+// extractVersion returns the field if it is a version already, otherwise its first version-looking token,
+// e.g. 2.4.1 for "MyApp 2.4.1 – Spring release", and whether a version has been found
+func extractVersion(field string) (Version, bool) {
+	if plainVersion.MatchString(field) {
+		return Version(field), true
+	}
+	for _, re := range []*regexp.Regexp{dottedVersion, numberVersion} {
+		if match := re.FindStringSubmatch(field); match != nil {
+			return Version(match[1]), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,23 @@
+package upstream
+
+import "testing"
+
+func TestExtractVersion(t *testing.T) {
+	for field, expected := range map[string]string{
+		"v0.11.34":                     "v0.11.34",
+		"7.0.0.post3":                  "7.0.0.post3",
+		"MyApp 2.4.1 – Spring release": "2.4.1",
+		"release-1.2.3":                "1.2.3",
+		"foo-v1.2":                     "1.2",
+		"Version 1.0rc1":               "1.0rc1",
+		"MyApp2 build 2.4":             "2.4",
+		"Release 42":                   "42",
+	} {
+		if version, ok := extractVersion(field); !ok || string(version) != expected {
+			t.Errorf("Expecting %s for %q, but got %s", expected, field, version)
+		}
+	}
+	if version, ok := extractVersion("Spring release"); ok {
+		t.Errorf("Expecting no version, but got %s", version)
+	}
+}
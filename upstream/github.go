@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"regexp"
 )
@@ -17,6 +18,10 @@ func (g gitHub) String() string {
 	return g.owner + "/" + g.repository
 }
 
+func (g gitHub) releaseURL(version Version) string {
+	return fmt.Sprintf("https://github.com/%s/%s/releases/tag/%s", g.owner, g.repository, url.PathEscape(string(version)))
+}
+
 func parseGitHub(url string) *gitHub {
 	match := regexp.MustCompile("github.com/([^/#.]+)/([^/#]+)").FindStringSubmatch(url)
 	if len(match) > 0 {
@@ -30,18 +35,14 @@ func parseGitHub(url string) *gitHub {
 }
 
 func (g gitHub) request(url string, target interface{}) error {
-	req, err := http.NewRequest("GET", url, nil)
-
+	header := http.Header{}
 	// Obtain GitHub token for higher request limits, see https://developer.github.com/v3/#rate-limiting
 	token := os.Getenv("GITHUB_TOKEN")
 	if token != "" {
-		req.Header.Set("Authorization", "token "+token)
-	}
-	if err != nil {
-		return err
+		header.Set("Authorization", "token "+token)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := get(url, header)
 	if err != nil {
 		return err
 	}
@@ -56,7 +57,7 @@ func (g gitHub) request(url string, target interface{}) error {
 		}
 		return err
 	} else if resp.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("No GitHub project found for %s on %s", g, url)
+		return withReason(Gone, fmt.Errorf("No GitHub project found for %s on %s", g, url))
 	}
 	return dec.Decode(target)
 }
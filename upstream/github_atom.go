@@ -3,7 +3,6 @@ package upstream
 import (
 	"encoding/xml"
 	"fmt"
-	"net/http"
 	"net/url"
 	"regexp"
 )
@@ -36,8 +35,12 @@ func (g gitHubAPIAtom) errorNotFound() error {
 	return fmt.Errorf("No GitHub release found for %s on %s", g, g.atomURL())
 }
 
+func (g gitHubAPIAtom) name() string {
+	return "github-atom"
+}
+
 func (g gitHubAPIAtom) latestVersion() (Version, error) {
-	resp, err := http.Get(g.atomURL())
+	resp, err := get(g.atomURL(), nil)
 	if err != nil {
 		return "", g.errorWrap(err)
 	}
@@ -24,11 +24,13 @@ func (g gitHubAPIReleases) errorNotFound() error {
 
 type gitHubRelease struct {
 	URL         string    `json:"url"`
+	HTMLURL     string    `json:"html_url"`
 	Name        string    `json:"name"`
 	TagName     string    `json:"tag_name"`
 	Prerelease  bool      `json:"prerelease"`
 	Draft       bool      `json:"draft"`
 	PublishedAt time.Time `json:"published_at"`
+	Body        string    `json:"body"`
 }
 
 type gitHubMessage struct {
@@ -36,19 +38,65 @@ type gitHubMessage struct {
 	DocumentationURL string `json:"documentation_url"`
 }
 
+func (g gitHubAPIReleases) name() string {
+	return "github"
+}
+
 func (g gitHubAPIReleases) latestVersion() (Version, error) {
+	result, err := g.latestRelease()
+	return result.Version, err
+}
+
+func (g gitHubAPIReleases) latestRelease() (Result, error) {
 	var release gitHubRelease
 	err := g.request(g.releasesURL(), &release)
-	if err != nil {
-		return "", g.errorWrap(err)
+	if ReasonOf(err) == Gone {
+		// GitHub also answers 404 for existing repositories without releases
+		return Result{}, g.errorNotFound()
+	} else if err != nil {
+		return Result{}, g.errorWrap(err)
 	} else if release.Prerelease {
-		return "", fmt.Errorf("Ignoring GitHub pre-release %s for %s", release.Name, g.String())
+		return Result{}, fmt.Errorf("Ignoring GitHub pre-release %s for %s", release.Name, g.String())
 	} else if release.Draft {
-		return "", fmt.Errorf("Ignoring GitHub release draft %s for %s", release.Name, g.String())
-	} else if release.TagName != "" {
-		return Version(release.TagName), nil
-	} else if release.Name != "" {
-		return Version(release.Name), nil
+		return Result{}, fmt.Errorf("Ignoring GitHub release draft %s for %s", release.Name, g.String())
+	}
+	return g.result(release)
+}
+
+// latestInChannel returns the newest release including pre-releases for the channel "prerelease"
+func (g gitHubAPIReleases) latestInChannel(channel string) (Result, error) {
+	if channel != "prerelease" {
+		return Result{}, fmt.Errorf("Unknown GitHub release channel %s (stable, prerelease)", channel)
+	}
+	var releases []gitHubRelease
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases?per_page=20", g.owner, g.repository)
+	if err := g.request(url, &releases); err != nil {
+		return Result{}, fmt.Errorf("Failed to obtain GitHub releases for %s from %s: %w", g.String(), url, err)
+	}
+	for _, release := range releases {
+		if !release.Draft {
+			return g.result(release)
+		}
+	}
+	return Result{}, fmt.Errorf("No GitHub release found for %s on %s", g, url)
+}
+
+// result extracts the version of the release, falling back to the raw tag if it contains no version-looking token
+func (g gitHubAPIReleases) result(release gitHubRelease) (Result, error) {
+	result := Result{Provider: g.name(), Released: release.PublishedAt, ReleaseURL: release.HTMLURL, ReleaseNotes: release.Body}
+	if match := gitHubHTMLURL.FindStringSubmatch(release.HTMLURL); match != nil {
+		setMoved(&result, "github.com", g.String(), match[1])
+	}
+	for _, field := range []string{release.TagName, release.Name} {
+		if version, ok := extractVersion(field); ok {
+			result.Version, result.Raw = version, field
+			return result, nil
+		}
+	}
+	if release.TagName != "" {
+		// such as curl-7_88_1, leaving the extraction to the regex configured for the package
+		result.Version, result.Raw = Version(release.TagName), release.TagName
+		return result, nil
 	}
-	return "", g.errorNotFound()
+	return Result{}, g.errorNotFound()
 }
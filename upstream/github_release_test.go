@@ -3,6 +3,7 @@ package upstream
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/h2non/gock"
 	"github.com/simon04/aur-out-of-date/pkg"
@@ -55,3 +56,65 @@ func TestGogsGitHubSource(t *testing.T) {
 		t.Errorf("Expecting version 0.11.34, but got %v", version)
 	}
 }
+
+func TestGogsGitHubReleased(t *testing.T) {
+	defer gock.Off()
+	mockGitHub()
+
+	p := pkg.New("gogs", "0", "https://github.com/gogits/gogs")
+	result, err := ResultForPkg(p)
+	if err != nil {
+		t.Error(err)
+	}
+	if result.Provider != "github" || !result.Released.Equal(time.Date(2017, 11, 22, 19, 52, 48, 0, time.UTC)) {
+		t.Errorf("Expecting GitHub release published at 2017-11-22T19:52:48Z, but got %v", result)
+	}
+}
+
+func TestGitHubReleaseName(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.github.com/").
+		Get("/repos/example/myapp/releases/latest").
+		Reply(http.StatusOK).
+		BodyString(`{"tag_name": "latest", "name": "MyApp 2.4.1 – Spring release"}`)
+
+	result, err := ResultForURL("https://github.com/example/myapp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Version != "2.4.1" || result.Raw != "MyApp 2.4.1 – Spring release" {
+		t.Errorf("Expecting version 2.4.1 extracted from the release name, but got %v", result)
+	}
+}
+
+func TestGitHubReleaseRawTag(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.github.com/").
+		Get("/repos/curl/curl/releases/latest").
+		Reply(http.StatusOK).
+		BodyString(`{"tag_name": "curl-7_88_1", "name": "curl-7_88_1"}`)
+
+	result, err := ResultForURL("https://github.com/curl/curl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Version != "curl-7_88_1" || result.Raw != "curl-7_88_1" {
+		t.Errorf("Expecting the raw tag curl-7_88_1 for the regex of the package, but got %v", result)
+	}
+}
+
+func TestGitHubReleaseNotes(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.github.com/").
+		Get("/repos/example/myapp/releases/latest").
+		Reply(http.StatusOK).
+		BodyString(`{"tag_name": "v2.4.1", "body": "## Security\n\n- Fix CVE-2024-0001"}`)
+
+	result, err := ResultForURL("https://github.com/example/myapp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ReleaseNotes != "## Security\n\n- Fix CVE-2024-0001" {
+		t.Errorf("Expecting the release body as release notes, but got %q", result.ReleaseNotes)
+	}
+}
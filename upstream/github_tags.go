@@ -25,6 +25,10 @@ type gitHubTag struct {
 	Name string `json:"name"`
 }
 
+func (g gitHubAPITags) name() string {
+	return "github-tags"
+}
+
 func (g gitHubAPITags) latestVersion() (Version, error) {
 	var taglist []gitHubTag
 	err := g.request(g.tagsURL(), &taglist)
@@ -14,3 +14,13 @@ func TestParseGitHub(t *testing.T) {
 		t.Errorf("Expecting foo/bar, but got %v", g.String())
 	}
 }
+
+func TestGitHubReleaseURL(t *testing.T) {
+	g := gitHub{"foo", "bar"}
+	if url := g.releaseURL(Version("v1.0")); url != "https://github.com/foo/bar/releases/tag/v1.0" {
+		t.Errorf("Expecting the tag v1.0, but got %s", url)
+	}
+	if url := g.releaseURL(Version("release/1.0")); url != "https://github.com/foo/bar/releases/tag/release%2F1.0" {
+		t.Errorf("Expecting the escaped tag release/1.0, but got %s", url)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"time"
 )
 
 // Self-hosted GitLab instances use different domain names
@@ -30,6 +31,10 @@ func (g gitLab) releasesURL() string {
 	return fmt.Sprintf("https://%s/api/v4/projects/%s/repository/tags", g.domain, g.encoded())
 }
 
+func (g gitLab) releaseURL(version Version) string {
+	return fmt.Sprintf("https://%s/%s/%s/-/tags/%s", g.domain, g.owner, g.repository, url.PathEscape(string(version)))
+}
+
 func (g gitLab) errorWrap(err error) error {
 	return fmt.Errorf("Failed to obtain GitLab tag for %s from %s: %w", g.String(), g.releasesURL(), err)
 }
@@ -40,28 +45,40 @@ func (g gitLab) errorNotFound() error {
 
 // Describes the individual tags in the returned taglist from the json call
 type gitLabTag struct {
-	Name string `json:"name"`
+	Name   string `json:"name"`
+	Commit struct {
+		CommittedDate time.Time `json:"committed_date"`
+	} `json:"commit"`
+	// Release is set for tags having release notes
+	Release *struct {
+		Description string `json:"description"`
+	} `json:"release"`
 }
 
 type gitLabMessage struct {
 	Message string `json:"message"`
 }
 
+func (g gitLab) name() string {
+	return "gitlab"
+}
+
 func (g gitLab) latestVersion() (Version, error) {
-	req, err := http.NewRequest("GET", g.releasesURL(), nil)
+	result, err := g.latestRelease()
+	return result.Version, err
+}
 
+func (g gitLab) latestRelease() (Result, error) {
+	header := http.Header{}
 	// Obtain GitLab token for higher request limits, see https://docs.gitlab.com/ee/api/#oauth2-tokens
 	token := os.Getenv("GITLAB_TOKEN")
 	if token != "" {
-		req.Header.Set("Authorization", "Bearer "+token)
-	}
-	if err != nil {
-		return "", g.errorWrap(err)
+		header.Set("Authorization", "Bearer "+token)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := get(g.releasesURL(), header)
 	if err != nil {
-		return "", g.errorWrap(err)
+		return Result{}, g.errorWrap(err)
 	}
 	defer resp.Body.Close()
 
@@ -72,9 +89,9 @@ func (g gitLab) latestVersion() (Version, error) {
 		if err == nil && message.Message != "" {
 			err = fmt.Errorf("%s", message.Message)
 		}
-		return "", g.errorWrap(err)
+		return Result{}, g.errorWrap(err)
 	} else if resp.StatusCode == http.StatusNotFound {
-		return "", g.errorNotFound()
+		return Result{}, withReason(Gone, g.errorNotFound())
 	}
 
 	// Can't get single tag, has to be an array
@@ -82,12 +99,20 @@ func (g gitLab) latestVersion() (Version, error) {
 	var taglist []gitLabTag
 	err = dec.Decode(&taglist)
 	if err != nil {
-		return "", g.errorWrap(err)
+		return Result{}, g.errorWrap(err)
 	} else if len(taglist) > 0 {
 		// [0] will always be the newest, as its sorted by default
 		if taglist[0].Name != "" {
-			return Version(taglist[0].Name), nil
+			result := Result{Version: Version(taglist[0].Name), Released: taglist[0].Commit.CommittedDate}
+			if taglist[0].Release != nil {
+				result.ReleaseNotes = taglist[0].Release.Description
+			}
+			if resp.Request != nil {
+				// GitLab redirects API requests for renamed projects
+				setMoved(&result, g.domain, g.owner+"/"+g.repository, gitLabProject(resp.Request.URL))
+			}
+			return result, nil
 		}
 	}
-	return "", g.errorNotFound()
+	return Result{}, g.errorNotFound()
 }
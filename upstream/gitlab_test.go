@@ -3,6 +3,7 @@ package upstream
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/h2non/gock"
 	"github.com/simon04/aur-out-of-date/pkg"
@@ -77,3 +78,17 @@ func TestGitLabceGitLabSource(t *testing.T) {
 		t.Errorf("Expecting version 11.0.0-rc13, but got %v", version)
 	}
 }
+
+func TestGitLabceGitLabReleased(t *testing.T) {
+	defer gock.Off()
+	mockGitLab()
+
+	p := pkg.New("gitlab-ce", "0", "https://gitlab.com/gitlab-org/gitlab-ce")
+	result, err := ResultForPkg(p)
+	if err != nil {
+		t.Error(err)
+	}
+	if !result.Released.Equal(time.Date(2018, 6, 18, 11, 55, 51, 0, time.UTC)) {
+		t.Errorf("Expecting GitLab tag committed at 2018-06-18T11:55:51Z, but got %v", result.Released)
+	}
+}
@@ -0,0 +1,15 @@
+package upstream
+
+// APIHosts maps the names of the built-in providers to the hosts of their APIs,
+// e.g. to configure request headers per provider
+var APIHosts = map[string][]string{
+	"github":      {"api.github.com"},
+	"github-tags": {"api.github.com"},
+	"github-atom": {"github.com"},
+	"gitlab":      {"gitlab.com"},
+	"npm":         {"registry.npmjs.org"},
+	"pypi":        {"pypi.org"},
+	"rubygems":    {"rubygems.org"},
+	"cpan":        {"fastapi.metacpan.org"},
+	"debian":      {"sources.debian.org"},
+}
@@ -0,0 +1,18 @@
+package upstream
+
+import (
+	"net/http"
+)
+
+// get performs a GET request using http.DefaultClient, which is shared by all providers.
+// The transports of http.DefaultClient set the User-Agent, log, cache (revalidating using ETag/Last-Modified), limit and retry requests.
+func get(url string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	return http.DefaultClient.Do(req)
+}
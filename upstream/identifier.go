@@ -0,0 +1,40 @@
+package upstream
+
+import (
+	"fmt"
+	"strings"
+)
+
+// identifierSources maps the provider of an identifier such as pypi:name onto the nvchecker source
+var identifierSources = map[string]string{
+	"github":      "github",
+	"github-tags": "github",
+	"gitlab":      "gitlab",
+	"pypi":        "pypi",
+	"npm":         "npm",
+	"gems":        "gems",
+	"rubygems":    "gems",
+	"cpan":        "cpan",
+	"debian":      "debianpkg",
+	"debianpkg":   "debianpkg",
+	"anitya":      "anitya",
+}
+
+// ParseIdentifier parses a provider identifier into an nvchecker entry, e.g. github:owner/repo, github-tags:owner/repo,
+// gitlab:group/project, gitlab:gitlab.gnome.org/GNOME/gtk, pypi:name, npm:name, gems:name, cpan:dist, debian:name, anitya:project
+func ParseIdentifier(id string) (NvcheckerEntry, error) {
+	parts := strings.SplitN(id, ":", 2)
+	source, ok := identifierSources[parts[0]]
+	if len(parts) != 2 || !ok || parts[1] == "" {
+		return nil, fmt.Errorf("Invalid provider identifier %q", id)
+	}
+	e := NvcheckerEntry{"source": source, source: parts[1]}
+	if parts[0] == "github-tags" {
+		e["use_max_tag"] = true
+	}
+	if host := strings.SplitN(parts[1], "/", 2)[0]; source == "gitlab" && strings.Contains(host, ".") {
+		e["host"] = host
+		e["gitlab"] = strings.TrimPrefix(parts[1], host+"/")
+	}
+	return e, nil
+}
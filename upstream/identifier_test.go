@@ -0,0 +1,50 @@
+package upstream
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/h2non/gock"
+)
+
+func TestParseIdentifier(t *testing.T) {
+	for id, expected := range map[string]NvcheckerEntry{
+		"github:BurntSushi/ripgrep":         {"source": "github", "github": "BurntSushi/ripgrep"},
+		"github-tags:BurntSushi/ripgrep":    {"source": "github", "github": "BurntSushi/ripgrep", "use_max_tag": true},
+		"gitlab:gitlab.gnome.org/GNOME/gtk": {"source": "gitlab", "gitlab": "GNOME/gtk", "host": "gitlab.gnome.org"},
+		"gitlab:gitlab-org/gitlab-ce":       {"source": "gitlab", "gitlab": "gitlab-org/gitlab-ce"},
+		"pypi:yt-dlp":                       {"source": "pypi", "pypi": "yt-dlp"},
+		"rubygems:rails":                    {"source": "gems", "gems": "rails"},
+		"debian:ripgrep":                    {"source": "debianpkg", "debianpkg": "ripgrep"},
+	} {
+		e, err := ParseIdentifier(id)
+		if err != nil {
+			t.Error(err)
+		} else if !reflect.DeepEqual(e, expected) {
+			t.Errorf("Expecting %v for %s, but got %v", expected, id, e)
+		}
+	}
+	for _, id := range []string{"yt-dlp", "pypi:", "sourceforge:foo"} {
+		if _, err := ParseIdentifier(id); err == nil {
+			t.Errorf("Expecting an error for %s", id)
+		}
+	}
+}
+
+func TestResultForIdentifier(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://pypi.org/").
+		Get("/pypi/yt-dlp/json").
+		Reply(http.StatusOK).
+		JSON(map[string]interface{}{"info": map[string]string{"version": "2023.3.4"}})
+
+	e, _ := ParseIdentifier("pypi:yt-dlp")
+	result, err := ResultForNvchecker(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Version != "2023.3.4" || result.Provider != "pypi" {
+		t.Errorf("Expecting pypi 2023.3.4, but got %v", result)
+	}
+}
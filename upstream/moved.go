@@ -0,0 +1,43 @@
+package upstream
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var gitHubHTMLURL = regexp.MustCompile(`^https://github.com/([^/]+/[^/]+)/`)
+
+// movedRepository returns the repository (owner/name) if it differs from the requested one, "" otherwise
+func movedRepository(requested, actual string) string {
+	if actual == "" || strings.EqualFold(strings.TrimSuffix(requested, ".git"), actual) {
+		return ""
+	}
+	return actual
+}
+
+// setMoved records the move of the repository on the host in the result
+func setMoved(result *Result, host, requested, actual string) {
+	if moved := movedRepository(requested, actual); moved != "" {
+		result.MovedFrom = host + "/" + strings.TrimSuffix(requested, ".git")
+		result.MovedTo = host + "/" + moved
+	}
+}
+
+// gitLabProject returns the project (owner/name) of a GitLab API URL, "" if none
+func gitLabProject(u *url.URL) string {
+	path := u.EscapedPath()
+	i := strings.Index(path, "/projects/")
+	if i < 0 {
+		return ""
+	}
+	path = path[i+len("/projects/"):]
+	if j := strings.Index(path, "/"); j >= 0 {
+		path = path[:j]
+	}
+	project, err := url.PathUnescape(path)
+	if err != nil {
+		return ""
+	}
+	return project
+}
@@ -0,0 +1,60 @@
+package upstream
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+)
+
+func TestGitHubMoved(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.github.com/").
+		Get("/repos/gogits/gogs/releases/latest").
+		Reply(http.StatusOK).
+		JSON(map[string]string{"tag_name": "v0.12.0", "html_url": "https://github.com/gogs/gogs/releases/tag/v0.12.0"})
+
+	result, err := ResultForURL("https://github.com/gogits/gogs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.MovedFrom != "github.com/gogits/gogs" || result.MovedTo != "github.com/gogs/gogs" {
+		t.Errorf("Expecting move from github.com/gogits/gogs to github.com/gogs/gogs, but got %s → %s", result.MovedFrom, result.MovedTo)
+	}
+}
+
+func TestGitHubNotMoved(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.github.com/").
+		Get("/repos/Gogs/Gogs/releases/latest").
+		Reply(http.StatusOK).
+		JSON(map[string]string{"tag_name": "v0.12.0", "html_url": "https://github.com/gogs/gogs/releases/tag/v0.12.0"})
+
+	result, err := ResultForURL("https://github.com/Gogs/Gogs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.MovedTo != "" {
+		t.Errorf("Expecting no move, but got %s", result.MovedTo)
+	}
+}
+
+func TestGitLabMoved(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://gitlab.com").
+		Get("/api/v4/projects/old-owner/foo/repository/tags").
+		Reply(http.StatusMovedPermanently).
+		SetHeader("Location", "https://gitlab.com/api/v4/projects/new-owner%2Ffoo/repository/tags")
+	gock.New("https://gitlab.com").
+		Get("/api/v4/projects/new-owner/foo/repository/tags").
+		Reply(http.StatusOK).
+		JSON([]map[string]string{{"name": "v1.2"}})
+
+	result, err := ResultForURL("https://gitlab.com/old-owner/foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Version != "v1.2" || result.MovedFrom != "gitlab.com/old-owner/foo" || result.MovedTo != "gitlab.com/new-owner/foo" {
+		t.Errorf("Expecting v1.2 moved to gitlab.com/new-owner/foo, but got %v", result)
+	}
+}
@@ -5,9 +5,8 @@ import (
 	"net/url"
 )
 
-type npmDistTags struct {
-	Latest string `json:"latest"`
-}
+// npmDistTags maps dist-tags such as "latest" or "next" to versions
+type npmDistTags map[string]string
 
 type npm string
 
@@ -16,10 +15,30 @@ func (n npm) releasesURL() string {
 	return fmt.Sprintf("https://registry.npmjs.org/-/package/%s/dist-tags", url.PathEscape(string(n)))
 }
 
+func (n npm) name() string {
+	return "npm"
+}
+
+func (n npm) releaseURL(version Version) string {
+	return fmt.Sprintf("https://www.npmjs.com/package/%s/v/%s", n, url.PathEscape(string(version)))
+}
+
 func (n npm) latestVersion() (Version, error) {
+	return n.distTag("latest")
+}
+
+// latestInChannel returns the version of the dist-tag, e.g. "next" or "beta"
+func (n npm) latestInChannel(channel string) (Result, error) {
+	version, err := n.distTag(channel)
+	return Result{Version: version}, err
+}
+
+func (n npm) distTag(tag string) (Version, error) {
 	var distTags npmDistTags
-	if err := fetchJSON(n, &distTags); err != nil || distTags.Latest == "" {
+	if err := fetchJSON(n, &distTags); err != nil {
 		return "", fmt.Errorf("No npm release found for %v: %w", n, err)
+	} else if distTags[tag] == "" {
+		return "", fmt.Errorf("No npm release found for %v with dist-tag %s", n, tag)
 	}
-	return Version(distTags.Latest), nil
+	return Version(distTags[tag]), nil
 }
@@ -60,3 +60,17 @@ func TestWebpackUnknownSource(t *testing.T) {
 		t.Error("Expecting an error, but got none")
 	}
 }
+
+func TestWebpackNpmReleaseURL(t *testing.T) {
+	defer gock.Off()
+	mockNpm("3.9.0")
+
+	p := pkg.New("webpack", "3.6.0", "https://www.npmjs.com/package/webpack")
+	result, err := ResultForPkg(p)
+	if err != nil {
+		t.Error(err)
+	}
+	if result.ReleaseURL != "https://www.npmjs.com/package/webpack/v/3.9.0" {
+		t.Errorf("Expecting release URL https://www.npmjs.com/package/webpack/v/3.9.0, but got %v", result.ReleaseURL)
+	}
+}
@@ -0,0 +1,116 @@
+package upstream
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// NvcheckerEntry is a package entry of an nvchecker configuration, e.g. source = "github", github = "owner/repo"
+type NvcheckerEntry map[string]interface{}
+
+func (e NvcheckerEntry) str(key string) string {
+	s, _ := e[key].(string)
+	return s
+}
+
+// excludeRegex compiles exclude_regex, a regular expression or a list of them, to fully match the excluded versions, nil if unset
+func (e NvcheckerEntry) excludeRegex() (*regexp.Regexp, error) {
+	var patterns []string
+	switch v := e["exclude_regex"].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		patterns = []string{v}
+	case []interface{}:
+		for _, p := range v {
+			s, ok := p.(string)
+			if !ok {
+				return nil, fmt.Errorf("Invalid nvchecker exclude_regex = %v", v)
+			}
+			patterns = append(patterns, "(?:"+s+")")
+		}
+	default:
+		return nil, fmt.Errorf("Invalid nvchecker exclude_regex = %v", v)
+	}
+	re, err := regexp.Compile("^(?:" + strings.Join(patterns, "|") + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("Invalid nvchecker exclude_regex: %w", err)
+	}
+	return re, nil
+}
+
+func (e NvcheckerEntry) provider() (provider, string, error) {
+	source := e.str("source")
+	switch source {
+	case "github":
+		parts := strings.SplitN(e.str("github"), "/", 2)
+		if len(parts) != 2 {
+			return nil, "", fmt.Errorf("Invalid nvchecker github = %q", e.str("github"))
+		}
+		g := gitHub{parts[0], parts[1]}
+		if e["use_max_tag"] == true {
+			return gitHubAPITags{gitHub: g}, "https://github.com/" + e.str("github"), nil
+		}
+		return gitHubAPIReleases{gitHub: g}, "https://github.com/" + e.str("github"), nil
+	case "gitlab":
+		host := e.str("host")
+		if host == "" {
+			host = "gitlab.com"
+		}
+		i := strings.LastIndex(e.str("gitlab"), "/")
+		if i < 0 {
+			return nil, "", fmt.Errorf("Invalid nvchecker gitlab = %q", e.str("gitlab"))
+		}
+		return gitLab{host, e.str("gitlab")[:i], e.str("gitlab")[i+1:]}, "https://" + host + "/" + e.str("gitlab"), nil
+	case "pypi":
+		return pypi(e.str("pypi")), "https://pypi.org/project/" + e.str("pypi"), nil
+	case "npm":
+		return npm(e.str("npm")), "https://www.npmjs.com/package/" + e.str("npm"), nil
+	case "gems":
+		return rubygem(e.str("gems")), "https://rubygems.org/gems/" + e.str("gems"), nil
+	case "cpan":
+		return cpan(e.str("cpan")), "https://metacpan.org/release/" + e.str("cpan"), nil
+	case "debianpkg":
+		return debian(e.str("debianpkg")), "https://tracker.debian.org/pkg/" + e.str("debianpkg"), nil
+	case "anitya":
+		return anitya(e.str("anitya")), "https://release-monitoring.org/projects/search/?pattern=" + url.QueryEscape(e.str("anitya")), nil
+	case "regex":
+		exclude, err := e.excludeRegex()
+		if err != nil {
+			return nil, "", err
+		}
+		return regexProvider{e.str("url"), e.str("regex"), exclude}, e.str("url"), nil
+	}
+	return nil, "", fmt.Errorf("Unsupported nvchecker source %q", source)
+}
+
+// ResultForNvchecker determines the upstream version by mapping the nvchecker entry onto the providers
+func ResultForNvchecker(e NvcheckerEntry) (Result, error) {
+	var result Result
+	var err error
+	switch e.str("source") {
+	case "cmd":
+		result, err = ResultForScript(e.str("cmd"))
+	case "manual":
+		result = Result{Version: Version(e.str("manual")), Provider: "manual"}
+	default:
+		var p provider
+		var url string
+		if p, url, err = e.provider(); err != nil {
+			return Result{}, err
+		}
+		result, err = resultFor(p, url, "")
+	}
+	if exclude, excludeErr := e.excludeRegex(); err == nil && excludeErr != nil {
+		return Result{}, excludeErr
+	} else if err == nil && exclude != nil && exclude.MatchString(string(result.Version)) {
+		// sources other than regex only report the latest version
+		return Result{}, fmt.Errorf("Version %s excluded by nvchecker exclude_regex", result.Version)
+	}
+	if prefix := e.str("prefix"); prefix != "" {
+		result.Version = Version(strings.TrimPrefix(string(result.Version), prefix))
+	}
+	return result, err
+}
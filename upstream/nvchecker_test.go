@@ -0,0 +1,82 @@
+package upstream
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/h2non/gock"
+)
+
+func TestNvcheckerRegex(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://example.org").
+		Get("/download/").
+		Reply(200).
+		BodyString(`<a href="foo-1.9.tar.gz">foo-1.9.tar.gz</a> <a href="foo-1.10.tar.gz">foo-1.10.tar.gz</a> <a href="foo-1.2.tar.gz">`)
+
+	result, err := ResultForNvchecker(NvcheckerEntry{"source": "regex", "url": "https://example.org/download/", "regex": `foo-([\d.]+)\.tar\.gz`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Version != "1.10" || result.Provider != "regex" {
+		t.Errorf("Expecting regex 1.10, but got %v", result)
+	}
+}
+
+func TestNvcheckerRegexExclude(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://example.org").
+		Get("/download/").
+		Reply(200).
+		BodyString(`foo-1.9.tar.gz foo-2.0rc1.tar.gz foo-2.0beta.tar.gz`)
+
+	result, err := ResultForNvchecker(NvcheckerEntry{"source": "regex", "url": "https://example.org/download/", "regex": `foo-([\w.]+)\.tar\.gz`,
+		"exclude_regex": []interface{}{".*rc.*", ".*beta"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Version != "1.9" {
+		t.Errorf("Expecting regex 1.9, but got %v", result)
+	}
+}
+
+func TestNvcheckerRegexNotFound(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://example.org").
+		Get("/download/").
+		Reply(404).
+		BodyString(`foo-1.9.tar.gz`)
+
+	_, err := ResultForNvchecker(NvcheckerEntry{"source": "regex", "url": "https://example.org/download/", "regex": `foo-([\d.]+)\.tar\.gz`})
+	if ReasonOf(err) != Gone {
+		t.Errorf("Expecting a gone error, but got %v", err)
+	}
+}
+
+func TestNvcheckerGitHubPrefix(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.github.com").
+		Get("/repos/lilydjwg/nvchecker/releases/latest").
+		Reply(200).
+		JSON(map[string]string{"tag_name": "release-2.12"})
+
+	result, err := ResultForNvchecker(NvcheckerEntry{"source": "github", "github": "lilydjwg/nvchecker", "use_latest_release": true, "prefix": "release-"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Version != "2.12" || result.Provider != "github" {
+		t.Errorf("Expecting github 2.12, but got %v", result)
+	}
+}
+
+func TestNvcheckerUnsupported(t *testing.T) {
+	if _, err := ResultForNvchecker(NvcheckerEntry{"source": "vcs"}); err == nil {
+		t.Error("Expecting an error for unsupported source")
+	}
+}
+
+func TestNewestMatch(t *testing.T) {
+	if _, err := newestMatch(regexp.MustCompile(`foo-([\d.]+)`), "bar", "https://example.org", nil); err == nil {
+		t.Error("Expecting an error for no match")
+	}
+}
@@ -15,6 +15,14 @@ func (p cpan) releasesURL() string {
 	return fmt.Sprintf("https://fastapi.metacpan.org/v1/release/%s", p)
 }
 
+func (p cpan) name() string {
+	return "cpan"
+}
+
+func (p cpan) releaseURL(version Version) string {
+	return fmt.Sprintf("https://metacpan.org/release/%s", p)
+}
+
 func (p cpan) latestVersion() (Version, error) {
 	var info cpanRelease
 	if err := fetchJSON(p, &info); err != nil || info.Version == "" {
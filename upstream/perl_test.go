@@ -52,3 +52,17 @@ func TestPerlSource2(t *testing.T) {
 		t.Errorf("Expecting version 1.130, but got %v", version)
 	}
 }
+
+func TestPerlDistributionPage(t *testing.T) {
+	defer gock.Off()
+	mockPerl()
+
+	p := pkg.New("perl-critic", "0", "https://metacpan.org/release/Perl-Critic", "https://cdn.example.org/Perl-Critic.tar.gz")
+	version, err := VersionForPkg(p)
+	if err != nil {
+		t.Error(err)
+	}
+	if version != "1.130" {
+		t.Errorf("Expecting version 1.130, but got %v", version)
+	}
+}
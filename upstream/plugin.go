@@ -0,0 +1,60 @@
+package upstream
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// PluginRequest is written as JSON to the stdin of a provider plugin
+type PluginRequest struct {
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	URL     string   `json:"url,omitempty"`
+	Sources []string `json:"sources,omitempty"`
+	// Channel is the release channel tracked by the package, empty for stable
+	Channel string `json:"channel,omitempty"`
+}
+
+// PluginResponse is read as JSON from the stdout of a provider plugin
+type PluginResponse struct {
+	Version    Version   `json:"version"`
+	Released   time.Time `json:"released,omitempty"`
+	ReleaseURL string    `json:"release_url,omitempty"`
+	// ReleaseNotes is the Markdown body of the release
+	ReleaseNotes string `json:"release_notes,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// ResultForPlugin runs the plugin command using `sh -c`, passing the request on stdin and reading the response from stdout
+func ResultForPlugin(command string, request PluginRequest) (Result, error) {
+	result := Result{Provider: "plugin"}
+	input, err := json.Marshal(request)
+	if err != nil {
+		return result, err
+	}
+	cmd := exec.Command("/bin/sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return result, fmt.Errorf("Failed to run plugin `%s`: %w: %s", command, err, strings.TrimSpace(stderr.String()))
+	}
+	var response PluginResponse
+	if err := json.Unmarshal(output, &response); err != nil {
+		return result, fmt.Errorf("Failed to parse output of plugin `%s`: %w", command, err)
+	} else if response.Error != "" {
+		return result, fmt.Errorf("Plugin `%s` failed: %s", command, response.Error)
+	} else if response.Version == "" {
+		return result, fmt.Errorf("Plugin `%s` did not return a version", command)
+	}
+	result.Version = response.Version
+	result.Released = response.Released
+	result.ReleaseURL = response.ReleaseURL
+	result.ReleaseNotes = response.ReleaseNotes
+	return result, nil
+}
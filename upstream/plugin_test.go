@@ -0,0 +1,30 @@
+package upstream
+
+import (
+	"testing"
+)
+
+func TestResultForPlugin(t *testing.T) {
+	// echoes the package name as version
+	command := `sed -e 's/.*"name":"\([^"]*\)".*/{"version":"\1","release_url":"https:\/\/example.com"}/'`
+	result, err := ResultForPlugin(command, PluginRequest{Name: "1.2.3", Version: "1.0-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Version != "1.2.3" || result.Provider != "plugin" || result.ReleaseURL != "https://example.com" {
+		t.Errorf("Unexpected result %v", result)
+	}
+}
+
+func TestResultForPluginError(t *testing.T) {
+	for _, command := range []string{
+		`echo '{"error":"not found"}'`,
+		`echo '{}'`,
+		`echo 'garbage'`,
+		`exit 1`,
+	} {
+		if _, err := ResultForPlugin(command, PluginRequest{Name: "foo"}); err == nil {
+			t.Errorf("Expecting an error for %s", command)
+		}
+	}
+}
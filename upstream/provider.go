@@ -0,0 +1,79 @@
+package upstream
+
+import "sync"
+
+// Provider obtains the latest upstream release of a software project, e.g. from the GitHub releases API
+type Provider interface {
+	// Name identifies the provider, such as "github"
+	Name() string
+	// Latest returns the latest upstream release
+	Latest() (Result, error)
+}
+
+// Matcher returns the Provider for the URL, nil if the URL is not supported
+type Matcher func(url string) Provider
+
+var matchers struct {
+	sync.Mutex
+	list []Matcher
+}
+
+// Register adds a Matcher consulted before the built-in providers, allowing to support further hosts
+func Register(m Matcher) {
+	matchers.Lock()
+	defer matchers.Unlock()
+	matchers.list = append(matchers.list, m)
+}
+
+// registered returns the Provider of the first registered Matcher supporting the URL, nil if none
+func registered(url string) Provider {
+	matchers.Lock()
+	defer matchers.Unlock()
+	for _, m := range matchers.list {
+		if p := m(url); p != nil {
+			return p
+		}
+	}
+	return nil
+}
+
+// builtin adapts a built-in provider to the Provider interface
+type builtin struct {
+	provider
+	url string
+}
+
+func (b builtin) Name() string {
+	return b.name()
+}
+
+func (b builtin) Latest() (Result, error) {
+	return resultFor(b.provider, b.url, "")
+}
+
+func (b builtin) LatestInChannel(channel string) (Result, error) {
+	return resultFor(b.provider, b.url, channel)
+}
+
+// ProviderForURL returns the Provider supporting the URL, nil if none.
+// Unsupported URLs are resolved by following up to MaxRedirects redirects.
+func ProviderForURL(url string) Provider {
+	if p := matching(url); p != nil {
+		return p
+	}
+	if target := resolve(url); target != url {
+		return matching(target)
+	}
+	return nil
+}
+
+// matching returns the Provider supporting the URL without following redirects, nil if none
+func matching(url string) Provider {
+	if p := registered(url); p != nil {
+		return p
+	}
+	if p := providerForURL(url); p != nil {
+		return builtin{p, url}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package upstream
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type staticProvider Version
+
+func (p staticProvider) Name() string {
+	return "static"
+}
+
+func (p staticProvider) Latest() (Result, error) {
+	return Result{Version: Version(p)}, nil
+}
+
+func TestRegister(t *testing.T) {
+	Register(func(url string) Provider {
+		if strings.HasPrefix(url, "https://git.example.org/") {
+			return staticProvider("4.2")
+		}
+		return nil
+	})
+	defer func() { matchers.list = nil }()
+
+	result, err := ResultForURL("https://git.example.org/foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Version != "4.2" || result.Provider != "static" {
+		t.Errorf("Expecting static 4.2, but got %v", result)
+	}
+	if p := ProviderForURL("https://github.com/simon04/aur-out-of-date"); p == nil || p.Name() != "github" {
+		t.Errorf("Expecting github provider, but got %v", p)
+	}
+	if p := ProviderForURL("https://example.org/"); p != nil {
+		t.Errorf("Expecting no provider, but got %v", p)
+	}
+}
+
+func TestProviderForRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/foo":
+			http.Redirect(w, r, "/bar", http.StatusMovedPermanently)
+		case "/bar":
+			http.Redirect(w, r, "https://github.com/simon04/aur-out-of-date", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	if p := ProviderForURL(server.URL + "/foo"); p != nil {
+		t.Errorf("Expecting no provider without following redirects, but got %v", p)
+	}
+	MaxRedirects = 3
+	defer func() { MaxRedirects = 0 }()
+	if p := ProviderForURL(server.URL + "/foo"); p == nil || p.Name() != "github" {
+		t.Errorf("Expecting github provider, but got %v", p)
+	}
+	if p := ProviderForURL(server.URL + "/baz"); p != nil {
+		t.Errorf("Expecting no provider, but got %v", p)
+	}
+	MaxRedirects = 1
+	resolved.urls = nil
+	if p := ProviderForURL(server.URL + "/foo"); p != nil {
+		t.Errorf("Expecting no provider after one redirect, but got %v", p)
+	}
+}
@@ -2,12 +2,17 @@ package upstream
 
 import (
 	"fmt"
+	"time"
 )
 
 type pypiResponse struct {
 	Info struct {
-		Version string `json:"version"`
+		Version     string            `json:"version"`
+		ProjectURLs map[string]string `json:"project_urls"`
 	} `json:"info"`
+	URLs []struct {
+		UploadTime time.Time `json:"upload_time_iso_8601"`
+	} `json:"urls"`
 }
 
 type pypi string
@@ -16,10 +21,33 @@ func (p pypi) releasesURL() string {
 	return fmt.Sprintf("https://pypi.org/pypi/%s/json", p)
 }
 
+func (p pypi) name() string {
+	return "pypi"
+}
+
+func (p pypi) releaseURL(version Version) string {
+	return fmt.Sprintf("https://pypi.org/project/%s/%s/", p, version)
+}
+
 func (p pypi) latestVersion() (Version, error) {
+	result, err := p.latestRelease()
+	return result.Version, err
+}
+
+func (p pypi) latestRelease() (Result, error) {
 	var response pypiResponse
 	if err := fetchJSON(p, &response); err != nil || response.Info.Version == "" {
-		return "", fmt.Errorf("No PyPI release found for %v: %w", p, err)
+		return Result{}, fmt.Errorf("No PyPI release found for %v: %w", p, err)
+	}
+	result := Result{Version: Version(response.Info.Version)}
+	for _, key := range []string{"Changelog", "Changes", "Release notes", "Release Notes"} {
+		if url, ok := response.Info.ProjectURLs[key]; ok {
+			result.ReleaseURL = url
+			break
+		}
+	}
+	if len(response.URLs) > 0 {
+		result.Released = response.URLs[0].UploadTime
 	}
-	return Version(response.Info.Version), nil
+	return result, nil
 }
@@ -50,3 +50,44 @@ func testPythonHttpie(t *testing.T, url string) {
 		t.Errorf("Expecting version 0.9.9, but got %v", version)
 	}
 }
+
+func TestPythonHttpieProjectPage(t *testing.T) {
+	defer gock.Off()
+	mockPython()
+
+	p := pkg.New("httpie", "0", "https://pypi.org/project/httpie/", "https://cdn.example.org/httpie-0.9.8.tar.gz")
+	version, err := VersionForPkg(p)
+	if err != nil {
+		t.Error(err)
+	}
+	if version != "0.9.9" {
+		t.Errorf("Expecting version 0.9.9, but got %v", version)
+	}
+}
+
+func TestPythonHttpieLaterSource(t *testing.T) {
+	defer gock.Off()
+	mockPython()
+
+	p := pkg.New("httpie", "0", "https://httpie.io/", "httpie.patch", "https://files.pythonhosted.org/packages/28/93/4ebf2de4bc74bd517a27a600b2b23a5254a20f28e6e36fc876fd98f7a51b/httpie-0.9.9.tar.gz")
+	version, err := VersionForPkg(p)
+	if err != nil {
+		t.Error(err)
+	}
+	if version != "0.9.9" {
+		t.Errorf("Expecting version 0.9.9, but got %v", version)
+	}
+}
+
+func TestPythonEcosystem(t *testing.T) {
+	defer gock.Off()
+	mockPython()
+
+	result, err := ResultForURL("https://pypi.org/project/httpie/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Ecosystem == nil || *result.Ecosystem != (Ecosystem{"PyPI", "httpie"}) {
+		t.Errorf("Expecting PyPI ecosystem, but got %v", result.Ecosystem)
+	}
+}
@@ -0,0 +1,73 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Reason categorizes why the upstream version could not be determined
+type Reason string
+
+const (
+	// NoProvider means that no provider supports the URL or sources of the package
+	NoProvider = Reason("no-provider")
+	// RateLimited means that the upstream API rejected requests due to its rate limit
+	RateLimited = Reason("rate-limited")
+	// Network means that upstream could not be reached (DNS, connection, TLS, timeout)
+	Network = Reason("network")
+	// Gone means that the upstream project has been deleted (404 or 410)
+	Gone = Reason("gone")
+	// Failed is any other error, such as an unexpected response
+	Failed = Reason("error")
+)
+
+// reasonError attaches the reason to an error
+type reasonError struct {
+	reason Reason
+	err    error
+}
+
+func (e reasonError) Error() string {
+	return e.err.Error()
+}
+
+func (e reasonError) Unwrap() error {
+	return e.err
+}
+
+// withReason attaches the reason to err, to be obtained using ReasonOf
+func withReason(reason Reason, err error) error {
+	return reasonError{reason, err}
+}
+
+// statusReason returns the reason of an unsuccessful HTTP status code, "" if not categorized
+func statusReason(code int) Reason {
+	switch code {
+	case http.StatusNotFound, http.StatusGone:
+		return Gone
+	case http.StatusTooManyRequests:
+		return RateLimited
+	}
+	return ""
+}
+
+// ReasonOf categorizes the error returned when determining the upstream version, "" for nil
+func ReasonOf(err error) Reason {
+	var r reasonError
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &r):
+		return r.reason
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr):
+		return Network
+	case strings.Contains(strings.ToLower(err.Error()), "rate limit"):
+		// e.g. 403 "API rate limit exceeded for …" by GitHub
+		return RateLimited
+	}
+	return Failed
+}
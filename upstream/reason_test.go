@@ -0,0 +1,67 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/simon04/aur-out-of-date/pkg"
+)
+
+func TestReasonOf(t *testing.T) {
+	for err, expected := range map[error]Reason{
+		nil:                      "",
+		errors.New("unexpected"): Failed,
+		fmt.Errorf("Failed: %w", context.DeadlineExceeded):                      Network,
+		errors.New("API rate limit exceeded for 127.0.0.1"):                     RateLimited,
+		fmt.Errorf("Failed: %w", withReason(Gone, errors.New("404 Not Found"))): Gone,
+	} {
+		if actual := ReasonOf(err); actual != expected {
+			t.Errorf("Expecting %q for %v, but got %q", expected, err, actual)
+		}
+	}
+}
+
+func TestReasonNoProvider(t *testing.T) {
+	p := pkg.New("foo", "1.0", "https://example.org/", "https://example.org/foo-1.0.tar.gz")
+	_, err := ResultForPkg(p)
+	if reason := ReasonOf(err); reason != NoProvider {
+		t.Errorf("Expecting no-provider, but got %q for %v", reason, err)
+	}
+}
+
+func TestReasonGone(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.github.com/").
+		Get("/repos/foo/bar/tags").
+		Reply(http.StatusNotFound).
+		BodyString(`{"message": "Not Found"}`)
+	gock.New("https://pypi.org/").
+		Get("/pypi/httpie/json").
+		Reply(http.StatusTooManyRequests)
+
+	_, err := gitHubAPITags{gitHub{"foo", "bar"}}.latestVersion()
+	if reason := ReasonOf(err); reason != Gone {
+		t.Errorf("Expecting gone, but got %q for %v", reason, err)
+	}
+	_, err = pypi("httpie").latestVersion()
+	if reason := ReasonOf(err); reason != RateLimited {
+		t.Errorf("Expecting rate-limited, but got %q for %v", reason, err)
+	}
+}
+
+func TestReasonGitHubWithoutReleases(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.github.com/").
+		Get("/repos/foo/bar/releases/latest").
+		Reply(http.StatusNotFound).
+		BodyString(`{"message": "Not Found"}`)
+
+	_, err := gitHubAPIReleases{gitHub{"foo", "bar"}}.latestRelease()
+	if err == nil || ReasonOf(err) == Gone {
+		t.Errorf("Expecting a missing release not to be gone, but got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package upstream
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/simon04/aur-out-of-date/logging"
+)
+
+// MaxRedirects is the number of redirects followed using HEAD requests to detect the provider of URLs
+// pointing at redirectors (such as vanity domains or shortlinks), 0 to disable
+var MaxRedirects = 0
+
+// resolved caches the URLs redirects have been resolved to
+var resolved struct {
+	sync.Mutex
+	urls map[string]string
+}
+
+// resolve follows up to MaxRedirects redirects of the URL until reaching a URL supported by a provider,
+// returning the last URL reached
+func resolve(url string) string {
+	if MaxRedirects <= 0 || !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return url
+	}
+	resolved.Lock()
+	target, ok := resolved.urls[url]
+	resolved.Unlock()
+	if ok {
+		return target
+	}
+	client := *http.DefaultClient
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	target = url
+	for i := 0; i < MaxRedirects; i++ {
+		resp, err := client.Head(target)
+		if err != nil {
+			logging.Log(logging.Debug, "Failed to resolve redirect", "url", target, "err", err)
+			break
+		}
+		resp.Body.Close()
+		location, err := resp.Location()
+		if err != nil {
+			break
+		}
+		logging.Log(logging.Debug, "Following redirect", "url", target, "location", location.String())
+		target = location.String()
+		if registered(target) != nil || providerForURL(target) != nil {
+			break
+		}
+	}
+	resolved.Lock()
+	if resolved.urls == nil {
+		resolved.urls = map[string]string{}
+	}
+	resolved.urls[url] = target
+	resolved.Unlock()
+	return target
+}
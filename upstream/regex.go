@@ -0,0 +1,71 @@
+package upstream
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+
+	pkgbuild "github.com/mikkeloscar/gopkgbuild"
+)
+
+// regexProvider matches a regular expression against a web page and picks the newest version
+type regexProvider struct {
+	url   string
+	regex string
+	// exclude skips the versions fully matching the regular expression, if set
+	exclude *regexp.Regexp
+}
+
+func (r regexProvider) name() string {
+	return "regex"
+}
+
+func (r regexProvider) latestVersion() (Version, error) {
+	re, err := regexp.Compile(r.regex)
+	if err != nil {
+		return "", err
+	}
+	resp, err := get(r.url, nil)
+	if err != nil {
+		return "", fmt.Errorf("Failed to fetch %s: %w", r.url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("Failed to fetch %s: %s", r.url, resp.Status)
+		if reason := statusReason(resp.StatusCode); reason != "" {
+			return "", withReason(reason, err)
+		}
+		return "", err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("Failed to fetch %s: %w", r.url, err)
+	}
+	return newestMatch(re, string(body), r.url, r.exclude)
+}
+
+// newestMatch returns the newest version matched by re (using the first group, if any), skipping those matching exclude
+func newestMatch(re *regexp.Regexp, body string, url string, exclude *regexp.Regexp) (Version, error) {
+	var newest *pkgbuild.CompleteVersion
+	for _, match := range re.FindAllStringSubmatch(body, -1) {
+		v := match[0]
+		if len(match) > 1 {
+			v = match[1]
+		}
+		if exclude != nil && exclude.MatchString(v) {
+			continue
+		}
+		version, err := pkgbuild.NewCompleteVersion(v)
+		if err != nil {
+			continue
+		}
+		if newest == nil || version.Newer(newest) {
+			newest = version
+		}
+	}
+	if newest == nil {
+		return "", fmt.Errorf("No match for %s found on %s", re, url)
+	}
+	return Version(newest.Version), nil
+}
@@ -2,6 +2,7 @@ package upstream
 
 import (
 	"fmt"
+	"net/url"
 	"time"
 )
 
@@ -28,10 +29,23 @@ func (g rubygem) releasesURL() string {
 	return fmt.Sprintf("https://rubygems.org/api/v1/versions/%s.json", g)
 }
 
+func (g rubygem) name() string {
+	return "rubygems"
+}
+
+func (g rubygem) releaseURL(version Version) string {
+	return fmt.Sprintf("https://rubygems.org/gems/%s/versions/%s", g, url.PathEscape(string(version)))
+}
+
 func (g rubygem) latestVersion() (Version, error) {
+	result, err := g.latestRelease()
+	return result.Version, err
+}
+
+func (g rubygem) latestRelease() (Result, error) {
 	var versions rubygemsVersions
 	if err := fetchJSON(g, &versions); err != nil || len(versions) == 0 {
-		return "", fmt.Errorf("No RubyGems release found for %v: %w", g, err)
+		return Result{}, fmt.Errorf("No RubyGems release found for %v: %w", g, err)
 	}
-	return Version(versions[0].Number), nil
+	return Result{Version: Version(versions[0].Number), Released: versions[0].CreatedAt}, nil
 }
@@ -35,3 +35,17 @@ func TestRubyGemsSource1(t *testing.T) {
 		t.Errorf("Expecting version 1.3.1, but got %v", version)
 	}
 }
+
+func TestRubyGemsProjectPage(t *testing.T) {
+	defer gock.Off()
+	mockRubyGems()
+
+	p := pkg.New("ruby-htmlbeautifier", "0", "https://rubygems.org/gems/htmlbeautifier")
+	version, err := VersionForPkg(p)
+	if err != nil {
+		t.Error(err)
+	}
+	if version != "1.3.1" {
+		t.Errorf("Expecting version 1.3.1, but got %v", version)
+	}
+}
@@ -1,14 +1,16 @@
+// Package upstream determines the latest upstream version of a software project from its URL
 package upstream
 
 import (
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"os"
 	"os/exec"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/simon04/aur-out-of-date/logging"
 	"github.com/simon04/aur-out-of-date/pkg"
 )
 
@@ -23,7 +25,93 @@ func (v Version) String() string {
 	return s
 }
 
-func forURL(url string) (Version, error) {
+// Result holds the upstream version along with the provider used to obtain it
+type Result struct {
+	Version  Version
+	Provider string
+	// Released is the publication date of the release, if known
+	Released time.Time
+	// ReleaseURL links to the release notes or changelog, if known
+	ReleaseURL string
+	// ReleaseNotes is the Markdown body of the release, if known
+	ReleaseNotes string
+	// Raw is the release name or tag Version has been extracted from, if any
+	Raw string
+	// Ecosystem identifies the package for security advisories, if known
+	Ecosystem *Ecosystem
+	// MovedFrom and MovedTo are the old and the new location (e.g. github.com/owner/name) if the repository has been renamed or transferred
+	MovedFrom string
+	MovedTo   string
+}
+
+type provider interface {
+	name() string
+	latestVersion() (Version, error)
+}
+
+// releaseProvider is implemented by providers obtaining further details about the latest release
+type releaseProvider interface {
+	provider
+	latestRelease() (Result, error)
+}
+
+// releaseURLProvider is implemented by providers able to link to the release notes of a version
+type releaseURLProvider interface {
+	releaseURL(version Version) string
+}
+
+// ResultForURL determines the upstream version and provider for the given URL
+func ResultForURL(url string) (Result, error) {
+	return forURL(url, "")
+}
+
+// ResultForURLInChannel determines the upstream version in the release channel (e.g. "prerelease") for the given URL
+func ResultForURLInChannel(url string, channel string) (Result, error) {
+	return forURL(url, channel)
+}
+
+func forURL(url string, channel string) (Result, error) {
+	p := ProviderForURL(url)
+	if p == nil {
+		logging.Log(logging.Debug, "No provider found", "url", url)
+		return Result{}, withReason(NoProvider, fmt.Errorf("No release found for %s", url))
+	}
+	result, err := latestInChannel(p, channel)
+	if result.Provider == "" {
+		result.Provider = p.Name()
+	}
+	return result, err
+}
+
+// resultFor obtains the latest release in the channel ("" for stable) using the provider
+func resultFor(p provider, url string, channel string) (Result, error) {
+	logging.Log(logging.Debug, "Using provider", "provider", p.name(), "url", url, "channel", channel)
+	var result Result
+	var err error
+	if c, ok := p.(channelProvider); ok && !isStable(channel) {
+		result, err = c.latestInChannel(channel)
+	} else if !isStable(channel) {
+		err = fmt.Errorf("Provider %s does not support the release channel %s", p.name(), channel)
+	} else if r, ok := p.(releaseProvider); ok {
+		result, err = r.latestRelease()
+	} else {
+		result.Version, err = p.latestVersion()
+	}
+	result.Provider = p.name()
+	if e, ok := p.(ecosystemProvider); ok {
+		ecosystem := e.ecosystem()
+		result.Ecosystem = &ecosystem
+	}
+	if r, ok := p.(releaseURLProvider); ok && err == nil && result.ReleaseURL == "" {
+		result.ReleaseURL = r.releaseURL(result.Version)
+	}
+	if err != nil {
+		logging.Log(logging.Info, "Provider failed", "provider", p.name(), "url", url, "err", err)
+	}
+	return result, err
+}
+
+func providerForURL(url string) provider {
 	switch {
 	case strings.Contains(url, "github.com"):
 		fallthrough
@@ -33,22 +121,22 @@ func forURL(url string) (Version, error) {
 			break
 		}
 		if os.Getenv("GITHUB_ATOM") != "" {
-			return gitHubAPIAtom{gitHub: *g}.latestVersion()
+			return gitHubAPIAtom{gitHub: *g}
 		} else if os.Getenv("GITHUB_TAGS") != "" {
-			return gitHubAPITags{gitHub: *g}.latestVersion()
+			return gitHubAPITags{gitHub: *g}
 		}
-		return gitHubAPIReleases{gitHub: *g}.latestVersion()
+		return gitHubAPIReleases{gitHub: *g}
 	case strings.Contains(url, "registry.npmjs.org"):
 		match := regexp.MustCompile("registry.npmjs.org/((@[^/#.]+/)?[^/#.]+)").FindStringSubmatch(url)
 		if len(match) > 0 {
-			return npm(match[1]).latestVersion()
+			return npm(match[1])
 		}
 	case strings.Contains(url, "npmjs.com/package"):
 		fallthrough
 	case strings.Contains(url, "npmjs.org/package"):
 		match := regexp.MustCompile("/package/((@[^/#.]+/)?[^/#.]+)").FindStringSubmatch(url)
 		if len(match) > 0 {
-			return npm(match[1]).latestVersion()
+			return npm(match[1])
 		}
 	case strings.Contains(url, "pypi.python.org"):
 		fallthrough
@@ -59,69 +147,111 @@ func forURL(url string) (Version, error) {
 	case strings.Contains(url, "pypi.org"):
 		match := regexp.MustCompile("/packages/source/[^/#.]+/([^/#.]+)/").FindStringSubmatch(url)
 		if len(match) > 0 {
-			return pypi(match[1]).latestVersion()
+			return pypi(match[1])
 		}
 		match = regexp.MustCompile("/([^/#.]+)-[0-9.]+(post.)?.tar.gz$").FindStringSubmatch(url)
 		if len(match) > 0 {
-			return pypi(match[1]).latestVersion()
+			return pypi(match[1])
+		}
+		// Example: https://pypi.org/project/httpie/
+		match = regexp.MustCompile("/project/([^/#?]+)/?$").FindStringSubmatch(url)
+		if len(match) > 0 {
+			return pypi(match[1])
 		}
 	case strings.Contains(url, "search.cpan.org"):
 		fallthrough
 	case strings.Contains(url, "search.mcpan.org"):
 		fallthrough
-	case strings.Contains(url, "cpan.metacpan.org"):
+	case strings.Contains(url, "metacpan.org"):
 		match := regexp.MustCompile("/([^/#.]+?)-v?([0-9.-]+)\\.(tgz|tar.gz)$").FindStringSubmatch(url)
 		if len(match) > 0 {
-			return cpan(match[1]).latestVersion()
+			return cpan(match[1])
+		}
+		// Example: https://metacpan.org/release/Moose
+		match = regexp.MustCompile("metacpan.org/(?:release|dist)/([^/#?]+)/?$").FindStringSubmatch(url)
+		if len(match) > 0 {
+			return cpan(match[1])
 		}
 	case strings.Contains(url, "rubygems.org"):
 		fallthrough
 	case strings.Contains(url, "gems.rubyforge.org"):
 		match := regexp.MustCompile("/([^/#]+?)-[^-]+\\.gem$").FindStringSubmatch(url)
 		if len(match) > 0 {
-			return rubygem(match[1]).latestVersion()
+			return rubygem(match[1])
+		}
+		// Example: https://rubygems.org/gems/rails
+		match = regexp.MustCompile("/gems/([^/#?]+)/?$").FindStringSubmatch(url)
+		if len(match) > 0 {
+			return rubygem(match[1])
 		}
 	case strings.Contains(url, "gitlab"):
 		// Example: https://gitlab.com/gitlab-org/gitlab-ce/-/archive/v11.0.0-rc7/gitlab-ce-v11.0.0-rc7.tar.gz
 		match := regexp.MustCompile("https?://([^/]+)/([^/]+)/([^/]+)(\\.git|/.*)?$").FindStringSubmatch(url)
 		if len(match) > 0 {
-			return gitLab{match[1], match[2], match[3]}.latestVersion()
+			return gitLab{match[1], match[2], match[3]}
 		}
 	case strings.Contains(url, "debian.org"):
 		// Example: http://ftp.debian.org/debian/pool/main/p/python3-defaults/python3-defaults_3.6.6-1.tar.gz
 		match := regexp.MustCompile("/debian/pool/(?:contrib|main|non-free)/[a-z]{1,4}/([^/#.]+)/[^/#]+(?:.tar|.deb)").FindStringSubmatch(url)
 		if len(match) > 0 {
-			return debian(match[1]).latestVersion()
+			return debian(match[1])
 		}
 	}
-	return "", fmt.Errorf("No release found for %s", url)
+	return nil
 }
 
 // VersionForPkg determines the upstream version for the given package
 func VersionForPkg(pkg pkg.Pkg) (Version, error) {
-	version, err := forURL(pkg.URL())
-	if err == nil {
-		return version, nil
+	result, err := ResultForPkg(pkg)
+	return result.Version, err
+}
+
+// ResultForPkg determines the upstream version and provider for the given package
+func ResultForPkg(pkg pkg.Pkg) (Result, error) {
+	return ResultForPkgInChannel(pkg, "")
+}
+
+// ResultForPkgInChannel determines the upstream version in the release channel (e.g. "prerelease") for the given package
+func ResultForPkgInChannel(pkg pkg.Pkg, channel string) (Result, error) {
+	result, urlErr := forURL(pkg.URL(), channel)
+	if urlErr == nil {
+		return result, nil
 	}
+	logging.Log(logging.Debug, "Falling back to sources", "pkg", pkg.Name())
 	sources, err := pkg.Sources()
 	if err != nil {
-		return "", fmt.Errorf("Failed to obtain sources for %s: %w", pkg.Name(), err)
+		return Result{}, fmt.Errorf("Failed to obtain sources for %s: %w", pkg.Name(), err)
 	}
-	if len(sources) > 0 {
-		return forURL(sources[0])
+	for _, source := range sources {
+		if ProviderForURL(source) != nil {
+			return forURL(source, channel)
+		}
 	}
-	return "", fmt.Errorf("No release found for %s: %w", pkg.Name(), err)
+	if ProviderForURL(pkg.URL()) != nil {
+		return result, urlErr
+	} else if len(sources) > 0 {
+		return forURL(sources[0], channel)
+	}
+	return Result{}, withReason(NoProvider, fmt.Errorf("No release found for %s: %w", pkg.Name(), err))
 }
 
 // VersionForScript runs the script using `sh -c` to determine the upstream version for the given package
 func VersionForScript(script string) (Version, error) {
+	result, err := ResultForScript(script)
+	return result.Version, err
+}
+
+// ResultForScript runs the script using `sh -c` to determine the upstream version for the given package
+func ResultForScript(script string) (Result, error) {
+	result := Result{Provider: "script"}
 	output, err := exec.Command("/bin/sh", "-c", script).Output()
 	if err != nil {
-		return "", fmt.Errorf("Failed to run script `%s`: %w", script, err)
+		return result, fmt.Errorf("Failed to run script `%s`: %w", script, err)
 	}
 	v := string(output)
 	v = strings.TrimSpace(v)
-	return Version(v), nil
+	result.Version = Version(v)
+	return result, nil
 }
 
 type releasesAPI interface {
@@ -130,12 +260,14 @@ type releasesAPI interface {
 }
 
 func fetchJSON(a releasesAPI, target interface{}) error {
-	url := a.releasesURL()
-	resp, err := http.Get(url)
+	resp, err := get(a.releasesURL(), nil)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
+	if reason := statusReason(resp.StatusCode); reason != "" {
+		return withReason(reason, fmt.Errorf("%s", resp.Status))
+	}
 
 	dec := json.NewDecoder(resp.Body)
 	return dec.Decode(target)
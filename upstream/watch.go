@@ -0,0 +1,273 @@
+package upstream
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+
+	pkgbuild "github.com/mikkeloscar/gopkgbuild"
+)
+
+// watchSubstitutions are the placeholders of the Debian watch file syntax
+var watchSubstitutions = strings.NewReplacer(
+	"@ANY_VERSION@", `[-_]?v?(\d[\-+\.:\~\da-zA-Z]*)`,
+	"@ARCHIVE_EXT@", `(?i)(?:\.(?:tar\.xz|tar\.bz2|tar\.gz|tar\.zstd?|zip|tgz|tbz|txz))`,
+	"@SIGNATURE_EXT@", `(?i)(?:\.(?:tar\.xz|tar\.bz2|tar\.gz|tar\.zstd?|zip|tgz|tbz|txz))\.(?:asc|pgp|gpg|sig|sign)`,
+	"@DEB_EXT@", `[\+~](debian|dfsg|ds|deb)(\.)?(\d+)?$`,
+)
+
+// href matches the links of a web page
+var href = regexp.MustCompile(`(?i)href\s*=\s*["']([^"']+)["']`)
+
+// watchLine is a rule of a Debian watch file, looking for links matching pattern on the page at url
+type watchLine struct {
+	url            string
+	pattern        *regexp.Regexp
+	uversionmangle []mangleRule
+	plain          bool
+}
+
+// watchProvider determines the newest version using the rules of a Debian watch file, see https://manpages.debian.org/uscan
+type watchProvider struct {
+	lines []watchLine
+}
+
+// ResultForWatch determines the upstream version using the rules of a Debian watch file (version=4 syntax), substituting @PACKAGE@ by pkgname
+func ResultForWatch(content string, pkgname string) (Result, error) {
+	lines, err := parseWatch(content, pkgname)
+	if err != nil {
+		return Result{Provider: "watch"}, err
+	}
+	return resultFor(watchProvider{lines}, lines[0].url, "")
+}
+
+func (w watchProvider) name() string {
+	return "watch"
+}
+
+func (w watchProvider) latestVersion() (Version, error) {
+	var newest *pkgbuild.CompleteVersion
+	for _, line := range w.lines {
+		versions, err := line.versions()
+		if err != nil {
+			return "", err
+		}
+		for _, v := range versions {
+			version, err := pkgbuild.NewCompleteVersion(v)
+			if err == nil && (newest == nil || version.Newer(newest)) {
+				newest = version
+			}
+		}
+	}
+	if newest == nil {
+		return "", fmt.Errorf("No version found using the watch file rules for %s", w.lines[0].url)
+	}
+	return Version(newest.Version), nil
+}
+
+// versions returns the mangled versions of the links matching the pattern
+func (l watchLine) versions() ([]string, error) {
+	resp, err := get(l.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch %s: %w", l.url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Failed to fetch %s: %s", l.url, resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch %s: %w", l.url, err)
+	}
+	var matches [][]string
+	if l.plain {
+		matches = l.pattern.FindAllStringSubmatch(string(body), -1)
+	} else {
+		for _, link := range href.FindAllStringSubmatch(string(body), -1) {
+			if match := l.pattern.FindStringSubmatch(link[1]); match != nil {
+				matches = append(matches, match)
+			}
+		}
+	}
+	var versions []string
+	for _, match := range matches {
+		version := strings.Join(match[1:], ".")
+		if len(match) == 1 {
+			version = match[0]
+		}
+		for _, rule := range l.uversionmangle {
+			version = rule.apply(version)
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+// parseWatch parses the rules of a watch file, skipping comments and the version line
+func parseWatch(content string, pkgname string) ([]watchLine, error) {
+	content = strings.Replace(content, "\\\n", "", -1)
+	content = strings.Replace(content, "@PACKAGE@", regexp.QuoteMeta(pkgname), -1)
+	var lines []watchLine
+	for _, text := range strings.Split(content, "\n") {
+		text = strings.TrimSpace(text)
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, "version=") {
+			continue
+		}
+		line, err := parseWatchLine(text)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse watch file line %q: %w", text, err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("No rules found in watch file")
+	}
+	return lines, nil
+}
+
+// watchOptions matches the opts="…" or opts=… prefix of a watch file line
+var watchOptions = regexp.MustCompile(`^opts\s*=\s*(?:"([^"]*)"|(\S*))\s*`)
+
+// unescapedComma separates the options, a comma preceded by a backslash is part of the option
+var unescapedComma = regexp.MustCompile(`(^|[^\\]),`)
+
+func parseWatchLine(text string) (watchLine, error) {
+	var line watchLine
+	if m := watchOptions.FindStringSubmatch(text); m != nil {
+		text = text[len(m[0]):]
+		options := m[1] + m[2]
+		for _, option := range strings.Split(unescapedComma.ReplaceAllString(options, "$1\x00"), "\x00") {
+			parts := strings.SplitN(strings.TrimSpace(option), "=", 2)
+			value := ""
+			if len(parts) == 2 {
+				value = strings.Replace(parts[1], `\,`, ",", -1)
+			}
+			switch parts[0] {
+			case "uversionmangle", "versionmangle":
+				for _, expr := range strings.Split(value, ";") {
+					rule, err := parseMangleRule(expr)
+					if err != nil {
+						return line, err
+					}
+					line.uversionmangle = append(line.uversionmangle, rule)
+				}
+			case "searchmode":
+				line.plain = value == "plain"
+			}
+		}
+	}
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return line, fmt.Errorf("Missing URL")
+	}
+	line.url = fields[0]
+	pattern := ""
+	if len(fields) > 1 {
+		pattern = fields[1]
+	} else if i := strings.LastIndex(line.url, "/"); i >= 0 && strings.Contains(line.url[i:], "(") {
+		line.url, pattern = line.url[:i+1], line.url[i+1:]
+	} else {
+		return line, fmt.Errorf("Missing pattern")
+	}
+	pattern = watchSubstitutions.Replace(pattern)
+	if !line.plain && strings.Contains(pattern, "://") {
+		pattern = "^" + pattern + "$"
+	} else if !line.plain {
+		// relative patterns match the last part of (relative or absolute) links
+		pattern = "(?:^|/)" + pattern + "$"
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return line, err
+	}
+	line.pattern = re
+	return line, nil
+}
+
+// mangleRule is a Perl substitution (s/…/…/g) or transliteration (tr/…/…/, y/…/…/) as used by uversionmangle
+type mangleRule struct {
+	regex       *regexp.Regexp
+	replacement string
+	global      bool
+	translate   *strings.Replacer
+}
+
+// perlGroup matches the group references of a Perl replacement (\1 or $1)
+var perlGroup = regexp.MustCompile(`[\\$](\d)`)
+
+func parseMangleRule(expr string) (mangleRule, error) {
+	var rule mangleRule
+	expr = strings.TrimSpace(expr)
+	kind := ""
+	for _, k := range []string{"s", "tr", "y"} {
+		if strings.HasPrefix(expr, k) && len(expr) > len(k) {
+			kind = k
+			break
+		}
+	}
+	if kind == "" {
+		return rule, fmt.Errorf("Unsupported mangle rule %s", expr)
+	}
+	delimiter := expr[len(kind) : len(kind)+1]
+	parts := strings.Split(expr[len(kind)+1:], delimiter)
+	if len(parts) != 3 {
+		return rule, fmt.Errorf("Invalid mangle rule %s", expr)
+	}
+	if kind == "s" {
+		re, err := regexp.Compile(parts[0])
+		if err != nil {
+			return rule, fmt.Errorf("Invalid mangle rule %s: %w", expr, err)
+		}
+		rule.regex = re
+		rule.replacement = perlGroup.ReplaceAllString(parts[1], "$${$1}")
+		rule.global = strings.Contains(parts[2], "g")
+		return rule, nil
+	}
+	from, to := expandRange(parts[0]), expandRange(parts[1])
+	if len(to) == 0 {
+		return rule, fmt.Errorf("Invalid mangle rule %s", expr)
+	}
+	var pairs []string
+	for i, r := range from {
+		replacement := to[len(to)-1]
+		if i < len(to) {
+			replacement = to[i]
+		}
+		pairs = append(pairs, string(r), string(replacement))
+	}
+	rule.translate = strings.NewReplacer(pairs...)
+	return rule, nil
+}
+
+// expandRange expands character ranges such as a-z of transliterations
+func expandRange(s string) []rune {
+	runes := []rune(s)
+	var r []rune
+	for i := 0; i < len(runes); i++ {
+		if i+2 < len(runes) && runes[i+1] == '-' {
+			for c := runes[i]; c <= runes[i+2]; c++ {
+				r = append(r, c)
+			}
+			i += 2
+		} else {
+			r = append(r, runes[i])
+		}
+	}
+	return r
+}
+
+func (r mangleRule) apply(version string) string {
+	if r.translate != nil {
+		return r.translate.Replace(version)
+	} else if r.global {
+		return r.regex.ReplaceAllString(version, r.replacement)
+	}
+	if loc := r.regex.FindStringSubmatchIndex(version); loc != nil {
+		var replaced []byte
+		replaced = r.regex.ExpandString(replaced, r.replacement, version, loc)
+		return version[:loc[0]] + string(replaced) + version[loc[1]:]
+	}
+	return version
+}
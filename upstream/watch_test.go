@@ -0,0 +1,79 @@
+package upstream
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+)
+
+func TestWatch(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://download.example.org").
+		Get("/releases/").
+		Reply(http.StatusOK).
+		BodyString(`<a href="foo-1.9.tar.gz">foo-1.9.tar.gz</a>
+<a href="/releases/foo-2.0-rc1.tar.gz">foo-2.0-rc1.tar.gz</a>
+<a href="https://download.example.org/releases/foo-1.10.tar.xz">foo-1.10.tar.xz</a>
+<a href="foo-1.10.tar.xz.asc">foo-1.10.tar.xz.asc</a>
+<a href="libfoo-3.0.tar.gz">libfoo-3.0.tar.gz</a>`)
+
+	watch := "version=4\n" +
+		"# releases\n" +
+		"opts=\"uversionmangle=s/-rc/~rc/;tr/A-Z/a-z/\" \\\n" +
+		"  https://download.example.org/releases/ @PACKAGE@@ANY_VERSION@@ARCHIVE_EXT@\n"
+	result, err := ResultForWatch(watch, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Provider != "watch" || result.Version != "2.0~rc1" {
+		t.Errorf("Expecting 2.0~rc1 from provider watch, but got %v", result)
+	}
+}
+
+func TestWatchSingleURL(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://download.example.org").
+		Get("/releases/").
+		Reply(http.StatusOK).
+		BodyString(`<a href="foo_1_9.tar.gz">1.9</a> <a href="foo_1_10.tar.gz">1.10</a>`)
+
+	result, err := ResultForWatch(`https://download.example.org/releases/foo_(\d+)_(\d+)\.tar\.gz`, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Version != "1.10" {
+		t.Errorf("Expecting 1.10, but got %v", result.Version)
+	}
+}
+
+func TestParseWatchInvalid(t *testing.T) {
+	for _, watch := range []string{
+		"version=4\n",
+		"https://example.org/",
+		`opts=uversionmangle=q/a/b/ https://example.org/ foo-(\d+)`,
+		`https://example.org/ foo-(?=\d+)`,
+	} {
+		if _, err := parseWatch(watch, "foo"); err == nil {
+			t.Errorf("Expecting error for %q", watch)
+		}
+	}
+}
+
+func TestMangleRule(t *testing.T) {
+	for _, test := range []struct{ rule, input, expected string }{
+		{`s/_/./g`, "1_2_3", "1.2.3"},
+		{`s/_/./`, "1_2_3", "1.2_3"},
+		{`s/^v(\d)/$1/`, "v1.0", "1.0"},
+		{`s%(\d+)-beta(\d*)%\1~beta\2%`, "2-beta3", "2~beta3"},
+		{`y/ABC/abc/`, "1.0ABC", "1.0abc"},
+	} {
+		rule, err := parseMangleRule(test.rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := rule.apply(test.input); actual != test.expected {
+			t.Errorf("Expecting %s for %s applied to %s, but got %s", test.expected, test.rule, test.input, actual)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/simon04/aur-out-of-date/logging"
+)
+
+// clearScreen moves the cursor to the top left corner and clears the terminal
+const clearScreen = "\033[H\033[2J"
+
+// watchPackages periodically checks all packages in the foreground, redrawing the results after each check
+func watchPackages() {
+	repeat(func() {
+		var output bytes.Buffer
+		if f, err := newFormatter(&output, commandline.output); err != nil {
+			logging.Errorf("Failed to create formatter: %v", err)
+		} else {
+			formatter = f
+		}
+		run(commandline.printStatistics)
+		if isTerminal(os.Stdout) {
+			fmt.Print(clearScreen)
+		}
+		fmt.Printf("Every %s: aur-out-of-date %s\n\n", commandline.interval, strings.Join(os.Args[1:], " "))
+		os.Stdout.Write(output.Bytes())
+		fmt.Printf("\nLast check at %s, press Ctrl-C to quit\n", time.Now().Format("15:04:05"))
+	}, watch(5*time.Second, configFiles()...))
+}